@@ -125,11 +125,24 @@ func (s *OrgService) CreateOrganization(ctx context.Context, o *influxdb.Organiz
 }
 
 // UpdateOrganization checks to see if the authorizer on context has write access to the organization provided.
+// Updating the quotas of an organization requires write access to all organizations, so that an organization
+// cannot raise its own quotas.
 func (s *OrgService) UpdateOrganization(ctx context.Context, id influxdb.ID, upd influxdb.OrganizationUpdate) (*influxdb.Organization, error) {
 	if err := authorizeWriteOrg(ctx, id); err != nil {
 		return nil, err
 	}
 
+	if upd.Quotas != nil {
+		p, err := influxdb.NewGlobalPermission(influxdb.WriteAction, influxdb.OrgsResourceType)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := IsAllowed(ctx, *p); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.s.UpdateOrganization(ctx, id, upd)
 }
 
@@ -316,6 +316,7 @@ func TestOrgService_UpdateOrganization(t *testing.T) {
 	}
 	type args struct {
 		id         influxdb.ID
+		upd        influxdb.OrganizationUpdate
 		permission influxdb.Permission
 	}
 	type wants struct {
@@ -381,6 +382,60 @@ func TestOrgService_UpdateOrganization(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "authorized to update org quotas",
+			fields: fields{
+				OrgService: &mock.OrganizationService{
+					UpdateOrganizationF: func(ctx context.Context, id influxdb.ID, upd influxdb.OrganizationUpdate) (*influxdb.Organization, error) {
+						return &influxdb.Organization{
+							ID: 1,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id:  1,
+				upd: influxdb.OrganizationUpdate{Quotas: &influxdb.OrgQuotas{MaxBuckets: 10}},
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to update org quotas",
+			fields: fields{
+				OrgService: &mock.OrganizationService{
+					UpdateOrganizationF: func(ctx context.Context, id influxdb.ID, upd influxdb.OrganizationUpdate) (*influxdb.Organization, error) {
+						return &influxdb.Organization{
+							ID: 1,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id:  1,
+				upd: influxdb.OrganizationUpdate{Quotas: &influxdb.OrgQuotas{MaxBuckets: 10}},
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -390,7 +445,7 @@ func TestOrgService_UpdateOrganization(t *testing.T) {
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
 
-			_, err := s.UpdateOrganization(ctx, tt.args.id, influxdb.OrganizationUpdate{})
+			_, err := s.UpdateOrganization(ctx, tt.args.id, tt.args.upd)
 			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
 		})
 	}
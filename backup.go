@@ -0,0 +1,51 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BackupService represents the data backup functions of InfluxDB.
+type BackupService interface {
+	// CreateBackup creates a local copy (hard links) of the TSM data for all orgs and buckets.
+	// The return values are used by the client to fetch the files.
+	CreateBackup(ctx context.Context) (int, []string, error)
+
+	// FetchBackupFile downloads one backup file.
+	// A file may be fetched again until the backup is finished or expires.
+	FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error
+
+	// FinishBackup removes the local copy of a backup once all its files have been fetched.
+	FinishBackup(ctx context.Context, backupID int) error
+
+	// InternalBackupPath is a utility to determine the on-disk location of a backup fileset.
+	InternalBackupPath(backupID int) (string, error)
+}
+
+// KVBackupService represents the meta data backup functions of InfluxDB.
+type KVBackupService interface {
+	// Backup writes a consistent snapshot of the key/value store to w.
+	Backup(ctx context.Context, w io.Writer) error
+}
+
+// BackupManifest describes the files that make up a backup.
+type BackupManifest struct {
+	// CreatedAt is the time the backup was taken.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// KV is the file name of the key/value store snapshot.
+	KV BackupManifestEntry `json:"kv"`
+
+	// Files are the TSM data files and their tombstones.
+	Files []BackupManifestEntry `json:"files"`
+}
+
+// BackupManifestEntry is a single file within a backup.
+type BackupManifestEntry struct {
+	FileName string `json:"fileName"`
+	Size     int64  `json:"size"`
+}
+
+// BackupManifestFileName is the name of the manifest file written alongside a backup.
+const BackupManifestFileName = "manifest.json"
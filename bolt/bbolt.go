@@ -3,6 +3,7 @@ package bolt
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -181,6 +182,15 @@ func (c *Client) initialize(ctx context.Context) error {
 	return nil
 }
 
+// Backup writes a consistent snapshot of the bolt database to w. The snapshot
+// is taken within a read transaction, so writers are not blocked.
+func (c *Client) Backup(ctx context.Context, w io.Writer) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
 // Close the connection to the bolt database
 func (c *Client) Close() error {
 	if c.db != nil {
@@ -73,6 +73,36 @@ func TestClientOpen(t *testing.T) {
 	}
 }
 
+func TestClientBackup(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	f, err := ioutil.TempFile("", "influxdata-platform-bolt-backup-")
+	if err != nil {
+		t.Fatalf("unable to create temporary backup file: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := c.Backup(context.Background(), f); err != nil {
+		t.Fatalf("unable to backup database: %v", err)
+	}
+	f.Close()
+
+	// The backup must itself be a valid bolt database.
+	backup := bolt.NewClient()
+	backup.Path = f.Name()
+	if err := backup.Open(context.Background()); err != nil {
+		t.Fatalf("unable to open backup %s: %v", f.Name(), err)
+	}
+
+	if err := backup.Close(); err != nil {
+		t.Fatalf("unable to close backup %s: %v", f.Name(), err)
+	}
+}
+
 func NewTestKVStore() (*bolt.KVStore, func(), error) {
 	f, err := ioutil.TempFile("", "influxdata-platform-bolt-")
 	if err != nil {
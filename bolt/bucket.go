@@ -341,6 +341,15 @@ func (c *Client) CreateBucket(ctx context.Context, b *platform.Bucket) error {
 			b.OrganizationID = o.ID
 		}
 
+		if b.Schema != nil {
+			if err := b.Schema.Valid(); err != nil {
+				return &platform.Error{
+					Err: err,
+					Op:  op,
+				}
+			}
+		}
+
 		unique := c.uniqueBucketName(ctx, tx, b)
 
 		if !unique {
@@ -527,6 +536,16 @@ func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID,
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.Schema != nil {
+		if err := upd.Schema.Valid(); err != nil {
+			return nil, err
+		}
+		b.Schema = upd.Schema
+		if len(b.Schema.Measurements) == 0 {
+			b.Schema = nil
+		}
+	}
+
 	if upd.Name != nil {
 		key, err := bucketIndexKey(b)
 		if err != nil {
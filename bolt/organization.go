@@ -225,6 +225,14 @@ func (c *Client) CreateOrganization(ctx context.Context, o *influxdb.Organizatio
 				Msg:  fmt.Sprintf("organization with name %s already exists", o.Name),
 			}
 		}
+		if o.Quotas != nil {
+			if err := o.Quotas.Valid(); err != nil {
+				return &influxdb.Error{
+					Err: err,
+					Op:  op,
+				}
+			}
+		}
 
 		o.ID = c.IDGenerator.ID()
 		if err := c.appendOrganizationEventToLog(ctx, tx, o.ID, organizationCreatedEvent); err != nil {
@@ -342,6 +350,18 @@ func (c *Client) updateOrganization(ctx context.Context, tx *bolt.Tx, id influxd
 		o.Name = *upd.Name
 	}
 
+	if upd.Quotas != nil {
+		if err := upd.Quotas.Valid(); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		o.Quotas = upd.Quotas
+		if o.Quotas.IsZero() {
+			o.Quotas = nil
+		}
+	}
+
 	if err := c.appendOrganizationEventToLog(ctx, tx, o.ID, organizationUpdatedEvent); err != nil {
 		return nil, &influxdb.Error{
 			Err: err,
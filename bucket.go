@@ -15,6 +15,10 @@ const (
 	BucketTypeLogs = BucketType(iota + 10)
 )
 
+// TasksSystemBucketName is the name of the bucket of each organization
+// that the runs and logs of its tasks are written to.
+const TasksSystemBucketName = "_tasks"
+
 // InfiniteRetention is default infinite retention period.
 const InfiniteRetention = 0
 
@@ -26,6 +30,9 @@ type Bucket struct {
 	Name                string        `json:"name"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	// Schema is the optional explicit schema of the bucket. Writes to a
+	// bucket without a schema may write any measurement.
+	Schema *BucketSchema `json:"schema,omitempty"`
 }
 
 // ops for buckets error and buckets op logs.
@@ -66,6 +73,9 @@ type BucketService interface {
 type BucketUpdate struct {
 	Name            *string        `json:"name,omitempty"`
 	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	// Schema replaces the schema of the bucket. A schema without
+	// measurements removes the schema.
+	Schema *BucketSchema `json:"schema,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
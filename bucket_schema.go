@@ -0,0 +1,138 @@
+package influxdb
+
+import (
+	"fmt"
+)
+
+// Field types of a bucket schema.
+const (
+	SchemaFieldTypeFloat    = "float"
+	SchemaFieldTypeInteger  = "integer"
+	SchemaFieldTypeUnsigned = "unsigned"
+	SchemaFieldTypeString   = "string"
+	SchemaFieldTypeBoolean  = "boolean"
+)
+
+// BucketSchema is the explicit schema of a bucket. A bucket with a schema only
+// accepts writes of its measurements, with their tag keys and field types.
+type BucketSchema struct {
+	Measurements []MeasurementSchema `json:"measurements"`
+}
+
+// MeasurementSchema is the schema of a measurement of a bucket.
+type MeasurementSchema struct {
+	Name string `json:"name"`
+	// Tags are the tag keys of the measurement. Points of the measurement
+	// may have any subset of them.
+	Tags   []string      `json:"tags,omitempty"`
+	Fields []FieldSchema `json:"fields"`
+}
+
+// FieldSchema is the name and type of a field of a measurement.
+type FieldSchema struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Valid returns an error if the schema has unnamed or duplicate measurements,
+// tag keys or fields, or fields of an unknown type.
+func (s *BucketSchema) Valid() error {
+	measurements := make(map[string]bool, len(s.Measurements))
+	for _, m := range s.Measurements {
+		if m.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "bucket schema measurement name is empty",
+			}
+		}
+		if measurements[m.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("bucket schema measurement %q is declared more than once", m.Name),
+			}
+		}
+		measurements[m.Name] = true
+
+		if len(m.Fields) == 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("bucket schema measurement %q has no fields", m.Name),
+			}
+		}
+
+		keys := make(map[string]bool, len(m.Tags)+len(m.Fields))
+		for _, k := range m.Tags {
+			if k == "" || k == "time" || k == "_measurement" || k == "_field" {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("bucket schema measurement %q has invalid tag key %q", m.Name, k),
+				}
+			}
+			if keys[k] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("bucket schema measurement %q declares %q more than once", m.Name, k),
+				}
+			}
+			keys[k] = true
+		}
+
+		for _, f := range m.Fields {
+			if f.Name == "" || f.Name == "time" {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("bucket schema measurement %q has invalid field %q", m.Name, f.Name),
+				}
+			}
+			if keys[f.Name] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("bucket schema measurement %q declares %q more than once", m.Name, f.Name),
+				}
+			}
+			keys[f.Name] = true
+
+			switch f.Type {
+			case SchemaFieldTypeFloat, SchemaFieldTypeInteger, SchemaFieldTypeUnsigned, SchemaFieldTypeString, SchemaFieldTypeBoolean:
+			default:
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("bucket schema field %q of measurement %q has unknown type %q", f.Name, m.Name, f.Type),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Measurement returns the schema of the measurement with the name, or nil if
+// the measurement is not in the schema.
+func (s *BucketSchema) Measurement(name string) *MeasurementSchema {
+	for i := range s.Measurements {
+		if s.Measurements[i].Name == name {
+			return &s.Measurements[i]
+		}
+	}
+	return nil
+}
+
+// HasTag returns true if key is a tag key of the measurement.
+func (m *MeasurementSchema) HasTag(key string) bool {
+	for _, k := range m.Tags {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldType returns the type of the field with the name, and false if the
+// field is not in the schema of the measurement.
+func (m *MeasurementSchema) FieldType(name string) (string, bool) {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f.Type, true
+		}
+	}
+	return "", false
+}
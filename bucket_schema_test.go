@@ -0,0 +1,77 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestBucketSchema_Valid(t *testing.T) {
+	cpu := func(tags []string, fields ...platform.FieldSchema) platform.MeasurementSchema {
+		return platform.MeasurementSchema{Name: "cpu", Tags: tags, Fields: fields}
+	}
+	usage := platform.FieldSchema{Name: "usage", Type: platform.SchemaFieldTypeFloat}
+
+	tests := []struct {
+		name    string
+		schema  platform.BucketSchema
+		wantErr string
+	}{
+		{
+			name:   "valid schema",
+			schema: platform.BucketSchema{Measurements: []platform.MeasurementSchema{cpu([]string{"host"}, usage)}},
+		},
+		{
+			name:   "no measurements",
+			schema: platform.BucketSchema{},
+		},
+		{
+			name:    "unnamed measurement",
+			schema:  platform.BucketSchema{Measurements: []platform.MeasurementSchema{{Fields: []platform.FieldSchema{usage}}}},
+			wantErr: "bucket schema measurement name is empty",
+		},
+		{
+			name:    "duplicate measurement",
+			schema:  platform.BucketSchema{Measurements: []platform.MeasurementSchema{cpu(nil, usage), cpu(nil, usage)}},
+			wantErr: `bucket schema measurement "cpu" is declared more than once`,
+		},
+		{
+			name:    "no fields",
+			schema:  platform.BucketSchema{Measurements: []platform.MeasurementSchema{cpu([]string{"host"})}},
+			wantErr: `bucket schema measurement "cpu" has no fields`,
+		},
+		{
+			name:    "reserved tag key",
+			schema:  platform.BucketSchema{Measurements: []platform.MeasurementSchema{cpu([]string{"_field"}, usage)}},
+			wantErr: `bucket schema measurement "cpu" has invalid tag key "_field"`,
+		},
+		{
+			name:    "field with the name of a tag key",
+			schema:  platform.BucketSchema{Measurements: []platform.MeasurementSchema{cpu([]string{"usage"}, usage)}},
+			wantErr: `bucket schema measurement "cpu" declares "usage" more than once`,
+		},
+		{
+			name:    "unknown field type",
+			schema:  platform.BucketSchema{Measurements: []platform.MeasurementSchema{cpu(nil, platform.FieldSchema{Name: "usage", Type: "double"})}},
+			wantErr: `bucket schema field "usage" of measurement "cpu" has unknown type "double"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Valid()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || platform.ErrorMessage(err) != tt.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tt.wantErr)
+			}
+			if code := platform.ErrorCode(err); code != platform.EInvalid {
+				t.Errorf("unexpected error code: got %q, want %q", code, platform.EInvalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Backup the data in InfluxDB",
+	Long: fmt.Sprintf(
+		`Backs up data and meta data for the running InfluxDB instance.
+Downloaded files are written to the directory indicated by --path.
+The target directory, and any parent directories, are created automatically.
+Data file have extension .tsm; meta data is written to %s in the same directory.`,
+		backupKVFileName),
+	RunE: wrapCheckSetup(backupF),
+}
+
+const backupKVFileName = "influxd.bolt"
+
+var backupFlags struct {
+	Path string
+}
+
+func init() {
+	backupCmd.PersistentFlags().StringVarP(&backupFlags.Path, "path", "p", "", "directory path to write backup files to")
+	backupCmd.MarkPersistentFlagRequired("path")
+}
+
+func backupF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if flags.local {
+		return fmt.Errorf("local flag not supported for backup command")
+	}
+
+	if backupFlags.Path == "" {
+		return fmt.Errorf("must specify path")
+	}
+
+	if err := os.MkdirAll(backupFlags.Path, 0777); err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	s := &http.BackupService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	manifest := platform.BackupManifest{
+		CreatedAt: time.Now().UTC(),
+	}
+
+	size, err := backupFile(backupFlags.Path, backupKVFileName, func(w io.Writer) error {
+		return s.Backup(ctx, w)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to backup meta data: %v", err)
+	}
+	manifest.KV = platform.BackupManifestEntry{FileName: backupKVFileName, Size: size}
+
+	id, files, err := s.CreateBackup(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %v", err)
+	}
+
+	for _, f := range files {
+		size, err := backupFile(backupFlags.Path, f, func(w io.Writer) error {
+			return s.FetchBackupFile(ctx, id, f, w)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch backup file %s: %v", f, err)
+		}
+		manifest.Files = append(manifest.Files, platform.BackupManifestEntry{FileName: f, Size: size})
+	}
+
+	// The files stay on the server until the backup is finished, in case a download has to be retried.
+	if err := s.FinishBackup(ctx, id); err != nil {
+		return fmt.Errorf("failed to finish backup: %v", err)
+	}
+
+	octets, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileSync(filepath.Join(backupFlags.Path, platform.BackupManifestFileName), octets); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+
+	fmt.Printf("Backup complete: %d files written to %s\n", len(files)+1, backupFlags.Path)
+	return nil
+}
+
+// backupFile creates the file name within dir and writes to it with fn,
+// returning the number of bytes written.
+func backupFile(dir, name string, fn func(w io.Writer) error) (int64, error) {
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0666)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := fn(f); err != nil {
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), f.Close()
+}
+
+func writeFileSync(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(b); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
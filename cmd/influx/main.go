@@ -27,13 +27,17 @@ var influxCmd = &cobra.Command{
 
 func init() {
 	influxCmd.AddCommand(authorizationCmd)
+	influxCmd.AddCommand(backupCmd)
 	influxCmd.AddCommand(bucketCmd)
+	influxCmd.AddCommand(deleteCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
 	influxCmd.AddCommand(replCmd)
+	influxCmd.AddCommand(restoreCmd)
 	influxCmd.AddCommand(setupCmd)
 	influxCmd.AddCommand(taskCmd)
 	influxCmd.AddCommand(userCmd)
+	influxCmd.AddCommand(v1Cmd)
 	influxCmd.AddCommand(writeCmd)
 	influxCmd.AddCommand(pingCmd)
 }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a backup of InfluxDB",
+	Long: `Restores a backup created with "influx backup" into a fresh data directory.
+influxd must not be running against the target paths. The engine path must be
+empty; the series file and index are rebuilt from the restored data files.
+Specifying --bucket or --bucket-id restores the data of a single bucket.`,
+	RunE: wrapErrorFmt(restoreF),
+}
+
+var restoreFlags struct {
+	Input      string
+	BoltPath   string
+	EnginePath string
+	OrgID      string
+	Org        string
+	BucketID   string
+	Bucket     string
+}
+
+func init() {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to determine influx directory: %v", err))
+	}
+
+	restoreCmd.PersistentFlags().StringVarP(&restoreFlags.Input, "input", "i", "", "directory path of the backup to restore")
+	restoreCmd.MarkPersistentFlagRequired("input")
+	restoreCmd.PersistentFlags().StringVar(&restoreFlags.BoltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "path to the target boltdb database")
+	restoreCmd.PersistentFlags().StringVar(&restoreFlags.EnginePath, "engine-path", filepath.Join(dir, "engine"), "path to the target persistent engine files")
+	restoreCmd.PersistentFlags().StringVar(&restoreFlags.OrgID, "org-id", "", "The ID of the organization that owns the bucket to restore")
+	restoreCmd.PersistentFlags().StringVarP(&restoreFlags.Org, "org", "o", "", "The name of the organization that owns the bucket to restore")
+	restoreCmd.PersistentFlags().StringVar(&restoreFlags.BucketID, "bucket-id", "", "The ID of a single bucket to restore")
+	restoreCmd.PersistentFlags().StringVarP(&restoreFlags.Bucket, "bucket", "b", "", "The name of a single bucket to restore")
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if restoreFlags.Org != "" && restoreFlags.OrgID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of org or org-id")
+	}
+
+	if restoreFlags.Bucket != "" && restoreFlags.BucketID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(restoreFlags.Input, platform.BackupManifestFileName))
+	if err != nil {
+		return fmt.Errorf("failed to read backup manifest: %v", err)
+	}
+
+	var manifest platform.BackupManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return fmt.Errorf("failed to decode backup manifest: %v", err)
+	}
+
+	if _, err := os.Stat(restoreFlags.BoltPath); err == nil {
+		return fmt.Errorf("bolt database %q already exists", restoreFlags.BoltPath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	kvPath := filepath.Join(restoreFlags.Input, manifest.KV.FileName)
+
+	var filter *storage.RestoreFilter
+	if restoreFlags.Bucket != "" || restoreFlags.BucketID != "" {
+		bucket, err := findRestoreBucket(ctx, kvPath)
+		if err != nil {
+			return err
+		}
+		filter = &storage.RestoreFilter{
+			OrganizationID: bucket.OrganizationID,
+			BucketID:       bucket.ID,
+		}
+	}
+
+	files := make([]string, 0, len(manifest.Files))
+	for _, f := range manifest.Files {
+		files = append(files, f.FileName)
+	}
+
+	// The data is restored before the meta data so a failed restore does not
+	// leave a bolt database behind that fails every retry.
+	logger := zap.NewNop()
+	if err := storage.RestoreEngine(restoreFlags.EnginePath, storage.NewConfig(), restoreFlags.Input, files, filter, logger); err != nil {
+		return fmt.Errorf("failed to restore data: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(restoreFlags.BoltPath), 0700); err != nil {
+		return err
+	}
+	if err := copyRestoreFile(kvPath, restoreFlags.BoltPath); err != nil {
+		os.Remove(restoreFlags.BoltPath)
+		return fmt.Errorf("failed to restore meta data: %v", err)
+	}
+
+	fmt.Printf("Restore complete: meta data written to %s, data written to %s\n", restoreFlags.BoltPath, restoreFlags.EnginePath)
+	return nil
+}
+
+// findRestoreBucket looks up the bucket to restore in the backed up meta data.
+func findRestoreBucket(ctx context.Context, kvPath string) (*platform.Bucket, error) {
+	// Open a temporary copy so the backup itself is never modified.
+	tmp, err := ioutil.TempDir("", "influx-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, "influxd.bolt")
+	if err := copyRestoreFile(kvPath, path); err != nil {
+		return nil, err
+	}
+
+	c := bolt.NewClient()
+	c.Path = path
+	if err := c.Open(ctx); err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	filter := platform.BucketFilter{}
+	if restoreFlags.BucketID != "" {
+		filter.ID, err = platform.IDFromString(restoreFlags.BucketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+	}
+	if restoreFlags.Bucket != "" {
+		filter.Name = &restoreFlags.Bucket
+	}
+
+	if restoreFlags.OrgID != "" {
+		filter.OrganizationID, err = platform.IDFromString(restoreFlags.OrgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode org-id id: %v", err)
+		}
+	}
+	if restoreFlags.Org != "" {
+		filter.Organization = &restoreFlags.Org
+	}
+
+	bucket, err := c.FindBucket(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bucket in backup: %v", err)
+	}
+	return bucket, nil
+}
+
+// copyRestoreFile streams src to the new file dst.
+func copyRestoreFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	return out.Close()
+}
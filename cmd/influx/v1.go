@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// v1 Command
+var v1Cmd = &cobra.Command{
+	Use:   "v1",
+	Short: "InfluxDB 1.x compatibility commands",
+	Run:   v1F,
+}
+
+func v1F(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+// dbrp Command
+var v1DBRPCmd = &cobra.Command{
+	Use:   "dbrp",
+	Short: "Database and retention policy mapping management commands",
+	Run:   v1DBRPF,
+}
+
+func v1DBRPF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func init() {
+	v1Cmd.AddCommand(v1DBRPCmd)
+}
+
+func newDBRPMappingService(f Flags) (platform.DBRPMappingService, error) {
+	if flags.local {
+		return nil, fmt.Errorf("local flag not supported for v1 command")
+	}
+	return &http.DBRPMappingService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+func writeDBRPMappings(ms []*platform.DBRPMapping, deleted bool) {
+	headers := []string{
+		"Cluster",
+		"Database",
+		"RetentionPolicy",
+		"Default",
+		"OrganizationID",
+		"BucketID",
+	}
+	if deleted {
+		headers = append(headers, "Deleted")
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(headers...)
+	for _, m := range ms {
+		row := map[string]interface{}{
+			"Cluster":         m.Cluster,
+			"Database":        m.Database,
+			"RetentionPolicy": m.RetentionPolicy,
+			"Default":         m.Default,
+			"OrganizationID":  m.OrganizationID.String(),
+			"BucketID":        m.BucketID.String(),
+		}
+		if deleted {
+			row["Deleted"] = true
+		}
+		w.Write(row)
+	}
+	w.Flush()
+}
+
+// V1DBRPCreateFlags define the Create Command
+type V1DBRPCreateFlags struct {
+	cluster   string
+	db        string
+	rp        string
+	bucketID  string
+	isDefault bool
+}
+
+var v1DBRPCreateFlags V1DBRPCreateFlags
+
+func init() {
+	v1DBRPCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a database and retention policy mapping to a bucket",
+		RunE:  wrapCheckSetup(v1DBRPCreateF),
+	}
+
+	v1DBRPCreateCmd.Flags().StringVarP(&v1DBRPCreateFlags.cluster, "cluster", "", platform.DefaultDBRPCluster, "The cluster of the mapping")
+	v1DBRPCreateCmd.Flags().StringVarP(&v1DBRPCreateFlags.db, "db", "", "", "The database name (required)")
+	v1DBRPCreateCmd.Flags().StringVarP(&v1DBRPCreateFlags.rp, "rp", "", "", "The retention policy name (required)")
+	v1DBRPCreateCmd.Flags().StringVarP(&v1DBRPCreateFlags.bucketID, "bucket-id", "", "", "The ID of the bucket that is mapped to (required)")
+	v1DBRPCreateCmd.Flags().BoolVarP(&v1DBRPCreateFlags.isDefault, "default", "", false, "Use the retention policy when a database is used without a retention policy")
+	v1DBRPCreateCmd.MarkFlagRequired("db")
+	v1DBRPCreateCmd.MarkFlagRequired("rp")
+	v1DBRPCreateCmd.MarkFlagRequired("bucket-id")
+
+	v1DBRPCmd.AddCommand(v1DBRPCreateCmd)
+}
+
+func v1DBRPCreateF(cmd *cobra.Command, args []string) error {
+	s, err := newDBRPMappingService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize dbrp mapping service client: %v", err)
+	}
+
+	bucketID, err := platform.IDFromString(v1DBRPCreateFlags.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", v1DBRPCreateFlags.bucketID, err)
+	}
+
+	m := &platform.DBRPMapping{
+		Cluster:         v1DBRPCreateFlags.cluster,
+		Database:        v1DBRPCreateFlags.db,
+		RetentionPolicy: v1DBRPCreateFlags.rp,
+		Default:         v1DBRPCreateFlags.isDefault,
+		BucketID:        *bucketID,
+	}
+	if err := s.Create(context.Background(), m); err != nil {
+		return fmt.Errorf("failed to create dbrp mapping: %v", err)
+	}
+
+	writeDBRPMappings([]*platform.DBRPMapping{m}, false)
+	return nil
+}
+
+// V1DBRPFindFlags define the Find Command
+type V1DBRPFindFlags struct {
+	cluster   string
+	db        string
+	rp        string
+	orgID     string
+	isDefault bool
+}
+
+var v1DBRPFindFlags V1DBRPFindFlags
+
+func init() {
+	v1DBRPFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find database and retention policy mappings",
+		RunE:  wrapCheckSetup(v1DBRPFindF),
+	}
+
+	v1DBRPFindCmd.Flags().StringVarP(&v1DBRPFindFlags.cluster, "cluster", "", "", "The cluster of the mappings")
+	v1DBRPFindCmd.Flags().StringVarP(&v1DBRPFindFlags.db, "db", "", "", "The database name")
+	v1DBRPFindCmd.Flags().StringVarP(&v1DBRPFindFlags.rp, "rp", "", "", "The retention policy name")
+	v1DBRPFindCmd.Flags().StringVarP(&v1DBRPFindFlags.orgID, "org-id", "", "", "The ID of the organization of the mappings")
+	v1DBRPFindCmd.Flags().BoolVarP(&v1DBRPFindFlags.isDefault, "default", "", false, "Only find default mappings")
+
+	v1DBRPCmd.AddCommand(v1DBRPFindCmd)
+}
+
+func v1DBRPFindF(cmd *cobra.Command, args []string) error {
+	s, err := newDBRPMappingService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize dbrp mapping service client: %v", err)
+	}
+
+	filter := platform.DBRPMappingFilter{}
+	if v1DBRPFindFlags.cluster != "" {
+		filter.Cluster = &v1DBRPFindFlags.cluster
+	}
+	if v1DBRPFindFlags.db != "" {
+		filter.Database = &v1DBRPFindFlags.db
+	}
+	if v1DBRPFindFlags.rp != "" {
+		filter.RetentionPolicy = &v1DBRPFindFlags.rp
+	}
+	if v1DBRPFindFlags.isDefault {
+		filter.Default = &v1DBRPFindFlags.isDefault
+	}
+	if v1DBRPFindFlags.orgID != "" {
+		orgID, err := platform.IDFromString(v1DBRPFindFlags.orgID)
+		if err != nil {
+			return fmt.Errorf("failed to decode org id %q: %v", v1DBRPFindFlags.orgID, err)
+		}
+		filter.OrganizationID = orgID
+	}
+
+	ms, _, err := s.FindMany(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve dbrp mappings: %v", err)
+	}
+
+	writeDBRPMappings(ms, false)
+	return nil
+}
+
+// V1DBRPDeleteFlags define the Delete Command
+type V1DBRPDeleteFlags struct {
+	cluster string
+	db      string
+	rp      string
+	orgID   string
+}
+
+var v1DBRPDeleteFlags V1DBRPDeleteFlags
+
+func init() {
+	v1DBRPDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a database and retention policy mapping",
+		RunE:  wrapCheckSetup(v1DBRPDeleteF),
+	}
+
+	v1DBRPDeleteCmd.Flags().StringVarP(&v1DBRPDeleteFlags.cluster, "cluster", "", platform.DefaultDBRPCluster, "The cluster of the mapping")
+	v1DBRPDeleteCmd.Flags().StringVarP(&v1DBRPDeleteFlags.db, "db", "", "", "The database name (required)")
+	v1DBRPDeleteCmd.Flags().StringVarP(&v1DBRPDeleteFlags.rp, "rp", "", "", "The retention policy name (required)")
+	v1DBRPDeleteCmd.Flags().StringVarP(&v1DBRPDeleteFlags.orgID, "org-id", "", "", "The ID of the organization of the mapping (required)")
+	v1DBRPDeleteCmd.MarkFlagRequired("db")
+	v1DBRPDeleteCmd.MarkFlagRequired("rp")
+	v1DBRPDeleteCmd.MarkFlagRequired("org-id")
+
+	v1DBRPCmd.AddCommand(v1DBRPDeleteCmd)
+}
+
+func v1DBRPDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newDBRPMappingService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize dbrp mapping service client: %v", err)
+	}
+
+	orgID, err := platform.IDFromString(v1DBRPDeleteFlags.orgID)
+	if err != nil {
+		return fmt.Errorf("failed to decode org id %q: %v", v1DBRPDeleteFlags.orgID, err)
+	}
+
+	ctx := context.Background()
+	m, err := s.FindBy(ctx, *orgID, v1DBRPDeleteFlags.cluster, v1DBRPDeleteFlags.db, v1DBRPDeleteFlags.rp)
+	if err != nil {
+		return fmt.Errorf("failed to find dbrp mapping: %v", err)
+	}
+
+	if err := s.Delete(ctx, m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy); err != nil {
+		return fmt.Errorf("failed to delete dbrp mapping: %v", err)
+	}
+
+	writeDBRPMappings([]*platform.DBRPMapping{m}, true)
+	return nil
+}
@@ -0,0 +1,223 @@
+package launcher
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/task/backend/coordinator"
+	itoml "github.com/influxdata/influxdb/toml"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the
+// configuration. The name of a variable is the path of the setting in the
+// configuration file in upper case with hyphens replaced by underscores.
+// For example INFLUXD_STORAGE_ENGINE_CACHE_MAX_MEMORY_SIZE overrides the
+// max-memory-size of the [storage.engine.cache] section.
+const EnvPrefix = "INFLUXD"
+
+const (
+	// DefaultHTTPMaxBodySize is the default maximum size of the line protocol of a write.
+	DefaultHTTPMaxBodySize = 25 << 20
+
+	// DefaultQueryConcurrencyQuota is the default number of queries that are executed concurrently.
+	DefaultQueryConcurrencyQuota = 10
+	// DefaultQueryMemoryBytesQuota is the default number of bytes a query can allocate.
+	DefaultQueryMemoryBytesQuota = 1e6
+
+	// DefaultTaskSchedulerTickInterval is the default interval at which the task
+	// scheduler checks for a new second.
+	DefaultTaskSchedulerTickInterval = 100 * time.Millisecond
+	// DefaultTaskRetryBackoff is the default delay before the second try of a failed task run.
+	DefaultTaskRetryBackoff = time.Second
+	// DefaultTaskSystemBucketRetention is the default retention period of the
+	// bucket that the runs and logs of the tasks of an organization are written to.
+	DefaultTaskSystemBucketRetention = 7 * 24 * time.Hour
+	// DefaultTaskLeaseTTL is the default time after which the tasks of an
+	// influxd that stopped renewing its leases are taken over by its peers.
+	DefaultTaskLeaseTTL = coordinator.DefaultLeaseTTL
+)
+
+// Config is the configuration of influxd.
+type Config struct {
+	LogLevel          string `toml:"log-level"`
+	ReportingDisabled bool   `toml:"reporting-disabled"`
+
+	// StoreType is the backing store of the REST resources, bolt or memory.
+	StoreType string `toml:"store"`
+	// SecretStore is the data store of secrets, bolt or vault.
+	SecretStore string `toml:"secret-store"`
+
+	BoltPath   string `toml:"bolt-path"`
+	EnginePath string `toml:"engine-path"`
+	ProtosPath string `toml:"protos-path"`
+	AssetsPath string `toml:"assets-path"`
+
+	// Testing adds the /debug/flush endpoint to clear the stores.
+	Testing bool `toml:"e2e-testing"`
+
+	HTTP    HTTPConfig     `toml:"http"`
+	Storage storage.Config `toml:"storage"`
+	Query   QueryConfig    `toml:"query"`
+	Tasks   TasksConfig    `toml:"tasks"`
+}
+
+// HTTPConfig is the configuration of the HTTP server.
+type HTTPConfig struct {
+	BindAddress string `toml:"bind-address"`
+
+	// TLSCert and TLSKey are the paths to the certificate and key of the server.
+	// The server serves HTTPS if they are set.
+	TLSCert string `toml:"tls-cert"`
+	TLSKey  string `toml:"tls-key"`
+	// TLSMinVersion is the minimum TLS version, one of 1.0, 1.1, 1.2 or 1.3.
+	TLSMinVersion string `toml:"tls-min-version"`
+	// TLSCiphers are the allowed cipher suites of TLS 1.2 and below.
+	TLSCiphers []string `toml:"tls-ciphers"`
+	// TLSClientCA is the path to the authorities of client certificates. A client
+	// certificate authenticates as the authorization whose ID is its common name.
+	TLSClientCA string `toml:"tls-client-ca"`
+
+	// The timeouts of a request. Zero means no timeout.
+	ReadTimeout       itoml.Duration `toml:"read-timeout"`
+	ReadHeaderTimeout itoml.Duration `toml:"read-header-timeout"`
+	WriteTimeout      itoml.Duration `toml:"write-timeout"`
+	IdleTimeout       itoml.Duration `toml:"idle-timeout"`
+	// MaxHeaderSize is the maximum size of the request headers.
+	MaxHeaderSize itoml.Size `toml:"max-header-size"`
+	// MaxBodySize is the maximum size of the line protocol of a write. Zero means no limit.
+	MaxBodySize itoml.Size `toml:"max-body-size"`
+}
+
+// TLSEnabled returns true if the server serves HTTPS.
+func (c HTTPConfig) TLSEnabled() bool {
+	return c.TLSCert != ""
+}
+
+// TLSConfig returns the TLS configuration of the server.
+func (c HTTPConfig) TLSConfig() (*tls.Config, error) {
+	return http.NewTLSConfig(http.TLSOptions{
+		CertFile:     c.TLSCert,
+		KeyFile:      c.TLSKey,
+		MinVersion:   c.TLSMinVersion,
+		CipherSuites: c.TLSCiphers,
+		ClientCAFile: c.TLSClientCA,
+	})
+}
+
+// QueryConfig is the configuration of the query controller.
+type QueryConfig struct {
+	ConcurrencyQuota int        `toml:"concurrency-quota"`
+	MemoryBytesQuota itoml.Size `toml:"memory-bytes-quota"`
+}
+
+// TasksConfig is the configuration of the task scheduler.
+type TasksConfig struct {
+	SchedulerTickInterval itoml.Duration `toml:"scheduler-tick-interval"`
+	RetryBackoff          itoml.Duration `toml:"retry-backoff"`
+	// SystemBucketRetention is the retention period of the task system
+	// buckets that are created. Zero is infinite retention.
+	SystemBucketRetention itoml.Duration `toml:"system-bucket-retention"`
+	// LeaseTTL is how long the lease of a task lasts without being renewed,
+	// when several influxd processes share the task store.
+	LeaseTTL itoml.Duration `toml:"lease-ttl"`
+}
+
+// NewConfig returns the default configuration with the files of influxd in dir.
+func NewConfig(dir string) *Config {
+	return &Config{
+		LogLevel:    "info",
+		StoreType:   BoltStore,
+		SecretStore: "bolt",
+		BoltPath:    filepath.Join(dir, "influxd.bolt"),
+		EnginePath:  filepath.Join(dir, "engine"),
+		ProtosPath:  filepath.Join(dir, "protos"),
+		HTTP: HTTPConfig{
+			BindAddress:   ":9999",
+			TLSMinVersion: http.DefaultTLSMinVersion,
+			MaxHeaderSize: itoml.Size(nethttp.DefaultMaxHeaderBytes),
+			MaxBodySize:   itoml.Size(DefaultHTTPMaxBodySize),
+		},
+		Storage: storage.NewConfig(),
+		Query: QueryConfig{
+			ConcurrencyQuota: DefaultQueryConcurrencyQuota,
+			MemoryBytesQuota: itoml.Size(DefaultQueryMemoryBytesQuota),
+		},
+		Tasks: TasksConfig{
+			SchedulerTickInterval: itoml.Duration(DefaultTaskSchedulerTickInterval),
+			RetryBackoff:          itoml.Duration(DefaultTaskRetryBackoff),
+			SystemBucketRetention: itoml.Duration(DefaultTaskSystemBucketRetention),
+			LeaseTTL:              itoml.Duration(DefaultTaskLeaseTTL),
+		},
+	}
+}
+
+// FromTomlFile loads the configuration from the TOML file at path. Settings
+// that are not in the file keep their current value. Unknown settings are an
+// error so that a misspelled setting is not silently ignored.
+func (c *Config) FromTomlFile(path string) error {
+	md, err := toml.DecodeFile(path, c)
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, k := range undecoded {
+			keys = append(keys, k.String())
+		}
+		return fmt.Errorf("unknown settings in config file %s: %s", path, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// ApplyEnvOverrides applies the INFLUXD_ environment variables to the configuration.
+func (c *Config) ApplyEnvOverrides(getenv func(string) string) error {
+	return itoml.ApplyEnvOverrides(getenv, EnvPrefix, c)
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c *Config) Validate() error {
+	if (c.HTTP.TLSCert == "") != (c.HTTP.TLSKey == "") {
+		return fmt.Errorf("http tls-cert and tls-key must be set together")
+	}
+	if c.HTTP.TLSClientCA != "" && !c.HTTP.TLSEnabled() {
+		return fmt.Errorf("http tls-client-ca requires tls-cert and tls-key")
+	}
+	if _, err := http.ParseTLSVersion(c.HTTP.TLSMinVersion); err != nil {
+		return fmt.Errorf("http tls-min-version: %v", err)
+	}
+	if _, err := http.ParseCipherSuites(c.HTTP.TLSCiphers); err != nil {
+		return fmt.Errorf("http tls-ciphers: %v", err)
+	}
+	if c.HTTP.MaxHeaderSize == 0 {
+		return fmt.Errorf("http max-header-size must be positive")
+	}
+	if c.Query.ConcurrencyQuota <= 0 {
+		return fmt.Errorf("query concurrency-quota must be positive")
+	}
+	if c.Query.MemoryBytesQuota == 0 {
+		return fmt.Errorf("query memory-bytes-quota must be positive")
+	}
+	if c.Tasks.SchedulerTickInterval <= 0 {
+		return fmt.Errorf("tasks scheduler-tick-interval must be positive")
+	}
+	if c.Tasks.SystemBucketRetention < 0 {
+		return fmt.Errorf("tasks system-bucket-retention must not be negative")
+	}
+	if c.Tasks.LeaseTTL < itoml.Duration(time.Second) {
+		return fmt.Errorf("tasks lease-ttl must be at least 1s")
+	}
+	return nil
+}
+
+// Write writes the configuration as TOML to w.
+func (c *Config) Write(w io.Writer) error {
+	return toml.NewEncoder(w).Encode(c)
+}
@@ -0,0 +1,282 @@
+package launcher_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	itoml "github.com/influxdata/influxdb/toml"
+)
+
+func writeConfigFile(t *testing.T, dir, s string) string {
+	t.Helper()
+	path := filepath.Join(dir, "influxd.toml")
+	if err := ioutil.WriteFile(path, []byte(s), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfig_FromTomlFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, `
+log-level = "debug"
+bolt-path = "/var/lib/influxdb/influxd.bolt"
+
+[http]
+  bind-address = ":8086"
+
+[storage]
+  retention-interval = "30m"
+
+[storage.engine.cache]
+  max-memory-size = "512m"
+
+[storage.wal]
+  fsync-delay = "100ms"
+
+[query]
+  concurrency-quota = 20
+
+[tasks]
+  scheduler-tick-interval = "250ms"
+`)
+
+	c := launcher.NewConfig(dir)
+	if err := c.FromTomlFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := c.LogLevel, "debug"; got != want {
+		t.Errorf("unexpected log-level: got %q, want %q", got, want)
+	}
+	if got, want := c.BoltPath, "/var/lib/influxdb/influxd.bolt"; got != want {
+		t.Errorf("unexpected bolt-path: got %q, want %q", got, want)
+	}
+	if got, want := c.EnginePath, filepath.Join(dir, "engine"); got != want {
+		t.Errorf("unexpected engine-path: got %q, want %q", got, want)
+	}
+	if got, want := c.HTTP.BindAddress, ":8086"; got != want {
+		t.Errorf("unexpected bind-address: got %q, want %q", got, want)
+	}
+	if got, want := time.Duration(c.Storage.RetentionInterval), 30*time.Minute; got != want {
+		t.Errorf("unexpected retention-interval: got %v, want %v", got, want)
+	}
+	if got, want := c.Storage.Engine.Cache.MaxMemorySize, itoml.Size(512<<20); got != want {
+		t.Errorf("unexpected max-memory-size: got %v, want %v", got, want)
+	}
+	if got, want := time.Duration(c.Storage.WAL.FsyncDelay), 100*time.Millisecond; got != want {
+		t.Errorf("unexpected fsync-delay: got %v, want %v", got, want)
+	}
+	if got, want := c.Query.ConcurrencyQuota, 20; got != want {
+		t.Errorf("unexpected concurrency-quota: got %d, want %d", got, want)
+	}
+	if got, want := time.Duration(c.Tasks.SchedulerTickInterval), 250*time.Millisecond; got != want {
+		t.Errorf("unexpected scheduler-tick-interval: got %v, want %v", got, want)
+	}
+}
+
+func TestConfig_FromTomlFile_UnknownSetting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, `
+[storage.engine.cache]
+  max-memory = "512m"
+`)
+
+	err = launcher.NewConfig(dir).FromTomlFile(path)
+	if err == nil || !strings.Contains(err.Error(), "storage.engine.cache.max-memory") {
+		t.Fatalf("expected unknown setting error, got %v", err)
+	}
+}
+
+func TestConfig_ApplyEnvOverrides(t *testing.T) {
+	env := map[string]string{
+		"INFLUXD_HTTP_BIND_ADDRESS":                                 ":8086",
+		"INFLUXD_STORAGE_ENGINE_CACHE_MAX_MEMORY_SIZE":              "1g",
+		"INFLUXD_STORAGE_ENGINE_COMPACTION_MAX_CONCURRENT":          "4",
+		"INFLUXD_STORAGE_INDEX_SERIES_ID_SET_CACHE_SIZE":            "50",
+		"INFLUXD_QUERY_MEMORY_BYTES_QUOTA":                          "10m",
+		"INFLUXD_TASKS_RETRY_BACKOFF":                               "5s",
+		"INFLUXD_TASKS_SYSTEM_BUCKET_RETENTION":                     "72h",
+		"INFLUXD_STORAGE_ENGINE_CACHE_SNAPSHOT_WRITE_COLD_DURATION": "",
+	}
+
+	c := launcher.NewConfig("")
+	if err := c.ApplyEnvOverrides(func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := c.HTTP.BindAddress, ":8086"; got != want {
+		t.Errorf("unexpected bind-address: got %q, want %q", got, want)
+	}
+	if got, want := c.Storage.Engine.Cache.MaxMemorySize, itoml.Size(1<<30); got != want {
+		t.Errorf("unexpected max-memory-size: got %v, want %v", got, want)
+	}
+	if got, want := c.Storage.Engine.Compaction.MaxConcurrent, 4; got != want {
+		t.Errorf("unexpected max-concurrent: got %d, want %d", got, want)
+	}
+	if got, want := c.Storage.Index.SeriesIDSetCacheSize, uint64(50); got != want {
+		t.Errorf("unexpected series-id-set-cache-size: got %d, want %d", got, want)
+	}
+	if got, want := c.Query.MemoryBytesQuota, itoml.Size(10<<20); got != want {
+		t.Errorf("unexpected memory-bytes-quota: got %v, want %v", got, want)
+	}
+	if got, want := time.Duration(c.Tasks.RetryBackoff), 5*time.Second; got != want {
+		t.Errorf("unexpected retry-backoff: got %v, want %v", got, want)
+	}
+	if got, want := time.Duration(c.Tasks.SystemBucketRetention), 72*time.Hour; got != want {
+		t.Errorf("unexpected system-bucket-retention: got %v, want %v", got, want)
+	}
+	if got, want := c.Storage.Engine.Cache.SnapshotWriteColdDuration, launcher.NewConfig("").Storage.Engine.Cache.SnapshotWriteColdDuration; got != want {
+		t.Errorf("unexpected snapshot-write-cold-duration: got %v, want %v", got, want)
+	}
+}
+
+func TestLauncher_PrintConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, `
+log-level = "error"
+
+[http]
+  bind-address = ":8086"
+
+[query]
+  concurrency-quota = 20
+`)
+
+	var buf bytes.Buffer
+	l := launcher.NewLauncher()
+	l.Stdout = &buf
+	if err := l.Run(ctx, "print-config", "--config", path, "--http-bind-address", ":9000"); err != nil {
+		t.Fatal(err)
+	}
+	if l.Running() {
+		t.Fatal("print-config must not start influxd")
+	}
+
+	// The printed configuration is a valid config file.
+	path = writeConfigFile(t, dir, buf.String())
+	c := launcher.NewConfig("")
+	if err := c.FromTomlFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := c.LogLevel, "error"; got != want {
+		t.Errorf("unexpected log-level: got %q, want %q", got, want)
+	}
+	if got, want := c.HTTP.BindAddress, ":9000"; got != want {
+		t.Errorf("flag must take precedence over the config file: got %q, want %q", got, want)
+	}
+	if got, want := c.Query.ConcurrencyQuota, 20; got != want {
+		t.Errorf("unexpected concurrency-quota: got %d, want %d", got, want)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(c *launcher.Config)
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			config: func(c *launcher.Config) {},
+		},
+		{
+			name: "tls",
+			config: func(c *launcher.Config) {
+				c.HTTP.TLSCert = "influxd.crt"
+				c.HTTP.TLSKey = "influxd.key"
+				c.HTTP.TLSMinVersion = "1.3"
+				c.HTTP.TLSClientCA = "ca.crt"
+			},
+		},
+		{
+			name: "tls cert without key",
+			config: func(c *launcher.Config) {
+				c.HTTP.TLSCert = "influxd.crt"
+			},
+			wantErr: "http tls-cert and tls-key must be set together",
+		},
+		{
+			name: "tls client ca without cert",
+			config: func(c *launcher.Config) {
+				c.HTTP.TLSClientCA = "ca.crt"
+			},
+			wantErr: "http tls-client-ca requires tls-cert and tls-key",
+		},
+		{
+			name: "unknown tls version",
+			config: func(c *launcher.Config) {
+				c.HTTP.TLSMinVersion = "1.4"
+			},
+			wantErr: "http tls-min-version",
+		},
+		{
+			name: "unknown tls cipher",
+			config: func(c *launcher.Config) {
+				c.HTTP.TLSCiphers = []string{"TLS_RSA_WITH_NOTHING"}
+			},
+			wantErr: "http tls-ciphers",
+		},
+		{
+			name: "no query concurrency",
+			config: func(c *launcher.Config) {
+				c.Query.ConcurrencyQuota = 0
+			},
+			wantErr: "query concurrency-quota must be positive",
+		},
+		{
+			name: "negative task system bucket retention",
+			config: func(c *launcher.Config) {
+				c.Tasks.SystemBucketRetention = -1
+			},
+			wantErr: "tasks system-bucket-retention must not be negative",
+		},
+		{
+			name: "task lease ttl under a second",
+			config: func(c *launcher.Config) {
+				c.Tasks.LeaseTTL = itoml.Duration(500 * time.Millisecond)
+			},
+			wantErr: "tasks lease-ttl must be at least 1s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := launcher.NewConfig("")
+			tt.config(c)
+
+			err := c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
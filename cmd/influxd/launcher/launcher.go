@@ -8,7 +8,6 @@ import (
 	nethttp "net/http"
 	_ "net/http/pprof" // needed to add pprof to our binary.
 	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -31,6 +30,7 @@ import (
 	"github.com/influxdata/influxdb/proto"
 	"github.com/influxdata/influxdb/query"
 	pcontrol "github.com/influxdata/influxdb/query/control"
+	"github.com/influxdata/influxdb/quota"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
@@ -42,10 +42,13 @@ import (
 	taskexecutor "github.com/influxdata/influxdb/task/backend/executor"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
 	_ "github.com/influxdata/influxdb/tsdb/tsm1" // needed for tsm1
+	"github.com/influxdata/influxdb/usage"
 	"github.com/influxdata/influxdb/vault"
 	pzap "github.com/influxdata/influxdb/zap"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -63,23 +66,15 @@ type Launcher struct {
 	cancel  func()
 	running bool
 
-	storeType  string
-	assetsPath string
-	testing    bool
-
-	logLevel          string
-	reportingDisabled bool
-
-	httpBindAddress string
-	boltPath        string
-	enginePath      string
-	protosPath      string
-	secretStore     string
+	config     *Config
+	configPath string
 
 	boltClient *bolt.Client
 	kvService  *kv.Service
 	engine     *storage.Engine
 
+	usageRecorder *usage.Recorder
+
 	queryController *pcontrol.Controller
 
 	httpPort   int
@@ -87,8 +82,9 @@ type Launcher struct {
 
 	natsServer *nats.Server
 
-	scheduler *taskbackend.TickScheduler
-	taskStore taskbackend.Store
+	scheduler       *taskbackend.TickScheduler
+	taskCoordinator *coordinator.Coordinator
+	taskStore       taskbackend.Store
 
 	logger *zap.Logger
 	reg    *prom.Registry
@@ -118,7 +114,7 @@ func (m *Launcher) Running() bool {
 
 // ReportingDisabled is true if opted out of usage stats.
 func (m *Launcher) ReportingDisabled() bool {
-	return m.reportingDisabled
+	return m.config.ReportingDisabled
 }
 
 // Registry returns the prometheus metrics registry.
@@ -140,6 +136,9 @@ func (m *Launcher) SetBuild(version, commit, date string) {
 
 // URL returns the URL to connect to the HTTP server.
 func (m *Launcher) URL() string {
+	if m.config.HTTP.TLSEnabled() {
+		return fmt.Sprintf("https://127.0.0.1:%d", m.httpPort)
+	}
 	return fmt.Sprintf("http://127.0.0.1:%d", m.httpPort)
 }
 
@@ -154,6 +153,7 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.httpServer.Shutdown(ctx)
 
 	m.logger.Info("Stopping", zap.String("service", "task"))
+	m.taskCoordinator.Stop()
 	m.scheduler.Stop()
 
 	m.logger.Info("Stopping", zap.String("service", "nats"))
@@ -169,6 +169,11 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.logger.Info("Failed closing query service", zap.Error(err))
 	}
 
+	m.logger.Info("Stopping", zap.String("service", "usage-recorder"))
+	if err := m.usageRecorder.Close(); err != nil {
+		m.logger.Error("failed to close usage recorder", zap.Error(err))
+	}
+
 	m.logger.Info("Stopping", zap.String("service", "storage-engine"))
 	if err := m.engine.Close(); err != nil {
 		m.logger.Error("failed to close engine", zap.Error(err))
@@ -183,71 +188,97 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 func (m *Launcher) Cancel() { m.cancel() }
 
 // Run executes the program with the given CLI arguments.
+//
+// The configuration starts with the defaults and is then overridden, in
+// order, by the --config file, the INFLUXD_ environment variables and the
+// command line flags.
 func (m *Launcher) Run(ctx context.Context, args ...string) error {
 	dir, err := fs.InfluxDir()
 	if err != nil {
 		return fmt.Errorf("failed to determine influx directory: %v", err)
 	}
+	m.config = NewConfig(dir)
 
+	var cmd *cobra.Command
 	prog := &cli.Program{
 		Name: "influxd",
-		Run:  func() error { return m.run(ctx) },
+		Run: func() error {
+			if err := m.loadConfig(cmd.Flags()); err != nil {
+				return err
+			}
+			return m.run(ctx)
+		},
 		Opts: []cli.Opt{
 			{
-				DestP:   &m.logLevel,
+				DestP: &m.configPath,
+				Flag:  "config",
+				Desc:  "path to a TOML configuration file",
+			},
+			{
+				DestP:   &m.config.LogLevel,
 				Flag:    "log-level",
-				Default: "info",
+				Default: m.config.LogLevel,
 				Desc:    "supported log levels are debug, info, and error",
 			},
 			{
-				DestP:   &m.httpBindAddress,
+				DestP:   &m.config.HTTP.BindAddress,
 				Flag:    "http-bind-address",
-				Default: ":9999",
+				Default: m.config.HTTP.BindAddress,
 				Desc:    "bind address for the REST HTTP API",
 			},
 			{
-				DestP:   &m.boltPath,
+				DestP: &m.config.HTTP.TLSCert,
+				Flag:  "tls-cert",
+				Desc:  "TLS certificate for HTTPS",
+			},
+			{
+				DestP: &m.config.HTTP.TLSKey,
+				Flag:  "tls-key",
+				Desc:  "TLS key for HTTPS",
+			},
+			{
+				DestP:   &m.config.BoltPath,
 				Flag:    "bolt-path",
-				Default: filepath.Join(dir, "influxd.bolt"),
+				Default: m.config.BoltPath,
 				Desc:    "path to boltdb database",
 			},
 			{
-				DestP: &m.assetsPath,
+				DestP: &m.config.AssetsPath,
 				Flag:  "assets-path",
 				Desc:  "override default assets by serving from a specific directory (developer mode)",
 			},
 			{
-				DestP:   &m.storeType,
+				DestP:   &m.config.StoreType,
 				Flag:    "store",
-				Default: "bolt",
+				Default: m.config.StoreType,
 				Desc:    "backing store for REST resources (bolt or memory)",
 			},
 			{
-				DestP:   &m.testing,
+				DestP:   &m.config.Testing,
 				Flag:    "e2e-testing",
 				Default: false,
 				Desc:    "add /debug/flush endpoint to clear stores; used for end-to-end tests",
 			},
 			{
-				DestP:   &m.enginePath,
+				DestP:   &m.config.EnginePath,
 				Flag:    "engine-path",
-				Default: filepath.Join(dir, "engine"),
+				Default: m.config.EnginePath,
 				Desc:    "path to persistent engine files",
 			},
 			{
-				DestP:   &m.secretStore,
+				DestP:   &m.config.SecretStore,
 				Flag:    "secret-store",
-				Default: "bolt",
+				Default: m.config.SecretStore,
 				Desc:    "data store for secrets (bolt or vault)",
 			},
 			{
-				DestP:   &m.protosPath,
+				DestP:   &m.config.ProtosPath,
 				Flag:    "protos-path",
-				Default: filepath.Join(dir, "protos"),
+				Default: m.config.ProtosPath,
 				Desc:    "path to protos on the filesystem",
 			},
 			{
-				DestP:   &m.reportingDisabled,
+				DestP:   &m.config.ReportingDisabled,
 				Flag:    "reporting-disabled",
 				Default: false,
 				Desc:    "disable sending telemetry data to https://telemetry.influxdata.com every 8 hours",
@@ -255,17 +286,57 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 		},
 	}
 
-	cmd := cli.NewCommand(prog)
+	cmd = cli.NewCommand(prog)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "print-config",
+		Short: "Print the effective configuration",
+		Long: `Print the effective configuration as TOML.
+
+The output applies the --config file, the INFLUXD_ environment variables
+and the command line flags to the defaults, and may be used as a config file.`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			if err := m.loadConfig(c.Flags()); err != nil {
+				return err
+			}
+			return m.config.Write(m.Stdout)
+		},
+	})
 	cmd.SetArgs(args)
 	return cmd.Execute()
 }
 
+// loadConfig applies the config file and the environment to the configuration.
+// The flags were already parsed into the configuration, so the flags that were
+// set on the command line are applied again to take precedence over both.
+func (m *Launcher) loadConfig(flags *pflag.FlagSet) error {
+	changed := make(map[string]string)
+	flags.Visit(func(f *pflag.Flag) {
+		changed[f.Name] = f.Value.String()
+	})
+
+	if m.configPath != "" {
+		if err := m.config.FromTomlFile(m.configPath); err != nil {
+			return err
+		}
+	}
+	if err := m.config.ApplyEnvOverrides(os.Getenv); err != nil {
+		return err
+	}
+	for name, value := range changed {
+		if err := flags.Set(name, value); err != nil {
+			return err
+		}
+	}
+	return m.config.Validate()
+}
+
 func (m *Launcher) run(ctx context.Context) (err error) {
 	m.running = true
 	ctx, m.cancel = context.WithCancel(ctx)
 
 	var lvl zapcore.Level
-	if err := lvl.Set(m.logLevel); err != nil {
+	if err := lvl.Set(m.config.LogLevel); err != nil {
 		return fmt.Errorf("unknown log level; supported levels are debug, info, and error")
 	}
 
@@ -292,7 +363,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	opentracing.SetGlobalTracer(tracer)
 
 	m.boltClient = bolt.NewClient()
-	m.boltClient.Path = m.boltPath
+	m.boltClient.Path = m.config.BoltPath
 	m.boltClient.WithLogger(m.logger.With(zap.String("service", "bolt")))
 
 	if err := m.boltClient.Open(ctx); err != nil {
@@ -301,22 +372,22 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	var flusher http.Flusher
-	switch m.storeType {
+	switch m.config.StoreType {
 	case BoltStore:
-		store := bolt.NewKVStore(m.boltPath)
+		store := bolt.NewKVStore(m.config.BoltPath)
 		store.WithDB(m.boltClient.DB())
 		m.kvService = kv.NewService(store)
-		if m.testing {
+		if m.config.Testing {
 			flusher = store
 		}
 	case MemoryStore:
 		store := inmem.NewKVStore()
 		m.kvService = kv.NewService(store)
-		if m.testing {
+		if m.config.Testing {
 			flusher = store
 		}
 	default:
-		err := fmt.Errorf("unknown store type %s; expected bolt or memory", m.storeType)
+		err := fmt.Errorf("unknown store type %s; expected bolt or memory", m.config.StoreType)
 		m.logger.Error("failed opening bolt", zap.Error(err))
 		return err
 	}
@@ -356,9 +427,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		labelSvc         platform.LabelService                    = m.kvService
 		secretSvc        platform.SecretService                   = m.kvService
 		lookupSvc        platform.LookupService                   = m.kvService
+		dbrpSvc          platform.DBRPMappingService              = m.kvService
 	)
 
-	switch m.secretStore {
+	switch m.config.SecretStore {
 	case "bolt":
 		// If it is bolt, then we already set it above.
 	case "vault":
@@ -371,13 +443,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		}
 		secretSvc = svc
 	default:
-		err := fmt.Errorf("unknown secret service %q, expected \"bolt\" or \"vault\"", m.secretStore)
+		err := fmt.Errorf("unknown secret service %q, expected \"bolt\" or \"vault\"", m.config.SecretStore)
 		m.logger.Error("failed setting secret service", zap.Error(err))
 		return err
 	}
 
 	// Load proto examples from the user data.
-	protoSvc := protofs.NewProtoService(m.protosPath, m.logger, dashboardSvc)
+	protoSvc := protofs.NewProtoService(m.config.ProtosPath, m.logger, dashboardSvc)
 	if err := protoSvc.Open(ctx); err != nil {
 		m.logger.Error("failed to read protos from the filesystem", zap.Error(err))
 		return err
@@ -398,11 +470,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	quotaEnforcer := quota.NewEnforcer(orgSvc)
+
 	var pointsWriter storage.PointsWriter
 	{
-		m.engine = storage.NewEngine(m.enginePath, storage.NewConfig(), storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.config.EnginePath, m.config.Storage, storage.WithRetentionEnforcer(bucketSvc))
 		m.engine.WithLogger(m.logger)
 
+		m.usageRecorder = usage.NewRecorder(m.engine)
+		m.usageRecorder.WithLogger(m.logger)
+		m.engine.WithUsageRecorder(m.usageRecorder)
+
 		if err := m.engine.Open(); err != nil {
 			m.logger.Error("failed to open engine", zap.Error(err))
 			return err
@@ -410,17 +488,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		// The Engine's metrics must be registered after it opens.
 		m.reg.MustRegister(m.engine.PrometheusCollectors()...)
 
-		pointsWriter = m.engine
+		if err := m.usageRecorder.Open(); err != nil {
+			m.logger.Error("failed to open usage recorder", zap.Error(err))
+			return err
+		}
 
-		const (
-			concurrencyQuota = 10
-			memoryBytesQuota = 1e6
-		)
+		pointsWriter = m.engine
 
 		cc := control.Config{
 			ExecutorDependencies: make(execute.Dependencies),
-			ConcurrencyQuota:     concurrencyQuota,
-			MemoryBytesQuota:     int64(memoryBytesQuota),
+			ConcurrencyQuota:     m.config.Query.ConcurrencyQuota,
+			MemoryBytesQuota:     int64(m.config.Query.MemoryBytesQuota),
 			Logger:               m.logger.With(zap.String("service", "storage-reads")),
 		}
 
@@ -432,6 +510,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		}
 
 		m.queryController = pcontrol.New(cc)
+		m.queryController.WithUsageRecorder(m.usageRecorder)
+		m.queryController.WithQuotaEnforcer(quotaEnforcer)
 		m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 	}
 
@@ -448,21 +528,24 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			return err
 		}
 
-		if m.storeType == "memory" {
+		if m.config.StoreType == MemoryStore {
 			store = taskbackend.NewInMemStore()
 		}
 
 		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, authSvc, store)
 
-		lw := taskbackend.NewPointLogWriter(pointsWriter)
-		m.scheduler = taskbackend.NewScheduler(store, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
+		systemBuckets := taskbackend.NewSystemBucketService(bucketSvc, time.Duration(m.config.Tasks.SystemBucketRetention))
+		lw := taskbackend.NewPointLogWriter(pointsWriter, systemBuckets)
+		m.scheduler = taskbackend.NewScheduler(store, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, time.Duration(m.config.Tasks.SchedulerTickInterval)), taskbackend.WithRetryBackoff(time.Duration(m.config.Tasks.RetryBackoff)), taskbackend.WithLogger(m.logger))
 		m.scheduler.Start(ctx)
 		m.reg.MustRegister(m.scheduler.PrometheusCollectors()...)
 
 		queryService := query.QueryServiceBridge{AsyncQueryService: m.queryController}
-		lr := taskbackend.NewQueryLogReader(queryService)
-		taskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, store), lr, m.scheduler, authSvc, userResourceSvc, orgSvc)
+		lr := taskbackend.NewQueryLogReader(queryService, systemBuckets)
+		m.taskCoordinator = coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, store, coordinator.WithLeaseTTL(time.Duration(m.config.Tasks.LeaseTTL)))
+		taskSvc = task.PlatformAdapter(m.taskCoordinator, lr, m.scheduler, authSvc, userResourceSvc, orgSvc)
 		taskSvc = task.NewValidator(taskSvc, bucketSvc)
+		taskSvc = quota.NewTaskService(taskSvc, orgSvc)
 		m.taskStore = store
 	}
 
@@ -509,18 +592,46 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}(m.logger)
 
 	m.httpServer = &nethttp.Server{
-		Addr: m.httpBindAddress,
+		Addr:              m.config.HTTP.BindAddress,
+		ReadTimeout:       time.Duration(m.config.HTTP.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(m.config.HTTP.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(m.config.HTTP.WriteTimeout),
+		IdleTimeout:       time.Duration(m.config.HTTP.IdleTimeout),
+		MaxHeaderBytes:    int(m.config.HTTP.MaxHeaderSize),
+	}
+
+	transport := "http"
+	if m.config.HTTP.TLSEnabled() {
+		if m.httpServer.TLSConfig, err = m.config.HTTP.TLSConfig(); err != nil {
+			m.logger.Error("failed to configure TLS", zap.Error(err))
+			return err
+		}
+		transport = "https"
+	}
+
+	usageSvc := &usage.Service{
+		Store:               readservice.NewStore(m.engine),
+		OrganizationService: orgSvc,
+		Recorder:            m.usageRecorder,
+		UsageService:        m.engine,
 	}
 
 	m.apibackend = &http.APIBackend{
-		AssetsPath:           m.assetsPath,
+		AssetsPath:           m.config.AssetsPath,
 		Logger:               m.logger,
+		WriteMaxBodySize:     int64(m.config.HTTP.MaxBodySize),
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		PredicateDeleter:     m.engine,
+		SchemaReader:         m.engine,
+		ReadStore:            readservice.NewStore(m.engine),
+		BackupService:        m.engine,
+		KVBackupService:      m.boltClient,
 		AuthorizationService: authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
-		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		BucketService:                   quota.NewBucketService(storage.NewBucketService(bucketSvc, m.engine), orgSvc),
+		DBRPMappingService:              dbrpSvc,
 		SessionService:                  sessionSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
@@ -539,6 +650,9 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		FluxService:                     storageQueryService,
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
+		UsageService:                    usageSvc,
+		UsageRecorder:                   m.usageRecorder,
+		QuotaEnforcer:                   quotaEnforcer,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
 		SecretService:                   secretSvc,
@@ -559,11 +673,11 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	m.httpServer.Handler = h
 	// If we are in testing mode we allow all data to be flushed and removed.
-	if m.testing {
+	if m.config.Testing {
 		m.httpServer.Handler = http.DebugFlush(h, flusher)
 	}
 
-	ln, err := net.Listen("tcp", m.httpBindAddress)
+	ln, err := net.Listen("tcp", m.config.HTTP.BindAddress)
 	if err != nil {
 		httpLogger.Error("failed http listener", zap.Error(err))
 		httpLogger.Info("Stopping")
@@ -577,9 +691,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.wg.Add(1)
 	go func(logger *zap.Logger) {
 		defer m.wg.Done()
-		logger.Info("Listening", zap.String("transport", "http"), zap.String("addr", m.httpBindAddress), zap.Int("port", m.httpPort))
-
-		if err := m.httpServer.Serve(ln); err != nethttp.ErrServerClosed {
+		logger.Info("Listening", zap.String("transport", transport), zap.String("addr", m.config.HTTP.BindAddress), zap.Int("port", m.httpPort))
+
+		var err error
+		if m.httpServer.TLSConfig != nil {
+			// The certificate is in the TLS configuration.
+			err = m.httpServer.ServeTLS(ln, "", "")
+		} else {
+			err = m.httpServer.Serve(ln)
+		}
+		if err != nethttp.ErrServerClosed {
 			logger.Error("failed http service", zap.Error(err))
 		}
 		logger.Info("Stopping")
@@ -5,16 +5,23 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	nethttp "net/http"
+	"net/url"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
 	platform "github.com/influxdata/influxdb"
 	phttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
 )
 
@@ -99,6 +106,183 @@ func TestPipeline_WriteV2_Query(t *testing.T) {
 	res.HasTableCount(t, 1)
 }
 
+func TestLauncher_PrometheusQuery(t *testing.T) {
+	be := RunLauncherOrFail(t, ctx)
+	be.SetupOrFail(t)
+	defer be.ShutdownOrFail(t, ctx)
+
+	now := time.Now().Truncate(time.Second)
+	var lines []string
+	for i := 0; i < 3; i++ {
+		ts := now.Add(time.Duration(i-2) * time.Minute).UnixNano()
+		lines = append(lines,
+			fmt.Sprintf("http_requests_total,job=api counter=%d %d", i+1, ts),
+			fmt.Sprintf("http_requests_total,job=web counter=%d %d", 10*(i+1), ts),
+		)
+	}
+
+	resp, err := nethttp.DefaultClient.Do(be.MustNewHTTPRequest(
+		"POST",
+		fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", be.Org.ID, be.Bucket.ID),
+		strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("failed call to write points: %d", resp.StatusCode)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		params url.Values
+		exp    string
+	}{
+		{
+			name:   "instant vector",
+			path:   "/api/v2/prometheus/api/v1/query",
+			params: url.Values{"query": {`http_requests_total{job="api"}`}, "time": {strconv.FormatInt(now.Unix(), 10)}},
+			exp:    fmt.Sprintf(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"http_requests_total","job":"api"},"value":[%d,"3"]}]}}`, now.Unix()),
+		},
+		{
+			name:   "aggregation",
+			path:   "/api/v2/prometheus/api/v1/query",
+			params: url.Values{"query": {`sum(http_requests_total)`}, "time": {strconv.FormatInt(now.Unix(), 10)}},
+			exp:    fmt.Sprintf(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[%d,"33"]}]}}`, now.Unix()),
+		},
+		{
+			name: "range query",
+			path: "/api/v2/prometheus/api/v1/query_range",
+			params: url.Values{
+				"query": {`sum(http_requests_total)`},
+				"start": {strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)},
+				"end":   {strconv.FormatInt(now.Add(time.Minute).Unix(), 10)},
+				"step":  {"60"},
+			},
+			exp: fmt.Sprintf(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[%d,"22"],[%d,"33"],[%d,"33"]]}]}}`, now.Add(-time.Minute).Unix(), now.Unix(), now.Add(time.Minute).Unix()),
+		},
+		{
+			name: "range query with a step shorter than the samples",
+			path: "/api/v2/prometheus/api/v1/query_range",
+			params: url.Values{
+				"query": {`sum(http_requests_total)`},
+				"start": {strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)},
+				"end":   {strconv.FormatInt(now.Unix(), 10)},
+				"step":  {"30"},
+			},
+			exp: fmt.Sprintf(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[%d,"22"],[%d,"22"],[%d,"33"]]}]}}`, now.Add(-time.Minute).Unix(), now.Add(-30*time.Second).Unix(), now.Unix()),
+		},
+		{
+			name:   "parse error",
+			path:   "/api/v2/prometheus/api/v1/query",
+			params: url.Values{"query": {`sum(`}},
+			exp:    `"errorType":"bad_data"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Set("orgID", be.Org.ID.String())
+			tt.params.Set("bucket", be.Bucket.Name)
+
+			resp, err := nethttp.DefaultClient.Do(be.MustNewHTTPRequest("GET", tt.path+"?"+tt.params.Encode(), ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(b)); !strings.Contains(got, tt.exp) {
+				t.Fatalf("unexpected response %d:\ngot %s\nexp %s", resp.StatusCode, got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestLauncher_PrometheusRemoteStorage(t *testing.T) {
+	be := RunLauncherOrFail(t, ctx)
+	be.SetupOrFail(t)
+	defer be.ShutdownOrFail(t, ctx)
+
+	params := url.Values{"orgID": {be.Org.ID.String()}, "bucket": {be.Bucket.Name}}
+	do := func(path string, msg proto.Message) *nethttp.Response {
+		t.Helper()
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := be.MustNewHTTPRequest("POST", path+"?"+params.Encode(), string(snappy.Encode(nil, data)))
+		// Prometheus authenticates with bearer tokens.
+		req.Header.Set("Authorization", "Bearer "+be.Auth.Token)
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	now := time.Now().Truncate(time.Millisecond)
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	series := []*prometheus.TimeSeries{
+		{
+			Labels: []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+			Samples: []*prometheus.Sample{
+				{Value: 1, Timestamp: ms(now.Add(-time.Minute))},
+				{Value: 0, Timestamp: ms(now)},
+			},
+		},
+		{
+			Labels:  []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "web"}},
+			Samples: []*prometheus.Sample{{Value: 1, Timestamp: ms(now)}},
+		},
+	}
+
+	resp := do("/api/v2/prometheus/write", &prometheus.WriteRequest{Timeseries: series})
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("failed call to remote write: %d", resp.StatusCode)
+	}
+
+	resp = do("/api/v2/prometheus/read", &prometheus.ReadRequest{
+		Queries: []*prometheus.Query{{
+			StartTimestampMs: ms(now.Add(-time.Hour)),
+			EndTimestampMs:   ms(now),
+			Matchers: []*prometheus.LabelMatcher{
+				{Type: prometheus.MatchEqual, Name: "__name__", Value: "up"},
+				{Type: prometheus.MatchRegexp, Name: "job", Value: "a.*"},
+			},
+		}},
+	})
+	defer resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("failed call to remote read: %d", resp.StatusCode)
+	}
+
+	compressed, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got prometheus.ReadResponse
+	if err := proto.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := prometheus.ReadResponse{
+		Results: []*prometheus.QueryResult{{Timeseries: series[:1]}},
+	}
+	if !proto.Equal(&got, &exp) {
+		t.Fatalf("unexpected read response:\ngot %v\nexp %v", &got, &exp)
+	}
+}
+
 // QueryResult wraps a single flux.Result with some helper methods.
 type QueryResult struct {
 	t *testing.T
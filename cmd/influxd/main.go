@@ -33,7 +33,8 @@ func main() {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	} else if !m.Running() {
-		os.Exit(1)
+		// A subcommand such as print-config, or --help, ran instead of influxd.
+		return
 	}
 
 	var wg sync.WaitGroup
@@ -8,19 +8,40 @@ import (
 	"unicode"
 )
 
+const (
+	// ErrDBRPMappingNotFound is an error message when a dbrp mapping does not exist.
+	ErrDBRPMappingNotFound = "dbrp mapping not found"
+	// ErrDBRPMappingExists is an error message when a different dbrp mapping
+	// already exists for the organization, cluster, database and retention policy.
+	ErrDBRPMappingExists = "dbrp mapping already exists"
+)
+
+// ops for dbrp mapping errors.
+const (
+	OpFindDBRPMapping   = "FindDBRPMapping"
+	OpFindDBRPMappings  = "FindDBRPMappings"
+	OpCreateDBRPMapping = "CreateDBRPMapping"
+	OpDeleteDBRPMapping = "DeleteDBRPMapping"
+)
+
+// DefaultDBRPCluster is the cluster of the dbrp mappings that are used by the
+// 1.x compatible HTTP API.
+const DefaultDBRPCluster = "default"
+
 // DBRPMappingService provides a mapping of cluster, database and retention policy to an organization ID and bucket ID.
+// The mappings of each organization are separate, so organizations may map the same database to their own buckets.
 type DBRPMappingService interface {
-	// FindBy returns the dbrp mapping the for cluster, db and rp.
-	FindBy(ctx context.Context, cluster, db, rp string) (*DBRPMapping, error)
+	// FindBy returns the dbrp mapping the for organization, cluster, db and rp.
+	FindBy(ctx context.Context, orgID ID, cluster, db, rp string) (*DBRPMapping, error)
 	// Find returns the first dbrp mapping the matches the filter.
 	Find(ctx context.Context, filter DBRPMappingFilter) (*DBRPMapping, error)
 	// FindMany returns a list of dbrp mappings that match filter and the total count of matching dbrp mappings.
 	FindMany(ctx context.Context, filter DBRPMappingFilter, opt ...FindOptions) ([]*DBRPMapping, int, error)
 	// Create creates a new dbrp mapping, if a different mapping exists an error is returned.
 	Create(ctx context.Context, dbrpMap *DBRPMapping) error
-	// Delete removes a dbrp mapping.
+	// Delete removes the dbrp mapping of the organization.
 	// Deleting a mapping that does not exists is not an error.
-	Delete(ctx context.Context, cluster, db, rp string) error
+	Delete(ctx context.Context, orgID ID, cluster, db, rp string) error
 }
 
 // DBRPMapping represents a mapping of a cluster, database and retention policy to an organization ID and bucket ID.
@@ -29,7 +50,7 @@ type DBRPMapping struct {
 	Database        string `json:"database"`
 	RetentionPolicy string `json:"retention_policy"`
 
-	// Default indicates if this mapping is the default for the cluster and database.
+	// Default indicates if this mapping is the default for the cluster and database of the organization.
 	Default bool `json:"default"`
 
 	OrganizationID ID `json:"organization_id"`
@@ -89,12 +110,14 @@ func (m *DBRPMapping) Equal(o *DBRPMapping) bool {
 		m.BucketID == o.BucketID
 }
 
-// DBRPMappingFilter represents a set of filters that restrict the returned results by cluster, database and retention policy.
+// DBRPMappingFilter represents a set of filters that restrict the returned results by cluster, database,
+// retention policy and organization.
 type DBRPMappingFilter struct {
 	Cluster         *string
 	Database        *string
 	RetentionPolicy *string
 	Default         *bool
+	OrganizationID  *ID
 }
 
 func (f DBRPMappingFilter) String() string {
@@ -127,6 +150,13 @@ func (f DBRPMappingFilter) String() string {
 	} else {
 		s.WriteString("<nil>")
 	}
+
+	s.WriteString(" org:")
+	if f.OrganizationID != nil {
+		s.WriteString(f.OrganizationID.String())
+	} else {
+		s.WriteString("<nil>")
+	}
 	s.WriteString("}")
 	return s.String()
 }
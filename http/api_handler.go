@@ -8,7 +8,9 @@ import (
 	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/quota"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
 	"go.uber.org/zap"
 )
 
@@ -19,6 +21,7 @@ type APIHandler struct {
 	OrgHandler           *OrgHandler
 	AuthorizationHandler *AuthorizationHandler
 	DashboardHandler     *DashboardHandler
+	DBRPHandler          *DBRPHandler
 	LabelHandler         *LabelHandler
 	AssetHandler         *AssetHandler
 	ChronografHandler    *ChronografHandler
@@ -27,9 +30,13 @@ type APIHandler struct {
 	VariableHandler      *VariableHandler
 	TaskHandler          *TaskHandler
 	TelegrafHandler      *TelegrafHandler
+	UsageHandler         *UsageHandler
 	QueryHandler         *FluxHandler
 	ProtoHandler         *ProtoHandler
 	WriteHandler         *WriteHandler
+	DeleteHandler        *DeleteHandler
+	BackupHandler        *BackupHandler
+	PrometheusHandler    *PrometheusHandler
 	SetupHandler         *SetupHandler
 	SessionHandler       *SessionHandler
 	SwaggerHandler       http.HandlerFunc
@@ -41,12 +48,20 @@ type APIBackend struct {
 	AssetsPath string // if empty then assets are served from bindata.
 	Logger     *zap.Logger
 
+	WriteMaxBodySize int64 // if zero then the body of a write is not limited.
+
 	NewBucketService func(*influxdb.Source) (influxdb.BucketService, error)
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
 
 	PointsWriter                    storage.PointsWriter
+	PredicateDeleter                storage.PredicateDeleter
+	SchemaReader                    storage.SchemaReader
+	ReadStore                       reads.Store
+	BackupService                   influxdb.BackupService
+	KVBackupService                 influxdb.KVBackupService
 	AuthorizationService            influxdb.AuthorizationService
 	BucketService                   influxdb.BucketService
+	DBRPMappingService              influxdb.DBRPMappingService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
@@ -65,6 +80,9 @@ type APIBackend struct {
 	FluxService                     query.ProxyQueryService
 	TaskService                     influxdb.TaskService
 	TelegrafService                 influxdb.TelegrafConfigStore
+	UsageService                    influxdb.UsageService
+	UsageRecorder                   influxdb.UsageRecorder
+	QuotaEnforcer                   *quota.Enforcer
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
 	SecretService                   influxdb.SecretService
 	LookupService                   influxdb.LookupService
@@ -100,6 +118,11 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
 	h.DashboardHandler = NewDashboardHandler(dashboardBackend)
 
+	dbrpBackend := NewDBRPBackend(b)
+	dbrpBackend.DBRPMappingService = authorizer.NewDBRPMappingService(b.DBRPMappingService)
+	dbrpBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	h.DBRPHandler = NewDBRPHandler(dbrpBackend)
+
 	variableBackend := NewVariableBackend(b)
 	variableBackend.VariableService = authorizer.NewVariableService(b.VariableService)
 	h.VariableHandler = NewVariableHandler(variableBackend)
@@ -129,12 +152,25 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	telegrafBackend.TelegrafService = authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)
 	h.TelegrafHandler = NewTelegrafHandler(telegrafBackend)
 
+	usageBackend := NewUsageBackend(b)
+	usageBackend.UsageService = authorizer.NewUsageService(b.UsageService)
+	h.UsageHandler = NewUsageHandler(usageBackend)
+
 	writeBackend := NewWriteBackend(b)
 	h.WriteHandler = NewWriteHandler(writeBackend)
 
+	deleteBackend := NewDeleteBackend(b)
+	h.DeleteHandler = NewDeleteHandler(deleteBackend)
+
+	backupBackend := NewBackupBackend(b)
+	h.BackupHandler = NewBackupHandler(backupBackend)
+
 	fluxBackend := NewFluxBackend(b)
 	h.QueryHandler = NewFluxHandler(fluxBackend)
 
+	prometheusBackend := NewPrometheusBackend(b)
+	h.PrometheusHandler = NewPrometheusHandler(prometheusBackend)
+
 	h.ProtoHandler = NewProtoHandler(NewProtoBackend(b))
 	h.ChronografHandler = NewChronografHandler(b.ChronografService)
 	h.SwaggerHandler = SwaggerHandler()
@@ -147,8 +183,11 @@ var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
 	"authorizations": "/api/v2/authorizations",
+	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
+	"dbrps":          "/api/v2/dbrps",
+	"delete":         "/api/v2/delete",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
@@ -156,7 +195,13 @@ var apiLinks = map[string]interface{}{
 	"variables": "/api/v2/variables",
 	"me":        "/api/v2/me",
 	"orgs":      "/api/v2/orgs",
-	"protos":    "/api/v2/protos",
+	"prometheus": map[string]string{
+		"query":      "/api/v2/prometheus/api/v1/query",
+		"queryRange": "/api/v2/prometheus/api/v1/query_range",
+		"read":       "/api/v2/prometheus/read",
+		"write":      "/api/v2/prometheus/write",
+	},
+	"protos": "/api/v2/protos",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
@@ -177,6 +222,7 @@ var apiLinks = map[string]interface{}{
 	},
 	"tasks":     "/api/v2/tasks",
 	"telegrafs": "/api/v2/telegrafs",
+	"usage":     "/api/v2/usage",
 	"users":     "/api/v2/users",
 	"write":     "/api/v2/write",
 }
@@ -217,11 +263,26 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/delete") {
+		h.DeleteHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/backup") {
+		h.BackupHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
 		h.QueryHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/prometheus") {
+		h.PrometheusHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/buckets") {
 		h.BucketHandler.ServeHTTP(w, r)
 		return
@@ -257,6 +318,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/dbrps") {
+		h.DBRPHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/sources") {
 		h.SourceHandler.ServeHTTP(w, r)
 		return
@@ -272,11 +338,16 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.HasPrefix(r.URL.Path, "/api/v2/telegrafs") {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/telegrafs") || strings.HasPrefix(r.URL.Path, "/api/v2/telegraf/") {
 		h.TelegrafHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/usage") {
+		h.UsageHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/variables") {
 		h.VariableHandler.ServeHTTP(w, r)
 		return
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"time"
@@ -42,16 +43,21 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 }
 
 const (
-	tokenAuthScheme   = "token"
-	sessionAuthScheme = "session"
+	tokenAuthScheme       = "token"
+	sessionAuthScheme     = "session"
+	certificateAuthScheme = "certificate"
 )
 
 // ProbeAuthScheme probes the http request for the requests for token or cookie session.
+// A request without either is authenticated by its verified TLS client certificate.
 func ProbeAuthScheme(r *http.Request) (string, error) {
 	_, tokenErr := GetToken(r)
 	_, sessErr := decodeCookieSession(r.Context(), r)
 
 	if tokenErr != nil && sessErr != nil {
+		if _, err := clientCertificate(r); err == nil {
+			return certificateAuthScheme, nil
+		}
 		return "", fmt.Errorf("token required")
 	}
 
@@ -93,6 +99,14 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		r = r.WithContext(ctx)
 		h.Handler.ServeHTTP(w, r)
 		return
+	case certificateAuthScheme:
+		ctx, err = h.extractCertificateAuthorization(ctx, r)
+		if err != nil {
+			break
+		}
+		r = r.WithContext(ctx)
+		h.Handler.ServeHTTP(w, r)
+		return
 	}
 
 	UnauthorizedError(ctx, w)
@@ -131,3 +145,33 @@ func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Requ
 
 	return platcontext.SetAuthorizer(ctx, s), nil
 }
+
+// clientCertificate returns the TLS client certificate of the request.
+// The certificate has been verified against the client CAs of the server.
+func clientCertificate(r *http.Request) (*x509.Certificate, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, fmt.Errorf("client certificate required")
+	}
+	return r.TLS.VerifiedChains[0][0], nil
+}
+
+// extractCertificateAuthorization authenticates the request as the authorization
+// whose ID is the common name of the client certificate.
+func (h *AuthenticationHandler) extractCertificateAuthorization(ctx context.Context, r *http.Request) (context.Context, error) {
+	cert, err := clientCertificate(r)
+	if err != nil {
+		return ctx, err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(cert.Subject.CommonName); err != nil {
+		return ctx, err
+	}
+
+	a, err := h.AuthorizationService.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return ctx, err
+	}
+
+	return platcontext.SetAuthorizer(ctx, a), nil
+}
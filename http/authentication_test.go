@@ -2,6 +2,9 @@ package http_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -149,8 +152,9 @@ func TestAuthenticationHandler(t *testing.T) {
 
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
-		token   string
-		session string
+		token       string
+		session     string
+		certificate bool
 	}
 	type wants struct {
 		scheme string
@@ -180,6 +184,25 @@ func TestProbeAuthScheme(t *testing.T) {
 				scheme: "token",
 			},
 		},
+		{
+			name: "certificate provided",
+			args: args{
+				certificate: true,
+			},
+			wants: wants{
+				scheme: "certificate",
+			},
+		},
+		{
+			name: "token and certificate provided",
+			args: args{
+				token:       "abc123",
+				certificate: true,
+			},
+			wants: wants{
+				scheme: "token",
+			},
+		},
 		{
 			name: "no auth provided",
 			args: args{},
@@ -201,6 +224,12 @@ func TestProbeAuthScheme(t *testing.T) {
 				platformhttp.SetToken(tt.args.token, r)
 			}
 
+			if tt.args.certificate {
+				r.TLS = &tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "0000000000000001"}}}},
+				}
+			}
+
 			scheme, err := platformhttp.ProbeAuthScheme(r)
 			if (err != nil) != (tt.wants.err != nil) {
 				t.Errorf("unexpected error got %v want %v", err, tt.wants.err)
@@ -0,0 +1,308 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// BackupBackend is all services and associated parameters required to construct
+// the BackupHandler.
+type BackupBackend struct {
+	Logger *zap.Logger
+
+	BackupService   platform.BackupService
+	KVBackupService platform.KVBackupService
+}
+
+// NewBackupBackend returns a new instance of BackupBackend.
+func NewBackupBackend(b *APIBackend) *BackupBackend {
+	return &BackupBackend{
+		Logger: b.Logger.With(zap.String("handler", "backup")),
+
+		BackupService:   b.BackupService,
+		KVBackupService: b.KVBackupService,
+	}
+}
+
+// BackupHandler creates backups of the engine data and the key/value store
+// and serves the resulting files.
+type BackupHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	BackupService   platform.BackupService
+	KVBackupService platform.KVBackupService
+}
+
+const (
+	backupPath        = "/api/v2/backup"
+	backupIDPath      = "/api/v2/backup/id/:backup_id"
+	backupKVStorePath = "/api/v2/backup/kv"
+	backupFilePath    = "/api/v2/backup/file/:backup_id/:backup_file"
+)
+
+// NewBackupHandler creates a new handler at /api/v2/backup to create and fetch backups.
+func NewBackupHandler(b *BackupBackend) *BackupHandler {
+	h := &BackupHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		BackupService:   b.BackupService,
+		KVBackupService: b.KVBackupService,
+	}
+
+	h.HandlerFunc("POST", backupPath, h.handleCreate)
+	h.HandlerFunc("GET", backupKVStorePath, h.handleFetchKVStore)
+	h.HandlerFunc("GET", backupFilePath, h.handleFetchFile)
+	h.HandlerFunc("DELETE", backupIDPath, h.handleFinish)
+
+	return h
+}
+
+type backup struct {
+	ID    int      `json:"id"`
+	Files []string `json:"files"`
+}
+
+func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeBackup(ctx); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	id, files, err := h.BackupService.CreateBackup(ctx)
+	if err != nil {
+		h.Logger.Error("Failed to create backup", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, &backup{ID: id, Files: files}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *BackupHandler) handleFetchKVStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeBackup(ctx); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if err := h.KVBackupService.Backup(ctx, w); err != nil {
+		h.Logger.Error("Failed to back up key/value store", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *BackupHandler) handleFetchFile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeBackup(ctx); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	backupID, err := decodeBackupID(params)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	backupFile := params.ByName("backup_file")
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if err := h.BackupService.FetchBackupFile(ctx, backupID, backupFile, w); err != nil {
+		h.Logger.Error("Failed to fetch backup file", zap.Int("backup_id", backupID), zap.String("backup_file", backupFile), zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *BackupHandler) handleFinish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeBackup(ctx); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	backupID, err := decodeBackupID(httprouter.ParamsFromContext(ctx))
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.BackupService.FinishBackup(ctx, backupID); err != nil {
+		h.Logger.Error("Failed to finish backup", zap.Int("backup_id", backupID), zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeBackupID(params httprouter.Params) (int, error) {
+	backupID, err := strconv.Atoi(params.ByName("backup_id"))
+	if err != nil {
+		return 0, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeBackupID",
+			Msg:  "invalid backup id",
+			Err:  err,
+		}
+	}
+	return backupID, nil
+}
+
+// authorizeBackup requires the authorizer to be able to read every bucket and
+// authorization, as a backup contains the data and metadata of all organizations.
+func authorizeBackup(ctx context.Context) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, rt := range []platform.ResourceType{platform.BucketsResourceType, platform.AuthorizationsResourceType} {
+		p, err := platform.NewGlobalPermission(platform.ReadAction, rt)
+		if err != nil {
+			return err
+		}
+		if !a.Allowed(*p) {
+			return &platform.Error{
+				Code: platform.EForbidden,
+				Op:   "http/authorizeBackup",
+				Msg:  "insufficient permissions for backup",
+			}
+		}
+	}
+	return nil
+}
+
+// BackupService is the client implementation of platform.BackupService and
+// platform.KVBackupService.
+type BackupService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// CreateBackup creates a new backup on the server and returns its ID and files.
+func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error) {
+	u, err := newURL(s.Addr, backupPath)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(nil))
+	if err != nil {
+		return 0, nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return 0, nil, err
+	}
+
+	var b backup
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return 0, nil, err
+	}
+
+	return b.ID, b.Files, nil
+}
+
+// FetchBackupFile writes the backup file to w.
+func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	return s.fetch(ctx, path.Join(backupPath, "file", strconv.Itoa(backupID), backupFile), w)
+}
+
+// Backup writes a snapshot of the key/value store to w.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) error {
+	return s.fetch(ctx, backupKVStorePath, w)
+}
+
+// FinishBackup removes the backup from the server once all its files have been fetched.
+func (s *BackupService) FinishBackup(ctx context.Context, backupID int) error {
+	u, err := newURL(s.Addr, path.Join(backupPath, "id", strconv.Itoa(backupID)))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+// InternalBackupPath is not available over HTTP.
+func (s *BackupService) InternalBackupPath(backupID int) (string, error) {
+	return "", &platform.Error{
+		Code: platform.EMethodNotAllowed,
+		Op:   "http/InternalBackupPath",
+		Msg:  "the internal backup path is not available over HTTP",
+	}
+}
+
+func (s *BackupService) fetch(ctx context.Context, p string, w io.Writer) error {
+	u, err := newURL(s.Addr, p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to copy %s: %v", p, err)
+	}
+	return nil
+}
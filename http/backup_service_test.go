@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+type fakeBackupService struct{}
+
+func (fakeBackupService) CreateBackup(ctx context.Context) (int, []string, error) {
+	return 1, []string{"000000001-000000001.tsm"}, nil
+}
+
+func (fakeBackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	_, err := io.WriteString(w, backupFile)
+	return err
+}
+
+func (fakeBackupService) FinishBackup(ctx context.Context, backupID int) error {
+	if backupID != 1 {
+		return &platform.Error{Code: platform.ENotFound, Msg: "backup not found"}
+	}
+	return nil
+}
+
+func (fakeBackupService) InternalBackupPath(backupID int) (string, error) { return "", nil }
+
+func (fakeBackupService) Backup(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "kv")
+	return err
+}
+
+func TestBackupHandler(t *testing.T) {
+	operPerms := platform.OperPermissions()
+	readBuckets := []platform.Permission{
+		{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType}},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		perms  []platform.Permission
+		status int
+		body   string
+	}{
+		{
+			name:   "create backup",
+			method: "POST",
+			path:   "/api/v2/backup",
+			perms:  operPerms,
+			status: http.StatusCreated,
+		},
+		{
+			name:   "fetch kv store",
+			method: "GET",
+			path:   "/api/v2/backup/kv",
+			perms:  operPerms,
+			status: http.StatusOK,
+			body:   "kv",
+		},
+		{
+			name:   "fetch file",
+			method: "GET",
+			path:   "/api/v2/backup/file/1/000000001-000000001.tsm",
+			perms:  operPerms,
+			status: http.StatusOK,
+			body:   "000000001-000000001.tsm",
+		},
+		{
+			name:   "invalid backup id",
+			method: "GET",
+			path:   "/api/v2/backup/file/abc/000000001-000000001.tsm",
+			perms:  operPerms,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "finish backup",
+			method: "DELETE",
+			path:   "/api/v2/backup/id/1",
+			perms:  operPerms,
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "finish unknown backup",
+			method: "DELETE",
+			path:   "/api/v2/backup/id/2",
+			perms:  operPerms,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "missing authorization read permission",
+			method: "POST",
+			path:   "/api/v2/backup",
+			perms:  readBuckets,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBackupHandler(&BackupBackend{
+				Logger:          zap.NewNop(),
+				BackupService:   fakeBackupService{},
+				KVBackupService: fakeBackupService{},
+			})
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: tt.perms}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+
+			if tt.status == http.StatusCreated {
+				var b backup
+				if err := json.NewDecoder(w.Body).Decode(&b); err != nil {
+					t.Fatal(err)
+				}
+				if b.ID != 1 || len(b.Files) != 1 {
+					t.Errorf("unexpected backup: %+v", b)
+				}
+			}
+
+			if tt.body != "" {
+				if got, want := w.Body.String(), tt.body; got != want {
+					t.Errorf("unexpected body: got %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
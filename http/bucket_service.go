@@ -10,6 +10,10 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -25,6 +29,7 @@ type BucketBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	SchemaReader               storage.SchemaReader
 }
 
 // NewBucketBackend returns a new instance of BucketBackend.
@@ -38,6 +43,7 @@ func NewBucketBackend(b *APIBackend) *BucketBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		SchemaReader:               b.SchemaReader,
 	}
 }
 
@@ -53,12 +59,14 @@ type BucketHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	SchemaReader               storage.SchemaReader
 }
 
 const (
 	bucketsPath            = "/api/v2/buckets"
 	bucketsIDPath          = "/api/v2/buckets/:id"
 	bucketsIDLogPath       = "/api/v2/buckets/:id/log"
+	bucketsIDSchemaPath    = "/api/v2/buckets/:id/schema"
 	bucketsIDMembersPath   = "/api/v2/buckets/:id/members"
 	bucketsIDMembersIDPath = "/api/v2/buckets/:id/members/:userID"
 	bucketsIDOwnersPath    = "/api/v2/buckets/:id/owners"
@@ -79,12 +87,14 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		SchemaReader:               b.SchemaReader,
 	}
 
 	h.HandlerFunc("POST", bucketsPath, h.handlePostBucket)
 	h.HandlerFunc("GET", bucketsPath, h.handleGetBuckets)
 	h.HandlerFunc("GET", bucketsIDPath, h.handleGetBucket)
 	h.HandlerFunc("GET", bucketsIDLogPath, h.handleGetBucketLog)
+	h.HandlerFunc("GET", bucketsIDSchemaPath, h.handleGetBucketSchema)
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
 
@@ -125,12 +135,13 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
-	ID                  influxdb.ID     `json:"id,omitempty"`
-	OrganizationID      influxdb.ID     `json:"organizationID,omitempty"`
-	Organization        string          `json:"organization,omitempty"`
-	Name                string          `json:"name"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	ID                  influxdb.ID            `json:"id,omitempty"`
+	OrganizationID      influxdb.ID            `json:"organizationID,omitempty"`
+	Organization        string                 `json:"organization,omitempty"`
+	Name                string                 `json:"name"`
+	RetentionPolicyName string                 `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules      []retentionRule        `json:"retentionRules"`
+	Schema              *influxdb.BucketSchema `json:"schema,omitempty"`
 }
 
 // retentionRule is the retention rule action for a bucket.
@@ -164,6 +175,7 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		Schema:              b.Schema,
 	}, nil
 }
 
@@ -188,13 +200,15 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Name:                pb.Name,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		Schema:              pb.Schema,
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name           *string                `json:"name,omitempty"`
+	RetentionRules []retentionRule        `json:"retentionRules,omitempty"`
+	Schema         *influxdb.BucketSchema `json:"schema,omitempty"`
 }
 
 func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
@@ -217,6 +231,7 @@ func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
 	return &influxdb.BucketUpdate{
 		Name:            b.Name,
 		RetentionPeriod: &d,
+		Schema:          b.Schema,
 	}, nil
 }
 
@@ -228,6 +243,7 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	up := &bucketUpdate{
 		Name:           pb.Name,
 		RetentionRules: []retentionRule{},
+		Schema:         pb.Schema,
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -870,6 +886,163 @@ func decodeGetBucketLogRequest(ctx context.Context, r *http.Request) (*getBucket
 	}, nil
 }
 
+// handleGetBucketSchema is the HTTP handler for the GET /api/v2/buckets/:id/schema route.
+func (h *BucketHandler) handleGetBucketSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketSchemaRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	// Finding the bucket checks that the schema of the bucket may be read.
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	names := []string{req.Measurement}
+	if req.Measurement == "" {
+		itr, err := h.SchemaReader.MeasurementNames(ctx, b.OrganizationID, b.ID, req.Start, req.Stop, req.Predicate)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		names = cursors.StringIteratorToSlice(itr)
+	}
+
+	measurements := make([]measurementSchemaResponse, 0, len(names))
+	for _, name := range names {
+		itr, err := h.SchemaReader.MeasurementFields(ctx, b.OrganizationID, b.ID, name, req.Start, req.Stop, req.Predicate)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+
+		fields := make([]fieldSchemaResponse, 0)
+		for itr.Next() {
+			f := itr.Value()
+			fields = append(fields, fieldSchemaResponse{Key: f.Key, Type: f.Type.String()})
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		measurements = append(measurements, measurementSchemaResponse{Name: name, Fields: fields})
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketSchemaResponse(b.ID, measurements)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type getBucketSchemaRequest struct {
+	BucketID    influxdb.ID
+	Measurement string
+	Start       int64
+	Stop        int64
+	Predicate   influxql.Expr
+}
+
+func decodeGetBucketSchemaRequest(ctx context.Context, r *http.Request) (*getBucketSchemaRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	qp := r.URL.Query()
+	req := &getBucketSchemaRequest{
+		BucketID:    i,
+		Measurement: qp.Get("measurement"),
+		Start:       models.MinNanoTime,
+		Stop:        models.MaxNanoTime,
+	}
+
+	for _, p := range []struct {
+		name string
+		t    *int64
+	}{
+		{name: "start", t: &req.Start},
+		{name: "stop", t: &req.Stop},
+	} {
+		v := qp.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   "http/decodeGetBucketSchemaRequest",
+				Msg:  fmt.Sprintf("invalid %s: %v", p.name, err),
+				Err:  err,
+			}
+		}
+		*p.t = t.UnixNano()
+	}
+
+	if req.Stop < req.Start {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/decodeGetBucketSchemaRequest",
+			Msg:  "stop must not be before start",
+		}
+	}
+
+	if v := qp.Get("predicate"); v != "" {
+		pred, err := influxql.ParseExpr(v)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   "http/decodeGetBucketSchemaRequest",
+				Msg:  fmt.Sprintf("invalid predicate: %v", err),
+				Err:  err,
+			}
+		}
+		req.Predicate = pred
+	}
+
+	return req, nil
+}
+
+type fieldSchemaResponse struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+type measurementSchemaResponse struct {
+	Name   string                `json:"name"`
+	Fields []fieldSchemaResponse `json:"fields"`
+}
+
+type bucketSchemaResponse struct {
+	Links        map[string]string           `json:"links"`
+	BucketID     influxdb.ID                 `json:"bucketID"`
+	Measurements []measurementSchemaResponse `json:"measurements"`
+}
+
+func newBucketSchemaResponse(id influxdb.ID, measurements []measurementSchemaResponse) *bucketSchemaResponse {
+	return &bucketSchemaResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/schema", id),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", id),
+		},
+		BucketID:     id,
+		Measurements: measurements,
+	}
+}
+
 func newBucketLogResponse(id influxdb.ID, es []*influxdb.OperationLogEntry) *operationLogResponse {
 	log := make([]*operationLogEntryResponse, 0, len(es))
 	for _, e := range es {
@@ -8,13 +8,17 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"testing"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
 	platformtesting "github.com/influxdata/influxdb/testing"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -339,6 +343,210 @@ func TestService_handleGetBucket(t *testing.T) {
 	}
 }
 
+// schemaReader is a storage.SchemaReader over the fields of the measurements
+// of a single bucket.
+type schemaReader struct {
+	fields map[string][]cursors.MeasurementField
+	start  int64
+	stop   int64
+	pred   influxql.Expr
+}
+
+func (s *schemaReader) MeasurementNames(ctx context.Context, orgID, bucketID platform.ID, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	s.start, s.stop, s.pred = start, end, predicate
+	names := make([]string, 0, len(s.fields))
+	for name := range s.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return cursors.NewStringSliceIterator(names), nil
+}
+
+func (s *schemaReader) MeasurementFields(ctx context.Context, orgID, bucketID platform.ID, measurement string, start, end int64, predicate influxql.Expr) (cursors.MeasurementFieldIterator, error) {
+	s.start, s.stop, s.pred = start, end, predicate
+	return cursors.NewMeasurementFieldSliceIterator(s.fields[measurement]), nil
+}
+
+func TestService_handleGetBucketSchema(t *testing.T) {
+	bucketService := &mock.BucketService{
+		FindBucketByIDFn: func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+			if id == platformtesting.MustIDBase16("020f755c3c082000") {
+				return &platform.Bucket{
+					ID:             platformtesting.MustIDBase16("020f755c3c082000"),
+					OrganizationID: platformtesting.MustIDBase16("020f755c3c082001"),
+					Name:           "hello",
+				}, nil
+			}
+			return nil, &platform.Error{
+				Code: platform.ENotFound,
+				Msg:  "bucket not found",
+			}
+		},
+	}
+
+	type wants struct {
+		statusCode int
+		body       string
+		start      int64
+		stop       int64
+		pred       string
+	}
+
+	tests := []struct {
+		name  string
+		id    string
+		query string
+		wants wants
+	}{
+		{
+			name: "get the schema of a bucket",
+			id:   "020f755c3c082000",
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/schema",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "bucketID": "020f755c3c082000",
+  "measurements": [
+    {
+      "name": "cpu",
+      "fields": [{"key": "count", "type": "integer"}, {"key": "usage", "type": "float"}]
+    },
+    {
+      "name": "mem",
+      "fields": [{"key": "free", "type": "unsigned"}]
+    }
+  ]
+}
+`,
+				start: models.MinNanoTime,
+				stop:  models.MaxNanoTime,
+			},
+		},
+		{
+			name:  "get the schema of a measurement within a range",
+			id:    "020f755c3c082000",
+			query: "?measurement=mem&start=2019-01-01T00:00:00Z&stop=2019-01-02T00:00:00Z&predicate=host%3D%27a%27",
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/schema",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "bucketID": "020f755c3c082000",
+  "measurements": [
+    {
+      "name": "mem",
+      "fields": [{"key": "free", "type": "unsigned"}]
+    }
+  ]
+}
+`,
+				start: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(),
+				stop:  time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC).UnixNano(),
+				pred:  "host = 'a'",
+			},
+		},
+		{
+			name:  "missing measurement",
+			id:    "020f755c3c082000",
+			query: "?measurement=disk",
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/schema",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "bucketID": "020f755c3c082000",
+  "measurements": []
+}
+`,
+				start: models.MinNanoTime,
+				stop:  models.MaxNanoTime,
+			},
+		},
+		{
+			name:  "stop before start",
+			id:    "020f755c3c082000",
+			query: "?start=2019-01-02T00:00:00Z&stop=2019-01-01T00:00:00Z",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:  "invalid predicate",
+			id:    "020f755c3c082000",
+			query: "?predicate=host%3D",
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "bucket not found",
+			id:   "020f755c3c082002",
+			wants: wants{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &schemaReader{
+				fields: map[string][]cursors.MeasurementField{
+					"cpu": {{Key: "count", Type: cursors.Integer}, {Key: "usage", Type: cursors.Float}},
+					"mem": {{Key: "free", Type: cursors.Unsigned}},
+				},
+			}
+			bucketBackend := NewMockBucketBackend()
+			bucketBackend.BucketService = bucketService
+			bucketBackend.SchemaReader = reader
+			h := NewBucketHandler(bucketBackend)
+
+			r := httptest.NewRequest("GET", "http://any.url"+tt.query, nil)
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: tt.id,
+					},
+				}))
+
+			w := httptest.NewRecorder()
+
+			h.handleGetBucketSchema(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Fatalf("handleGetBucketSchema() = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if tt.wants.body == "" {
+				return
+			}
+			if eq, diff, _ := jsonEqual(string(body), tt.wants.body); !eq {
+				t.Errorf("handleGetBucketSchema() = ***%s***", diff)
+			}
+			if reader.start != tt.wants.start || reader.stop != tt.wants.stop {
+				t.Errorf("unexpected range: got [%d, %d], want [%d, %d]", reader.start, reader.stop, tt.wants.start, tt.wants.stop)
+			}
+			if got := fmt.Sprint(reader.pred); tt.wants.pred != "" && got != tt.wants.pred {
+				t.Errorf("unexpected predicate: got %s, want %s", got, tt.wants.pred)
+			}
+		})
+	}
+}
+
 func TestService_handlePostBucket(t *testing.T) {
 	type fields struct {
 		BucketService       platform.BucketService
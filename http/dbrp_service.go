@@ -0,0 +1,476 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	dbrpsPath = "/api/v2/dbrps"
+)
+
+// DBRPBackend is all services and associated parameters required to construct
+// the DBRPHandler.
+type DBRPBackend struct {
+	Logger *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+	BucketService      influxdb.BucketService
+}
+
+// NewDBRPBackend returns a new instance of DBRPBackend.
+func NewDBRPBackend(b *APIBackend) *DBRPBackend {
+	return &DBRPBackend{
+		Logger: b.Logger.With(zap.String("handler", "dbrp")),
+
+		DBRPMappingService: b.DBRPMappingService,
+		BucketService:      b.BucketService,
+	}
+}
+
+// DBRPHandler is the handler for the mappings of 1.x databases and
+// retention policies to buckets.
+type DBRPHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+	BucketService      influxdb.BucketService
+}
+
+// NewDBRPHandler returns a new instance of DBRPHandler.
+func NewDBRPHandler(b *DBRPBackend) *DBRPHandler {
+	h := &DBRPHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		DBRPMappingService: b.DBRPMappingService,
+		BucketService:      b.BucketService,
+	}
+
+	h.HandlerFunc("POST", dbrpsPath, h.handlePostDBRP)
+	h.HandlerFunc("GET", dbrpsPath, h.handleGetDBRPs)
+	h.HandlerFunc("GET", dbrpsPath+"/:cluster/:db/:rp", h.handleGetDBRP)
+	h.HandlerFunc("DELETE", dbrpsPath+"/:cluster/:db/:rp", h.handleDeleteDBRP)
+
+	return h
+}
+
+type dbrpResponse struct {
+	*influxdb.DBRPMapping
+	Links map[string]string `json:"links"`
+}
+
+func newDBRPResponse(m *influxdb.DBRPMapping) *dbrpResponse {
+	return &dbrpResponse{
+		DBRPMapping: m,
+		Links: map[string]string{
+			"self":   dbrpPath(m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy),
+			"org":    fmt.Sprintf("/api/v2/orgs/%s", m.OrganizationID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", m.BucketID),
+		},
+	}
+}
+
+type dbrpsResponse struct {
+	DBRPs []*dbrpResponse   `json:"dbrps"`
+	Links map[string]string `json:"links"`
+}
+
+func newDBRPsResponse(ms []*influxdb.DBRPMapping) *dbrpsResponse {
+	res := &dbrpsResponse{
+		DBRPs: make([]*dbrpResponse, 0, len(ms)),
+		Links: map[string]string{
+			"self": dbrpsPath,
+		},
+	}
+	for _, m := range ms {
+		res.DBRPs = append(res.DBRPs, newDBRPResponse(m))
+	}
+	return res
+}
+
+// handlePostDBRP is the HTTP handler for the POST /api/v2/dbrps route.
+func (h *DBRPHandler) handlePostDBRP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostDBRPRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	// The organization of a mapping is always the organization of its bucket.
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	req.OrganizationID = b.OrganizationID
+
+	if err := h.DBRPMappingService.Create(ctx, req.DBRPMapping); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newDBRPResponse(req.DBRPMapping)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type postDBRPRequest struct {
+	*influxdb.DBRPMapping
+}
+
+func decodePostDBRPRequest(ctx context.Context, r *http.Request) (*postDBRPRequest, error) {
+	m := &influxdb.DBRPMapping{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	if m.Cluster == "" {
+		m.Cluster = influxdb.DefaultDBRPCluster
+	}
+	if !m.BucketID.Valid() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "bucket_id is required",
+		}
+	}
+
+	return &postDBRPRequest{
+		DBRPMapping: m,
+	}, nil
+}
+
+// handleGetDBRPs is the HTTP handler for the GET /api/v2/dbrps route.
+func (h *DBRPHandler) handleGetDBRPs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetDBRPsRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.DBRPMappingService.FindMany(ctx, req.filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDBRPsResponse(ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type getDBRPsRequest struct {
+	filter influxdb.DBRPMappingFilter
+}
+
+func decodeGetDBRPsRequest(ctx context.Context, r *http.Request) (*getDBRPsRequest, error) {
+	qp := r.URL.Query()
+	req := &getDBRPsRequest{}
+
+	if cluster := qp.Get("cluster"); cluster != "" {
+		req.filter.Cluster = &cluster
+	}
+	if db := qp.Get("db"); db != "" {
+		req.filter.Database = &db
+	}
+	if rp := qp.Get("rp"); rp != "" {
+		req.filter.RetentionPolicy = &rp
+	}
+	if s := qp.Get("default"); s != "" {
+		d, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "default must be true or false",
+				Err:  err,
+			}
+		}
+		req.filter.Default = &d
+	}
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrganizationID = id
+	}
+
+	return req, nil
+}
+
+// handleGetDBRP is the HTTP handler for the GET /api/v2/dbrps/:cluster/:db/:rp route.
+func (h *DBRPHandler) handleGetDBRP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDBRPRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	m, err := h.DBRPMappingService.FindBy(ctx, req.OrganizationID, req.Cluster, req.Database, req.RetentionPolicy)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDBRPResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteDBRP is the HTTP handler for the DELETE /api/v2/dbrps/:cluster/:db/:rp route.
+func (h *DBRPHandler) handleDeleteDBRP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDBRPRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Delete(ctx, req.OrganizationID, req.Cluster, req.Database, req.RetentionPolicy); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type dbrpRequest struct {
+	OrganizationID  influxdb.ID
+	Cluster         string
+	Database        string
+	RetentionPolicy string
+}
+
+// decodeDBRPRequest decodes the mapping of the path. The organization of the
+// mapping is the orgID query parameter as each organization has its own mappings.
+func decodeDBRPRequest(ctx context.Context, r *http.Request) (*dbrpRequest, error) {
+	orgID := r.URL.Query().Get("orgID")
+	if orgID == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	id, err := influxdb.IDFromString(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	return &dbrpRequest{
+		OrganizationID:  *id,
+		Cluster:         params.ByName("cluster"),
+		Database:        params.ByName("db"),
+		RetentionPolicy: params.ByName("rp"),
+	}, nil
+}
+
+func dbrpPath(orgID influxdb.ID, cluster, db, rp string) string {
+	return path.Join(dbrpsPath, cluster, db, rp) + "?orgID=" + orgID.String()
+}
+
+// DBRPMappingService connects to Influx via HTTP using tokens to manage dbrp mappings.
+type DBRPMappingService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.DBRPMappingService = (*DBRPMappingService)(nil)
+
+// FindBy returns the dbrp mapping for the organization, cluster, db and rp.
+func (s *DBRPMappingService) FindBy(ctx context.Context, orgID influxdb.ID, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	u, err := newDBRPURL(s.Addr, orgID, cluster, db, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var m dbrpResponse
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, err
+	}
+	return m.DBRPMapping, nil
+}
+
+// Find returns the first dbrp mapping that matches the filter.
+func (s *DBRPMappingService) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	ms, n, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindDBRPMapping,
+			Msg:  influxdb.ErrDBRPMappingNotFound,
+		}
+	}
+	return ms[0], nil
+}
+
+// FindMany returns the dbrp mappings that match the filter and the total count of matching dbrp mappings.
+func (s *DBRPMappingService) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	u, err := newURL(s.Addr, dbrpsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := u.Query()
+	if filter.Cluster != nil {
+		query.Add("cluster", *filter.Cluster)
+	}
+	if filter.Database != nil {
+		query.Add("db", *filter.Database)
+	}
+	if filter.RetentionPolicy != nil {
+		query.Add("rp", *filter.RetentionPolicy)
+	}
+	if filter.Default != nil {
+		query.Add("default", strconv.FormatBool(*filter.Default))
+	}
+	if filter.OrganizationID != nil {
+		query.Add("orgID", filter.OrganizationID.String())
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.URL.RawQuery = query.Encode()
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, 0, err
+	}
+
+	var res dbrpsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, 0, err
+	}
+
+	ms := make([]*influxdb.DBRPMapping, 0, len(res.DBRPs))
+	for _, m := range res.DBRPs {
+		ms = append(ms, m.DBRPMapping)
+	}
+	return ms, len(ms), nil
+}
+
+// Create creates a new dbrp mapping. The organization of the mapping is set
+// to the organization of its bucket.
+func (s *DBRPMappingService) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	u, err := newURL(s.Addr, dbrpsPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckErrorStatus(http.StatusCreated, resp); err != nil {
+		return err
+	}
+
+	res := dbrpResponse{DBRPMapping: m}
+	return json.NewDecoder(resp.Body).Decode(&res)
+}
+
+// Delete removes the dbrp mapping of the organization.
+func (s *DBRPMappingService) Delete(ctx context.Context, orgID influxdb.ID, cluster, db, rp string) error {
+	u, err := newDBRPURL(s.Addr, orgID, cluster, db, rp)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusNoContent, resp)
+}
+
+// newDBRPURL returns the URL of the dbrp mapping of the organization.
+func newDBRPURL(addr string, orgID influxdb.ID, cluster, db, rp string) (*url.URL, error) {
+	u, err := newURL(addr, path.Join(dbrpsPath, cluster, db, rp))
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("orgID", orgID.String())
+	u.RawQuery = query.Encode()
+	return u, nil
+}
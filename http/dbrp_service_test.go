@@ -0,0 +1,129 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	platformtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap"
+)
+
+// dbrpBucketOrgs are the organizations of the buckets of the dbrp mapping
+// tests. The handler sets the organization of a new mapping to the
+// organization of its bucket.
+var dbrpBucketOrgs = map[string]string{
+	"cab00d1ecab00d1e": "ba55ba55ba55ba55",
+	"ca1fca1fca1fca1f": "beadbeadbeadbead",
+	"a55e55eda55e55ed": "1005e1eaf1005e1e",
+	"b1077edb1077eded": "1005e1eaf1005e1e",
+}
+
+func newDBRPTestBackend(svc platform.DBRPMappingService) *DBRPBackend {
+	return &DBRPBackend{
+		Logger:             zap.NewNop(),
+		DBRPMappingService: svc,
+		BucketService: &mock.BucketService{
+			FindBucketByIDFn: func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+				orgID, ok := dbrpBucketOrgs[id.String()]
+				if !ok {
+					return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+				}
+				return &platform.Bucket{ID: id, OrganizationID: platformtesting.MustIDBase16(orgID)}, nil
+			},
+		},
+	}
+}
+
+func initDBRPMappingService(f platformtesting.DBRPMappingFields, t *testing.T) (platform.DBRPMappingService, func()) {
+	svc := inmem.NewService()
+
+	ctx := context.Background()
+	if err := f.Populate(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewDBRPHandler(newDBRPTestBackend(svc))
+	server := httptest.NewServer(handler)
+	client := DBRPMappingService{
+		Addr: server.URL,
+	}
+	return &client, server.Close
+}
+
+func TestDBRPMappingService(t *testing.T) {
+	t.Run("CreateDBRPMapping", func(t *testing.T) { platformtesting.CreateDBRPMapping(initDBRPMappingService, t) })
+	t.Run("FindDBRPMappingByKey", func(t *testing.T) { platformtesting.FindDBRPMappingByKey(initDBRPMappingService, t) })
+	t.Run("FindDBRPMappings", func(t *testing.T) { platformtesting.FindDBRPMappings(initDBRPMappingService, t) })
+	t.Run("FindDBRPMapping", func(t *testing.T) { platformtesting.FindDBRPMapping(initDBRPMappingService, t) })
+	t.Run("DeleteDBRPMapping", func(t *testing.T) { platformtesting.DeleteDBRPMapping(initDBRPMappingService, t) })
+}
+
+func TestDBRPHandler_handlePostDBRP(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   *platform.DBRPMapping
+	}{
+		{
+			name:   "organization and cluster are set",
+			body:   `{"database":"telegraf","retention_policy":"autogen","default":true,"organization_id":"0000000000000001","bucket_id":"cab00d1ecab00d1e"}`,
+			status: http.StatusCreated,
+			want: &platform.DBRPMapping{
+				Cluster:         platform.DefaultDBRPCluster,
+				Database:        "telegraf",
+				RetentionPolicy: "autogen",
+				Default:         true,
+				OrganizationID:  platformtesting.MustIDBase16("ba55ba55ba55ba55"),
+				BucketID:        platformtesting.MustIDBase16("cab00d1ecab00d1e"),
+			},
+		},
+		{
+			name:   "missing bucket",
+			body:   `{"database":"telegraf","retention_policy":"autogen"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown bucket",
+			body:   `{"database":"telegraf","retention_policy":"autogen","bucket_id":"0000000000000001"}`,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "invalid database",
+			body:   `{"database":"tele/graf","retention_policy":"autogen","bucket_id":"cab00d1ecab00d1e"}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := inmem.NewService()
+			h := NewDBRPHandler(newDBRPTestBackend(svc))
+
+			r := httptest.NewRequest("POST", "/api/v2/dbrps", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+			if tt.want == nil {
+				return
+			}
+
+			m, err := svc.FindBy(context.Background(), tt.want.OrganizationID, tt.want.Cluster, tt.want.Database, tt.want.RetentionPolicy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !m.Equal(tt.want) {
+				t.Errorf("unexpected dbrp mapping: got %+v, want %+v", m, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxql"
+	"go.uber.org/zap"
+)
+
+type predicateDeleter struct {
+	orgID, bucketID platform.ID
+	min, max        int64
+	pred            influxql.Expr
+}
+
+func (d *predicateDeleter) DeleteBucketRangePredicate(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error {
+	d.orgID, d.bucketID, d.min, d.max, d.pred = orgID, bucketID, min, max, pred
+	return nil
+}
+
+func TestDeleteHandler_handleDelete(t *testing.T) {
+	const (
+		orgID    = platform.ID(1)
+		bucketID = platform.ID(2)
+	)
+
+	tests := []struct {
+		name   string
+		body   string
+		perms  []platform.Permission
+		status int
+		pred   string
+	}{
+		{
+			name: "delete with predicate",
+			body: `{"start": "1970-01-01T00:00:00Z", "stop": "1970-01-01T00:00:01Z", "predicate": "_measurement = 'cpu' AND host = 'a'"}`,
+			perms: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: idPtr(orgID)}},
+			},
+			status: http.StatusNoContent,
+			pred:   `_measurement = 'cpu' AND host = 'a'`,
+		},
+		{
+			name: "read only token is forbidden",
+			body: `{"start": "1970-01-01T00:00:00Z", "stop": "1970-01-01T00:00:01Z"}`,
+			perms: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: idPtr(orgID)}},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "invalid predicate",
+			body: `{"start": "1970-01-01T00:00:00Z", "stop": "1970-01-01T00:00:01Z", "predicate": "host = "}`,
+			perms: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: idPtr(orgID)}},
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "missing time range",
+			body: `{"predicate": "host = 'a'"}`,
+			perms: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: idPtr(orgID)}},
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgSvc := mock.NewOrganizationService()
+			orgSvc.FindOrganizationByIDF = func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+				return &platform.Organization{ID: id, Name: "org"}, nil
+			}
+			bucketSvc := mock.NewBucketService()
+			bucketSvc.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID, Name: "bucket"}, nil
+			}
+			deleter := &predicateDeleter{}
+
+			h := NewDeleteHandler(&DeleteBackend{
+				Logger:              zap.NewNop(),
+				PredicateDeleter:    deleter,
+				BucketService:       bucketSvc,
+				OrganizationService: orgSvc,
+			})
+
+			r := httptest.NewRequest("POST", "/api/v2/delete?org="+orgID.String()+"&bucket="+bucketID.String(), strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: tt.perms}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+
+			if tt.status != http.StatusNoContent {
+				return
+			}
+
+			if deleter.orgID != orgID || deleter.bucketID != bucketID {
+				t.Errorf("unexpected org/bucket: got %s/%s", deleter.orgID, deleter.bucketID)
+			}
+			if got, want := deleter.max-deleter.min, int64(1e9); got != want {
+				t.Errorf("unexpected time range: got %d, want %d", got, want)
+			}
+			if got, want := deleter.pred.String(), tt.pred; got != want {
+				t.Errorf("unexpected predicate: got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func idPtr(id platform.ID) *platform.ID {
+	return &id
+}
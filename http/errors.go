@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 )
@@ -64,6 +66,15 @@ func CheckError(resp *http.Response) (err error) {
 	return pe
 }
 
+// setRetryAfter sets the Retry-After header of the response when err tells
+// how long to wait before the request is retried.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	if d := platform.RetryAfter(err); d > 0 {
+		// Retry-After is in whole seconds, so round up to not retry too early.
+		w.Header().Set("Retry-After", strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+	}
+}
+
 // EncodeError encodes err with the appropriate status code and format,
 // sets the X-Platform-Error-Code headers on the response.
 // We're no longer using X-Influx-Error and X-Influx-Reference.
@@ -80,6 +91,7 @@ func EncodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	}
 	w.Header().Set(PlatformErrorCodeHeader, code)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	setRetryAfter(w, err)
 	w.WriteHeader(httpCode)
 	var e error
 	if pe, ok := err.(*platform.Error); ok {
@@ -119,4 +131,5 @@ var statusCodePlatformError = map[string]int{
 	platform.EForbidden:           http.StatusForbidden,
 	platform.EUnauthorized:        http.StatusUnauthorized,
 	platform.EMethodNotAllowed:    http.StatusMethodNotAllowed,
+	platform.ETooManyRequests:     http.StatusTooManyRequests,
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/http"
@@ -49,3 +50,25 @@ func TestEncodeErrorWithError(t *testing.T) {
 		t.Errorf("errors encode err: got %s", w.Body.String())
 	}
 }
+
+func TestEncodeErrorWithQuotaExceeded(t *testing.T) {
+	ctx := context.TODO()
+	err := influxdb.NewQuotaExceededError("test", influxdb.QuotaWriteBytesPerSecond, 100, 1500*time.Millisecond)
+
+	w := httptest.NewRecorder()
+
+	http.EncodeError(ctx, err, w)
+
+	if w.Code != 429 {
+		t.Errorf("expected status code 429, got: %d", w.Code)
+	}
+
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("expected Retry-After: 2, got: %s", got)
+	}
+
+	errHeader := w.Header().Get("X-Platform-Error-Code")
+	if errHeader != influxdb.ETooManyRequests {
+		t.Errorf("expected X-Platform-Error-Code: %s, got: %s", influxdb.ETooManyRequests, errHeader)
+	}
+}
@@ -12,6 +12,7 @@ type PlatformHandler struct {
 	AssetHandler *AssetHandler
 	DocsHandler  http.HandlerFunc
 	APIHandler   http.Handler
+	V1Handler    http.Handler
 }
 
 func setCORSResponseHeaders(w http.ResponseWriter, r *http.Request) {
@@ -43,6 +44,7 @@ func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 		AssetHandler: assetHandler,
 		DocsHandler:  Redoc("/api/v2/swagger.json"),
 		APIHandler:   h,
+		V1Handler:    NewV1Handler(NewV1Backend(b)),
 	}
 }
 
@@ -58,6 +60,12 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The 1.x API authenticates requests itself.
+	if isV1Path(r.URL.Path) {
+		h.V1Handler.ServeHTTP(w, r)
+		return
+	}
+
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
@@ -0,0 +1,669 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/prometheus"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/promql"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/quota"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	prometheusQueryPath      = "/api/v2/prometheus/api/v1/query"
+	prometheusQueryRangePath = "/api/v2/prometheus/api/v1/query_range"
+	prometheusWritePath      = "/api/v2/prometheus/write"
+	prometheusReadPath       = "/api/v2/prometheus/read"
+
+	// defaultPrometheusBucket is the bucket queried if the request does not name one.
+	defaultPrometheusBucket = "prometheus"
+)
+
+// PrometheusBackend is all services and associated parameters required to construct
+// the PrometheusHandler.
+type PrometheusBackend struct {
+	Logger      *zap.Logger
+	MaxBodySize int64
+
+	OrganizationService platform.OrganizationService
+	BucketService       platform.BucketService
+	ProxyQueryService   query.ProxyQueryService
+	PointsWriter        storage.PointsWriter
+	Store               reads.Store
+	UsageRecorder       platform.UsageRecorder
+	QuotaEnforcer       *quota.Enforcer
+}
+
+// NewPrometheusBackend returns a new instance of PrometheusBackend.
+func NewPrometheusBackend(b *APIBackend) *PrometheusBackend {
+	return &PrometheusBackend{
+		Logger:      b.Logger.With(zap.String("handler", "prometheus")),
+		MaxBodySize: b.WriteMaxBodySize,
+
+		OrganizationService: b.OrganizationService,
+		BucketService:       b.BucketService,
+		ProxyQueryService:   b.FluxService,
+		PointsWriter:        b.PointsWriter,
+		Store:               b.ReadStore,
+		UsageRecorder:       b.UsageRecorder,
+		QuotaEnforcer:       b.QuotaEnforcer,
+	}
+}
+
+// PrometheusHandler implements the Prometheus HTTP query API by transpiling
+// PromQL to flux, and the Prometheus remote storage protocol so that a
+// bucket can be the long-term storage of Prometheus servers.
+type PrometheusHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	// MaxBodySize is the maximum size in bytes of the compressed body of a
+	// remote write. The size is not limited if it is zero.
+	MaxBodySize int64
+
+	Now                 func() time.Time
+	OrganizationService platform.OrganizationService
+	BucketService       platform.BucketService
+	ProxyQueryService   query.ProxyQueryService
+	PointsWriter        storage.PointsWriter
+	Store               reads.Store
+
+	// UsageRecorder, if set, records the number and size of the remote
+	// writes of each bucket.
+	UsageRecorder platform.UsageRecorder
+
+	// QuotaEnforcer, if set, limits the rate of the remote writes of each
+	// organization to its write bytes per second quota.
+	QuotaEnforcer *quota.Enforcer
+}
+
+// NewPrometheusHandler returns a new handler at /api/v2/prometheus for PromQL
+// queries and remote storage.
+func NewPrometheusHandler(b *PrometheusBackend) *PrometheusHandler {
+	h := &PrometheusHandler{
+		Router:      NewRouter(),
+		Now:         time.Now,
+		Logger:      b.Logger,
+		MaxBodySize: b.MaxBodySize,
+
+		OrganizationService: b.OrganizationService,
+		BucketService:       b.BucketService,
+		ProxyQueryService:   b.ProxyQueryService,
+		PointsWriter:        b.PointsWriter,
+		Store:               b.Store,
+		UsageRecorder:       b.UsageRecorder,
+		QuotaEnforcer:       b.QuotaEnforcer,
+	}
+
+	h.HandlerFunc("GET", prometheusQueryPath, h.handleQuery)
+	h.HandlerFunc("POST", prometheusQueryPath, h.handleQuery)
+	h.HandlerFunc("GET", prometheusQueryRangePath, h.handleQueryRange)
+	h.HandlerFunc("POST", prometheusQueryRangePath, h.handleQueryRange)
+	h.HandlerFunc("POST", prometheusWritePath, h.handleRemoteWrite)
+	h.HandlerFunc("POST", prometheusReadPath, h.handleRemoteRead)
+	return h
+}
+
+// handleQuery evaluates an instant query at a single point in time.
+func (h *PrometheusHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	t := h.Now()
+	if v := r.FormValue("time"); v != "" {
+		var err error
+		if t, err = parsePrometheusTime(v); err != nil {
+			h.encodeError(w, http.StatusBadRequest, promql.ErrorTypeBadData, fmt.Errorf("invalid parameter \"time\": %v", err))
+			return
+		}
+	}
+
+	h.query(ctx, w, r, promql.Evaluation{
+		Start: t,
+		End:   t,
+	})
+}
+
+// handleQueryRange evaluates a query over a range of time.
+func (h *PrometheusHandler) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	start, err := parsePrometheusTime(r.FormValue("start"))
+	if err != nil {
+		h.encodeError(w, http.StatusBadRequest, promql.ErrorTypeBadData, fmt.Errorf("invalid parameter \"start\": %v", err))
+		return
+	}
+
+	end, err := parsePrometheusTime(r.FormValue("end"))
+	if err != nil {
+		h.encodeError(w, http.StatusBadRequest, promql.ErrorTypeBadData, fmt.Errorf("invalid parameter \"end\": %v", err))
+		return
+	}
+	if end.Before(start) {
+		h.encodeError(w, http.StatusBadRequest, promql.ErrorTypeBadData, fmt.Errorf("invalid parameter \"end\": end timestamp must not be before start time"))
+		return
+	}
+
+	step, err := parsePrometheusDuration(r.FormValue("step"))
+	if err != nil {
+		h.encodeError(w, http.StatusBadRequest, promql.ErrorTypeBadData, fmt.Errorf("invalid parameter \"step\": %v", err))
+		return
+	}
+	if step <= 0 {
+		h.encodeError(w, http.StatusBadRequest, promql.ErrorTypeBadData, fmt.Errorf("zero or negative query resolution step widths are not accepted"))
+		return
+	}
+
+	h.query(ctx, w, r, promql.Evaluation{
+		Start: start,
+		End:   end,
+		Step:  step,
+	})
+}
+
+func (h *PrometheusHandler) query(ctx context.Context, w http.ResponseWriter, r *http.Request, e promql.Evaluation) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.encodeError(w, http.StatusUnauthorized, promql.ErrorTypeBadData, err)
+		return
+	}
+	auth, ok := a.(*platform.Authorization)
+	if !ok {
+		h.encodeError(w, http.StatusForbidden, promql.ErrorTypeBadData, platform.ErrAuthorizerNotSupported)
+		return
+	}
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		code, ok := statusCodePlatformError[platform.ErrorCode(err)]
+		if !ok {
+			code = http.StatusBadRequest
+		}
+		h.encodeError(w, code, promql.ErrorTypeBadData, err)
+		return
+	}
+
+	e.Bucket = r.FormValue("bucket")
+	if e.Bucket == "" {
+		e.Bucket = defaultPrometheusBucket
+	}
+
+	spec, dialect, err := promql.Compile(r.FormValue("query"), e)
+	if err != nil {
+		h.encodeError(w, http.StatusBadRequest, promql.ErrorTypeBadData, err)
+		return
+	}
+
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  auth,
+			OrganizationID: org.ID,
+			Compiler:       lang.SpecCompiler{Spec: spec},
+		},
+		Dialect: dialect,
+	}
+
+	dialect.SetHeaders(w)
+	n, err := h.ProxyQueryService.Query(ctx, w, req)
+	if err != nil {
+		if n == 0 {
+			// Only record the error if nothing has been written to w.
+			h.encodeError(w, http.StatusUnprocessableEntity, promql.ErrorTypeExecution, err)
+			return
+		}
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "prometheus"),
+			zap.Error(err),
+		)
+	}
+}
+
+// encodeError writes err in the error format of the Prometheus query API.
+func (h *PrometheusHandler) encodeError(w http.ResponseWriter, code int, errorType string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(promql.NewErrorResponse(errorType, err)); err != nil {
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "prometheus"),
+			zap.Error(err),
+		)
+	}
+}
+
+// handleRemoteWrite writes the samples of a snappy compressed remote write
+// request to a bucket.
+func (h *PrometheusHandler) handleRemoteWrite(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleRemoteWrite"
+	ctx := r.Context()
+
+	org, bucket, err := h.findRemoteBucket(ctx, r, platform.WriteAction)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{Op: op, Err: err}, w)
+		return
+	}
+
+	meter := writeMeter{UsageRecorder: h.UsageRecorder, QuotaEnforcer: h.QuotaEnforcer}
+	if err := meter.allow(org); err != nil {
+		h.Logger.Info("Write exceeds organization quota", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.MaxBodySize > 0 && r.ContentLength > h.MaxBodySize {
+		encodeBodyTooLarge(w, h.MaxBodySize, 0)
+		return
+	}
+
+	in := r.Body
+	if h.MaxBodySize > 0 {
+		in = http.MaxBytesReader(w, in, h.MaxBodySize)
+	}
+
+	body := &countingReader{r: in}
+	defer meter.record(org, bucket.ID, body)
+
+	var req prometheus.WriteRequest
+	if err := decodeRemoteRequest(body, &req); err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			encodeBodyTooLarge(w, h.MaxBodySize, 0)
+			return
+		}
+		EncodeError(ctx, &platform.Error{Op: op, Err: err}, w)
+		return
+	}
+
+	points, err := prometheus.PointsFromTimeSeries(req.Timeseries)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to convert samples: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	points, err = tsdb.ExplodePoints(org.ID, bucket.ID, points)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to convert samples: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		h.Logger.Error("Error writing points", zap.Error(err))
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRemoteRead responds to a snappy compressed remote read request with
+// the samples of the matching series of a bucket.
+func (h *PrometheusHandler) handleRemoteRead(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleRemoteRead"
+	ctx := r.Context()
+
+	org, bucket, err := h.findRemoteBucket(ctx, r, platform.ReadAction)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{Op: op, Err: err}, w)
+		return
+	}
+
+	var req prometheus.ReadRequest
+	if err := decodeRemoteRequest(r.Body, &req); err != nil {
+		EncodeError(ctx, &platform.Error{Op: op, Err: err}, w)
+		return
+	}
+
+	resp := &prometheus.ReadResponse{
+		Results: make([]*prometheus.QueryResult, 0, len(req.Queries)),
+	}
+	for _, q := range req.Queries {
+		series, err := h.readTimeSeries(ctx, org.ID, bucket.ID, q)
+		if err != nil {
+			EncodeError(ctx, &platform.Error{Op: op, Err: err}, w)
+			return
+		}
+		resp.Results = append(resp.Results, &prometheus.QueryResult{Timeseries: series})
+	}
+
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   op,
+			Msg:  "unable to encode read response",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Encoding", "snappy")
+	if _, err := w.Write(snappy.Encode(nil, data)); err != nil {
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "prometheus"),
+			zap.Error(err),
+		)
+	}
+}
+
+// findRemoteBucket finds the bucket of a remote storage request and checks
+// that the authorizer of the request is allowed to perform action on it.
+func (h *PrometheusHandler) findRemoteBucket(ctx context.Context, r *http.Request, action platform.Action) (*platform.Organization, *platform.Bucket, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := r.URL.Query().Get("bucket")
+	if name == "" {
+		name = defaultPrometheusBucket
+	}
+	bucket, err := findBucketByNameOrID(ctx, h.BucketService, org.ID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, err := platform.NewPermissionAtID(bucket.ID, action, platform.BucketsResourceType, org.ID)
+	if err != nil {
+		return nil, nil, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}
+	}
+	if !a.Allowed(*p) {
+		return nil, nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions for %s", action),
+		}
+	}
+	return org, bucket, nil
+}
+
+// readTimeSeries reads the series of a bucket that match a remote read query.
+func (h *PrometheusHandler) readTimeSeries(ctx context.Context, orgID, bucketID platform.ID, q *prometheus.Query) ([]*prometheus.TimeSeries, error) {
+	src, err := h.Store.GetSource(influxdb.ReadSpec{
+		OrganizationID: orgID,
+		BucketID:       bucketID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var req datatypes.ReadRequest
+	if req.ReadSource, err = types.MarshalAny(src); err != nil {
+		return nil, err
+	}
+	if req.Predicate, err = remoteReadPredicate(q.Matchers); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  err.Error(),
+			Err:  err,
+		}
+	}
+	req.TimestampRange.Start = q.StartTimestampMs * int64(time.Millisecond)
+	req.TimestampRange.End = q.EndTimestampMs * int64(time.Millisecond)
+
+	rs, err := h.Store.Read(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]*prometheus.TimeSeries, 0)
+	if rs == nil {
+		return series, nil
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		samples, err := readSamples(cur)
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) == 0 {
+			continue
+		}
+
+		// The store names the measurement and field tags as flux does.
+		ts := &prometheus.TimeSeries{Samples: samples}
+		for _, tag := range rs.Tags() {
+			switch string(tag.Key) {
+			case "_field":
+				continue
+			case "_measurement":
+				ts.Labels = append(ts.Labels, &prometheus.Label{Name: prometheus.MetricNameLabel, Value: string(tag.Value)})
+			default:
+				ts.Labels = append(ts.Labels, &prometheus.Label{Name: string(tag.Key), Value: string(tag.Value)})
+			}
+		}
+		prometheus.SortLabels(ts.Labels)
+		series = append(series, ts)
+	}
+	return series, rs.Err()
+}
+
+// readSamples reads every numeric value of a cursor as a sample and closes it.
+func readSamples(cur cursors.Cursor) ([]*prometheus.Sample, error) {
+	defer cur.Close()
+
+	var samples []*prometheus.Sample
+	appendSample := func(ts int64, v float64) {
+		samples = append(samples, &prometheus.Sample{
+			Value:     v,
+			Timestamp: ts / int64(time.Millisecond),
+		})
+	}
+
+	switch c := cur.(type) {
+	case cursors.FloatArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for i, ts := range a.Timestamps {
+				appendSample(ts, a.Values[i])
+			}
+		}
+	case cursors.IntegerArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for i, ts := range a.Timestamps {
+				appendSample(ts, float64(a.Values[i]))
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for i, ts := range a.Timestamps {
+				appendSample(ts, float64(a.Values[i]))
+			}
+		}
+	}
+	return samples, cur.Err()
+}
+
+// remoteReadPredicate builds the storage predicate that selects the value
+// fields of the series matched by all of matchers.
+func remoteReadPredicate(matchers []*prometheus.LabelMatcher) (*datatypes.Predicate, error) {
+	var fields *datatypes.Node
+	for _, f := range promql.ValueFields {
+		eq := comparisonNode(datatypes.ComparisonEqual, tsdb.FieldKeyTagKey, stringLiteralNode(f))
+		if fields == nil {
+			fields = eq
+			continue
+		}
+		fields = logicalNode(datatypes.LogicalOr, fields, eq)
+	}
+
+	root := fields
+	for _, m := range matchers {
+		key := m.Name
+		if key == prometheus.MetricNameLabel {
+			key = tsdb.MeasurementTagKey
+		}
+
+		var node *datatypes.Node
+		switch m.Type {
+		case prometheus.MatchEqual:
+			node = comparisonNode(datatypes.ComparisonEqual, key, stringLiteralNode(m.Value))
+		case prometheus.MatchNotEqual:
+			node = comparisonNode(datatypes.ComparisonNotEqual, key, stringLiteralNode(m.Value))
+		case prometheus.MatchRegexp, prometheus.MatchNotRegexp:
+			// Like Prometheus, the expression must match the whole label value.
+			re := "^(?:" + m.Value + ")$"
+			if _, err := regexp.Compile(re); err != nil {
+				return nil, fmt.Errorf("invalid regular expression for label %q: %v", m.Name, err)
+			}
+			cmp := datatypes.ComparisonRegex
+			if m.Type == prometheus.MatchNotRegexp {
+				cmp = datatypes.ComparisonNotRegex
+			}
+			node = comparisonNode(cmp, key, regexLiteralNode(re))
+		default:
+			return nil, fmt.Errorf("unknown label matcher type %d", m.Type)
+		}
+		root = logicalNode(datatypes.LogicalAnd, root, node)
+	}
+	return &datatypes.Predicate{Root: root}, nil
+}
+
+func comparisonNode(cmp datatypes.Node_Comparison, key string, literal *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: cmp},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			literal,
+		},
+	}
+}
+
+func stringLiteralNode(v string) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLiteral,
+		Value:    &datatypes.Node_StringValue{StringValue: v},
+	}
+}
+
+func regexLiteralNode(v string) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLiteral,
+		Value:    &datatypes.Node_RegexValue{RegexValue: v},
+	}
+}
+
+func logicalNode(op datatypes.Node_Logical, lhs, rhs *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLogicalExpression,
+		Value:    &datatypes.Node_Logical_{Logical: op},
+		Children: []*datatypes.Node{lhs, rhs},
+	}
+}
+
+// decodeRemoteRequest decodes the snappy compressed protobuf body of a remote
+// storage request into msg. The *http.MaxBytesError of a body larger than
+// its limit is returned as is.
+func decodeRemoteRequest(r io.Reader, msg proto.Message) error {
+	compressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			return err
+		}
+		return &platform.Error{
+			Code: platform.EInternal,
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		}
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("unable to decompress request: %v", err),
+			Err:  err,
+		}
+	}
+
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("unable to decode request: %v", err),
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// parsePrometheusTime parses a timestamp as either a unix timestamp in
+// seconds or an RFC3339 time.
+func parsePrometheusTime(s string) (time.Time, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// parsePrometheusDuration parses a duration as either a number of seconds or
+// a Go duration string.
+func parsePrometheusDuration(s string) (time.Duration, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		d := f * float64(time.Second)
+		if d > float64(math.MaxInt64) || d < float64(math.MinInt64) {
+			return 0, fmt.Errorf("cannot parse %q to a valid duration. It overflows int64", s)
+		}
+		return time.Duration(d), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
@@ -0,0 +1,409 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/prometheus"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/promql"
+	"github.com/influxdata/influxdb/quota"
+	"go.uber.org/zap"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	orgID := platform.ID(1)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		status     int
+		errorType  string
+		resultType promql.ResultType
+		queryErr   error
+	}{
+		{
+			name:       "instant query",
+			method:     "GET",
+			path:       "/api/v2/prometheus/api/v1/query?orgID=0000000000000001&query=up&time=1500000000",
+			status:     http.StatusOK,
+			resultType: promql.VectorResult,
+		},
+		{
+			name:       "range query",
+			method:     "POST",
+			path:       "/api/v2/prometheus/api/v1/query_range?orgID=0000000000000001&query=sum(up)&start=2017-07-14T02:40:00Z&end=2017-07-14T03:40:00Z&step=1m",
+			status:     http.StatusOK,
+			resultType: promql.MatrixResult,
+		},
+		{
+			name:      "invalid query",
+			method:    "GET",
+			path:      "/api/v2/prometheus/api/v1/query?orgID=0000000000000001&query=sum(",
+			status:    http.StatusBadRequest,
+			errorType: promql.ErrorTypeBadData,
+		},
+		{
+			name:      "invalid time",
+			method:    "GET",
+			path:      "/api/v2/prometheus/api/v1/query?orgID=0000000000000001&query=up&time=yesterday",
+			status:    http.StatusBadRequest,
+			errorType: promql.ErrorTypeBadData,
+		},
+		{
+			name:      "zero step",
+			method:    "GET",
+			path:      "/api/v2/prometheus/api/v1/query_range?orgID=0000000000000001&query=up&start=1500000000&end=1500003600&step=0",
+			status:    http.StatusBadRequest,
+			errorType: promql.ErrorTypeBadData,
+		},
+		{
+			name:      "range vector in range query",
+			method:    "GET",
+			path:      "/api/v2/prometheus/api/v1/query_range?orgID=0000000000000001&query=up[5m]&start=1500000000&end=1500003600&step=60",
+			status:    http.StatusBadRequest,
+			errorType: promql.ErrorTypeBadData,
+		},
+		{
+			name:      "execution error",
+			method:    "GET",
+			path:      "/api/v2/prometheus/api/v1/query?orgID=0000000000000001&query=up",
+			status:    http.StatusUnprocessableEntity,
+			errorType: promql.ErrorTypeExecution,
+			queryErr:  fmt.Errorf("bucket not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewPrometheusHandler(&PrometheusBackend{
+				Logger: zap.NewNop(),
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: orgID}, nil
+					},
+				},
+				ProxyQueryService: &mock.ProxyQueryService{
+					QueryFn: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (int64, error) {
+						if tt.queryErr != nil {
+							return 0, tt.queryErr
+						}
+						if req.Request.OrganizationID != orgID {
+							t.Errorf("unexpected organization: %s", req.Request.OrganizationID)
+						}
+						d := req.Dialect.(*promql.Dialect)
+						resp := promql.Response{
+							Status: "success",
+							Data:   &promql.ResponseData{ResultType: d.ResultType},
+						}
+						return 0, json.NewEncoder(w).Encode(resp)
+					},
+				},
+			})
+			h.Now = func() time.Time { return time.Unix(1500000000, 0) }
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+
+			var resp promql.Response
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := resp.ErrorType, tt.errorType; got != want {
+				t.Errorf("unexpected error type: got %q, want %q: %s", got, want, resp.Error)
+			}
+			if tt.resultType != "" {
+				if resp.Data == nil || resp.Data.ResultType != tt.resultType {
+					t.Errorf("unexpected result type: got %+v, want %q", resp.Data, tt.resultType)
+				}
+			}
+		})
+	}
+}
+
+func TestPrometheusHandler_RemoteWrite(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	writeBucket := []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+	readBucket := []platform.Permission{
+		{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+
+	encode := func(req *prometheus.WriteRequest) []byte {
+		data, err := proto.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return snappy.Encode(nil, data)
+	}
+
+	tests := []struct {
+		name   string
+		body   []byte
+		perms  []platform.Permission
+		status int
+		points int
+	}{
+		{
+			name: "write samples",
+			body: encode(&prometheus.WriteRequest{
+				Timeseries: []*prometheus.TimeSeries{{
+					Labels:  []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+					Samples: []*prometheus.Sample{{Value: 1, Timestamp: 1500000000000}, {Value: 0, Timestamp: 1500000015000}},
+				}},
+			}),
+			perms:  writeBucket,
+			status: http.StatusNoContent,
+			points: 2,
+		},
+		{
+			name: "missing metric name",
+			body: encode(&prometheus.WriteRequest{
+				Timeseries: []*prometheus.TimeSeries{{
+					Labels:  []*prometheus.Label{{Name: "job", Value: "api"}},
+					Samples: []*prometheus.Sample{{Value: 1, Timestamp: 1500000000000}},
+				}},
+			}),
+			perms:  writeBucket,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "uncompressed body",
+			body:   []byte("up 1"),
+			perms:  writeBucket,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "missing write permission",
+			body:   encode(&prometheus.WriteRequest{}),
+			perms:  readBucket,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := NewPrometheusHandler(&PrometheusBackend{
+				Logger: zap.NewNop(),
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: orgID}, nil
+					},
+				},
+				BucketService: &mock.BucketService{
+					FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+						if filter.Name == nil || *filter.Name != "prometheus" {
+							t.Errorf("unexpected bucket filter: %+v", filter)
+						}
+						return &platform.Bucket{ID: bucketID, OrganizationID: orgID}, nil
+					},
+				},
+				PointsWriter: pw,
+			})
+
+			r := httptest.NewRequest("POST", "/api/v2/prometheus/write?orgID=0000000000000001", bytes.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: tt.perms}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+			if got, want := len(pw.Points), tt.points; got != want {
+				t.Errorf("unexpected number of points written: got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestPrometheusHandler_RemoteWrite_MaxBodySize(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	data, err := proto.Marshal(&prometheus.WriteRequest{
+		Timeseries: []*prometheus.TimeSeries{{
+			Labels:  []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+			Samples: []*prometheus.Sample{{Value: 1, Timestamp: 1500000000000}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := snappy.Encode(nil, data)
+
+	for _, contentLength := range []int64{int64(len(body)), -1} {
+		t.Run(fmt.Sprintf("content length %d", contentLength), func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := NewPrometheusHandler(&PrometheusBackend{
+				Logger:      zap.NewNop(),
+				MaxBodySize: 16,
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: orgID}, nil
+					},
+				},
+				BucketService: &mock.BucketService{
+					FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+						return &platform.Bucket{ID: bucketID, OrganizationID: orgID}, nil
+					},
+				},
+				PointsWriter: pw,
+			})
+
+			r := httptest.NewRequest("POST", "/api/v2/prometheus/write?orgID=0000000000000001", bytes.NewReader(body))
+			r.ContentLength = contentLength
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: platform.OperPermissions(),
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, http.StatusRequestEntityTooLarge; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+			if eq, diff, err := jsonEqual(w.Body.String(), `{
+  "code": "invalid",
+  "message": "unable to read data: body exceeds the maximum size of 16 bytes",
+  "maxLength": 16
+}`); err != nil || !eq {
+				t.Errorf("unexpected body -got/+want\ndiff %s", diff)
+			}
+			if len(pw.Points) != 0 {
+				t.Errorf("unexpected points written: %d", len(pw.Points))
+			}
+		})
+	}
+}
+
+func TestPrometheusHandler_RemoteWrite_Usage(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	data, err := proto.Marshal(&prometheus.WriteRequest{
+		Timeseries: []*prometheus.TimeSeries{{
+			Labels:  []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+			Samples: []*prometheus.Sample{{Value: 1, Timestamp: 1500000000000}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := snappy.Encode(nil, data)
+
+	rec := usageRecorder{}
+	h := NewPrometheusHandler(&PrometheusBackend{
+		Logger: zap.NewNop(),
+		OrganizationService: &mock.OrganizationService{
+			FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+				return &platform.Organization{ID: orgID}, nil
+			},
+		},
+		BucketService: &mock.BucketService{
+			FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: bucketID, OrganizationID: orgID}, nil
+			},
+		},
+		PointsWriter:  &mock.PointsWriter{},
+		UsageRecorder: rec,
+	})
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest("POST", "/api/v2/prometheus/write?orgID=0000000000000001", bytes.NewReader(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: platform.OperPermissions(),
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got, want := w.Code, http.StatusNoContent; got != want {
+			t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+		}
+	}
+
+	want := usageRecorder{
+		usageKey{orgID, bucketID, platform.UsageWriteRequestCount}: 2,
+		usageKey{orgID, bucketID, platform.UsageWriteRequestBytes}: float64(2 * len(body)),
+	}
+	if diff := cmp.Diff(rec, want); diff != "" {
+		t.Errorf("unexpected usage -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestPrometheusHandler_RemoteWrite_Quota(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	data, err := proto.Marshal(&prometheus.WriteRequest{
+		Timeseries: []*prometheus.TimeSeries{{
+			Labels:  []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+			Samples: []*prometheus.Sample{{Value: 1, Timestamp: 1500000000000}, {Value: 0, Timestamp: 1500000015000}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := snappy.Encode(nil, data)
+
+	org := &platform.Organization{ID: orgID, Quotas: &platform.OrgQuotas{WriteBytesPerSecond: 10}}
+	orgs := &mock.OrganizationService{
+		FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+			return org, nil
+		},
+		FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			return org, nil
+		},
+	}
+	h := NewPrometheusHandler(&PrometheusBackend{
+		Logger:              zap.NewNop(),
+		OrganizationService: orgs,
+		BucketService: &mock.BucketService{
+			FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: bucketID, OrganizationID: orgID}, nil
+			},
+		},
+		PointsWriter:  &mock.PointsWriter{},
+		QuotaEnforcer: quota.NewEnforcer(orgs),
+	})
+
+	// The first write spends more than the quota allows, so the second
+	// write must wait.
+	for _, want := range []int{http.StatusNoContent, http.StatusTooManyRequests} {
+		r := httptest.NewRequest("POST", "/api/v2/prometheus/write?orgID=0000000000000001", bytes.NewReader(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: platform.OperPermissions(),
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got := w.Code; got != want {
+			t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+		}
+		if want == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Fatal("expected Retry-After header")
+		}
+	}
+}
@@ -865,6 +865,15 @@ func decodeGetLogsRequest(ctx context.Context, r *http.Request) (*getLogsRequest
 		req.filter.Run = id
 	}
 
+	qp := r.URL.Query()
+
+	if req.filter.After, req.filter.Limit, err = decodeRunPage(qp); err != nil {
+		return nil, err
+	}
+	if req.filter.AfterTime, req.filter.BeforeTime, err = decodeTimeRange(qp); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -947,56 +956,87 @@ func decodeGetRunsRequest(ctx context.Context, r *http.Request) (*getRunsRequest
 
 	qp := r.URL.Query()
 
+	if req.filter.After, req.filter.Limit, err = decodeRunPage(qp); err != nil {
+		return nil, err
+	}
+	if req.filter.AfterTime, req.filter.BeforeTime, err = decodeTimeRange(qp); err != nil {
+		return nil, err
+	}
+
+	if status := qp.Get("status"); status != "" {
+		switch status {
+		case backend.RunStarted.String(), backend.RunSuccess.String(), backend.RunFail.String(), backend.RunCanceled.String():
+			req.filter.Status = status
+		default:
+			return nil, &platform.Error{
+				Code: platform.EUnprocessableEntity,
+				Msg:  fmt.Sprintf("unknown run status %q", status),
+			}
+		}
+	}
+
+	return req, nil
+}
+
+// decodeRunPage decodes the after and limit query parameters, which page
+// through runs and their logs by run ID.
+func decodeRunPage(qp url.Values) (*platform.ID, int, error) {
+	var after *platform.ID
 	if id := qp.Get("after"); id != "" {
 		afterID, err := platform.IDFromString(id)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
-		req.filter.After = afterID
+		after = afterID
 	}
 
-	if limit := qp.Get("limit"); limit != "" {
-		i, err := strconv.Atoi(limit)
+	var limit int
+	if l := qp.Get("limit"); l != "" {
+		i, err := strconv.Atoi(l)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		if i < 1 || i > 100 {
-			return nil, &platform.Error{
+			return nil, 0, &platform.Error{
 				Code: platform.EUnprocessableEntity,
 				Msg:  "limit must be between 1 and 100",
 			}
 		}
 
-		req.filter.Limit = i
+		limit = i
 	}
 
+	return after, limit, nil
+}
+
+// decodeTimeRange decodes the afterTime and beforeTime query parameters.
+func decodeTimeRange(qp url.Values) (string, string, error) {
 	var at, bt string
 	var afterTime, beforeTime time.Time
+	var err error
 	if at = qp.Get("afterTime"); at != "" {
 		afterTime, err = time.Parse(time.RFC3339, at)
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
-		req.filter.AfterTime = at
 	}
 
 	if bt = qp.Get("beforeTime"); bt != "" {
 		beforeTime, err = time.Parse(time.RFC3339, bt)
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
-		req.filter.BeforeTime = bt
 	}
 
 	if at != "" && bt != "" && !beforeTime.After(afterTime) {
-		return nil, &platform.Error{
+		return "", "", &platform.Error{
 			Code: platform.EUnprocessableEntity,
 			Msg:  "beforeTime must be later than afterTime",
 		}
 	}
 
-	return req, nil
+	return at, bt, nil
 }
 
 func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
@@ -1600,6 +1640,20 @@ func (t TaskService) FindLogs(ctx context.Context, filter platform.LogFilter) ([
 		return nil, 0, err
 	}
 
+	val := url.Values{}
+	if filter.After != nil {
+		val.Set("after", filter.After.String())
+	}
+	if filter.Limit > 0 {
+		val.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if filter.AfterTime != "" {
+		val.Set("afterTime", filter.AfterTime)
+	}
+	if filter.BeforeTime != "" {
+		val.Set("beforeTime", filter.BeforeTime)
+	}
+	u.RawQuery = val.Encode()
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, 0, err
@@ -1644,6 +1698,15 @@ func (t TaskService) FindRuns(ctx context.Context, filter platform.RunFilter) ([
 	if filter.Limit > 0 {
 		val.Set("limit", strconv.Itoa(filter.Limit))
 	}
+	if filter.AfterTime != "" {
+		val.Set("afterTime", filter.AfterTime)
+	}
+	if filter.BeforeTime != "" {
+		val.Set("beforeTime", filter.BeforeTime)
+	}
+	if filter.Status != "" {
+		val.Set("status", filter.Status)
+	}
 	u.RawQuery = val.Encode()
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/inmem"
@@ -1604,3 +1605,119 @@ func TestTaskHandler_Sessions(t *testing.T) {
 		}
 	})
 }
+
+func Test_decodeGetRunsRequest(t *testing.T) {
+	taskID := platformtesting.MustIDBase16("1111111111111111")
+	afterID := platformtesting.MustIDBase16("2222222222222222")
+
+	tests := []struct {
+		name    string
+		query   string
+		want    platform.RunFilter
+		wantErr string
+	}{
+		{
+			name:  "task only",
+			query: "",
+			want:  platform.RunFilter{Task: taskID},
+		},
+		{
+			name:  "filters and page",
+			query: "?after=2222222222222222&limit=10&afterTime=2019-01-01T00:00:00Z&beforeTime=2019-01-02T00:00:00Z&status=failed",
+			want: platform.RunFilter{
+				Task:       taskID,
+				After:      &afterID,
+				Limit:      10,
+				AfterTime:  "2019-01-01T00:00:00Z",
+				BeforeTime: "2019-01-02T00:00:00Z",
+				Status:     "failed",
+			},
+		},
+		{
+			name:    "unknown status",
+			query:   "?status=scheduled",
+			wantErr: `unknown run status "scheduled"`,
+		},
+		{
+			name:    "limit out of range",
+			query:   "?limit=101",
+			wantErr: "limit must be between 1 and 100",
+		},
+		{
+			name:    "inverted time range",
+			query:   "?afterTime=2019-01-02T00:00:00Z&beforeTime=2019-01-01T00:00:00Z",
+			wantErr: "beforeTime must be later than afterTime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://any.url"+tt.query, nil)
+			ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: taskID.String()}})
+
+			req, err := decodeGetRunsRequest(ctx, r)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, req.filter); diff != "" {
+				t.Errorf("unexpected run filter -want/+got\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_decodeGetLogsRequest(t *testing.T) {
+	taskID := platformtesting.MustIDBase16("1111111111111111")
+	runID := platformtesting.MustIDBase16("3333333333333333")
+	afterID := platformtesting.MustIDBase16("2222222222222222")
+
+	tests := []struct {
+		name   string
+		params httprouter.Params
+		query  string
+		want   platform.LogFilter
+	}{
+		{
+			name:   "task logs",
+			params: httprouter.Params{{Key: "id", Value: taskID.String()}},
+			query:  "?after=2222222222222222&limit=5&afterTime=2019-01-01T00:00:00Z",
+			want: platform.LogFilter{
+				Task:      taskID,
+				After:     &afterID,
+				Limit:     5,
+				AfterTime: "2019-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:   "run logs",
+			params: httprouter.Params{{Key: "id", Value: taskID.String()}, {Key: "rid", Value: runID.String()}},
+			query:  "?beforeTime=2019-01-02T00:00:00Z",
+			want: platform.LogFilter{
+				Task:       taskID,
+				Run:        &runID,
+				BeforeTime: "2019-01-02T00:00:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://any.url"+tt.query, nil)
+			ctx := context.WithValue(context.Background(), httprouter.ParamsKey, tt.params)
+
+			req, err := decodeGetLogsRequest(ctx, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, req.filter); diff != "" {
+				t.Errorf("unexpected log filter -want/+got\n%s", diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ type TelegrafBackend struct {
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 	OrganizationService        platform.OrganizationService
+	SecretService              platform.SecretService
 }
 
 // NewTelegrafBackend returns a new instance of TelegrafBackend.
@@ -36,6 +37,7 @@ func NewTelegrafBackend(b *APIBackend) *TelegrafBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		SecretService:              b.SecretService,
 	}
 }
 
@@ -49,6 +51,10 @@ type TelegrafHandler struct {
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 	OrganizationService        platform.OrganizationService
+
+	// SecretService, if set, resolves the secrets referenced by the plugins
+	// of the configs rendered for tokens that may read them.
+	SecretService platform.SecretService
 }
 
 const (
@@ -60,6 +66,7 @@ const (
 	telegrafsIDOwnersIDPath  = "/api/v2/telegrafs/:id/owners/:userID"
 	telegrafsIDLabelsPath    = "/api/v2/telegrafs/:id/labels"
 	telegrafsIDLabelsIDPath  = "/api/v2/telegrafs/:id/labels/:lid"
+	telegrafPluginsPath      = "/api/v2/telegraf/plugins"
 )
 
 // NewTelegrafHandler returns a new instance of TelegrafHandler.
@@ -73,12 +80,14 @@ func NewTelegrafHandler(b *TelegrafBackend) *TelegrafHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		SecretService:              b.SecretService,
 	}
 	h.HandlerFunc("POST", telegrafsPath, h.handlePostTelegraf)
 	h.HandlerFunc("GET", telegrafsPath, h.handleGetTelegrafs)
 	h.HandlerFunc("GET", telegrafsIDPath, h.handleGetTelegraf)
 	h.HandlerFunc("DELETE", telegrafsIDPath, h.handleDeleteTelegraf)
 	h.HandlerFunc("PUT", telegrafsIDPath, h.handlePutTelegraf)
+	h.HandlerFunc("GET", telegrafPluginsPath, h.handleGetTelegrafPlugins)
 
 	memberBackend := MemberBackend{
 		Logger:                     b.Logger.With(zap.String("handler", "member")),
@@ -193,6 +202,22 @@ func (h *TelegrafHandler) handleGetTelegrafs(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+type telegrafPluginsResponse struct {
+	Plugins []platform.TelegrafPluginInfo `json:"plugins"`
+}
+
+// handleGetTelegrafPlugins is the HTTP handler for the GET /api/v2/telegraf/plugins route.
+func (h *TelegrafHandler) handleGetTelegrafPlugins(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res := telegrafPluginsResponse{
+		Plugins: platform.TelegrafPlugins(),
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id, err := decodeGetTelegrafRequest(ctx, r)
@@ -211,10 +236,15 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 	mimeType := httputil.NegotiateContentType(r, offers, defaultOffer)
 	switch mimeType {
 	case "application/octet-stream":
+		toml, err := h.renderTOML(ctx, tc)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
 		w.Header().Set("Content-Type", "application/octet-stream")
 		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.toml\"", strings.Replace(strings.TrimSpace(tc.Name), " ", "_", -1)))
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(tc.TOML()))
+		w.Write([]byte(toml))
 	case "application/json":
 		labels, err := h.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: tc.ID})
 		if err != nil {
@@ -227,12 +257,78 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 			return
 		}
 	case "application/toml":
+		toml, err := h.renderTOML(ctx, tc)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
 		w.Header().Set("Content-Type", "application/toml; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(tc.TOML()))
+		w.Write([]byte(toml))
 	}
 }
 
+// renderTOML renders the toml of the config with the values of the secrets
+// it references if the token may read the secrets of the organization.
+// Otherwise the secrets are rendered as environment variable placeholders.
+func (h *TelegrafHandler) renderTOML(ctx context.Context, tc *platform.TelegrafConfig) (string, error) {
+	keys := tc.SecretKeys()
+	if len(keys) == 0 || h.SecretService == nil {
+		return tc.TOML(), nil
+	}
+
+	a, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		return "", err
+	}
+	p, err := platform.NewPermission(platform.ReadAction, platform.SecretsResourceType, tc.OrganizationID)
+	if err != nil {
+		return "", err
+	}
+	if !a.Allowed(*p) {
+		return tc.TOML(), nil
+	}
+
+	secrets := make(map[string]string, len(keys))
+	for _, k := range keys {
+		v, err := h.SecretService.LoadSecret(ctx, tc.OrganizationID, k)
+		if platform.ErrorCode(err) == platform.ENotFound {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		secrets[k] = v
+	}
+	return tc.TOMLWithSecrets(secrets), nil
+}
+
+// validateSecrets returns an error if the config references a secret that
+// the organization does not have.
+func (h *TelegrafHandler) validateSecrets(ctx context.Context, orgID platform.ID, tc *platform.TelegrafConfig) error {
+	keys := tc.SecretKeys()
+	if len(keys) == 0 || h.SecretService == nil {
+		return nil
+	}
+
+	existing, err := h.SecretService.GetSecretKeys(ctx, orgID)
+	if err != nil && platform.ErrorCode(err) != platform.ENotFound {
+		return err
+	}
+	has := make(map[string]bool, len(existing))
+	for _, k := range existing {
+		has[k] = true
+	}
+	for _, k := range keys {
+		if !has[k] {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("telegraf config references secret %q that does not exist", k),
+			}
+		}
+	}
+	return nil
+}
+
 func decodeTelegrafConfigFilter(ctx context.Context, r *http.Request) (*platform.TelegrafConfigFilter, error) {
 	f := &platform.TelegrafConfigFilter{}
 	urm, err := decodeUserResourceMappingFilter(ctx, r)
@@ -322,6 +418,11 @@ func (h *TelegrafHandler) handlePostTelegraf(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if err := h.validateSecrets(ctx, tc.OrganizationID, tc); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err := h.TelegrafService.CreateTelegrafConfig(ctx, tc, auth.GetUserID()); err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -348,6 +449,18 @@ func (h *TelegrafHandler) handlePutTelegraf(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if len(tc.SecretKeys()) > 0 {
+		current, err := h.TelegrafService.FindTelegrafConfigByID(ctx, tc.ID)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		if err := h.validateSecrets(ctx, current.OrganizationID, tc); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+	}
+
 	tc, err = h.TelegrafService.UpdateTelegrafConfig(ctx, tc.ID, tc, auth.GetUserID())
 	if err != nil {
 		EncodeError(ctx, err, w)
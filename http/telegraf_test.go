@@ -10,10 +10,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"go.uber.org/zap"
 
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/telegraf/plugins"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
 )
@@ -730,6 +733,142 @@ func TestTelegrafHandler_handleGetTelegraf(t *testing.T) {
 	}
 }
 
+func TestTelegrafHandler_handleGetTelegraf_Secrets(t *testing.T) {
+	orgID := platform.ID(2)
+	tc := &platform.TelegrafConfig{
+		ID:             platform.ID(1),
+		OrganizationID: orgID,
+		Name:           "my config",
+		Plugins: []platform.TelegrafPlugin{
+			{
+				Config: &outputs.InfluxDBV2{
+					URLs:         []string{"http://127.0.0.1:9999"},
+					Token:        platform.TelegrafSecretRef("token"),
+					Organization: "my_org",
+					Bucket:       "my_bucket",
+				},
+			},
+		},
+	}
+
+	readSecrets, err := platform.NewPermission(platform.ReadAction, platform.SecretsResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readTelegrafs, err := platform.NewPermission(platform.ReadAction, platform.TelegrafsResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		permissions  []platform.Permission
+		acceptHeader string
+		want         string
+		notWant      string
+	}{
+		{
+			name:         "token may read secrets",
+			permissions:  []platform.Permission{*readTelegrafs, *readSecrets},
+			acceptHeader: "application/toml",
+			want:         `token = "no_more_secrets"`,
+		},
+		{
+			name:         "token may not read secrets",
+			permissions:  []platform.Permission{*readTelegrafs},
+			acceptHeader: "application/toml",
+			want:         `token = "${INFLUX_SECRET_TOKEN}"`,
+			notWant:      "no_more_secrets",
+		},
+		{
+			name:         "json never has secret values",
+			permissions:  []platform.Permission{*readTelegrafs, *readSecrets},
+			acceptHeader: "application/json",
+			want:         `"token":"${secret:token}"`,
+			notWant:      "no_more_secrets",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telegrafBackend := NewMockTelegrafBackend()
+			telegrafBackend.TelegrafService = &mock.TelegrafConfigStore{
+				FindTelegrafConfigByIDF: func(ctx context.Context, id platform.ID) (*platform.TelegrafConfig, error) {
+					return tc, nil
+				},
+			}
+			telegrafBackend.SecretService = &mock.SecretService{
+				LoadSecretFn: func(ctx context.Context, id platform.ID, k string) (string, error) {
+					if id != orgID || k != "token" {
+						t.Fatalf("unexpected secret %s of organization %s", k, id)
+					}
+					return "no_more_secrets", nil
+				},
+			}
+			h := NewTelegrafHandler(telegrafBackend)
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/telegrafs/0000000000000001", nil)
+			r.Header.Set("Accept", tt.acceptHeader)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d", w.Code, http.StatusOK)
+			}
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("body does not contain %s:\n%s", tt.want, body)
+			}
+			if tt.notWant != "" && strings.Contains(body, tt.notWant) {
+				t.Errorf("body contains %s:\n%s", tt.notWant, body)
+			}
+		})
+	}
+}
+
+func TestTelegrafHandler_handleGetTelegrafPlugins(t *testing.T) {
+	h := NewTelegrafHandler(NewMockTelegrafBackend())
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/telegraf/plugins", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", w.Code, http.StatusOK)
+	}
+	var res telegrafPluginsResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+
+	var file *platform.TelegrafPluginInfo
+	for i, p := range res.Plugins {
+		if p.Type == plugins.Output && p.Name == "file" {
+			file = &res.Plugins[i]
+		}
+	}
+	if file == nil {
+		t.Fatalf("file output plugin is missing: %+v", res.Plugins)
+	}
+	want := []platform.TelegrafPluginField{
+		{
+			Name:  "files",
+			Type:  "array",
+			Items: "object",
+			Fields: []platform.TelegrafPluginField{
+				{Name: "type", Type: "string"},
+				{Name: "path", Type: "string"},
+			},
+		},
+	}
+	if diff := cmp.Diff(file.Fields, want); diff != "" {
+		t.Errorf("unexpected file output plugin fields -got/+want\ndiff %s", diff)
+	}
+}
+
 func Test_newTelegrafResponses(t *testing.T) {
 	type args struct {
 		tcs []*platform.TelegrafConfig
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// DefaultTLSMinVersion is the default minimum TLS version of the server.
+const DefaultTLSMinVersion = "1.2"
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion returns the TLS version of a version name such as "1.2".
+func ParseTLSVersion(name string) (uint16, error) {
+	v, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(name), "tls")]
+	if !ok {
+		names := make([]string, 0, len(tlsVersions))
+		for n := range tlsVersions {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return 0, fmt.Errorf("unknown TLS version %q; expected one of %s", name, strings.Join(names, ", "))
+	}
+	return v, nil
+}
+
+// ParseCipherSuites returns the IDs of the cipher suites with the given names,
+// for example TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	suites := make(map[string]uint16)
+	for _, s := range tls.CipherSuites() {
+		suites[s.Name] = s.ID
+	}
+	for _, s := range tls.InsecureCipherSuites() {
+		suites[s.Name] = s.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := suites[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// TLSOptions are the TLS settings of the HTTP server.
+type TLSOptions struct {
+	// CertFile and KeyFile are the paths to the PEM encoded certificate and key of the server.
+	CertFile string
+	KeyFile  string
+
+	// MinVersion is the minimum TLS version, DefaultTLSMinVersion if empty.
+	MinVersion string
+	// CipherSuites are the names of the allowed cipher suites of TLS 1.2 and below.
+	// All secure cipher suites are allowed if empty.
+	CipherSuites []string
+
+	// ClientCAFile is the path to the PEM encoded certificates of the authorities
+	// that sign client certificates. Clients may authenticate with a certificate
+	// signed by one of the authorities if it is set.
+	ClientCAFile string
+}
+
+// NewTLSConfig returns the TLS configuration of a server with the options.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	minVersion := opts.MinVersion
+	if minVersion == "" {
+		minVersion = DefaultTLSMinVersion
+	}
+	version, err := ParseTLSVersion(minVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   version,
+	}
+
+	if len(opts.CipherSuites) > 0 {
+		if config.CipherSuites, err = ParseCipherSuites(opts.CipherSuites); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ClientCAFile != "" {
+		pem, err := ioutil.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS client CA: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in TLS client CA %s", opts.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		// Clients without a certificate still authenticate with a token or session.
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return config, nil
+}
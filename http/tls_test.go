@@ -0,0 +1,241 @@
+package http_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/mock"
+)
+
+// testCertificate is a certificate and key for the TLS tests.
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCertificate returns a certificate for commonName signed by parent,
+// or a self-signed certificate authority if parent is nil.
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{cert: cert, key: key}
+}
+
+// writeFiles writes the PEM encoded certificate and key to dir.
+func (c *testCertificate) writeFiles(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, name+".crt")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keyFile = filepath.Join(dir, name+".key")
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{c.cert.Raw},
+		PrivateKey:  c.key,
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    uint16
+		wantErr bool
+	}{
+		{name: "1.2", want: tls.VersionTLS12},
+		{name: "1.3", want: tls.VersionTLS13},
+		{name: "TLS1.1", want: tls.VersionTLS11},
+		{name: "1.4", wantErr: true},
+		{name: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := platformhttp.ParseTLSVersion(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected version: got %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	got, err := platformhttp.ParseCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "tls_ecdhe_ecdsa_with_aes_256_gcm_sha384"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("unexpected cipher suites: got %x, want %x", got, want)
+	}
+
+	if _, err := platformhttp.ParseCipherSuites([]string{"TLS_NOT_A_CIPHER"}); err == nil {
+		t.Error("expected error for unknown cipher suite")
+	}
+}
+
+func TestNewTLSConfig_ClientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxdb-tls-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCertificate(t, "ca", nil)
+	caFile, _ := ca.writeFiles(t, dir, "ca")
+	certFile, keyFile := newTestCertificate(t, "server", ca).writeFiles(t, dir, "server")
+
+	config, err := platformhttp.NewTLSConfig(platformhttp.TLSOptions{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		MinVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+		ClientCAFile: caFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := config.MinVersion, uint16(tls.VersionTLS12); got != want {
+		t.Errorf("unexpected min version: got %x, want %x", got, want)
+	}
+
+	authID := platform.ID(1)
+	h := platformhttp.NewAuthenticationHandler()
+	h.SessionService = mock.NewSessionService()
+	h.AuthorizationService = &mock.AuthorizationService{
+		FindAuthorizationByIDFn: func(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
+			if id != authID {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: "authorization not found"}
+			}
+			return &platform.Authorization{ID: id, Status: platform.Active}, nil
+		},
+	}
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, err := platcontext.GetAuthorizer(r.Context())
+		if err != nil || a.Identifier() != authID {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewUnstartedServer(h)
+	server.TLS = config
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name   string
+		cert   *testCertificate
+		status int
+	}{
+		{
+			name:   "certificate of an authorization",
+			cert:   newTestCertificate(t, authID.String(), ca),
+			status: http.StatusOK,
+		},
+		{
+			name:   "certificate of an unknown authorization",
+			cert:   newTestCertificate(t, platform.ID(2).String(), ca),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "certificate without an authorization id",
+			cert:   newTestCertificate(t, "telegraf", ca),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "certificate of an unknown authority",
+			cert:   newTestCertificate(t, authID.String(), newTestCertificate(t, "other", nil)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "no certificate",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConfig := &tls.Config{RootCAs: roots}
+			if tt.cert != nil {
+				clientConfig.Certificates = []tls.Certificate{tt.cert.tlsCertificate()}
+			}
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+
+			resp, err := client.Get(server.URL + "/api/v2/buckets")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if got, want := resp.StatusCode, tt.status; got != want {
+				t.Errorf("unexpected status code: got %d, want %d", got, want)
+			}
+		})
+	}
+}
@@ -56,6 +56,15 @@ func TestGetToken(t *testing.T) {
 				result: "tok2",
 			},
 		},
+		{
+			name: "good bearer token",
+			args: args{
+				header: "Bearer tok2",
+			},
+			wants: wants{
+				result: "tok2",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -11,6 +11,23 @@ import (
 	"go.uber.org/zap"
 )
 
+// UsageBackend is all services and associated parameters required to construct
+// the UsageHandler.
+type UsageBackend struct {
+	Logger *zap.Logger
+
+	UsageService platform.UsageService
+}
+
+// NewUsageBackend returns a new instance of UsageBackend.
+func NewUsageBackend(b *APIBackend) *UsageBackend {
+	return &UsageBackend{
+		Logger: b.Logger.With(zap.String("handler", "usage")),
+
+		UsageService: b.UsageService,
+	}
+}
+
 // UsageHandler represents an HTTP API handler for usages.
 type UsageHandler struct {
 	*httprouter.Router
@@ -21,10 +38,12 @@ type UsageHandler struct {
 }
 
 // NewUsageHandler returns a new instance of UsageHandler.
-func NewUsageHandler() *UsageHandler {
+func NewUsageHandler(b *UsageBackend) *UsageHandler {
 	h := &UsageHandler{
 		Router: NewRouter(),
-		Logger: zap.NewNop(),
+		Logger: b.Logger,
+
+		UsageService: b.UsageService,
 	}
 
 	h.HandlerFunc("GET", "/api/v2/usage", h.handleGetUsage)
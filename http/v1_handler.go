@@ -0,0 +1,533 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	"github.com/influxdata/influxdb/quota"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	v1WritePath = "/write"
+	v1QueryPath = "/query"
+	v1PingPath  = "/ping"
+)
+
+// V1Backend is all services and associated parameters required to construct
+// the V1Handler.
+type V1Backend struct {
+	Logger      *zap.Logger
+	MaxBodySize int64
+
+	AuthorizationService platform.AuthorizationService
+	BucketService        platform.BucketService
+	OrganizationService  platform.OrganizationService
+	DBRPMappingService   platform.DBRPMappingService
+	PointsWriter         storage.PointsWriter
+	ProxyQueryService    query.ProxyQueryService
+	UsageRecorder        platform.UsageRecorder
+	QuotaEnforcer        *quota.Enforcer
+}
+
+// NewV1Backend returns a new instance of V1Backend.
+func NewV1Backend(b *APIBackend) *V1Backend {
+	return &V1Backend{
+		Logger:      b.Logger.With(zap.String("handler", "v1")),
+		MaxBodySize: b.WriteMaxBodySize,
+
+		AuthorizationService: b.AuthorizationService,
+		BucketService:        b.BucketService,
+		OrganizationService:  b.OrganizationService,
+		DBRPMappingService:   b.DBRPMappingService,
+		PointsWriter:         b.PointsWriter,
+		ProxyQueryService:    b.FluxService,
+		UsageRecorder:        b.UsageRecorder,
+		QuotaEnforcer:        b.QuotaEnforcer,
+	}
+}
+
+// V1Handler serves the write, query and ping endpoints of the 1.x HTTP API so
+// that 1.x clients can write to and query buckets. The database and retention
+// policy of a request are resolved to a bucket with the dbrp mappings of the
+// DefaultDBRPCluster.
+//
+// The handler authenticates requests itself since 1.x clients send the token
+// as a password. The token may be the p query parameter, the password of basic
+// authentication or the Authorization header with the Token scheme.
+type V1Handler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	// MaxBodySize is the maximum size in bytes of the line protocol of a write.
+	// The size is not limited if it is zero.
+	MaxBodySize int64
+
+	AuthorizationService platform.AuthorizationService
+	OrganizationService  platform.OrganizationService
+	DBRPMappingService   platform.DBRPMappingService
+	PointsWriter         storage.PointsWriter
+	ProxyQueryService    query.ProxyQueryService
+	PreAuthorizer        query.PreAuthorizer
+
+	// UsageRecorder, if set, records the number and size of the writes of
+	// each bucket.
+	UsageRecorder platform.UsageRecorder
+
+	// QuotaEnforcer, if set, limits the rate of the writes of each
+	// organization to its write bytes per second quota.
+	QuotaEnforcer *quota.Enforcer
+}
+
+// NewV1Handler returns a new handler for the 1.x HTTP API.
+func NewV1Handler(b *V1Backend) *V1Handler {
+	h := &V1Handler{
+		Router:      NewRouter(),
+		Logger:      b.Logger,
+		MaxBodySize: b.MaxBodySize,
+
+		AuthorizationService: b.AuthorizationService,
+		OrganizationService:  b.OrganizationService,
+		DBRPMappingService:   b.DBRPMappingService,
+		PointsWriter:         b.PointsWriter,
+		ProxyQueryService:    b.ProxyQueryService,
+		PreAuthorizer:        query.NewPreAuthorizer(b.BucketService),
+		UsageRecorder:        b.UsageRecorder,
+		QuotaEnforcer:        b.QuotaEnforcer,
+	}
+
+	h.HandlerFunc("POST", v1WritePath, h.handleWrite)
+	h.HandlerFunc("GET", v1QueryPath, h.handleQuery)
+	h.HandlerFunc("POST", v1QueryPath, h.handleQuery)
+	h.HandlerFunc("GET", v1PingPath, h.handlePing)
+	h.HandlerFunc("HEAD", v1PingPath, h.handlePing)
+	return h
+}
+
+// isV1Path reports whether the path is served by the V1Handler.
+func isV1Path(path string) bool {
+	switch path {
+	case v1WritePath, v1QueryPath, v1PingPath:
+		return true
+	}
+	return false
+}
+
+func (h *V1Handler) handlePing(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *V1Handler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleV1Write"
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	a, err := h.authorize(ctx, r)
+	if err != nil {
+		h.encodeError(w, err)
+		return
+	}
+
+	qp := r.URL.Query()
+	db := qp.Get("db")
+	if db == "" {
+		h.encodeError(w, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  "database is required",
+		})
+		return
+	}
+
+	precision, err := v1Precision(qp.Get("precision"))
+	if err != nil {
+		h.encodeError(w, err)
+		return
+	}
+
+	mapping, err := h.findMapping(ctx, a.OrgID, db, qp.Get("rp"))
+	if err != nil {
+		h.encodeError(w, err)
+		return
+	}
+
+	p, err := platform.NewPermissionAtID(mapping.BucketID, platform.WriteAction, platform.BucketsResourceType, mapping.OrganizationID)
+	if err != nil {
+		h.encodeError(w, &platform.Error{
+			Code: platform.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		})
+		return
+	}
+
+	if !a.Allowed(*p) {
+		h.encodeError(w, &platform.Error{
+			Code: platform.EForbidden,
+			Op:   op,
+			Msg:  "insufficient permissions for write",
+		})
+		return
+	}
+
+	org, err := h.OrganizationService.FindOrganizationByID(ctx, mapping.OrganizationID)
+	if err != nil {
+		h.encodeError(w, err)
+		return
+	}
+
+	meter := writeMeter{UsageRecorder: h.UsageRecorder, QuotaEnforcer: h.QuotaEnforcer}
+	if err := meter.allow(org); err != nil {
+		h.Logger.Info("Write exceeds organization quota", zap.Error(err))
+		h.encodeError(w, err)
+		return
+	}
+
+	if h.MaxBodySize > 0 && r.ContentLength > h.MaxBodySize {
+		h.encodeBodyTooLarge(w)
+		return
+	}
+
+	var in io.ReadCloser = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		in, err = gzip.NewReader(r.Body)
+		if err != nil {
+			h.encodeError(w, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Msg:  errInvalidGzipHeader,
+				Err:  err,
+			})
+			return
+		}
+		defer in.Close()
+	}
+
+	// The limit applies to the uncompressed line protocol.
+	if h.MaxBodySize > 0 {
+		in = http.MaxBytesReader(w, in, h.MaxBodySize)
+	}
+
+	body := &countingReader{r: in}
+	defer meter.record(org, mapping.BucketID, body)
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			h.encodeBodyTooLarge(w)
+			return
+		}
+		h.encodeError(w, &platform.Error{
+			Code: platform.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		})
+		return
+	}
+
+	mm := tsdb.EncodeName(mapping.OrganizationID, mapping.BucketID)
+	points, err := models.ParsePointsWithPrecision(data, mm[:], time.Now(), precision)
+	if err != nil {
+		h.encodeError(w, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to parse points: %v", err),
+			Err:  err,
+		})
+		return
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		if _, ok := err.(tsdb.PartialWriteError); ok {
+			// The points that were not dropped were written.
+			h.encodeError(w, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Msg:  err.Error(),
+				Err:  err,
+			})
+			return
+		}
+		h.Logger.Error("Error writing points", zap.Error(err))
+		h.encodeError(w, &platform.Error{
+			Code: platform.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+			Err:  err,
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *V1Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleV1Query"
+	ctx := r.Context()
+
+	a, err := h.authorize(ctx, r)
+	if err != nil {
+		h.encodeError(w, err)
+		return
+	}
+
+	q := r.FormValue("q")
+	if q == "" {
+		h.encodeError(w, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  `missing required parameter "q"`,
+		})
+		return
+	}
+
+	// The queries are run in the organization of the authorization and the
+	// databases are resolved to the buckets of that organization.
+	db, rp := r.FormValue("db"), r.FormValue("rp")
+	if db != "" {
+		if _, err := h.findMapping(ctx, a.OrgID, db, rp); err != nil {
+			h.encodeError(w, err)
+			return
+		}
+	}
+
+	dialect, err := decodeV1Dialect(r)
+	if err != nil {
+		h.encodeError(w, err)
+		return
+	}
+
+	compiler := influxql.NewCompiler(h.DBRPMappingService)
+	compiler.Cluster = platform.DefaultDBRPCluster
+	compiler.DB = db
+	compiler.RP = rp
+	compiler.OrganizationID = a.OrgID
+	compiler.Query = q
+
+	spec, err := compiler.Compile(ctx)
+	if err != nil {
+		h.encodeError(w, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  err.Error(),
+			Err:  err,
+		})
+		return
+	}
+
+	if err := h.PreAuthorizer.PreAuthorize(ctx, spec, a); err != nil {
+		h.encodeError(w, &platform.Error{
+			Code: platform.EForbidden,
+			Op:   op,
+			Msg:  err.Error(),
+			Err:  err,
+		})
+		return
+	}
+
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  a,
+			OrganizationID: a.OrgID,
+			Compiler:       lang.SpecCompiler{Spec: spec},
+		},
+		Dialect: dialect,
+	}
+
+	dialect.SetHeaders(w)
+	n, err := h.ProxyQueryService.Query(ctx, w, req)
+	if err != nil {
+		if n == 0 {
+			// Only record the error if nothing has been written to w.
+			h.encodeError(w, err)
+			return
+		}
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "v1"),
+			zap.Error(err),
+		)
+	}
+}
+
+// authorize finds the authorization of the token of a 1.x request.
+func (h *V1Handler) authorize(ctx context.Context, r *http.Request) (*platform.Authorization, error) {
+	token, err := v1Token(r)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := h.AuthorizationService.FindAuthorizationByToken(ctx, token)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "authorization failed",
+			Err:  err,
+		}
+	}
+
+	if !a.IsActive() {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "authorization is inactive",
+		}
+	}
+	return a, nil
+}
+
+// v1Token returns the token of a 1.x request. The username of the u query
+// parameter and of basic authentication is ignored.
+func v1Token(r *http.Request) (string, error) {
+	if token, err := GetToken(r); err == nil {
+		return token, nil
+	}
+	if _, password, ok := r.BasicAuth(); ok {
+		return password, nil
+	}
+	if password := r.URL.Query().Get("p"); password != "" {
+		return password, nil
+	}
+	return "", &platform.Error{
+		Code: platform.EUnauthorized,
+		Msg:  "unable to parse authentication credentials",
+	}
+}
+
+// findMapping finds the dbrp mapping of the database and retention policy in
+// the organization. The default mapping of the database is used when the
+// retention policy is empty.
+func (h *V1Handler) findMapping(ctx context.Context, orgID platform.ID, db, rp string) (*platform.DBRPMapping, error) {
+	cluster := platform.DefaultDBRPCluster
+	filter := platform.DBRPMappingFilter{
+		Cluster:        &cluster,
+		Database:       &db,
+		OrganizationID: &orgID,
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		defaultRP := true
+		filter.Default = &defaultRP
+	}
+
+	m, err := h.DBRPMappingService.Find(ctx, filter)
+	if err != nil {
+		msg := fmt.Sprintf("database not found: %q", db)
+		if rp != "" {
+			msg = fmt.Sprintf("retention policy not found: %q", rp)
+		}
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  msg,
+			Err:  err,
+		}
+	}
+	return m, nil
+}
+
+// v1Precision converts the precision of a 1.x write to the precision of the
+// points parser.
+func v1Precision(precision string) (string, error) {
+	switch precision {
+	case "", "n", "ns":
+		return "ns", nil
+	case "u", "us", "µ":
+		return "us", nil
+	case "ms", "s":
+		return precision, nil
+	}
+	return "", &platform.Error{
+		Code: platform.EInvalid,
+		Msg:  errInvalidPrecision,
+	}
+}
+
+// decodeV1Dialect decodes the format of the query results from the epoch and
+// pretty parameters and the Accept header of a 1.x query.
+func decodeV1Dialect(r *http.Request) (*influxql.Dialect, error) {
+	d := &influxql.Dialect{}
+	switch epoch := r.FormValue("epoch"); epoch {
+	case "":
+		d.TimeFormat = influxql.RFC3339Nano
+	case "h":
+		d.TimeFormat = influxql.Hour
+	case "m":
+		d.TimeFormat = influxql.Minute
+	case "s":
+		d.TimeFormat = influxql.Second
+	case "ms":
+		d.TimeFormat = influxql.Millisecond
+	case "u", "µ":
+		d.TimeFormat = influxql.Microsecond
+	case "n", "ns":
+		d.TimeFormat = influxql.Nanosecond
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("invalid epoch %q", epoch),
+		}
+	}
+
+	switch accept := r.Header.Get("Accept"); {
+	case strings.Contains(accept, "application/csv"), strings.Contains(accept, "text/csv"):
+		d.Encoding = influxql.CSV
+	case r.FormValue("pretty") == "true":
+		d.Encoding = influxql.JSONPretty
+	default:
+		d.Encoding = influxql.JSON
+	}
+	return d, nil
+}
+
+// encodeError writes the error in the format of the 1.x HTTP API.
+func (h *V1Handler) encodeError(w http.ResponseWriter, err error) {
+	code, ok := statusCodePlatformError[platform.ErrorCode(err)]
+	if !ok {
+		code = http.StatusBadRequest
+	}
+	msg := err.Error()
+	if _, ok := err.(*platform.Error); ok {
+		msg = platform.ErrorMessage(err)
+	}
+
+	setRetryAfter(w, err)
+	h.writeError(w, code, msg)
+}
+
+// encodeBodyTooLarge responds to a write with a body larger than MaxBodySize.
+func (h *V1Handler) encodeBodyTooLarge(w http.ResponseWriter) {
+	h.writeError(w, http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("unable to read data: body exceeds the maximum size of %d bytes", h.MaxBodySize))
+}
+
+// writeError writes an error response in the format of the 1.x HTTP API.
+func (h *V1Handler) writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Influxdb-Error", msg)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(struct {
+		Err string `json:"error"`
+	}{Err: msg}); err != nil {
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "v1"),
+			zap.Error(err),
+		)
+	}
+}
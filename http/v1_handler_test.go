@@ -0,0 +1,431 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	"github.com/influxdata/influxdb/quota"
+	"go.uber.org/zap"
+)
+
+func newV1TestBackend(t *testing.T, perms []platform.Permission) *V1Backend {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	return &V1Backend{
+		Logger: zap.NewNop(),
+		AuthorizationService: &mock.AuthorizationService{
+			FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+				switch token {
+				case "secret":
+					return &platform.Authorization{OrgID: orgID, Status: platform.Active, Permissions: perms}, nil
+				case "other":
+					// The token of another organization with the same permissions.
+					return &platform.Authorization{OrgID: platform.ID(3), Status: platform.Active, Permissions: perms}, nil
+				}
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: "authorization not found"}
+			},
+		},
+		BucketService: &mock.BucketService{
+			FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: bucketID, OrganizationID: orgID, Name: "telegraf"}, nil
+			},
+		},
+		OrganizationService: &mock.OrganizationService{
+			FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+				return &platform.Organization{ID: id}, nil
+			},
+		},
+		DBRPMappingService: &mock.DBRPMappingService{
+			FindFn: func(ctx context.Context, filter platform.DBRPMappingFilter) (*platform.DBRPMapping, error) {
+				if *filter.Cluster != platform.DefaultDBRPCluster {
+					t.Errorf("unexpected cluster: %s", *filter.Cluster)
+				}
+				if *filter.Database != "telegraf" || filter.OrganizationID == nil || *filter.OrganizationID != orgID {
+					return nil, fmt.Errorf("dbrp mapping not found")
+				}
+				return &platform.DBRPMapping{
+					Cluster:         platform.DefaultDBRPCluster,
+					Database:        "telegraf",
+					RetentionPolicy: "autogen",
+					Default:         true,
+					OrganizationID:  orgID,
+					BucketID:        bucketID,
+				}, nil
+			},
+		},
+		PointsWriter: &mock.PointsWriter{},
+	}
+}
+
+func TestV1Handler_Write(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	writeBucket := []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+	readBucket := []platform.Permission{
+		{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		auth   func(r *http.Request)
+		body   string
+		perms  []platform.Permission
+		status int
+		points int
+	}{
+		{
+			name:   "password parameter",
+			path:   "/write?db=telegraf&u=me&p=secret",
+			body:   "cpu,host=a value=1 1500000000\ncpu,host=b value=2 1500000000",
+			perms:  writeBucket,
+			status: http.StatusNoContent,
+			points: 2,
+		},
+		{
+			name: "basic authentication",
+			path: "/write?db=telegraf&rp=autogen&precision=s",
+			auth: func(r *http.Request) {
+				r.SetBasicAuth("me", "secret")
+			},
+			body:   "cpu value=1 1500000000",
+			perms:  writeBucket,
+			status: http.StatusNoContent,
+			points: 1,
+		},
+		{
+			name: "token header",
+			path: "/write?db=telegraf",
+			auth: func(r *http.Request) {
+				SetToken("secret", r)
+			},
+			body:   "cpu value=1",
+			perms:  writeBucket,
+			status: http.StatusNoContent,
+			points: 1,
+		},
+		{
+			name:   "missing credentials",
+			path:   "/write?db=telegraf",
+			body:   "cpu value=1",
+			perms:  writeBucket,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid token",
+			path:   "/write?db=telegraf&p=wrong",
+			body:   "cpu value=1",
+			perms:  writeBucket,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "missing database",
+			path:   "/write?p=secret",
+			body:   "cpu value=1",
+			perms:  writeBucket,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown database",
+			path:   "/write?db=mydb&p=secret",
+			body:   "cpu value=1",
+			perms:  writeBucket,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "database of another organization",
+			path:   "/write?db=telegraf&p=other",
+			body:   "cpu value=1",
+			perms:  writeBucket,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "invalid precision",
+			path:   "/write?db=telegraf&p=secret&precision=d",
+			body:   "cpu value=1",
+			perms:  writeBucket,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "missing write permission",
+			path:   "/write?db=telegraf&p=secret",
+			body:   "cpu value=1",
+			perms:  readBucket,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "invalid line protocol",
+			path:   "/write?db=telegraf&p=secret",
+			body:   "cpu value=",
+			perms:  writeBucket,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newV1TestBackend(t, tt.perms)
+			pw := b.PointsWriter.(*mock.PointsWriter)
+			h := NewV1Handler(b)
+
+			r := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			if tt.auth != nil {
+				tt.auth(r)
+			}
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+			if w.Code != http.StatusNoContent {
+				var resp struct {
+					Err string `json:"error"`
+				}
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Err == "" {
+					t.Error("expected an error message")
+				}
+			}
+			if got, want := len(pw.Points), tt.points; got != want {
+				t.Errorf("unexpected number of points written: got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestV1Handler_Write_MaxBodySize(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	writeBucket := []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+	body := "cpu value=1 1000\ncpu value=2 2000\n"
+
+	for _, contentLength := range []int64{int64(len(body)), -1} {
+		t.Run(fmt.Sprintf("content length %d", contentLength), func(t *testing.T) {
+			b := newV1TestBackend(t, writeBucket)
+			b.MaxBodySize = 16
+			pw := b.PointsWriter.(*mock.PointsWriter)
+			h := NewV1Handler(b)
+
+			r := httptest.NewRequest("POST", "/write?db=telegraf&p=secret", strings.NewReader(body))
+			r.ContentLength = contentLength
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, http.StatusRequestEntityTooLarge; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+			var resp struct {
+				Err string `json:"error"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := resp.Err, "unable to read data: body exceeds the maximum size of 16 bytes"; got != want {
+				t.Errorf("unexpected error: got %q, want %q", got, want)
+			}
+			if len(pw.Points) != 0 {
+				t.Errorf("unexpected points written: %d", len(pw.Points))
+			}
+		})
+	}
+}
+
+func TestV1Handler_Write_Usage(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	writeBucket := []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+	body := "cpu value=1 1000\ncpu value=2 2000\n"
+
+	rec := usageRecorder{}
+	b := newV1TestBackend(t, writeBucket)
+	b.UsageRecorder = rec
+	h := NewV1Handler(b)
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest("POST", "/write?db=telegraf&p=secret", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got, want := w.Code, http.StatusNoContent; got != want {
+			t.Fatalf("unexpected status code: got %d, want %d", got, want)
+		}
+	}
+
+	want := usageRecorder{
+		usageKey{orgID, bucketID, platform.UsageWriteRequestCount}: 2,
+		usageKey{orgID, bucketID, platform.UsageWriteRequestBytes}: float64(2 * len(body)),
+	}
+	if diff := cmp.Diff(rec, want); diff != "" {
+		t.Errorf("unexpected usage -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestV1Handler_Write_Quota(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	writeBucket := []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+
+	b := newV1TestBackend(t, writeBucket)
+	b.OrganizationService = &mock.OrganizationService{
+		FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			return &platform.Organization{ID: id, Quotas: &platform.OrgQuotas{WriteBytesPerSecond: 10}}, nil
+		},
+	}
+	b.QuotaEnforcer = quota.NewEnforcer(b.OrganizationService)
+	h := NewV1Handler(b)
+
+	// The first write spends more than the quota allows, so the second
+	// write must wait.
+	for _, want := range []int{http.StatusNoContent, http.StatusTooManyRequests} {
+		r := httptest.NewRequest("POST", "/write?db=telegraf&p=secret", strings.NewReader("cpu value=1 1000\ncpu value=2 2000\n"))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got := w.Code; got != want {
+			t.Fatalf("unexpected status code: got %d, want %d", got, want)
+		}
+		if want == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Fatal("expected Retry-After header")
+		}
+	}
+}
+
+func TestV1Handler_Query(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	readBucket := []platform.Permission{
+		{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &orgID, ID: &bucketID}},
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		accept  string
+		perms   []platform.Permission
+		status  int
+		dialect *influxql.Dialect
+	}{
+		{
+			name:    "query",
+			method:  "GET",
+			path:    "/query?db=telegraf&p=secret&q=SELECT+value+FROM+cpu",
+			perms:   readBucket,
+			status:  http.StatusOK,
+			dialect: &influxql.Dialect{TimeFormat: influxql.RFC3339Nano, Encoding: influxql.JSON},
+		},
+		{
+			name:    "query with epoch",
+			method:  "POST",
+			path:    "/query?db=telegraf&rp=autogen&p=secret&q=SELECT+value+FROM+cpu&epoch=ms&pretty=true",
+			perms:   readBucket,
+			status:  http.StatusOK,
+			dialect: &influxql.Dialect{TimeFormat: influxql.Millisecond, Encoding: influxql.JSONPretty},
+		},
+		{
+			name:    "query with csv",
+			method:  "GET",
+			path:    "/query?p=secret&q=SELECT+value+FROM+telegraf..cpu",
+			accept:  "application/csv",
+			perms:   readBucket,
+			status:  http.StatusOK,
+			dialect: &influxql.Dialect{TimeFormat: influxql.RFC3339Nano, Encoding: influxql.CSV},
+		},
+		{
+			name:   "missing query",
+			method: "GET",
+			path:   "/query?db=telegraf&p=secret",
+			perms:  readBucket,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid query",
+			method: "GET",
+			path:   "/query?db=telegraf&p=secret&q=SELECT",
+			perms:  readBucket,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid epoch",
+			method: "GET",
+			path:   "/query?db=telegraf&p=secret&q=SELECT+value+FROM+cpu&epoch=d",
+			perms:  readBucket,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown database",
+			method: "GET",
+			path:   "/query?db=mydb&p=secret&q=SELECT+value+FROM+cpu",
+			perms:  readBucket,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "database of another organization",
+			method: "GET",
+			path:   "/query?db=telegraf&p=other&q=SELECT+value+FROM+cpu",
+			perms:  readBucket,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "missing read permission",
+			method: "GET",
+			path:   "/query?db=telegraf&p=secret&q=SELECT+value+FROM+cpu",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "missing credentials",
+			method: "GET",
+			path:   "/query?db=telegraf&q=SELECT+value+FROM+cpu",
+			perms:  readBucket,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newV1TestBackend(t, tt.perms)
+			b.ProxyQueryService = &mock.ProxyQueryService{
+				QueryFn: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (int64, error) {
+					if got, want := req.Request.OrganizationID, orgID; got != want {
+						t.Errorf("unexpected organization: got %s, want %s", got, want)
+					}
+					if got, want := *req.Dialect.(*influxql.Dialect), *tt.dialect; got != want {
+						t.Errorf("unexpected dialect: got %+v, want %+v", got, want)
+					}
+					n, err := io.WriteString(w, `{"results":[{"statement_id":0}]}`)
+					return int64(n), err
+				},
+			}
+			h := NewV1Handler(b)
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.status; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,17 +1,19 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/quota"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/julienschmidt/httprouter"
@@ -21,21 +23,27 @@ import (
 // WriteBackend is all services and associated parameters required to construct
 // the WriteHandler.
 type WriteBackend struct {
-	Logger *zap.Logger
+	Logger      *zap.Logger
+	MaxBodySize int64
 
 	PointsWriter        storage.PointsWriter
 	BucketService       platform.BucketService
 	OrganizationService platform.OrganizationService
+	UsageRecorder       platform.UsageRecorder
+	QuotaEnforcer       *quota.Enforcer
 }
 
 // NewWriteBackend returns a new instance of WriteBackend.
 func NewWriteBackend(b *APIBackend) *WriteBackend {
 	return &WriteBackend{
-		Logger: b.Logger.With(zap.String("handler", "write")),
+		Logger:      b.Logger.With(zap.String("handler", "write")),
+		MaxBodySize: b.WriteMaxBodySize,
 
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		UsageRecorder:       b.UsageRecorder,
+		QuotaEnforcer:       b.QuotaEnforcer,
 	}
 }
 
@@ -45,10 +53,22 @@ type WriteHandler struct {
 
 	Logger *zap.Logger
 
+	// MaxBodySize is the maximum size in bytes of the line protocol of a write.
+	// The size is not limited if it is zero.
+	MaxBodySize int64
+
 	BucketService       platform.BucketService
 	OrganizationService platform.OrganizationService
 
 	PointsWriter storage.PointsWriter
+
+	// UsageRecorder, if set, records the number and size of the writes of
+	// each bucket.
+	UsageRecorder platform.UsageRecorder
+
+	// QuotaEnforcer, if set, limits the rate of the writes of each
+	// organization to its write bytes per second quota.
+	QuotaEnforcer *quota.Enforcer
 }
 
 const (
@@ -57,15 +77,26 @@ const (
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
 )
 
+const (
+	// writeBatchSize is the number of points that are written to the
+	// PointsWriter at once while the body of a write is parsed.
+	writeBatchSize = 5000
+	// maxLineErrors is the maximum number of rejected lines in the response.
+	maxLineErrors = 100
+)
+
 // NewWriteHandler creates a new handler at /api/v2/write to receive line protocol.
 func NewWriteHandler(b *WriteBackend) *WriteHandler {
 	h := &WriteHandler{
-		Router: NewRouter(),
-		Logger: b.Logger,
+		Router:      NewRouter(),
+		Logger:      b.Logger,
+		MaxBodySize: b.MaxBodySize,
 
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		UsageRecorder:       b.UsageRecorder,
+		QuotaEnforcer:       b.QuotaEnforcer,
 	}
 
 	h.HandlerFunc("POST", writePath, h.handleWrite)
@@ -106,57 +137,20 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
 
-	var org *platform.Organization
-	if id, err := platform.IDFromString(req.Org); err == nil {
-		// Decoded ID successfully. Make sure it's a real org.
-		o, err := h.OrganizationService.FindOrganizationByID(ctx, *id)
-		if err == nil {
-			org = o
-		} else if platform.ErrorCode(err) != platform.ENotFound {
-			EncodeError(ctx, err, w)
-			return
-		}
-	}
-	if org == nil {
-		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &req.Org})
-		if err != nil {
-			logger.Info("Failed to find organization", zap.Error(err))
-			EncodeError(ctx, err, w)
-			return
-		}
-
-		org = o
-	}
-
-	var bucket *platform.Bucket
-	if id, err := platform.IDFromString(req.Bucket); err == nil {
-		// Decoded ID successfully. Make sure it's a real bucket.
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
-			OrganizationID: &org.ID,
-			ID:             id,
-		})
-		if err == nil {
-			bucket = b
-		} else if platform.ErrorCode(err) != platform.ENotFound {
-			EncodeError(ctx, err, w)
-			return
-		}
+	org, err := findOrganizationByNameOrID(ctx, h.OrganizationService, req.Org)
+	if err != nil {
+		logger.Info("Failed to find organization", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
 	}
 
-	if bucket == nil {
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
-			OrganizationID: &org.ID,
-			Name:           &req.Bucket,
-		})
-		if err != nil {
-			EncodeError(ctx, &platform.Error{
-				Op:  "http/handleWrite",
-				Err: err,
-			}, w)
-			return
-		}
-
-		bucket = b
+	bucket, err := findBucketByNameOrID(ctx, h.BucketService, org.ID, req.Bucket)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Op:  "http/handleWrite",
+			Err: err,
+		}, w)
+		return
 	}
 
 	p, err := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResourceType, org.ID)
@@ -179,48 +173,374 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO(jeff): we should be publishing with the org and bucket instead of
-	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
-	// be sure to remove this when it is there!
-	data, err := ioutil.ReadAll(in)
-	if err != nil {
+	meter := writeMeter{UsageRecorder: h.UsageRecorder, QuotaEnforcer: h.QuotaEnforcer}
+	if err := meter.allow(org); err != nil {
+		logger.Info("Write exceeds organization quota", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.MaxBodySize > 0 && r.ContentLength > h.MaxBodySize {
+		encodeBodyTooLarge(w, h.MaxBodySize, 0)
+		return
+	}
+
+	// The limit applies to the uncompressed line protocol.
+	if h.MaxBodySize > 0 {
+		in = http.MaxBytesReader(w, in, h.MaxBodySize)
+	}
+
+	body := &countingReader{r: in}
+	defer meter.record(org, bucket.ID, body)
+
+	// The body is parsed and written in batches so that the lines that fail to
+	// parse are rejected without rejecting the rest of the body.
+	var (
+		res    = &writeResult{}
+		points []models.Point
+	)
+	flush := func() error {
+		if len(points) == 0 {
+			return nil
+		}
+		err := h.PointsWriter.WritePoints(ctx, points)
+		points = nil
+		// The points that were not dropped were written, so the write goes on.
+		if pwe, ok := err.(tsdb.PartialWriteError); ok {
+			res.drop(pwe)
+			err = nil
+		}
+		if err == nil {
+			res.written = res.Accepted
+		}
+		return err
+	}
+
+	mm := tsdb.EncodeName(org.ID, bucket.ID)
+	scanner := models.NewPointsScanner(body, mm[:], time.Now(), req.Precision)
+	for scanner.Scan() {
+		if err := scanner.LineErr(); err != nil {
+			res.reject(scanner.Line(), err)
+			continue
+		}
+		if err := checkBucketSchema(bucket.Schema, scanner.Points()); err != nil {
+			res.reject(scanner.Line(), err)
+			continue
+		}
+
+		res.Accepted++
+		points = append(points, scanner.Points()...)
+		if len(points) < writeBatchSize {
+			continue
+		}
+		if err := flush(); err != nil {
+			h.encodeWriteError(ctx, w, logger, err, res.written)
+			return
+		}
+	}
+
+	// The batches written before an error are reported as accepted so that
+	// the client does not send them again.
+	if err := scanner.Err(); err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			encodeBodyTooLarge(w, h.MaxBodySize, res.written)
+			return
+		}
 		logger.Error("Error reading body", zap.Error(err))
-		EncodeError(ctx, &platform.Error{
+		encodePartialWriteError(ctx, w, &platform.Error{
 			Code: platform.EInternal,
 			Op:   "http/handleWrite",
 			Msg:  fmt.Sprintf("unable to read data: %v", err),
 			Err:  err,
-		}, w)
+		}, res.written)
 		return
 	}
 
-	mm := tsdb.EncodeName(org.ID, bucket.ID)
-	points, err := models.ParsePointsWithPrecision(data, mm[:], time.Now(), req.Precision)
-	if err != nil {
-		logger.Error("Error parsing points", zap.Error(err))
-		EncodeError(ctx, &platform.Error{
-			Code: platform.EInvalid,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to parse points: %v", err),
-			Err:  err,
-		}, w)
+	if err := flush(); err != nil {
+		h.encodeWriteError(ctx, w, logger, err, res.written)
 		return
 	}
 
-	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
-		logger.Error("Error writing points", zap.Error(err))
-		EncodeError(ctx, &platform.Error{
-			Code: platform.EInternal,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
-			Err:  err,
-		}, w)
+	if res.Rejected > 0 || res.Dropped > 0 {
+		logger.Info("Partial write", zap.Int("accepted", res.Accepted), zap.Int("rejected", res.Rejected), zap.Int("dropped", res.Dropped))
+		res.encode(w)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *WriteHandler) encodeWriteError(ctx context.Context, w http.ResponseWriter, logger *zap.Logger, err error, accepted int) {
+	logger.Error("Error writing points", zap.Error(err))
+	encodePartialWriteError(ctx, w, &platform.Error{
+		Code: platform.EInternal,
+		Op:   "http/handleWrite",
+		Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+		Err:  err,
+	}, accepted)
+}
+
+// partialWriteError is the response to a write that failed after some of its
+// lines were written.
+type partialWriteError struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Op       string `json:"op"`
+	Accepted int    `json:"accepted"`
+}
+
+// encodePartialWriteError encodes err with the number of lines that were
+// written before it. The error is encoded as usual if no line was written.
+func encodePartialWriteError(ctx context.Context, w http.ResponseWriter, err *platform.Error, accepted int) {
+	if accepted == 0 {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	code := platform.ErrorCode(err)
+	httpCode, ok := statusCodePlatformError[code]
+	if !ok {
+		httpCode = http.StatusBadRequest
+	}
+	w.Header().Set(PlatformErrorCodeHeader, code)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(httpCode)
+	b, _ := json.Marshal(&partialWriteError{
+		Code:     code,
+		Message:  platform.ErrorMessage(err),
+		Op:       platform.ErrorOp(err),
+		Accepted: accepted,
+	})
+	_, _ = w.Write(b)
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// writeMeter records the usage and enforces the write quotas of
+// organizations. It is shared by the handlers of every write endpoint so that
+// the usage and the quota of an organization cover all of its writes.
+type writeMeter struct {
+	// UsageRecorder, if set, records the number and size of the writes of
+	// each bucket.
+	UsageRecorder platform.UsageRecorder
+
+	// QuotaEnforcer, if set, limits the rate of the writes of each
+	// organization to its write bytes per second quota.
+	QuotaEnforcer *quota.Enforcer
+}
+
+// allow returns a too many requests error if the organization may not write
+// until more of its quota is available.
+func (m writeMeter) allow(org *platform.Organization) error {
+	if m.QuotaEnforcer == nil {
+		return nil
+	}
+	return m.QuotaEnforcer.AllowWrite(org)
+}
+
+// record records the usage of a write to a bucket of the organization and
+// spends the bytes read from its body from the quota of the organization.
+// It is called once the body of the write has been read.
+func (m writeMeter) record(org *platform.Organization, bucketID platform.ID, body *countingReader) {
+	if m.UsageRecorder != nil {
+		m.UsageRecorder.RecordUsage(org.ID, bucketID, platform.UsageWriteRequestCount, 1)
+		m.UsageRecorder.RecordUsage(org.ID, bucketID, platform.UsageWriteRequestBytes, float64(body.n))
+	}
+	if m.QuotaEnforcer != nil {
+		m.QuotaEnforcer.RecordWrite(org, body.n)
+	}
+}
+
+// bodyTooLargeError is the response to a write with a body larger than the maximum body size.
+// Accepted is the number of lines written before the limit was reached.
+type bodyTooLargeError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	MaxLength int64  `json:"maxLength"`
+	Accepted  int    `json:"accepted,omitempty"`
+}
+
+func encodeBodyTooLarge(w http.ResponseWriter, maxBodySize int64, accepted int) {
+	w.Header().Set(PlatformErrorCodeHeader, platform.EInvalid)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	b, _ := json.Marshal(&bodyTooLargeError{
+		Code:      platform.EInvalid,
+		Message:   fmt.Sprintf("unable to read data: body exceeds the maximum size of %d bytes", maxBodySize),
+		MaxLength: maxBodySize,
+		Accepted:  accepted,
+	})
+	_, _ = w.Write(b)
+}
+
+// lineError is a line of line protocol that was rejected.
+type lineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// writeResult is the response to a write with rejected lines or points dropped
+// by the storage engine. The accepted lines were written, except for the
+// dropped points.
+type writeResult struct {
+	Code     string      `json:"code"`
+	Message  string      `json:"message"`
+	Op       string      `json:"op"`
+	Line     int         `json:"line,omitempty"`
+	Accepted int         `json:"accepted"`
+	Rejected int         `json:"rejected"`
+	Dropped  int         `json:"dropped,omitempty"`
+	Errors   []lineError `json:"errors"`
+
+	dropErr error // the first error of the dropped points
+	written int   // the number of accepted lines that were written
+}
+
+// reject records a rejected line. Only the first maxLineErrors lines are listed.
+func (res *writeResult) reject(line int, err error) {
+	if res.Rejected == 0 {
+		res.Line = line
+	}
+	res.Rejected++
+	if len(res.Errors) < maxLineErrors {
+		res.Errors = append(res.Errors, lineError{Line: line, Message: err.Error()})
+	}
+}
+
+// drop records the points of the accepted lines that the storage engine dropped.
+func (res *writeResult) drop(err tsdb.PartialWriteError) {
+	if res.dropErr == nil {
+		res.dropErr = err
+	}
+	res.Dropped += err.Dropped
+}
+
+func (res *writeResult) encode(w http.ResponseWriter) {
+	res.Code = platform.EInvalid
+	res.Op = "http/handleWrite"
+	switch {
+	case res.Rejected > 0 && res.dropErr != nil:
+		res.Message = fmt.Sprintf("partial write: %d of %d lines rejected; first rejected line %d: %s; %s",
+			res.Rejected, res.Accepted+res.Rejected, res.Line, res.Errors[0].Message, res.dropErr)
+	case res.Rejected > 0:
+		res.Message = fmt.Sprintf("partial write: %d of %d lines rejected; first rejected line %d: %s",
+			res.Rejected, res.Accepted+res.Rejected, res.Line, res.Errors[0].Message)
+	default:
+		res.Message = res.dropErr.Error()
+	}
+	if res.Errors == nil {
+		res.Errors = []lineError{}
+	}
+
+	w.Header().Set(PlatformErrorCodeHeader, res.Code)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	b, _ := json.Marshal(res)
+	_, _ = w.Write(b)
+}
+
+// checkBucketSchema returns an error if the points of a line do not match the
+// schema of the bucket. All points match if the bucket has no schema.
+func checkBucketSchema(schema *platform.BucketSchema, points []models.Point) error {
+	if schema == nil {
+		return nil
+	}
+
+	for _, p := range points {
+		tags := p.Tags()
+		measurement, field := tags.Get(tsdb.MeasurementTagKeyBytes), tags.Get(tsdb.FieldKeyTagKeyBytes)
+
+		m := schema.Measurement(string(measurement))
+		if m == nil {
+			return fmt.Errorf("measurement %q is not in the bucket schema", measurement)
+		}
+
+		for _, t := range tags {
+			if bytes.Equal(t.Key, tsdb.MeasurementTagKeyBytes) || bytes.Equal(t.Key, tsdb.FieldKeyTagKeyBytes) {
+				continue
+			}
+			if !m.HasTag(string(t.Key)) {
+				return fmt.Errorf("tag key %q is not in the bucket schema of measurement %q", t.Key, measurement)
+			}
+		}
+
+		typ, ok := m.FieldType(string(field))
+		if !ok {
+			return fmt.Errorf("field %q is not in the bucket schema of measurement %q", field, measurement)
+		}
+		if iter := p.FieldIterator(); iter.Next() {
+			if got := schemaFieldType(iter.Type()); got != typ {
+				return fmt.Errorf("%s: input field %q on measurement %q is type %s, the bucket schema declares type %s",
+					tsdb.ErrFieldTypeConflict, field, measurement, got, typ)
+			}
+		}
+	}
+	return nil
+}
+
+// schemaFieldType returns the bucket schema type of a field type.
+func schemaFieldType(typ models.FieldType) string {
+	switch typ {
+	case models.Float:
+		return platform.SchemaFieldTypeFloat
+	case models.Integer:
+		return platform.SchemaFieldTypeInteger
+	case models.Unsigned:
+		return platform.SchemaFieldTypeUnsigned
+	case models.String:
+		return platform.SchemaFieldTypeString
+	case models.Boolean:
+		return platform.SchemaFieldTypeBoolean
+	}
+	return "unknown"
+}
+
+// findOrganizationByNameOrID finds an organization by its ID or, failing that, its name.
+func findOrganizationByNameOrID(ctx context.Context, svc platform.OrganizationService, org string) (*platform.Organization, error) {
+	if id, err := platform.IDFromString(org); err == nil {
+		// Decoded ID successfully. Make sure it's a real org.
+		o, err := svc.FindOrganizationByID(ctx, *id)
+		if err == nil {
+			return o, nil
+		} else if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+	}
+
+	return svc.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+}
+
+// findBucketByNameOrID finds a bucket belonging to orgID by its ID or, failing that, its name.
+func findBucketByNameOrID(ctx context.Context, svc platform.BucketService, orgID platform.ID, bucket string) (*platform.Bucket, error) {
+	if id, err := platform.IDFromString(bucket); err == nil {
+		// Decoded ID successfully. Make sure it's a real bucket.
+		b, err := svc.FindBucket(ctx, platform.BucketFilter{
+			OrganizationID: &orgID,
+			ID:             id,
+		})
+		if err == nil {
+			return b, nil
+		} else if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+	}
+
+	return svc.FindBucket(ctx, platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &bucket,
+	})
+}
+
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
@@ -1,8 +1,11 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -10,7 +13,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/quota"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
 )
 
 func TestWriteService_Write(t *testing.T) {
@@ -69,3 +79,427 @@ func TestWriteService_Write(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteHandler_handleWrite(t *testing.T) {
+	type wants struct {
+		code   int
+		body   string
+		points int
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		gzip        bool
+		maxBodySize int64
+		schema      *platform.BucketSchema
+		writeErr    error
+		wants       wants
+	}{
+		{
+			name: "all lines are written",
+			body: "m,t=v f=1 1000\nm,t=v f=2,g=3 2000\n",
+			wants: wants{
+				code:   http.StatusNoContent,
+				points: 3,
+			},
+		},
+		{
+			name: "valid lines are written and invalid lines are reported",
+			body: "m,t=v f=1 1000\nm,t=v\n# comment\nm,t=v f=2 2000\nm f= 3000\n",
+			wants: wants{
+				code: http.StatusBadRequest,
+				body: `{
+  "code": "invalid",
+  "message": "partial write: 2 of 4 lines rejected; first rejected line 2: unable to parse 'm,t=v': missing fields",
+  "op": "http/handleWrite",
+  "line": 2,
+  "accepted": 2,
+  "rejected": 2,
+  "errors": [
+    {"line": 2, "message": "unable to parse 'm,t=v': missing fields"},
+    {"line": 5, "message": "unable to parse 'm f= 3000': missing field value"}
+  ]
+}`,
+				points: 2,
+			},
+		},
+		{
+			name: "gzipped body",
+			body: "m,t=v f=1 1000\nm,t=v\n",
+			gzip: true,
+			wants: wants{
+				code:   http.StatusBadRequest,
+				points: 1,
+			},
+		},
+		{
+			name:        "content length exceeds max body size",
+			body:        "m,t=v f=1 1000\nm,t=v f=2 2000\n",
+			maxBodySize: 16,
+			wants: wants{
+				code: http.StatusRequestEntityTooLarge,
+				body: `{
+  "code": "invalid",
+  "message": "unable to read data: body exceeds the maximum size of 16 bytes",
+  "maxLength": 16
+}`,
+			},
+		},
+		{
+			name:        "uncompressed body exceeds max body size",
+			body:        strings.Repeat("m,t=v f=1 1000\n", 100),
+			gzip:        true,
+			maxBodySize: 200,
+			wants: wants{
+				code: http.StatusRequestEntityTooLarge,
+			},
+		},
+		{
+			name: "points dropped by the storage engine are reported",
+			body: "m,t=v f=1 1000\nm,t=v f=2i 2000\n",
+			writeErr: tsdb.PartialWriteError{
+				Reason:  `field type conflict: input field "f" on measurement "m" is type integer, already exists as type float`,
+				Dropped: 1,
+			},
+			wants: wants{
+				code: http.StatusBadRequest,
+				body: `{
+  "code": "invalid",
+  "message": "partial write: field type conflict: input field \"f\" on measurement \"m\" is type integer, already exists as type float dropped=1",
+  "op": "http/handleWrite",
+  "accepted": 2,
+  "rejected": 0,
+  "dropped": 1,
+  "errors": []
+}`,
+				points: 2,
+			},
+		},
+		{
+			name: "lines that do not match the bucket schema are rejected",
+			body: "m,t=v f=1 1000\nm,t=v f=1i 2000\nm,u=v f=1 3000\nm,t=v g=1 4000\nn f=1 5000\n",
+			schema: &platform.BucketSchema{
+				Measurements: []platform.MeasurementSchema{
+					{
+						Name:   "m",
+						Tags:   []string{"t"},
+						Fields: []platform.FieldSchema{{Name: "f", Type: platform.SchemaFieldTypeFloat}},
+					},
+				},
+			},
+			wants: wants{
+				code: http.StatusBadRequest,
+				body: `{
+  "code": "invalid",
+  "message": "partial write: 4 of 5 lines rejected; first rejected line 2: field type conflict: input field \"f\" on measurement \"m\" is type integer, the bucket schema declares type float",
+  "op": "http/handleWrite",
+  "line": 2,
+  "accepted": 1,
+  "rejected": 4,
+  "errors": [
+    {"line": 2, "message": "field type conflict: input field \"f\" on measurement \"m\" is type integer, the bucket schema declares type float"},
+    {"line": 3, "message": "tag key \"u\" is not in the bucket schema of measurement \"m\""},
+    {"line": 4, "message": "field \"g\" is not in the bucket schema of measurement \"m\""},
+    {"line": 5, "message": "measurement \"n\" is not in the bucket schema"}
+  ]
+}`,
+				points: 1,
+			},
+		},
+		{
+			name:        "body within max body size",
+			body:        "m,t=v f=1 1000\nm,t=v f=2 2000\n",
+			maxBodySize: 64,
+			wants: wants{
+				code:   http.StatusNoContent,
+				points: 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgID, bucketID := platform.ID(1), platform.ID(2)
+			pw := &mock.PointsWriter{Err: tt.writeErr}
+			h := NewWriteHandler(&WriteBackend{
+				Logger:       zap.NewNop(),
+				MaxBodySize:  tt.maxBodySize,
+				PointsWriter: pw,
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+						return &platform.Organization{ID: id}, nil
+					},
+				},
+				BucketService: &mock.BucketService{
+					FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+						return &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID, Schema: tt.schema}, nil
+					},
+				},
+			})
+
+			var body bytes.Buffer
+			if tt.gzip {
+				gw := gzip.NewWriter(&body)
+				gw.Write([]byte(tt.body))
+				gw.Close()
+			} else {
+				body.WriteString(tt.body)
+			}
+
+			r := httptest.NewRequest("POST", "/api/v2/write?org="+orgID.String()+"&bucket="+bucketID.String(), &body)
+			if tt.gzip {
+				r.Header.Set("Content-Encoding", "gzip")
+			}
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: platform.OperPermissions(),
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(w.Body.String(), tt.wants.body); err != nil || !eq {
+					t.Errorf("unexpected body -got/+want\ndiff %s", diff)
+				}
+			}
+			if got, want := len(pw.Points), tt.wants.points; got != want {
+				t.Errorf("unexpected number of points written: got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWrite_Batches(t *testing.T) {
+	var lines bytes.Buffer
+	for i := 0; i < writeBatchSize+10; i++ {
+		lines.WriteString("m,t=v f=1 1000\n")
+	}
+	lines.WriteString("m,t=v\n")
+
+	var batches []int
+	pw := &batchPointsWriter{fn: func(n int) error {
+		batches = append(batches, n)
+		return nil
+	}}
+	h := NewWriteHandler(&WriteBackend{
+		Logger:       zap.NewNop(),
+		PointsWriter: pw,
+		OrganizationService: &mock.OrganizationService{
+			FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+				return &platform.Organization{ID: id}, nil
+			},
+		},
+		BucketService: &mock.BucketService{
+			FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID}, nil
+			},
+		},
+	})
+
+	r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002", &lines)
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+		Status:      platform.Active,
+		Permissions: platform.OperPermissions(),
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if got, want := w.Code, http.StatusBadRequest; got != want {
+		t.Fatalf("unexpected status code: got %d, want %d", got, want)
+	}
+	if diff := cmp.Diff(batches, []int{writeBatchSize, 10}); diff != "" {
+		t.Errorf("unexpected batches -got/+want\ndiff %s", diff)
+	}
+
+	var res writeResult
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Accepted != writeBatchSize+10 || res.Rejected != 1 || res.Line != writeBatchSize+11 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
+
+func TestWriteHandler_handleWrite_PartialFailure(t *testing.T) {
+	line := "m,t=v f=1 1000\n"
+	lines := strings.Repeat(line, writeBatchSize+10)
+
+	tests := []struct {
+		name        string
+		maxBodySize int64
+		writeErr    error
+		code        int
+	}{
+		{
+			name:     "write error after the first batch",
+			writeErr: errors.New("disk full"),
+			code:     http.StatusInternalServerError,
+		},
+		{
+			name:        "body exceeds max body size after the first batch",
+			maxBodySize: int64(writeBatchSize*len(line) + len(line)/2),
+			code:        http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var batches []int
+			pw := &batchPointsWriter{fn: func(n int) error {
+				batches = append(batches, n)
+				if len(batches) > 1 {
+					return tt.writeErr
+				}
+				return nil
+			}}
+			h := NewWriteHandler(&WriteBackend{
+				Logger:       zap.NewNop(),
+				MaxBodySize:  tt.maxBodySize,
+				PointsWriter: pw,
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+						return &platform.Organization{ID: id}, nil
+					},
+				},
+				BucketService: &mock.BucketService{
+					FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+						return &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID}, nil
+					},
+				},
+			})
+
+			r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002", strings.NewReader(lines))
+			// The size of the body is not known until it is read.
+			r.ContentLength = -1
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: platform.OperPermissions(),
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.code; got != want {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", got, want, w.Body.String())
+			}
+
+			// The first batch was written, so it is reported as accepted.
+			var res struct {
+				Accepted int `json:"accepted"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := res.Accepted, writeBatchSize; got != want {
+				t.Errorf("unexpected number of accepted lines: got %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWrite_Usage(t *testing.T) {
+	body := "m,t=v f=1 1000\nm,t=v f=2 2000\n"
+
+	rec := usageRecorder{}
+	h := NewWriteHandler(&WriteBackend{
+		Logger:        zap.NewNop(),
+		PointsWriter:  &mock.PointsWriter{},
+		UsageRecorder: rec,
+		OrganizationService: &mock.OrganizationService{
+			FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+				return &platform.Organization{ID: id}, nil
+			},
+		},
+		BucketService: &mock.BucketService{
+			FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID}, nil
+			},
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002", strings.NewReader(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: platform.OperPermissions(),
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got, want := w.Code, http.StatusNoContent; got != want {
+			t.Fatalf("unexpected status code: got %d, want %d", got, want)
+		}
+	}
+
+	want := usageRecorder{
+		usageKey{1, 2, platform.UsageWriteRequestCount}: 2,
+		usageKey{1, 2, platform.UsageWriteRequestBytes}: float64(2 * len(body)),
+	}
+	if diff := cmp.Diff(rec, want); diff != "" {
+		t.Errorf("unexpected usage -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestWriteHandler_handleWrite_Quota(t *testing.T) {
+	body := "m,t=v f=1 1000\nm,t=v f=2 2000\n"
+
+	orgs := &mock.OrganizationService{
+		FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			return &platform.Organization{ID: id, Quotas: &platform.OrgQuotas{WriteBytesPerSecond: 10}}, nil
+		},
+	}
+	h := NewWriteHandler(&WriteBackend{
+		Logger:              zap.NewNop(),
+		PointsWriter:        &mock.PointsWriter{},
+		QuotaEnforcer:       quota.NewEnforcer(orgs),
+		OrganizationService: orgs,
+		BucketService: &mock.BucketService{
+			FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID}, nil
+			},
+		},
+	})
+
+	// The first write spends more than the quota allows, so the second
+	// write must wait.
+	for _, want := range []int{http.StatusNoContent, http.StatusTooManyRequests} {
+		r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002", strings.NewReader(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: platform.OperPermissions(),
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got := w.Code; got != want {
+			t.Fatalf("unexpected status code: got %d, want %d", got, want)
+		}
+		if want == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Fatal("expected Retry-After header")
+		}
+	}
+}
+
+type usageKey struct {
+	org, bucket platform.ID
+	metric      platform.UsageMetric
+}
+
+// usageRecorder sums the recorded usage.
+type usageRecorder map[usageKey]float64
+
+func (r usageRecorder) RecordUsage(orgID, bucketID platform.ID, metric platform.UsageMetric, value float64) {
+	r[usageKey{orgID, bucketID, metric}] += value
+}
+
+// batchPointsWriter calls fn with the number of points of each write.
+type batchPointsWriter struct {
+	fn func(n int) error
+}
+
+func (w *batchPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	return w.fn(len(points))
+}
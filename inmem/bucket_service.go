@@ -254,6 +254,14 @@ func (s *Service) CreateBucket(ctx context.Context, b *platform.Bucket) error {
 		}
 		b.OrganizationID = o.ID
 	}
+	if b.Schema != nil {
+		if err := b.Schema.Valid(); err != nil {
+			return &platform.Error{
+				Err: err,
+				Op:  OpPrefix + platform.OpCreateBucket,
+			}
+		}
+	}
 	filter := platform.BucketFilter{
 		Name:           &b.Name,
 		OrganizationID: &b.OrganizationID,
@@ -294,6 +302,19 @@ func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.Schema != nil {
+		if err := upd.Schema.Valid(); err != nil {
+			return nil, &platform.Error{
+				Op:  OpPrefix + platform.OpUpdateBucket,
+				Err: err,
+			}
+		}
+		b.Schema = upd.Schema
+		if len(b.Schema.Measurements) == 0 {
+			b.Schema = nil
+		}
+	}
+
 	s.bucketKV.Store(b.ID.String(), b)
 
 	return b, nil
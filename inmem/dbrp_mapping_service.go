@@ -2,7 +2,6 @@ package inmem
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path"
 
@@ -10,15 +9,18 @@ import (
 )
 
 var (
-	errDBRPMappingNotFound = fmt.Errorf("dbrp mapping not found")
+	errDBRPMappingNotFound = &platform.Error{
+		Code: platform.ENotFound,
+		Msg:  platform.ErrDBRPMappingNotFound,
+	}
 )
 
-func encodeDBRPMappingKey(cluster, db, rp string) string {
-	return path.Join(cluster, db, rp)
+func encodeDBRPMappingKey(orgID platform.ID, cluster, db, rp string) string {
+	return path.Join(orgID.String(), cluster, db, rp)
 }
 
-func (c *Service) loadDBRPMapping(ctx context.Context, cluster, db, rp string) (*platform.DBRPMapping, error) {
-	i, ok := c.dbrpMappingKV.Load(encodeDBRPMappingKey(cluster, db, rp))
+func (c *Service) loadDBRPMapping(ctx context.Context, orgID platform.ID, cluster, db, rp string) (*platform.DBRPMapping, error) {
+	i, ok := c.dbrpMappingKV.Load(encodeDBRPMappingKey(orgID, cluster, db, rp))
 	if !ok {
 		return nil, errDBRPMappingNotFound
 	}
@@ -31,9 +33,9 @@ func (c *Service) loadDBRPMapping(ctx context.Context, cluster, db, rp string) (
 	return &m, nil
 }
 
-// FindBy returns a single dbrp mapping by cluster, db and rp.
-func (s *Service) FindBy(ctx context.Context, cluster, db, rp string) (*platform.DBRPMapping, error) {
-	return s.loadDBRPMapping(ctx, cluster, db, rp)
+// FindBy returns a single dbrp mapping by organization, cluster, db and rp.
+func (s *Service) FindBy(ctx context.Context, orgID platform.ID, cluster, db, rp string) (*platform.DBRPMapping, error) {
+	return s.loadDBRPMapping(ctx, orgID, cluster, db, rp)
 }
 
 func (c *Service) forEachDBRPMapping(ctx context.Context, fn func(m *platform.DBRPMapping) bool) error {
@@ -73,8 +75,8 @@ func (s *Service) Find(ctx context.Context, filter platform.DBRPMappingFilter) (
 	}
 
 	// filter by dbrpMapping id
-	if filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
-		return s.FindBy(ctx, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
+	if filter.OrganizationID != nil && filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
+		return s.FindBy(ctx, *filter.OrganizationID, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
 	}
 
 	mappings, n, err := s.FindMany(ctx, filter)
@@ -93,8 +95,8 @@ func (s *Service) Find(ctx context.Context, filter platform.DBRPMappingFilter) (
 // Additional options provide pagination & sorting.
 func (s *Service) FindMany(ctx context.Context, filter platform.DBRPMappingFilter, opt ...platform.FindOptions) ([]*platform.DBRPMapping, int, error) {
 	// filter by dbrpMapping id
-	if filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
-		m, err := s.FindBy(ctx, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
+	if filter.OrganizationID != nil && filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
+		m, err := s.FindBy(ctx, *filter.OrganizationID, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -105,7 +107,8 @@ func (s *Service) FindMany(ctx context.Context, filter platform.DBRPMappingFilte
 		return (filter.Cluster == nil || (*filter.Cluster) == mapping.Cluster) &&
 			(filter.Database == nil || (*filter.Database) == mapping.Database) &&
 			(filter.RetentionPolicy == nil || (*filter.RetentionPolicy) == mapping.RetentionPolicy) &&
-			(filter.Default == nil || (*filter.Default) == mapping.Default)
+			(filter.Default == nil || (*filter.Default) == mapping.Default) &&
+			(filter.OrganizationID == nil || (*filter.OrganizationID) == mapping.OrganizationID)
 	}
 
 	mappings, err := s.filterDBRPMappings(ctx, filterFunc)
@@ -119,9 +122,12 @@ func (s *Service) FindMany(ctx context.Context, filter platform.DBRPMappingFilte
 // Create creates a new dbrp mapping.
 func (s *Service) Create(ctx context.Context, m *platform.DBRPMapping) error {
 	if err := m.Validate(); err != nil {
-		return nil
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  err.Error(),
+		}
 	}
-	existing, err := s.loadDBRPMapping(ctx, m.Cluster, m.Database, m.RetentionPolicy)
+	existing, err := s.loadDBRPMapping(ctx, m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy)
 	if err != nil {
 		if err == errDBRPMappingNotFound {
 			return s.PutDBRPMapping(ctx, m)
@@ -130,7 +136,10 @@ func (s *Service) Create(ctx context.Context, m *platform.DBRPMapping) error {
 	}
 
 	if !existing.Equal(m) {
-		return errors.New("dbrp mapping already exists")
+		return &platform.Error{
+			Code: platform.EConflict,
+			Msg:  platform.ErrDBRPMappingExists,
+		}
 	}
 
 	return s.PutDBRPMapping(ctx, m)
@@ -138,13 +147,13 @@ func (s *Service) Create(ctx context.Context, m *platform.DBRPMapping) error {
 
 // PutDBRPMapping sets dbrpMapping with the current ID.
 func (s *Service) PutDBRPMapping(ctx context.Context, m *platform.DBRPMapping) error {
-	k := encodeDBRPMappingKey(m.Cluster, m.Database, m.RetentionPolicy)
+	k := encodeDBRPMappingKey(m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy)
 	s.dbrpMappingKV.Store(k, *m)
 	return nil
 }
 
-// Delete removes a dbrp mapping
-func (s *Service) Delete(ctx context.Context, cluster, db, rp string) error {
-	s.dbrpMappingKV.Delete(encodeDBRPMappingKey(cluster, db, rp))
+// Delete removes the dbrp mapping of the organization.
+func (s *Service) Delete(ctx context.Context, orgID platform.ID, cluster, db, rp string) error {
+	s.dbrpMappingKV.Delete(encodeDBRPMappingKey(orgID, cluster, db, rp))
 	return nil
 }
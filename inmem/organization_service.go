@@ -187,6 +187,14 @@ func (s *Service) CreateOrganization(ctx context.Context, o *platform.Organizati
 		}
 
 	}
+	if o.Quotas != nil {
+		if err := o.Quotas.Valid(); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+	}
 	o.ID = s.IDGenerator.ID()
 	err := s.PutOrganization(ctx, o)
 	if err != nil {
@@ -218,6 +226,19 @@ func (s *Service) UpdateOrganization(ctx context.Context, id platform.ID, upd pl
 		o.Name = *upd.Name
 	}
 
+	if upd.Quotas != nil {
+		if err := upd.Quotas.Valid(); err != nil {
+			return nil, &platform.Error{
+				Err: err,
+				Op:  OpPrefix + platform.OpUpdateOrganization,
+			}
+		}
+		o.Quotas = upd.Quotas
+		if o.Quotas.IsZero() {
+			o.Quotas = nil
+		}
+	}
+
 	s.organizationKV.Store(o.ID.String(), o)
 
 	return o, nil
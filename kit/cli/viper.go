@@ -42,6 +42,8 @@ type Program struct {
 // Uses the upper-case version of the program's name as a prefix
 // to all environment variables.
 //
+// The options are persistent flags so that subcommands accept them too.
+//
 // This is to simplify the viper/cobra boilerplate.
 func NewCommand(p *Program) *cobra.Command {
 	var cmd = &cobra.Command{
@@ -63,36 +65,36 @@ func NewCommand(p *Program) *cobra.Command {
 			if o.Default == nil {
 				o.Default = ""
 			}
-			cmd.Flags().StringVar(o.DestP.(*string), o.Flag, o.Default.(string), o.Desc)
-			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
+			cmd.PersistentFlags().StringVar(o.DestP.(*string), o.Flag, o.Default.(string), o.Desc)
+			viper.BindPFlag(o.Flag, cmd.PersistentFlags().Lookup(o.Flag))
 			*o.DestP.(*string) = viper.GetString(o.Flag)
 		case *int:
 			if o.Default == nil {
 				o.Default = 0
 			}
-			cmd.Flags().IntVar(o.DestP.(*int), o.Flag, o.Default.(int), o.Desc)
-			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
+			cmd.PersistentFlags().IntVar(o.DestP.(*int), o.Flag, o.Default.(int), o.Desc)
+			viper.BindPFlag(o.Flag, cmd.PersistentFlags().Lookup(o.Flag))
 			*o.DestP.(*int) = viper.GetInt(o.Flag)
 		case *bool:
 			if o.Default == nil {
 				o.Default = false
 			}
-			cmd.Flags().BoolVar(o.DestP.(*bool), o.Flag, o.Default.(bool), o.Desc)
-			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
+			cmd.PersistentFlags().BoolVar(o.DestP.(*bool), o.Flag, o.Default.(bool), o.Desc)
+			viper.BindPFlag(o.Flag, cmd.PersistentFlags().Lookup(o.Flag))
 			*o.DestP.(*bool) = viper.GetBool(o.Flag)
 		case *time.Duration:
 			if o.Default == nil {
 				o.Default = time.Duration(0)
 			}
-			cmd.Flags().DurationVar(o.DestP.(*time.Duration), o.Flag, o.Default.(time.Duration), o.Desc)
-			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
+			cmd.PersistentFlags().DurationVar(o.DestP.(*time.Duration), o.Flag, o.Default.(time.Duration), o.Desc)
+			viper.BindPFlag(o.Flag, cmd.PersistentFlags().Lookup(o.Flag))
 			*o.DestP.(*time.Duration) = viper.GetDuration(o.Flag)
 		case *[]string:
 			if o.Default == nil {
 				o.Default = []string{}
 			}
-			cmd.Flags().StringSliceVar(o.DestP.(*[]string), o.Flag, o.Default.([]string), o.Desc)
-			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
+			cmd.PersistentFlags().StringSliceVar(o.DestP.(*[]string), o.Flag, o.Default.([]string), o.Desc)
+			viper.BindPFlag(o.Flag, cmd.PersistentFlags().Lookup(o.Flag))
 			*o.DestP.(*[]string) = viper.GetStringSlice(o.Flag)
 		default:
 			// if you get a panic here, sorry about that!
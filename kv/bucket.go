@@ -373,6 +373,12 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) e
 		b.OrganizationID = o.ID
 	}
 
+	if b.Schema != nil {
+		if err := b.Schema.Valid(); err != nil {
+			return err
+		}
+	}
+
 	// if the bucket name is not unique for this organization, then, do not
 	// allow creation.
 	if err := s.uniqueBucketName(ctx, tx, b); err != nil {
@@ -394,6 +400,10 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) e
 	if err := s.createBucketUserResourceMappings(ctx, tx, b); err != nil {
 		return err
 	}
+
+	if err := s.createBucketDBRPMapping(ctx, tx, b); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -572,6 +582,16 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.Schema != nil {
+		if err := upd.Schema.Valid(); err != nil {
+			return nil, err
+		}
+		b.Schema = upd.Schema
+		if len(b.Schema.Measurements) == 0 {
+			b.Schema = nil
+		}
+	}
+
 	if upd.Name != nil {
 		key, err := bucketIndexKey(b)
 		if err != nil {
@@ -662,6 +682,10 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
+	if err := s.deleteBucketDBRPMappings(ctx, tx, id); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,299 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+
+	influxdb "github.com/influxdata/influxdb"
+)
+
+var (
+	dbrpMappingBucket = []byte("dbrpmappingsv1")
+)
+
+var _ influxdb.DBRPMappingService = (*Service)(nil)
+
+func (s *Service) initializeDBRPMappings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(dbrpMappingBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// dbrpMappingKey is the key of a dbrp mapping. The organization is the prefix
+// of the key so the mappings of each organization are separate.
+func dbrpMappingKey(orgID influxdb.ID, cluster, db, rp string) []byte {
+	return []byte(path.Join(orgID.String(), cluster, db, rp))
+}
+
+// FindBy returns the dbrp mapping for the organization, cluster, db and rp.
+func (s *Service) FindBy(ctx context.Context, orgID influxdb.ID, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	var m *influxdb.DBRPMapping
+	err := s.kv.View(func(tx Tx) error {
+		var err error
+		m, err = s.findDBRPMapping(ctx, tx, orgID, cluster, db, rp)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindDBRPMapping,
+			Err: err,
+		}
+	}
+	return m, nil
+}
+
+func (s *Service) findDBRPMapping(ctx context.Context, tx Tx, orgID influxdb.ID, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(dbrpMappingKey(orgID, cluster, db, rp))
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrDBRPMappingNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m influxdb.DBRPMapping
+	if err := json.Unmarshal(v, &m); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return &m, nil
+}
+
+// Find returns the first dbrp mapping that matches the filter.
+func (s *Service) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	if filter.Cluster == nil && filter.Database == nil && filter.RetentionPolicy == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpFindDBRPMapping,
+			Msg:  "no filter parameters provided",
+		}
+	}
+
+	ms, _, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindDBRPMapping,
+			Msg:  influxdb.ErrDBRPMappingNotFound,
+		}
+	}
+	return ms[0], nil
+}
+
+// FindMany returns the dbrp mappings that match the filter and the total
+// count of matching dbrp mappings. A filter with an organization, cluster,
+// database and retention policy is a single lookup and other filters scan
+// all mappings.
+func (s *Service) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	ms := []*influxdb.DBRPMapping{}
+	err := s.kv.View(func(tx Tx) error {
+		if filter.OrganizationID != nil && filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
+			m, err := s.findDBRPMapping(ctx, tx, *filter.OrganizationID, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if filterDBRPMappingFn(filter)(m) {
+				ms = append(ms, m)
+			}
+			return nil
+		}
+
+		filterFn := filterDBRPMappingFn(filter)
+		return s.forEachDBRPMapping(ctx, tx, func(m *influxdb.DBRPMapping) bool {
+			if filterFn(m) {
+				ms = append(ms, m)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindDBRPMappings,
+			Err: err,
+		}
+	}
+	return ms, len(ms), nil
+}
+
+func filterDBRPMappingFn(filter influxdb.DBRPMappingFilter) func(m *influxdb.DBRPMapping) bool {
+	return func(m *influxdb.DBRPMapping) bool {
+		return (filter.Cluster == nil || *filter.Cluster == m.Cluster) &&
+			(filter.Database == nil || *filter.Database == m.Database) &&
+			(filter.RetentionPolicy == nil || *filter.RetentionPolicy == m.RetentionPolicy) &&
+			(filter.Default == nil || *filter.Default == m.Default) &&
+			(filter.OrganizationID == nil || *filter.OrganizationID == m.OrganizationID)
+	}
+}
+
+// forEachDBRPMapping will iterate through all dbrp mappings while fn returns true.
+func (s *Service) forEachDBRPMapping(ctx context.Context, tx Tx, fn func(*influxdb.DBRPMapping) bool) error {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		m := &influxdb.DBRPMapping{}
+		if err := json.Unmarshal(v, m); err != nil {
+			return err
+		}
+		if !fn(m) {
+			break
+		}
+	}
+	return nil
+}
+
+// Create creates a new dbrp mapping. Creating a mapping that is identical to
+// an existing mapping is not an error.
+func (s *Service) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	err := s.kv.Update(func(tx Tx) error {
+		return s.createDBRPMapping(ctx, tx, m)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateDBRPMapping,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) createDBRPMapping(ctx context.Context, tx Tx, m *influxdb.DBRPMapping) error {
+	if err := m.Validate(); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}
+	}
+
+	existing, err := s.findDBRPMapping(ctx, tx, m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy)
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+	if existing != nil && !existing.Equal(m) {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  influxdb.ErrDBRPMappingExists,
+		}
+	}
+	return s.putDBRPMapping(ctx, tx, m)
+}
+
+func (s *Service) putDBRPMapping(ctx context.Context, tx Tx, m *influxdb.DBRPMapping) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(dbrpMappingKey(m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy), v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// Delete removes the dbrp mapping of the organization.
+// Deleting a mapping that does not exist is not an error.
+func (s *Service) Delete(ctx context.Context, orgID influxdb.ID, cluster, db, rp string) error {
+	err := s.kv.Update(func(tx Tx) error {
+		b, err := tx.Bucket(dbrpMappingBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(dbrpMappingKey(orgID, cluster, db, rp))
+	})
+	if err != nil && !IsNotFound(err) {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteDBRPMapping,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// createBucketDBRPMapping creates the dbrp mapping of a bucket with a retention
+// policy name so that 1.x clients and InfluxQL queries can find the bucket.
+// The database is the name of the bucket without a "/<retention policy>" suffix,
+// which allows buckets to be named like "telegraf/autogen". The mapping is the
+// default of the database if the database does not have a default in the
+// organization of the bucket yet.
+func (s *Service) createBucketDBRPMapping(ctx context.Context, tx Tx, b *influxdb.Bucket) error {
+	if b.RetentionPolicyName == "" {
+		return nil
+	}
+
+	m := &influxdb.DBRPMapping{
+		Cluster:         influxdb.DefaultDBRPCluster,
+		Database:        strings.TrimSuffix(b.Name, "/"+b.RetentionPolicyName),
+		RetentionPolicy: b.RetentionPolicyName,
+		Default:         true,
+		OrganizationID:  b.OrganizationID,
+		BucketID:        b.ID,
+	}
+	if err := s.forEachDBRPMapping(ctx, tx, func(o *influxdb.DBRPMapping) bool {
+		if o.OrganizationID == m.OrganizationID && o.Cluster == m.Cluster && o.Database == m.Database && o.Default {
+			m.Default = false
+		}
+		return m.Default
+	}); err != nil {
+		return err
+	}
+	return s.createDBRPMapping(ctx, tx, m)
+}
+
+// deleteBucketDBRPMappings removes the dbrp mappings of a bucket.
+func (s *Service) deleteBucketDBRPMappings(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	var ms []*influxdb.DBRPMapping
+	if err := s.forEachDBRPMapping(ctx, tx, func(m *influxdb.DBRPMapping) bool {
+		if m.BucketID == bucketID {
+			ms = append(ms, m)
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if err := b.Delete(dbrpMappingKey(m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy)); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+	}
+	return nil
+}
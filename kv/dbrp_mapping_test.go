@@ -0,0 +1,236 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltDBRPMappingService(t *testing.T) {
+	t.Run("CreateDBRPMapping", func(t *testing.T) { influxdbtesting.CreateDBRPMapping(initBoltDBRPMappingService, t) })
+	t.Run("FindDBRPMappingByKey", func(t *testing.T) { influxdbtesting.FindDBRPMappingByKey(initBoltDBRPMappingService, t) })
+	t.Run("FindDBRPMappings", func(t *testing.T) { influxdbtesting.FindDBRPMappings(initBoltDBRPMappingService, t) })
+	t.Run("FindDBRPMapping", func(t *testing.T) { influxdbtesting.FindDBRPMapping(initBoltDBRPMappingService, t) })
+	t.Run("DeleteDBRPMapping", func(t *testing.T) { influxdbtesting.DeleteDBRPMapping(initBoltDBRPMappingService, t) })
+}
+
+func TestInmemDBRPMappingService(t *testing.T) {
+	t.Run("CreateDBRPMapping", func(t *testing.T) { influxdbtesting.CreateDBRPMapping(initInmemDBRPMappingService, t) })
+	t.Run("FindDBRPMappingByKey", func(t *testing.T) { influxdbtesting.FindDBRPMappingByKey(initInmemDBRPMappingService, t) })
+	t.Run("FindDBRPMappings", func(t *testing.T) { influxdbtesting.FindDBRPMappings(initInmemDBRPMappingService, t) })
+	t.Run("FindDBRPMapping", func(t *testing.T) { influxdbtesting.FindDBRPMapping(initInmemDBRPMappingService, t) })
+	t.Run("DeleteDBRPMapping", func(t *testing.T) { influxdbtesting.DeleteDBRPMapping(initInmemDBRPMappingService, t) })
+}
+
+func initBoltDBRPMappingService(f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initDBRPMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemDBRPMappingService(f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	s, closeBolt, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initDBRPMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initDBRPMappingService(s kv.Store, f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing dbrp mapping service: %v", err)
+	}
+	if err := f.Populate(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+	return svc, func() {
+		if err := influxdbtesting.CleanupDBRPMappings(ctx, svc); err != nil {
+			t.Logf("failed to remove dbrp mappings: %v", err)
+		}
+	}
+}
+
+func TestService_BucketDBRPMappings(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{ID: influxdbtesting.MustIDBase16("020f755c3c083000"), Name: "theorg"}
+	if err := svc.PutOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	// A bucket without a retention policy name does not have a mapping.
+	svc.IDGenerator = mock.NewIDGenerator("020f755c3c082000", t)
+	plain := &influxdb.Bucket{OrganizationID: org.ID, Name: "plain"}
+	if err := svc.CreateBucket(ctx, plain); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first retention policy of a database is the default.
+	svc.IDGenerator = mock.NewIDGenerator("020f755c3c082001", t)
+	autogen := &influxdb.Bucket{OrganizationID: org.ID, Name: "telegraf/autogen", RetentionPolicyName: "autogen"}
+	if err := svc.CreateBucket(ctx, autogen); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.IDGenerator = mock.NewIDGenerator("020f755c3c082002", t)
+	weekly := &influxdb.Bucket{OrganizationID: org.ID, Name: "telegraf-weekly", RetentionPolicyName: "weekly"}
+	if err := svc.CreateBucket(ctx, weekly); err != nil {
+		t.Fatal(err)
+	}
+
+	ms, _, err := svc.FindMany(ctx, influxdb.DBRPMappingFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*influxdb.DBRPMapping{
+		{
+			Cluster:         influxdb.DefaultDBRPCluster,
+			Database:        "telegraf-weekly",
+			RetentionPolicy: "weekly",
+			Default:         true,
+			OrganizationID:  org.ID,
+			BucketID:        weekly.ID,
+		},
+		{
+			Cluster:         influxdb.DefaultDBRPCluster,
+			Database:        "telegraf",
+			RetentionPolicy: "autogen",
+			Default:         true,
+			OrganizationID:  org.ID,
+			BucketID:        autogen.ID,
+		},
+	}
+	if diff := cmp.Diff(want, ms); diff != "" {
+		t.Fatalf("unexpected dbrp mappings -want/+got:\n%s", diff)
+	}
+
+	svc.IDGenerator = mock.NewIDGenerator("020f755c3c082003", t)
+	monthly := &influxdb.Bucket{OrganizationID: org.ID, Name: "telegraf/monthly", RetentionPolicyName: "monthly"}
+	if err := svc.CreateBucket(ctx, monthly); err != nil {
+		t.Fatal(err)
+	}
+	m, err := svc.FindBy(ctx, org.ID, influxdb.DefaultDBRPCluster, "telegraf", "monthly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Default {
+		t.Error("expected the second retention policy of a database not to be the default")
+	}
+	if m.BucketID != monthly.ID {
+		t.Errorf("unexpected bucket id: got %s, want %s", m.BucketID, monthly.ID)
+	}
+
+	// Deleting a bucket deletes its mappings.
+	if err := svc.DeleteBucket(ctx, autogen.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindBy(ctx, org.ID, influxdb.DefaultDBRPCluster, "telegraf", "autogen"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the dbrp mapping of a deleted bucket to be not found, got %v", err)
+	}
+	if _, err := svc.FindBy(ctx, org.ID, influxdb.DefaultDBRPCluster, "telegraf", "monthly"); err != nil {
+		t.Errorf("unexpected error finding the dbrp mapping of another bucket: %v", err)
+	}
+}
+
+func TestService_BucketDBRPMappingsOfOrganizations(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org1 := &influxdb.Organization{ID: influxdbtesting.MustIDBase16("020f755c3c083000"), Name: "org1"}
+	org2 := &influxdb.Organization{ID: influxdbtesting.MustIDBase16("020f755c3c083001"), Name: "org2"}
+	for _, org := range []*influxdb.Organization{org1, org2} {
+		if err := svc.PutOrganization(ctx, org); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each organization has its own mappings, so the buckets of both
+	// organizations with the same name are the defaults of their database.
+	svc.IDGenerator = mock.NewIDGenerator("020f755c3c082001", t)
+	bucket1 := &influxdb.Bucket{OrganizationID: org1.ID, Name: "telegraf/autogen", RetentionPolicyName: "autogen"}
+	if err := svc.CreateBucket(ctx, bucket1); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.IDGenerator = mock.NewIDGenerator("020f755c3c082002", t)
+	bucket2 := &influxdb.Bucket{OrganizationID: org2.ID, Name: "telegraf/autogen", RetentionPolicyName: "autogen"}
+	if err := svc.CreateBucket(ctx, bucket2); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, db, defaultRP := influxdb.DefaultDBRPCluster, "telegraf", true
+	for _, b := range []*influxdb.Bucket{bucket1, bucket2} {
+		m, err := svc.Find(ctx, influxdb.DBRPMappingFilter{
+			Cluster:        &cluster,
+			Database:       &db,
+			Default:        &defaultRP,
+			OrganizationID: &b.OrganizationID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &influxdb.DBRPMapping{
+			Cluster:         influxdb.DefaultDBRPCluster,
+			Database:        "telegraf",
+			RetentionPolicy: "autogen",
+			Default:         true,
+			OrganizationID:  b.OrganizationID,
+			BucketID:        b.ID,
+		}
+		if diff := cmp.Diff(want, m); diff != "" {
+			t.Errorf("unexpected dbrp mapping -want/+got:\n%s", diff)
+		}
+	}
+
+	// Deleting the bucket of one organization leaves the mapping of the other.
+	if err := svc.DeleteBucket(ctx, bucket1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindBy(ctx, org1.ID, influxdb.DefaultDBRPCluster, "telegraf", "autogen"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the dbrp mapping of a deleted bucket to be not found, got %v", err)
+	}
+	if _, err := svc.FindBy(ctx, org2.ID, influxdb.DefaultDBRPCluster, "telegraf", "autogen"); err != nil {
+		t.Errorf("unexpected error finding the dbrp mapping of another organization: %v", err)
+	}
+}
@@ -238,6 +238,11 @@ func (s *Service) createOrganization(ctx context.Context, tx Tx, o *influxdb.Org
 	if err := s.uniqueOrganizationName(ctx, tx, o); err != nil {
 		return err
 	}
+	if o.Quotas != nil {
+		if err := o.Quotas.Valid(); err != nil {
+			return err
+		}
+	}
 
 	o.ID = s.IDGenerator.ID()
 	if err := s.appendOrganizationEventToLog(ctx, tx, o.ID, organizationCreatedEvent); err != nil {
@@ -383,6 +388,16 @@ func (s *Service) updateOrganization(ctx context.Context, tx Tx, id influxdb.ID,
 		o.Name = *upd.Name
 	}
 
+	if upd.Quotas != nil {
+		if err := upd.Quotas.Valid(); err != nil {
+			return nil, err
+		}
+		o.Quotas = upd.Quotas
+		if o.Quotas.IsZero() {
+			o.Quotas = nil
+		}
+	}
+
 	if err := s.appendOrganizationEventToLog(ctx, tx, o.ID, organizationUpdatedEvent); err != nil {
 		return nil, &influxdb.Error{
 			Err: err,
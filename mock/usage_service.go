@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.UsageService = (*UsageService)(nil)
+
+// UsageService is a mock implementation of a platform.UsageService.
+type UsageService struct {
+	GetUsageFn func(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error)
+}
+
+// NewUsageService returns a mock UsageService where its methods will return
+// zero values.
+func NewUsageService() *UsageService {
+	return &UsageService{
+		GetUsageFn: func(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+			return map[platform.UsageMetric]*platform.Usage{}, nil
+		},
+	}
+}
+
+// GetUsage returns the usage of the filter.
+func (s *UsageService) GetUsage(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+	return s.GetUsageFn(ctx, filter)
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PointsScanner parses line protocol from a reader one line at a time.
+// Unlike ParsePointsWithPrecision it does not need the whole input in memory,
+// and a line that fails to parse does not prevent the other lines from being read.
+//
+//	s := NewPointsScanner(r, mm, time.Now(), "ns")
+//	for s.Scan() {
+//		if err := s.LineErr(); err != nil {
+//			// line s.Line() was rejected
+//			continue
+//		}
+//		points = append(points, s.Points()...)
+//	}
+//	if err := s.Err(); err != nil {
+//		// reading failed
+//	}
+type PointsScanner struct {
+	r           *bufio.Reader
+	mm          []byte
+	defaultTime time.Time
+	precision   string
+
+	lines   int // number of lines read
+	line    int // number of the first line of the current block
+	points  []Point
+	lineErr error
+	err     error
+}
+
+// NewPointsScanner returns a scanner of the line protocol in r.
+func NewPointsScanner(r io.Reader, mm []byte, defaultTime time.Time, precision string) *PointsScanner {
+	return &PointsScanner{
+		r:           bufio.NewReader(r),
+		mm:          mm,
+		defaultTime: defaultTime,
+		precision:   precision,
+	}
+}
+
+// Scan advances to the next line of the input that is not empty or a comment.
+// It returns false at the end of the input or if reading the input fails.
+func (s *PointsScanner) Scan() bool {
+	for s.err == nil {
+		s.line = s.lines + 1
+		block, ok := s.readBlock()
+		if !ok {
+			return false
+		}
+
+		// lines which start with '#' are comments
+		start := skipWhitespace(block, 0)
+
+		// If line is all whitespace, just skip it
+		if start >= len(block) || block[start] == '#' || block[start] == '\n' {
+			continue
+		}
+
+		// strip the newline if one is present
+		if block[len(block)-1] == '\n' {
+			block = block[:len(block)-1]
+		}
+
+		// The points refer to the block, so the points of the previous
+		// line must not be reused.
+		s.points, s.lineErr = parsePointsAppend(nil, block[start:], s.mm, s.defaultTime, s.precision)
+		if s.lineErr != nil {
+			s.points = nil
+			s.lineErr = fmt.Errorf("unable to parse '%s': %v", string(block[start:]), s.lineErr)
+		}
+		return true
+	}
+	return false
+}
+
+// readBlock reads the next line of the input. A line ends with a newline that
+// is not within a quoted string field value.
+func (s *PointsScanner) readBlock() ([]byte, bool) {
+	var block []byte
+	for {
+		buf, err := s.r.ReadBytes('\n')
+		if block == nil {
+			block = buf
+		} else {
+			block = append(block, buf...)
+		}
+		s.lines += bytes.Count(buf, []byte{'\n'})
+
+		if err == io.EOF {
+			return block, len(block) > 0
+		} else if err != nil {
+			s.err = err
+			return nil, false
+		}
+
+		if i, _ := scanLine(block, 0); i < len(block) {
+			return block, true
+		}
+	}
+}
+
+// Line returns the number of the first line of the current line protocol
+// line, which may span more than one line of the input.
+func (s *PointsScanner) Line() int {
+	return s.line
+}
+
+// Points returns the points of the current line.
+func (s *PointsScanner) Points() []Point {
+	return s.points
+}
+
+// LineErr returns the reason the current line failed to parse.
+func (s *PointsScanner) LineErr() error {
+	return s.lineErr
+}
+
+// Err returns the error that stopped reading the input, if any.
+func (s *PointsScanner) Err() error {
+	return s.err
+}
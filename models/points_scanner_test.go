@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+func TestPointsScanner(t *testing.T) {
+	type line struct {
+		line   int
+		points []string
+		err    string
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		exp   []line
+	}{
+		{
+			name:  "valid lines",
+			input: "cpu value=1 1000\ncpu value=2,other=3 2000\n",
+			exp: []line{
+				{line: 1, points: []string{"mm,_f=value,_m=cpu value=1 1000"}},
+				{line: 2, points: []string{"mm,_f=value,_m=cpu value=2 2000", "mm,_f=other,_m=cpu other=3 2000"}},
+			},
+		},
+		{
+			name:  "invalid lines do not stop the scan",
+			input: "cpu value=1 1000\ncpu\ncpu value= 3000\ncpu value=4 4000",
+			exp: []line{
+				{line: 1, points: []string{"mm,_f=value,_m=cpu value=1 1000"}},
+				{line: 2, err: "unable to parse 'cpu': missing fields"},
+				{line: 3, err: "unable to parse 'cpu value= 3000': missing field value"},
+				{line: 4, points: []string{"mm,_f=value,_m=cpu value=4 4000"}},
+			},
+		},
+		{
+			name:  "comments and empty lines are skipped",
+			input: "# comment\n\n   \ncpu value=1 1000\n\n",
+			exp: []line{
+				{line: 4, points: []string{"mm,_f=value,_m=cpu value=1 1000"}},
+			},
+		},
+		{
+			name:  "newline in a string field",
+			input: "log msg=\"a\nb\" 1000\ncpu value=1 1000\n",
+			exp: []line{
+				{line: 1, points: []string{"mm,_f=msg,_m=log msg=\"a\nb\" 1000"}},
+				{line: 3, points: []string{"mm,_f=value,_m=cpu value=1 1000"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Read one byte at a time to check that lines are read across reads.
+			s := models.NewPointsScanner(iotest.OneByteReader(strings.NewReader(tt.input)), []byte("mm"), time.Now().UTC(), "ns")
+
+			var got []line
+			for s.Scan() {
+				l := line{line: s.Line()}
+				if err := s.LineErr(); err != nil {
+					l.err = err.Error()
+				}
+				for _, p := range s.Points() {
+					l.points = append(l.points, p.String())
+				}
+				got = append(got, l)
+			}
+			if err := s.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(tt.exp) {
+				t.Fatalf("unexpected number of lines: got %d, exp %d: %+v", len(got), len(tt.exp), got)
+			}
+			for i := range got {
+				if got[i].line != tt.exp[i].line || got[i].err != tt.exp[i].err || strings.Join(got[i].points, "\n") != strings.Join(tt.exp[i].points, "\n") {
+					t.Errorf("unexpected line %d:\ngot %+v\nexp %+v", i, got[i], tt.exp[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPointsScanner_ReadError(t *testing.T) {
+	s := models.NewPointsScanner(iotest.TimeoutReader(strings.NewReader("cpu value=1 1000\n")), []byte("mm"), time.Now().UTC(), "ns")
+
+	if !s.Scan() || s.LineErr() != nil {
+		t.Fatalf("expected the first line to parse: %v", s.LineErr())
+	}
+	if s.Scan() {
+		t.Fatal("expected the scan to stop on the read error")
+	}
+	if err := s.Err(); err != iotest.ErrTimeout {
+		t.Fatalf("unexpected error: got %v, exp %v", err, iotest.ErrTimeout)
+	}
+}
@@ -6,6 +6,9 @@ import "context"
 type Organization struct {
 	ID   ID     `json:"id,omitempty"`
 	Name string `json:"name"`
+	// Quotas limit the resources used by the organization. An organization
+	// without quotas is not limited.
+	Quotas *OrgQuotas `json:"quotas,omitempty"`
 }
 
 // ops for orgs error and orgs op logs.
@@ -45,6 +48,9 @@ type OrganizationService interface {
 // Only fields which are set are updated.
 type OrganizationUpdate struct {
 	Name *string
+	// Quotas replaces the quotas of the organization. Quotas without any
+	// limit remove them.
+	Quotas *OrgQuotas
 }
 
 // OrganizationFilter represents a set of filter that restrict the returned results.
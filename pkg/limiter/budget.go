@@ -0,0 +1,66 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget is a token bucket that limits a rate of bytes whose amount is only
+// known after they were used, such as the bytes of a request body. Spending
+// more than is available puts the budget into debt, and further use must wait
+// until the debt is paid back at the rate of the budget.
+type Budget struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewBudget returns a full budget that refills at bytesPerSec up to burstLimit.
+func NewBudget(bytesPerSec, burstLimit int64) *Budget {
+	return &Budget{
+		rate:   float64(bytesPerSec),
+		burst:  float64(burstLimit),
+		tokens: float64(burstLimit),
+		last:   time.Now(),
+	}
+}
+
+// Rate returns the rate at which the budget refills.
+func (b *Budget) Rate() int64 {
+	return int64(b.rate)
+}
+
+// Wait returns how long until the budget is out of debt, or zero if it may
+// be spent now.
+func (b *Budget) Wait() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Spend takes n tokens from the budget, going into debt if there are fewer.
+func (b *Budget) Spend(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	b.tokens -= float64(n)
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *Budget) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+}
@@ -0,0 +1,26 @@
+package limiter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/limiter"
+)
+
+func TestBudget_Wait(t *testing.T) {
+	b := limiter.NewBudget(1000, 1000)
+	if d := b.Wait(); d != 0 {
+		t.Fatalf("unexpected wait of full budget: %v", d)
+	}
+
+	b.Spend(1000)
+	if d := b.Wait(); d != 0 {
+		t.Fatalf("unexpected wait of empty budget: %v", d)
+	}
+
+	// 500 bytes of debt take half a second to pay back.
+	b.Spend(500)
+	if d := b.Wait(); d <= 400*time.Millisecond || d > 500*time.Millisecond {
+		t.Fatalf("unexpected wait of budget in debt: %v", d)
+	}
+}
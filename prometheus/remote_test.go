@@ -0,0 +1,98 @@
+package prometheus_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	pr "github.com/influxdata/influxdb/prometheus"
+)
+
+func TestWriteRequest_Marshal(t *testing.T) {
+	req := &pr.WriteRequest{
+		Timeseries: []*pr.TimeSeries{{
+			Labels:  []*pr.Label{{Name: "a", Value: "b"}},
+			Samples: []*pr.Sample{{Value: 1, Timestamp: 2}},
+		}},
+	}
+
+	// The encoding of the request by the protobuf definitions of Prometheus.
+	exp := []byte{
+		0x0a, 0x15, // timeseries
+		0x0a, 0x06, 0x0a, 0x01, 'a', 0x12, 0x01, 'b', // labels
+		0x12, 0x0b, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, 0x10, 0x02, // samples
+	}
+
+	got, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, exp) {
+		t.Fatalf("unexpected encoding:\ngot %x\nexp %x", got, exp)
+	}
+
+	var decoded pr.WriteRequest
+	if err := proto.Unmarshal(got, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&decoded, req) {
+		t.Fatalf("unexpected decoded request:\ngot %v\nexp %v", &decoded, req)
+	}
+}
+
+func TestPointsFromTimeSeries(t *testing.T) {
+	tests := []struct {
+		name    string
+		series  []*pr.TimeSeries
+		exp     []string
+		wantErr bool
+	}{
+		{
+			name: "labels are tags",
+			series: []*pr.TimeSeries{{
+				Labels: []*pr.Label{{Name: "job", Value: "api"}, {Name: "__name__", Value: "up"}},
+				Samples: []*pr.Sample{
+					{Value: 1, Timestamp: 1500000000000},
+					{Value: 0.5, Timestamp: 1500000001000},
+				},
+			}},
+			exp: []string{
+				"up,job=api value=1 1500000000000000000",
+				"up,job=api value=0.5 1500000001000000000",
+			},
+		},
+		{
+			name: "stale markers are dropped",
+			series: []*pr.TimeSeries{{
+				Labels:  []*pr.Label{{Name: "__name__", Value: "up"}},
+				Samples: []*pr.Sample{{Value: math.NaN(), Timestamp: 1500000000000}},
+			}},
+		},
+		{
+			name: "metric name is required",
+			series: []*pr.TimeSeries{{
+				Labels:  []*pr.Label{{Name: "job", Value: "api"}},
+				Samples: []*pr.Sample{{Value: 1, Timestamp: 1500000000000}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pts, err := pr.PointsFromTimeSeries(tt.series)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PointsFromTimeSeries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(pts) != len(tt.exp) {
+				t.Fatalf("PointsFromTimeSeries() got %d points, want %d", len(pts), len(tt.exp))
+			}
+			for i := range pts {
+				if got := pts[i].String(); got != tt.exp[i] {
+					t.Errorf("PointsFromTimeSeries()[%d] = %s, want %s", i, got, tt.exp[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package benchmarks_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+// tagSchemaPushedDown reports whether the plan reads tag keys or tag values
+// from storage.
+func tagSchemaPushedDown(ps *plan.PlanSpec) bool {
+	var pushed bool
+	ps.BottomUpWalk(func(pn plan.PlanNode) error {
+		if spec, ok := pn.ProcedureSpec().(*influxdb.PhysicalFromProcedureSpec); ok {
+			pushed = spec.TagKeysSet || spec.TagValuesSet
+		}
+		return nil
+	})
+	return pushed
+}
+
+// distinctValues returns the sorted distinct values of the _value column of
+// the tables. The tag keys and values read from storage are in a single
+// table, where the other plans produce a table for each group.
+func distinctValues(tables []*executetest.Table) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, tbl := range tables {
+		j := -1
+		for k, c := range tbl.ColMeta {
+			if c.Label == "_value" {
+				j = k
+			}
+		}
+		if j < 0 {
+			continue
+		}
+		for _, row := range tbl.Data {
+			v := row[j].(string)
+			if !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func tagSchemaQuery(call string) string {
+	return fmt.Sprintf(`import "influxdata/influxdb/schema"
+
+option now = () => %s
+
+schema.%s`, start.Add(time.Hour).Format(time.RFC3339), call)
+}
+
+func TestTagSchema(t *testing.T) {
+	e := newEngine(t, 3, 20, 10*time.Second)
+	defer e.Close()
+
+	pushDownRules := append(readRules, influxdb.PushDownReadTagKeysRule{}, influxdb.PushDownReadTagValuesRule{})
+	tests := []struct {
+		call string
+		want []string
+	}{
+		{
+			call: `tagKeys(bucket: "b")`,
+			want: []string{"_field", "_measurement", "_start", "_stop", "host"},
+		},
+		{
+			call: `measurementTagKeys(bucket: "b", measurement: "m")`,
+			want: []string{"_field", "_measurement", "_start", "_stop", "host"},
+		},
+		{
+			call: `tagValues(bucket: "b", tag: "host")`,
+			want: []string{"host0", "host1", "host2"},
+		},
+		{
+			call: `tagValues(bucket: "b", tag: "host", predicate: (r) => r.host != "host1")`,
+			want: []string{"host0", "host2"},
+		},
+		{
+			call: `tagValues(bucket: "b", tag: "_field", predicate: (r) => r._measurement == "m")`,
+			want: []string{"b", "f", "i", "s", "u"},
+		},
+		{
+			call: `measurementTagValues(bucket: "b", measurement: "other", tag: "host")`,
+		},
+		{
+			call: `measurements(bucket: "b")`,
+			want: []string{"m"},
+		},
+	}
+	for _, tt := range tests {
+		q := tagSchemaQuery(tt.call)
+		t.Run(tt.call, func(t *testing.T) {
+			if tagSchemaPushedDown(e.plan(t, q, readRules)) || !tagSchemaPushedDown(e.plan(t, q, pushDownRules)) {
+				t.Fatal("expected only the tag schema rules to push down the tag schema read")
+			}
+
+			want, err := e.query(t, q, readRules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := e.query(t, q, pushDownRules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tt.want, distinctValues(want)) {
+				t.Fatalf("unexpected values without push down -want/+got\n%s", cmp.Diff(tt.want, distinctValues(want)))
+			}
+			if !cmp.Equal(tt.want, distinctValues(got)) {
+				t.Errorf("unexpected values -want/+got\n%s", cmp.Diff(tt.want, distinctValues(got)))
+			}
+		})
+	}
+}
+
+func BenchmarkTagSchema(b *testing.B) {
+	e := newEngine(b, 100, 1000, 10*time.Second)
+	defer e.Close()
+
+	rules := map[string][]plan.Rule{
+		"storage": readRules,
+		"pushed":  append(readRules, influxdb.PushDownReadTagKeysRule{}, influxdb.PushDownReadTagValuesRule{}),
+	}
+	for _, call := range []string{`tagKeys(bucket: "b")`, `tagValues(bucket: "b", tag: "host")`} {
+		q := tagSchemaQuery(call)
+		for _, name := range []string{"storage", "pushed"} {
+			b.Run(call+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := e.query(b, q, rules[name]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMeasurementFields(t *testing.T) {
+	e := newEngine(t, 3, 20, 10*time.Second)
+	defer e.Close()
+
+	tests := []struct {
+		call string
+		want []*executetest.Table
+	}{
+		{
+			call: `measurementFields(bucket: "b", measurement: "m")`,
+			want: []*executetest.Table{{
+				KeyCols: []string{"_measurement"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_value", Type: flux.TString},
+					{Label: "type", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{"m", "b", "boolean"},
+					{"m", "f", "float"},
+					{"m", "i", "integer"},
+					{"m", "s", "string"},
+					{"m", "u", "unsigned"},
+				},
+			}},
+		},
+		{
+			call: `measurementFields(bucket: "b", measurement: "m", start: 2019-01-01T00:30:00Z)`,
+		},
+		{
+			call: `measurementFields(bucket: "b", measurement: "other")`,
+		},
+	}
+	for _, tt := range tests {
+		q := tagSchemaQuery(tt.call)
+		t.Run(tt.call, func(t *testing.T) {
+			got, err := e.query(t, q, readRules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			executetest.NormalizeTables(tt.want)
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package benchmarks_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/readservice"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	orgID    = platform.ID(1)
+	bucketID = platform.ID(2)
+)
+
+var start = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// readRules are the physical rules that push reads into storage, without the
+// rules that push windowed aggregates.
+var readRules = []plan.Rule{
+	physicalConverterRule{},
+	influxdb.FromConversionRule{},
+	influxdb.MergeFromRangeRule{},
+	influxdb.MergeFromFilterRule{},
+	influxdb.FromDistinctRule{},
+	influxdb.MergeFromGroupRule{},
+	influxdb.FromKeysRule{},
+}
+
+// physicalConverterRule converts the logical nodes to physical nodes, as the
+// physical planner does with its default rules.
+type physicalConverterRule struct{}
+
+func (physicalConverterRule) Name() string          { return "physicalConverterRule" }
+func (physicalConverterRule) Pattern() plan.Pattern { return plan.Any() }
+
+func (physicalConverterRule) Rewrite(pn plan.PlanNode) (plan.PlanNode, bool, error) {
+	ln, ok := pn.(*plan.LogicalPlanNode)
+	if !ok {
+		return pn, false, nil
+	}
+	spec, ok := ln.Spec.(plan.PhysicalProcedureSpec)
+	if !ok {
+		return pn, false, nil
+	}
+	newNode := plan.CreatePhysicalNode(pn.ID(), spec)
+	plan.ReplaceNode(pn, newNode)
+	return newNode, true, nil
+}
+
+// engine is a storage engine with a bucket of test data.
+type engine struct {
+	path   string
+	engine *storage.Engine
+	deps   execute.Dependencies
+}
+
+// newEngine returns an engine with series of every field type, and points
+// every interval for n intervals that skip every seventh interval.
+func newEngine(tb testing.TB, series, n int, interval time.Duration) *engine {
+	path, err := ioutil.TempDir("", "window_aggregate")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	e := &engine{path: path, engine: storage.NewEngine(path, storage.NewConfig())}
+	if err := e.engine.Open(); err != nil {
+		e.Close()
+		tb.Fatal(err)
+	}
+
+	var points []models.Point
+	for s := 0; s < series; s++ {
+		tags := models.NewTags(map[string]string{"host": fmt.Sprintf("host%d", s)})
+		for i := 0; i < n; i++ {
+			if i%7 == 3 {
+				continue
+			}
+			v := (i*31 + s*17) % 101
+			pt, err := models.NewPoint("m", tags, models.Fields{
+				"f": float64(v) / 4,
+				"i": int64(v - 50),
+				"u": uint64(v),
+				"s": fmt.Sprintf("v%03d", v),
+				"b": v%2 == 0,
+			}, start.Add(time.Duration(i)*interval))
+			if err != nil {
+				tb.Fatal(err)
+			}
+			points = append(points, pt)
+		}
+	}
+
+	points, err = tsdb.ExplodePoints(orgID, bucketID, points)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := e.engine.WritePoints(context.Background(), points); err != nil {
+		e.Close()
+		tb.Fatal(err)
+	}
+
+	e.deps = make(execute.Dependencies)
+	if err := influxdb.InjectFromDependencies(e.deps, influxdb.Dependencies{
+		Reader:             reads.NewReader(readservice.NewStore(e.engine)),
+		BucketLookup:       bucketLookup{},
+		OrganizationLookup: organizationLookup{},
+	}); err != nil {
+		tb.Fatal(err)
+	}
+	return e
+}
+
+func (e *engine) Close() {
+	e.engine.Close()
+	os.RemoveAll(e.path)
+}
+
+// plan plans the query with the physical rules.
+func (e *engine) plan(tb testing.TB, q string, rules []plan.Rule) *plan.PlanSpec {
+	spec, err := flux.Compile(context.Background(), q, start)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	lp := plan.NewLogicalPlanner()
+	ps, err := lp.CreateInitialPlan(spec)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if ps, err = lp.Plan(ps); err != nil {
+		tb.Fatal(err)
+	}
+	if ps, err = plan.NewPhysicalPlanner(plan.OnlyPhysicalRules(rules...)).Plan(ps); err != nil {
+		tb.Fatal(err)
+	}
+	return ps
+}
+
+// query runs the query, planned with the physical rules, and returns the
+// tables of its result.
+func (e *engine) query(tb testing.TB, q string, rules []plan.Rule) ([]*executetest.Table, error) {
+	ctx := query.ContextWithRequest(context.Background(), &query.Request{OrganizationID: orgID})
+	results, _, err := execute.NewExecutor(e.deps, zap.NewNop()).Execute(ctx, e.plan(tb, q, rules), &memory.Allocator{})
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []*executetest.Table
+	for _, res := range results {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			t, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			tables = append(tables, t)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	sort.Sort(executetest.SortedTables(tables))
+	return tables, nil
+}
+
+// pushedDown reports whether the plan reads a windowed aggregate from storage.
+func pushedDown(ps *plan.PlanSpec) bool {
+	var pushed bool
+	ps.BottomUpWalk(func(pn plan.PlanNode) error {
+		if spec, ok := pn.ProcedureSpec().(*influxdb.PhysicalFromProcedureSpec); ok {
+			pushed = spec.WindowSet && spec.AggregateSet
+		}
+		return nil
+	})
+	return pushed
+}
+
+type bucketLookup struct{}
+
+func (bucketLookup) Lookup(orgID platform.ID, name string) (platform.ID, bool) { return bucketID, true }
+
+type organizationLookup struct{}
+
+func (organizationLookup) Lookup(ctx context.Context, name string) (platform.ID, bool) {
+	return orgID, true
+}
+
+func windowAggregateQuery(field, fn string, every time.Duration, createEmpty bool) string {
+	return fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: %s, stop: %s)
+	|> filter(fn: (r) => r._measurement == "m" and r._field == %q)
+	|> window(every: %s, createEmpty: %t)
+	|> %s()`,
+		bucketID, start.Add(-90*time.Second).Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339),
+		field, every, createEmpty, fn)
+}
+
+func TestWindowAggregate(t *testing.T) {
+	e := newEngine(t, 2, 200, 10*time.Second)
+	defer e.Close()
+
+	pushDownRules := append(readRules, influxdb.PushDownWindowAggregateRules()...)
+	for _, fn := range []string{"count", "sum", "mean", "min", "max", "first", "last"} {
+		for _, field := range []string{"f", "i", "u", "s", "b"} {
+			for _, createEmpty := range []bool{false, true} {
+				q := windowAggregateQuery(field, fn, 3*time.Minute, createEmpty)
+				t.Run(fmt.Sprintf("%s %s createEmpty=%t", fn, field, createEmpty), func(t *testing.T) {
+					if pushedDown(e.plan(t, q, readRules)) || !pushedDown(e.plan(t, q, pushDownRules)) {
+						t.Fatal("expected only the window aggregate rules to push down the window aggregate")
+					}
+
+					want, wantErr := e.query(t, q, readRules)
+					got, err := e.query(t, q, pushDownRules)
+					if (wantErr != nil) != (err != nil) {
+						t.Fatalf("unexpected error: got %v, want %v", err, wantErr)
+					}
+					if wantErr == nil && len(want) == 0 {
+						t.Fatal("expected tables")
+					}
+					if !cmp.Equal(want, got, cmpopts.EquateApprox(0, 1e-12)) {
+						t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-12)))
+					}
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkWindowAggregate(b *testing.B) {
+	e := newEngine(b, 10, 10000, 10*time.Second)
+	defer e.Close()
+
+	rules := map[string][]plan.Rule{
+		"storage": readRules,
+		"pushed":  append(readRules, influxdb.PushDownWindowAggregateRules()...),
+	}
+	for _, fn := range []string{"count", "mean", "max", "last"} {
+		q := windowAggregateQuery("f", fn, 5*time.Minute, true)
+		for _, name := range []string{"storage", "pushed"} {
+			b.Run(fn+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := e.query(b, q, rules[name]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -3,11 +3,15 @@ package control
 
 import (
 	"context"
+	"encoding/json"
+	"math"
+	"sync"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/control"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/quota"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -16,24 +20,71 @@ const orgLabel = "org"
 
 // Controller implements AsyncQueryService by consuming a control.Controller.
 type Controller struct {
-	c *control.Controller
+	c      *control.Controller
+	usage  platform.UsageRecorder
+	quotas *quota.Enforcer
+
+	// memoryBytesQuota is the memory all queries may use together.
+	memoryBytesQuota int64
 }
 
 // NewController creates a new Controller specific to platform.
 func New(config control.Config) *Controller {
 	config.MetricLabelKeys = append(config.MetricLabelKeys, orgLabel)
+	// Without a memory quota, queries whose memory is limited by the quota of
+	// their organization would never be admitted.
+	if config.MemoryBytesQuota <= 0 {
+		config.MemoryBytesQuota = math.MaxInt64
+	}
 	c := control.New(config)
-	return &Controller{c: c}
+	return &Controller{c: c, memoryBytesQuota: config.MemoryBytesQuota}
+}
+
+// WithUsageRecorder sets the recorder of the number and size of the queries
+// of each organization.
+func (c *Controller) WithUsageRecorder(r platform.UsageRecorder) {
+	c.usage = r
+}
+
+// WithQuotaEnforcer sets the enforcer of the concurrent queries and query
+// memory quotas of each organization.
+func (c *Controller) WithQuotaEnforcer(e *quota.Enforcer) {
+	c.quotas = e
 }
 
 // Query satisfies the AsyncQueryService while ensuring the request is propagated on the context.
 func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query, error) {
+	if c.usage != nil {
+		c.usage.RecordUsage(req.OrganizationID, 0, platform.UsageQueryRequestCount, 1)
+		c.usage.RecordUsage(req.OrganizationID, 0, platform.UsageQueryRequestBytes, float64(queryBytes(req.Compiler)))
+	}
+
+	compiler := req.Compiler
+	release := func() {}
+	if c.quotas != nil {
+		var (
+			memory int64
+			err    error
+		)
+		release, memory, err = c.quotas.StartQuery(ctx, req.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if memory > 0 {
+			if memory > c.memoryBytesQuota {
+				memory = c.memoryBytesQuota
+			}
+			compiler = memoryCompiler{Compiler: compiler, memory: memory}
+		}
+	}
+
 	// Set the request on the context so platform specific Flux operations can retrieve it later.
 	ctx = query.ContextWithRequest(ctx, req)
 	// Set the org label value for controller metrics
 	ctx = context.WithValue(ctx, orgLabel, req.OrganizationID.String())
-	q, err := c.c.Query(ctx, req.Compiler)
+	q, err := c.c.Query(ctx, compiler)
 	if err != nil {
+		release()
 		// If the controller reports an error, it's usually because of a syntax error
 		// or other problem that the client must fix.
 		return q, &platform.Error{
@@ -42,7 +93,7 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 		}
 	}
 
-	return q, nil
+	return &quotaQuery{Query: q, release: release}, nil
 }
 
 // PrometheusCollectors satisifies the prom.PrometheusCollector interface.
@@ -54,3 +105,42 @@ func (c *Controller) PrometheusCollectors() []prometheus.Collector {
 func (c *Controller) Shutdown(ctx context.Context) error {
 	return c.c.Shutdown(ctx)
 }
+
+// queryBytes returns the size of the JSON encoding of the query of a compiler.
+func queryBytes(c flux.Compiler) int {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// memoryCompiler limits the memory of the queries it compiles.
+type memoryCompiler struct {
+	flux.Compiler
+	memory int64
+}
+
+func (c memoryCompiler) Compile(ctx context.Context) (*flux.Spec, error) {
+	spec, err := c.Compiler.Compile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if q := spec.Resources.MemoryBytesQuota; q == 0 || q > c.memory {
+		spec.Resources.MemoryBytesQuota = c.memory
+	}
+	return spec, nil
+}
+
+// quotaQuery releases the concurrent queries quota of its organization when
+// the query is done.
+type quotaQuery struct {
+	flux.Query
+	release func()
+	once    sync.Once
+}
+
+func (q *quotaQuery) Done() {
+	q.Query.Done()
+	q.once.Do(q.release)
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -25,6 +28,9 @@ type Compiler struct {
 	Query   string     `json:"query"`
 	Now     *time.Time `json:"now,omitempty"`
 
+	// OrganizationID is the organization of the dbrp mappings of the query.
+	OrganizationID platform.ID `json:"organization_id,omitempty"`
+
 	dbrpMappingSvc platform.DBRPMappingService
 }
 
@@ -49,13 +55,50 @@ func (c *Compiler) Compile(ctx context.Context) (*flux.Spec, error) {
 			DefaultDatabase:        c.DB,
 			DefaultRetentionPolicy: c.RP,
 			Now:                    now,
+			OrganizationID:         c.OrganizationID,
 		},
 	)
 	astPkg, err := transpiler.Transpile(ctx, c.Query)
 	if err != nil {
 		return nil, err
 	}
-	return flux.CompileAST(ctx, astPkg, now)
+	return compileAST(astPkg, now)
+}
+
+// compileAST is flux.CompileAST with each of the side effects only once. The
+// result of a yield is both a side effect and the value of its statement, and
+// flux compares the side effects by comparing each of the tables they read,
+// which takes exponential time in the number of transformations in the query.
+func compileAST(astPkg *ast.Package, now time.Time) (*flux.Spec, error) {
+	sideEffects, _, err := flux.EvalAST(astPkg, flux.SetOption("now", nowFunc(now)))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[*flux.TableObject]bool, len(sideEffects))
+	unique := sideEffects[:0]
+	for _, v := range sideEffects {
+		if tbl, ok := v.(*flux.TableObject); ok {
+			if seen[tbl] {
+				continue
+			}
+			seen[tbl] = true
+		}
+		unique = append(unique, v)
+	}
+	return flux.ToSpec(unique, now)
+}
+
+// nowFunc returns the now option of a query evaluated at now.
+func nowFunc(now time.Time) values.Function {
+	timeVal := values.NewTime(values.ConvertTime(now))
+	ftype := semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+		Return: semantic.Time,
+	})
+	call := func(args values.Object) (values.Value, error) {
+		return timeVal, nil
+	}
+	return values.NewFunction("now", ftype, call, false)
 }
 func (c *Compiler) CompilerType() flux.CompilerType {
 	return CompilerType
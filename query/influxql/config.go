@@ -2,6 +2,8 @@ package influxql
 
 import (
 	"time"
+
+	platform "github.com/influxdata/influxdb"
 )
 
 // Config modifies the behavior of the Transpiler.
@@ -10,4 +12,8 @@ type Config struct {
 	DefaultRetentionPolicy string
 	Now                    time.Time
 	Cluster                string
+
+	// OrganizationID restricts the databases to the dbrp mappings of the
+	// organization when it is valid.
+	OrganizationID platform.ID
 }
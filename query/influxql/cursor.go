@@ -1,7 +1,11 @@
 package influxql
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"sort"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -33,27 +37,132 @@ type varRefCursor struct {
 // createVarRefCursor creates a new cursor from a variable reference using the sources
 // in the transpilerState.
 func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error) {
-	if len(t.stmt.Sources) != 1 {
-		// TODO(jsternberg): Support multiple sources.
-		return nil, errors.New("unimplemented: only one source is allowed")
-	}
-
-	// Only support a direct measurement. Subqueries are not supported yet.
-	mm, ok := t.stmt.Sources[0].(*influxql.Measurement)
-	if !ok {
-		return nil, errors.New("unimplemented: source must be a measurement")
+	expr, err := createSourcesExpr(t, []influxql.Expr{ref}, false)
+	if err != nil {
+		return nil, err
 	}
+	return &varRefCursor{
+		expr: expr,
+		ref:  ref,
+	}, nil
+}
 
-	// Create the from spec and add it to the list of operations.
-	from, err := t.from(mm)
+// createSourcesExpr reads the fields from the sources in the transpilerState. Each
+// of the fields is a variable reference, a wildcard or a regex. If there are multiple
+// sources, the tables of each of them are combined with a union. When pivoted is
+// true, each of the fields is read into its own column rather than the value column.
+func createSourcesExpr(t *transpilerState, fields []influxql.Expr, pivoted bool) (ast.Expression, error) {
+	tr, err := t.queryTimeRange()
 	if err != nil {
 		return nil, err
 	}
 
+	// The subqueries may each select only some of the fields. When there is more
+	// than one source, the fields of every source are read into the value column
+	// and pivoted together so a missing field is a null value rather than a missing
+	// column.
+	sources := t.fieldSources(fields)
+	combined := pivoted && len(sources) > 1 && hasSubQuery(sources)
+
+	exprs := make([]ast.Expression, 0, len(sources))
+	for _, source := range sources {
+		var expr ast.Expression
+		switch source := source.(type) {
+		case *influxql.Measurement:
+			expr, err = createMeasurementExpr(t, source, fields, tr)
+			if err == nil && pivoted && !combined {
+				expr = pivot(expr)
+			}
+		case *influxql.SubQuery:
+			// The columns of a subquery are already the fields it selected.
+			if combined {
+				expr, err = createSubQueryFieldsExpr(t, source, fields, tr)
+				break
+			} else if pivoted {
+				expr, err = createSubQueryExpr(t, source, nil, tr)
+				break
+			}
+
+			expr, err = createSubQueryExpr(t, source, fields[0], tr)
+		default:
+			err = fmt.Errorf("unsupported source type: %T", source)
+		}
+		if err != nil {
+			return nil, err
+		} else if expr != nil {
+			exprs = append(exprs, expr)
+		}
+	}
+
+	if len(exprs) == 0 {
+		return nil, errors.New("none of the sources select the fields")
+	}
+	expr := exprs[0]
+	if len(exprs) > 1 {
+		expr = union(t, exprs)
+	}
+	if combined {
+		// Every series is pivoted into a single table. The start and stop of the
+		// subqueries are not the same so they are not a part of the group key.
+		expr = pipeCall(expr, &ast.Identifier{Name: "group"},
+			&ast.Property{
+				Key: &ast.Identifier{Name: "columns"},
+				Value: stringArray(
+					execute.DefaultTimeColLabel,
+					execute.DefaultStartColLabel,
+					execute.DefaultStopColLabel,
+					execute.DefaultValueColLabel,
+					"_field",
+				),
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "mode"},
+				Value: &ast.StringLiteral{Value: "except"},
+			},
+		)
+		expr = pivot(sortByTime(expr))
+	}
+	return expr, nil
+}
+
+// hasSubQuery returns true if any of the sources is a subquery.
+func hasSubQuery(sources influxql.Sources) bool {
+	for _, source := range sources {
+		if _, ok := source.(*influxql.SubQuery); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldSources returns the sources that may have the fields. A subquery that does
+// not select a field has none of its values, so it is only read when none of the
+// sources select the field.
+func (t *transpilerState) fieldSources(fields []influxql.Expr) influxql.Sources {
+	ref, ok := fields[0].(*influxql.VarRef)
+	if !ok || len(fields) > 1 {
+		return t.stmt.Sources
+	}
+
+	sources := make(influxql.Sources, 0, len(t.stmt.Sources))
+	for _, source := range t.stmt.Sources {
+		if sq, ok := source.(*influxql.SubQuery); ok && !selectsColumn(sq.Statement, ref.Val) {
+			continue
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return t.stmt.Sources
+	}
+	return sources
+}
+
+// queryTimeRange returns the time range that the statement reads.
+func (t *transpilerState) queryTimeRange() (influxql.TimeRange, error) {
 	valuer := influxql.NowValuer{Now: t.config.Now}
 	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
 	if err != nil {
-		return nil, err
+		return influxql.TimeRange{}, err
 	}
 
 	// If the maximum is not set and we have a windowing function, then
@@ -64,79 +173,525 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 		}
 	}
 
-	range_ := &ast.PipeExpression{
-		Argument: from,
+	// A subquery is limited to the time range of the query that contains it.
+	return tr.Intersect(t.timeRange), nil
+}
+
+// createMeasurementExpr reads the fields from the measurements matched by mm.
+func createMeasurementExpr(t *transpilerState, mm *influxql.Measurement, fields []influxql.Expr, tr influxql.TimeRange) (ast.Expression, error) {
+	// Create the from spec and add it to the list of operations.
+	from, err := t.from(mm)
+	if err != nil {
+		return nil, err
+	}
+
+	var measurement ast.Expression = &ast.BinaryExpression{
+		Operator: ast.EqualOperator,
+		Left: &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: "_measurement"},
+		},
+		Right: &ast.StringLiteral{
+			Value: mm.Name,
+		},
+	}
+	if mm.Regex != nil {
+		measurement = &ast.BinaryExpression{
+			Operator: ast.RegexpMatchOperator,
+			Left: &ast.MemberExpression{
+				Object:   &ast.Identifier{Name: "r"},
+				Property: &ast.Identifier{Name: "_measurement"},
+			},
+			Right: &ast.RegexpLiteral{
+				Value: mm.Regex.Val,
+			},
+		}
+	}
+
+	// A wildcard reads all of the fields so it does not filter them.
+	body := measurement
+	if field := fieldFilterExpr(fields); field != nil {
+		body = &ast.LogicalExpression{
+			Operator: ast.AndOperator,
+			Left:     measurement,
+			Right:    field,
+		}
+	}
+
+	return &ast.PipeExpression{
+		Argument: rangeExpr(from, tr),
 		Call: &ast.CallExpression{
 			Callee: &ast.Identifier{
-				Name: "range",
+				Name: "filter",
 			},
 			Arguments: []ast.Expression{
 				&ast.ObjectExpression{
 					Properties: []*ast.Property{
 						{
 							Key: &ast.Identifier{
-								Name: "start",
-							},
-							Value: &ast.DateTimeLiteral{
-								Value: tr.MinTime().UTC(),
-							},
-						},
-						{
-							Key: &ast.Identifier{
-								Name: "stop",
+								Name: "fn",
 							},
-							Value: &ast.DateTimeLiteral{
-								Value: tr.MaxTime().UTC(),
+							Value: &ast.FunctionExpression{
+								Params: []*ast.Property{{
+									Key: &ast.Identifier{
+										Name: "r",
+									},
+								}},
+								Body: body,
 							},
 						},
 					},
 				},
 			},
 		},
+	}, nil
+}
+
+// fieldFilterExpr creates the expression that matches the field keys of the fields.
+// It returns nil if any of the fields is a wildcard.
+func fieldFilterExpr(fields []influxql.Expr) ast.Expression {
+	var expr ast.Expression
+	for i := len(fields) - 1; i >= 0; i-- {
+		var match ast.Expression
+		switch f := fields[i].(type) {
+		case *influxql.Wildcard:
+			return nil
+		case *influxql.RegexLiteral:
+			match = &ast.BinaryExpression{
+				Operator: ast.RegexpMatchOperator,
+				Left: &ast.MemberExpression{
+					Object:   &ast.Identifier{Name: "r"},
+					Property: &ast.Identifier{Name: "_field"},
+				},
+				Right: &ast.RegexpLiteral{
+					Value: f.Val,
+				},
+			}
+		case *influxql.VarRef:
+			match = &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left: &ast.MemberExpression{
+					Object:   &ast.Identifier{Name: "r"},
+					Property: &ast.Identifier{Name: "_field"},
+				},
+				Right: &ast.StringLiteral{
+					Value: f.Val,
+				},
+			}
+		default:
+			continue
+		}
+
+		if expr == nil {
+			expr = match
+			continue
+		}
+		expr = &ast.LogicalExpression{
+			Operator: ast.OrOperator,
+			Left:     match,
+			Right:    expr,
+		}
+	}
+	return expr
+}
+
+// createSubQueryExpr reads the results of a subquery. The column of the variable
+// reference, if the field is one, is duplicated into the value column so the results
+// of the subquery can be used in the same way as the raw values of a field. The
+// other columns are kept so the tags of the subquery can be referenced and grouped.
+// The columns matched by a wildcard or a regex are read into the value column with
+// the name of each column as the field key instead.
+func createSubQueryExpr(t *transpilerState, sq *influxql.SubQuery, field influxql.Expr, tr influxql.TimeRange) (ast.Expression, error) {
+	// A subquery without an order inherits the order of the query itself.
+	if len(sq.Statement.SortFields) > 0 && sq.Statement.TimeAscending() != t.stmt.TimeAscending() {
+		return nil, errors.New("subqueries must be ordered in the same direction as the query itself")
+	}
+
+	key := subQueryKey{sq: sq}
+	switch field.(type) {
+	case *influxql.Wildcard, *influxql.RegexLiteral:
+		key.unpivoted = true
 	}
 
-	expr := &ast.PipeExpression{
-		Argument: range_,
+	// The results of the subquery are assigned to a variable the first time
+	// they are read so each of the fields reads the same results.
+	var expr ast.Expression
+	if ident, ok := t.subqueries[key]; ok {
+		expr = ident
+	} else {
+		sub := t.subquery(tr)
+		sub.unpivoted = key.unpivoted
+		cur, err := sub.transpileSelect(context.TODO(), t.inheritDimensions(t.inheritInterval(sq.Statement)))
+		if err != nil {
+			return nil, err
+		}
+
+		// The range sets the start and stop of every table to the time range of this query.
+		ident := t.assignment(rangeExpr(cur.Expr(), tr))
+		t.subqueries[key] = ident
+		expr = ident
+	}
+
+	switch field := field.(type) {
+	case *influxql.VarRef:
+		return pipeCall(expr, &ast.Identifier{Name: "duplicate"},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: field.Val},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "as"},
+				Value: &ast.StringLiteral{Value: execute.DefaultValueColLabel},
+			},
+		), nil
+	case *influxql.RegexLiteral:
+		return pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+			Key: &ast.Identifier{Name: "fn"},
+			Value: &ast.FunctionExpression{
+				Params: []*ast.Property{{
+					Key: &ast.Identifier{Name: "r"},
+				}},
+				Body: &ast.BinaryExpression{
+					Operator: ast.RegexpMatchOperator,
+					Left: &ast.MemberExpression{
+						Object:   &ast.Identifier{Name: "r"},
+						Property: &ast.Identifier{Name: "_field"},
+					},
+					Right: &ast.RegexpLiteral{Value: field.Val},
+				},
+			},
+		}), nil
+	}
+	return expr, nil
+}
+
+// createSubQueryFieldsExpr reads each of the fields that the subquery selects
+// into the value column with the name of the field as the field key, which is
+// how the fields of a measurement are read. The other fields are skipped and
+// the expression is nil if the subquery selects none of them.
+func createSubQueryFieldsExpr(t *transpilerState, sq *influxql.SubQuery, fields []influxql.Expr, tr influxql.TimeRange) (ast.Expression, error) {
+	columns := make(map[string]bool)
+	for _, name := range sq.Statement.ColumnNames() {
+		columns[name] = true
+	}
+
+	// The columns matched by a wildcard or a regex are read on their own and
+	// the variable references are skipped when they are matched by one of them.
+	var (
+		refs     []*influxql.VarRef
+		wildcard bool
+		patterns []*regexp.Regexp
+	)
+	exprs := make([]ast.Expression, 0, len(fields))
+	for _, f := range fields {
+		switch f := f.(type) {
+		case *influxql.Wildcard:
+			wildcard = true
+		case *influxql.RegexLiteral:
+			patterns = append(patterns, f.Val)
+		default:
+			continue
+		}
+		expr, err := createSubQueryExpr(t, sq, f, tr)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	for _, f := range fields {
+		ref, ok := f.(*influxql.VarRef)
+		if !ok || !columns[ref.Val] || wildcard {
+			continue
+		}
+		var matched bool
+		for _, re := range patterns {
+			matched = matched || re.MatchString(ref.Val)
+		}
+		if !matched {
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, ref := range refs {
+		in, err := createSubQueryExpr(t, sq, nil, tr)
+		if err != nil {
+			return nil, err
+		}
+		expr := pipeCall(in, &ast.Identifier{Name: "duplicate"},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: ref.Val},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "as"},
+				Value: &ast.StringLiteral{Value: execute.DefaultValueColLabel},
+			},
+		)
+		exprs = append(exprs, renameField(expr, &ast.StringLiteral{Value: ref.Val}))
+	}
+	if len(exprs) == 0 {
+		return nil, nil
+	} else if len(exprs) > 1 {
+		return union(t, exprs), nil
+	}
+	return exprs[0], nil
+}
+
+// selectsColumn returns false if the results of the statement cannot have a
+// column with the name. The columns of a statement that selects a wildcard or
+// groups by a wildcard are not known, so it may have any of them.
+func selectsColumn(stmt *influxql.SelectStatement, name string) bool {
+	for _, d := range stmt.Dimensions {
+		switch expr := d.Expr.(type) {
+		case *influxql.VarRef:
+			if expr.Val == name {
+				return true
+			}
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			return true
+		}
+	}
+
+	var wildcard bool
+	influxql.WalkFunc(stmt.Fields, func(n influxql.Node) {
+		switch n.(type) {
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			wildcard = true
+		}
+	})
+	if wildcard {
+		return true
+	}
+
+	for _, column := range stmt.ColumnNames() {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritDimensions returns the subquery grouped by the tags of this query too
+// so the series of the subquery have the tags that this query groups by.
+func (t *transpilerState) inheritDimensions(stmt *influxql.SelectStatement) *influxql.SelectStatement {
+	var dimensions influxql.Dimensions
+	for _, d := range t.stmt.Dimensions {
+		switch d.Expr.(type) {
+		case *influxql.VarRef, *influxql.Wildcard, *influxql.RegexLiteral:
+		default:
+			continue
+		}
+
+		var found bool
+		for _, sd := range stmt.Dimensions {
+			if sd.Expr.String() == d.Expr.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dimensions = append(dimensions, &influxql.Dimension{Expr: d.Expr})
+		}
+	}
+	if len(dimensions) == 0 {
+		return stmt
+	}
+
+	stmt = stmt.Clone()
+	stmt.Dimensions = append(stmt.Dimensions, dimensions...)
+	return stmt
+}
+
+// inheritInterval returns the subquery with the GROUP BY interval of this
+// query when the subquery calls a function without an interval of its own.
+func (t *transpilerState) inheritInterval(stmt *influxql.SelectStatement) *influxql.SelectStatement {
+	if interval, err := stmt.GroupByInterval(); err != nil || interval > 0 {
+		return stmt
+	}
+
+	var hasCall bool
+	influxql.WalkFunc(stmt.Fields, func(n influxql.Node) {
+		if call, ok := n.(*influxql.Call); ok && !isMathFunction(call) {
+			hasCall = true
+		}
+	})
+	if !hasCall {
+		return stmt
+	}
+
+	for _, d := range t.stmt.Dimensions {
+		if call, ok := d.Expr.(*influxql.Call); ok && call.Name == "time" {
+			stmt = stmt.Clone()
+			stmt.Dimensions = append(stmt.Dimensions, &influxql.Dimension{Expr: call})
+			return stmt
+		}
+	}
+	return stmt
+}
+
+// subQueryColumn is a column of the results of a subquery.
+type subQueryColumn struct {
+	name string
+	tag  bool
+}
+
+// expandSubQueryWildcards replaces the wildcards and regexes in the fields of the
+// statement with the columns of its subqueries in the same way as 1.x. The columns
+// of a subquery are only known when it does not select or group by a wildcard
+// itself, so the statement is returned unchanged when any of the sources is a
+// measurement or one of those subqueries. The types of the columns are not known
+// so a function is called on each of the columns that are not tags.
+func expandSubQueryWildcards(stmt *influxql.SelectStatement) (*influxql.SelectStatement, error) {
+	if !stmt.HasFieldWildcard() {
+		return stmt, nil
+	}
+
+	tags := make(map[string]bool)
+	for _, source := range stmt.Sources {
+		sq, ok := source.(*influxql.SubQuery)
+		if !ok {
+			return stmt, nil
+		}
+
+		// The wildcards of the subquery are expanded first when it reads
+		// subqueries of its own.
+		inner, err := expandSubQueryWildcards(sq.Statement)
+		if err != nil {
+			return nil, err
+		} else if inner.HasFieldWildcard() || inner.HasDimensionWildcard() {
+			return stmt, nil
+		}
+
+		names := inner.ColumnNames()
+		if !inner.OmitTime {
+			names = names[1:]
+		}
+		for _, name := range names {
+			tags[name] = false
+		}
+		for _, d := range inner.Dimensions {
+			if ref, ok := d.Expr.(*influxql.VarRef); ok {
+				if _, ok := tags[ref.Val]; !ok {
+					tags[ref.Val] = true
+				}
+			}
+		}
+	}
+
+	// The tags are not columns when the statement groups by them.
+	for _, d := range stmt.Dimensions {
+		switch expr := d.Expr.(type) {
+		case *influxql.VarRef:
+			if tags[expr.Val] {
+				delete(tags, expr.Val)
+			}
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			for name, tag := range tags {
+				if tag {
+					delete(tags, name)
+				}
+			}
+		}
+	}
+
+	columns := make([]subQueryColumn, 0, len(tags))
+	for name, tag := range tags {
+		columns = append(columns, subQueryColumn{name: name, tag: tag})
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].name < columns[j].name
+	})
+
+	other := stmt.Clone()
+	fields := make(influxql.Fields, 0, len(other.Fields)+len(columns))
+	for _, f := range other.Fields {
+		switch expr := f.Expr.(type) {
+		case *influxql.Wildcard:
+			for _, c := range columns {
+				if (expr.Type == influxql.FIELD && c.tag) || (expr.Type == influxql.TAG && !c.tag) {
+					continue
+				}
+				fields = append(fields, &influxql.Field{Expr: &influxql.VarRef{Val: c.name}})
+			}
+		case *influxql.RegexLiteral:
+			for _, c := range columns {
+				if expr.Val.MatchString(c.name) {
+					fields = append(fields, &influxql.Field{Expr: &influxql.VarRef{Val: c.name}})
+				}
+			}
+		case *influxql.Call:
+			// The wildcard is the first argument of the innermost call, such
+			// as the field of an aggregate that is called by a transformation.
+			template := influxql.CloneExpr(expr).(*influxql.Call)
+			call := template
+			for len(call.Args) > 0 {
+				arg, ok := call.Args[0].(*influxql.Call)
+				if !ok {
+					break
+				}
+				call = arg
+			}
+			if len(call.Args) == 0 {
+				fields = append(fields, f)
+				continue
+			}
+
+			var re *regexp.Regexp
+			switch arg := call.Args[0].(type) {
+			case *influxql.Wildcard:
+				if arg.Type == influxql.TAG {
+					return nil, fmt.Errorf("unable to use tag wildcard in %s()", call.Name)
+				}
+			case *influxql.RegexLiteral:
+				re = arg.Val
+			default:
+				fields = append(fields, f)
+				continue
+			}
+
+			for _, c := range columns {
+				if c.tag || (re != nil && !re.MatchString(c.name)) {
+					continue
+				}
+				call.Args[0] = &influxql.VarRef{Val: c.name}
+				fields = append(fields, &influxql.Field{
+					Expr:  influxql.CloneExpr(template),
+					Alias: fmt.Sprintf("%s_%s", f.Name(), c.name),
+				})
+			}
+		default:
+			fields = append(fields, f)
+		}
+	}
+	other.Fields = fields
+	return other, nil
+}
+
+// rangeExpr limits the tables of expr to the time range.
+func rangeExpr(expr ast.Expression, tr influxql.TimeRange) ast.Expression {
+	return &ast.PipeExpression{
+		Argument: expr,
 		Call: &ast.CallExpression{
 			Callee: &ast.Identifier{
-				Name: "filter",
+				Name: "range",
 			},
 			Arguments: []ast.Expression{
 				&ast.ObjectExpression{
 					Properties: []*ast.Property{
 						{
 							Key: &ast.Identifier{
-								Name: "fn",
+								Name: "start",
 							},
-							Value: &ast.FunctionExpression{
-								Params: []*ast.Property{{
-									Key: &ast.Identifier{
-										Name: "r",
-									},
-								}},
-								Body: &ast.LogicalExpression{
-									Operator: ast.AndOperator,
-									Left: &ast.BinaryExpression{
-										Operator: ast.EqualOperator,
-										Left: &ast.MemberExpression{
-											Object:   &ast.Identifier{Name: "r"},
-											Property: &ast.Identifier{Name: "_measurement"},
-										},
-										Right: &ast.StringLiteral{
-											Value: mm.Name,
-										},
-									},
-									Right: &ast.BinaryExpression{
-										Operator: ast.EqualOperator,
-										Left: &ast.MemberExpression{
-											Object:   &ast.Identifier{Name: "r"},
-											Property: &ast.Identifier{Name: "_field"},
-										},
-										Right: &ast.StringLiteral{
-											Value: ref.Val,
-										},
-									},
-								},
+							Value: &ast.DateTimeLiteral{
+								Value: tr.MinTime().UTC(),
+							},
+						},
+						{
+							Key: &ast.Identifier{
+								Name: "stop",
+							},
+							Value: &ast.DateTimeLiteral{
+								Value: tr.MaxTime().UTC(),
 							},
 						},
 					},
@@ -144,10 +699,29 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 			},
 		},
 	}
-	return &varRefCursor{
-		expr: expr,
-		ref:  ref,
-	}, nil
+}
+
+// union combines the tables of each of the expressions into a single stream.
+func union(t *transpilerState, exprs []ast.Expression) ast.Expression {
+	tables := make([]ast.Expression, 0, len(exprs))
+	for _, expr := range exprs {
+		tables = append(tables, t.assignment(expr))
+	}
+	return &ast.CallExpression{
+		Callee: &ast.Identifier{
+			Name: "union",
+		},
+		Arguments: []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: []*ast.Property{{
+					Key: &ast.Identifier{Name: "tables"},
+					Value: &ast.ArrayExpression{
+						Elements: tables,
+					},
+				}},
+			},
+		},
+	}
 }
 
 func (c *varRefCursor) Expr() ast.Expression {
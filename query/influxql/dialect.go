@@ -36,8 +36,11 @@ func (d *Dialect) SetHeaders(w http.ResponseWriter) {
 
 func (d *Dialect) Encoder() flux.MultiResultEncoder {
 	switch d.Encoding {
-	case JSON, JSONPretty:
-		return new(MultiResultEncoder)
+	case JSON, JSONPretty, CSV:
+		return &MultiResultEncoder{
+			TimeFormat: d.TimeFormat,
+			Encoding:   d.Encoding,
+		}
 	default:
 		panic("not implemented")
 	}
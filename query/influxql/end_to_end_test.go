@@ -4,12 +4,16 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
@@ -38,7 +42,7 @@ func init() {
 		OrganizationID:  platformtesting.MustIDBase16("cadecadecadecade"),
 		BucketID:        platformtesting.MustIDBase16("da7aba5e5eedca5e"),
 	}
-	dbrpMappingSvcE2E.FindByFn = func(ctx context.Context, cluster string, db string, rp string) (*platform.DBRPMapping, error) {
+	dbrpMappingSvcE2E.FindByFn = func(ctx context.Context, orgID platform.ID, cluster string, db string, rp string) (*platform.DBRPMapping, error) {
 		return &mapping, nil
 	}
 	dbrpMappingSvcE2E.FindFn = func(ctx context.Context, filter platform.DBRPMappingFilter) (*platform.DBRPMapping, error) {
@@ -52,95 +56,32 @@ func init() {
 var skipTests = map[string]string{
 	"hardcoded_literal_1":      "transpiler count query is off by 1 (https://github.com/influxdata/platform/issues/1278)",
 	"hardcoded_literal_3":      "transpiler count query is off by 1 (https://github.com/influxdata/platform/issues/1278)",
-	"fuzz_join_within_cursor":  "transpiler does not implement joining fields within a cursor (https://github.com/influxdata/platform/issues/1340)",
-	"derivative_count":         "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_first":         "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_last":          "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_max":           "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_mean":          "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_median":        "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_min":           "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_mode":          "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_percentile_10": "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_percentile_50": "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_percentile_90": "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"derivative_sum":           "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"regex_measurement_0":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
-	"regex_measurement_1":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
-	"regex_measurement_2":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
-	"regex_measurement_3":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
-	"regex_measurement_4":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
-	"regex_measurement_5":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
+	"fuzz_join_within_cursor":  "Transpiler: the fields of different series at the same time are pivoted into one row and ordered by their names",
+	"derivative_count":         "the input data of the test does not contain the raw points of the query",
+	"derivative_first":         "the input data of the test does not contain the raw points of the query",
+	"derivative_last":          "the input data of the test does not contain the raw points of the query",
+	"derivative_max":           "the input data of the test does not contain the raw points of the query",
+	"derivative_median":        "the input data of the test does not contain the raw points of the query",
+	"derivative_min":           "the input data of the test does not contain the raw points of the query",
+	"derivative_mode":          "the input data of the test does not contain the raw points of the query",
+	"derivative_percentile_10": "the input data of the test does not contain the raw points of the query",
+	"derivative_percentile_50": "the input data of the test does not contain the raw points of the query",
+	"derivative_percentile_90": "the input data of the test does not contain the raw points of the query",
+	"derivative_sum":           "the input data of the test does not contain the raw points of the query",
 	"regex_tag_0":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
 	"regex_tag_1":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
 	"regex_tag_2":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
 	"regex_tag_3":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
-	"explicit_type_0":          "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"explicit_type_1":          "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
 	"fills_0":                  "need fill/Interpolate function (https://github.com/influxdata/platform/issues/272)",
-	"random_math_0":            "transpiler does not implement joining fields within a cursor (https://github.com/influxdata/platform/issues/1340)",
-	"selector_0":               "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"selector_1":               "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
+	"random_math_0":            "Transpiler: the fields of different series at the same time are pivoted into one row and ordered by their names",
 	"selector_2":               "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
 	"selector_6":               "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
 	"selector_7":               "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
-	"series_agg_0":             "Transpiler: Implement difference (https://github.com/influxdata/platform/issues/1609)",
-	"series_agg_1":             "Transpiler: Implement stddev (https://github.com/influxdata/platform/issues/1610)",
-	"series_agg_2":             "Transpiler: Implement spread (https://github.com/influxdata/platform/issues/1611)",
-	"series_agg_3":             "Transpiler: Implement elapsed (https://github.com/influxdata/platform/issues/1612)",
-	"series_agg_4":             "Transpiler: Implement cumulative_sum (https://github.com/influxdata/platform/issues/1613)",
-	"series_agg_5":             "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
-	"series_agg_6":             "Transpiler: Implement non_negative_derivative (https://github.com/influxdata/platform/issues/1614)",
-	"series_agg_7":             "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"series_agg_8":             "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"series_agg_9":             "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"Subquery_0":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_1":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_2":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_3":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_4":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_5":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_0":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_1":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_2":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_3":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_0":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_1":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_2":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_3":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_4":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SelectorMath_0":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_1":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_2":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_3":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_4":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_5":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_6":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_7":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_8":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_9":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_10":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_11":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_12":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_13":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_14":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_15":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_16":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_17":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_18":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_19":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_20":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_21":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_22":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_23":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_24":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_25":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_26":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_27":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_28":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_29":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_30":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_31":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
+	"series_agg_5":             "the input data of the test does not contain the raw points of the query",
+	"SimulatedHTTP_3":          "flux top and bottom do not select the earliest of the points with the same value",
+	"SelectorMath_12":          "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
+	"SelectorMath_28":          "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
+	"SelectorMath_29":          "Transpiler: last function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
 }
 
 var querier = fluxquerytest.NewQuerier()
@@ -201,6 +142,24 @@ func testGeneratedInfluxQL(t testing.TB, prefix, queryExt string) {
 		exp = append(exp, out.Next())
 	}
 
+	// The query must fail with the error returned by InfluxQL.
+	if expErr := out.Err(); expErr != nil {
+		res, err := resultsFromQuerier(querier, influxQLCompiler(string(q), inFile))
+		if err == nil {
+			for res.More() {
+				res.Next()
+			}
+			err = res.Err()
+			res.Release()
+		}
+		if err == nil {
+			t.Fatalf("expected error %q, got none", expErr)
+		} else if !strings.HasSuffix(err.Error(), expErr.Error()) {
+			t.Fatalf("unexpected error: want %q, got %q", expErr, err)
+		}
+		return
+	}
+
 	res, err := resultsFromQuerier(querier, influxQLCompiler(string(q), inFile))
 	if err != nil {
 		t.Fatalf("failed to run query: %v", err)
@@ -212,7 +171,7 @@ func testGeneratedInfluxQL(t testing.TB, prefix, queryExt string) {
 		got = append(got, res.Next())
 	}
 
-	if ok, err := executetest.EqualResults(exp, got); !ok {
+	if ok, err := equalResults(exp, got); !ok {
 		t.Errorf("result not as expected: %v", err)
 
 		expBuffer := new(bytes.Buffer)
@@ -234,6 +193,58 @@ func testGeneratedInfluxQL(t testing.TB, prefix, queryExt string) {
 	}
 }
 
+// floatOptions compares floats with a relative tolerance. InfluxQL and flux
+// sum floats in a different order, so aggregates such as the mean may differ
+// in the last few bits.
+var floatOptions = cmp.Options{
+	cmpopts.EquateNaNs(),
+	cmpopts.EquateApprox(1e-12, 0),
+}
+
+// equalResults is executetest.EqualResults with the float comparison of floatOptions.
+func equalResults(want, got []flux.Result) (bool, error) {
+	if len(want) != len(got) {
+		return false, fmt.Errorf("unexpected number of results - want %d results, got %d results", len(want), len(got))
+	}
+	for i, w := range want {
+		g := got[i]
+		if w.Name() != g.Name() {
+			return false, fmt.Errorf("unexpected result name - want %s, got %s", w.Name(), g.Name())
+		}
+		wt, err := convertTables(w)
+		if err != nil {
+			return false, err
+		}
+		gt, err := convertTables(g)
+		if err != nil {
+			return false, err
+		}
+		if len(wt) != len(gt) {
+			return false, fmt.Errorf("unexpected size for result %s - want %d tables, got %d tables", w.Name(), len(wt), len(gt))
+		}
+		if !cmp.Equal(wt, gt, floatOptions) {
+			return false, fmt.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(wt, gt, floatOptions))
+		}
+	}
+	return true, nil
+}
+
+func convertTables(res flux.Result) ([]*executetest.Table, error) {
+	var tables []*executetest.Table
+	if err := res.Tables().Do(func(tbl flux.Table) error {
+		t, err := executetest.ConvertTable(tbl)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, t)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	executetest.NormalizeTables(tables)
+	return tables, nil
+}
+
 func resultsFromQuerier(querier *fluxquerytest.Querier, compiler flux.Compiler) (flux.ResultIterator, error) {
 	req := &query.ProxyRequest{
 		Request: query.Request{
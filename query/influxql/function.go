@@ -3,9 +3,11 @@ package influxql
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
+	stdinfluxql "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxql"
 )
 
@@ -14,6 +16,7 @@ import (
 // pre-validates that we know the function exists. The cursor creation should be
 // done by this struct, but it isn't at the moment.
 type function struct {
+	// Ref is nil when the function is applied to a field wildcard.
 	Ref  *influxql.VarRef
 	call *influxql.Call
 }
@@ -32,21 +35,41 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 				Ref:  ref,
 				call: expr,
 			}, nil
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			// The function is applied to each of the matching fields.
+			return &function{call: expr}, nil
 		case *influxql.Call:
 			if ref.Name == "distinct" {
-				return nil, errors.New("unimplemented: count(distinct)")
+				// The distinct values of the field are counted.
+				fn, err := parseFunction(ref)
+				if err != nil {
+					return nil, err
+				}
+				return &function{
+					Ref:  fn.Ref,
+					call: expr,
+				}, nil
 			}
 			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
-		case *influxql.Distinct:
-			return nil, errors.New("unimplemented: count(distinct)")
-		case *influxql.Wildcard:
-			return nil, errors.New("unimplemented: wildcard function")
-		case *influxql.RegexLiteral:
-			return nil, errors.New("unimplemented: wildcard regex function")
 		default:
 			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 		}
-	case "min", "max", "sum", "first", "last", "mean", "median":
+	case "distinct":
+		if len(expr.Args) == 0 {
+			return nil, errors.New("distinct function requires at least one argument")
+		} else if len(expr.Args) > 1 {
+			return nil, errors.New("distinct function can only have one argument")
+		}
+
+		ref, ok := expr.Args[0].(*influxql.VarRef)
+		if !ok {
+			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+		}
+		return &function{
+			Ref:  ref,
+			call: expr,
+		}, nil
+	case "min", "max", "sum", "first", "last", "mean", "median", "mode", "stddev", "spread":
 		if exp, got := 1, len(expr.Args); exp != got {
 			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
 		}
@@ -57,10 +80,9 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 				Ref:  ref,
 				call: expr,
 			}, nil
-		case *influxql.Wildcard:
-			return nil, errors.New("unimplemented: wildcard function")
-		case *influxql.RegexLiteral:
-			return nil, errors.New("unimplemented: wildcard regex function")
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			// The function is applied to each of the matching fields.
+			return &function{call: expr}, nil
 		default:
 			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 		}
@@ -74,10 +96,8 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 		switch ref := expr.Args[0].(type) {
 		case *influxql.VarRef:
 			functionRef = ref
-		case *influxql.Wildcard:
-			return nil, errors.New("unimplemented: wildcard function")
-		case *influxql.RegexLiteral:
-			return nil, errors.New("unimplemented: wildcard regex function")
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			// The function is applied to each of the matching fields.
 		default:
 			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 		}
@@ -93,21 +113,244 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 			Ref:  functionRef,
 			call: expr,
 		}, nil
+	case "top", "bottom":
+		if exp, got := 2, len(expr.Args); got < exp {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least %d, got %d", expr.Name, exp, got)
+		}
+
+		limit, ok := expr.Args[len(expr.Args)-1].(*influxql.IntegerLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected integer as last argument in %s(), found %s", expr.Name, expr.Args[len(expr.Args)-1])
+		} else if limit.Val <= 0 {
+			return nil, fmt.Errorf("limit (%d) in %s function must be at least 1", limit.Val, expr.Name)
+		}
+
+		ref, ok := expr.Args[0].(*influxql.VarRef)
+		if !ok {
+			return nil, fmt.Errorf("expected first argument to be a field in %s(), found %s", expr.Name, expr.Args[0])
+		}
+
+		// The arguments between the field and the limit are the tags
+		// that the points are selected from.
+		for _, arg := range expr.Args[1 : len(expr.Args)-1] {
+			if _, ok := arg.(*influxql.VarRef); !ok {
+				return nil, fmt.Errorf("only fields or tags are allowed in %s(), found %s", expr.Name, arg)
+			}
+		}
+		return &function{
+			Ref:  ref,
+			call: expr,
+		}, nil
+	case "integral":
+		if got := len(expr.Args); got < 1 || got > 2 {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least 1 but no more than 2, got %d", expr.Name, got)
+		}
+
+		if len(expr.Args) == 2 {
+			switch arg := expr.Args[1].(type) {
+			case *influxql.DurationLiteral:
+				if arg.Val <= 0 {
+					return nil, fmt.Errorf("duration argument must be positive, got %s", arg)
+				}
+			default:
+				return nil, errors.New("second argument must be a duration")
+			}
+		}
+
+		switch ref := expr.Args[0].(type) {
+		case *influxql.VarRef:
+			return &function{
+				Ref:  ref,
+				call: expr,
+			}, nil
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			// The function is applied to each of the matching fields.
+			return &function{call: expr}, nil
+		default:
+			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+		}
+	case "derivative", "non_negative_derivative", "elapsed":
+		if got := len(expr.Args); got < 1 || got > 2 {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least 1 but no more than 2, got %d", expr.Name, got)
+		}
+
+		if len(expr.Args) == 2 {
+			switch arg := expr.Args[1].(type) {
+			case *influxql.DurationLiteral:
+				if arg.Val <= 0 {
+					return nil, fmt.Errorf("duration argument must be positive, got %s", arg)
+				}
+			default:
+				return nil, fmt.Errorf("second argument to %s must be a duration, got %T", expr.Name, expr.Args[1])
+			}
+		}
+		return parseTransformation(expr)
+	case "difference", "non_negative_difference", "cumulative_sum":
+		if exp, got := 1, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+		return parseTransformation(expr)
+	case "moving_average":
+		if exp, got := 2, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+
+		switch arg := expr.Args[1].(type) {
+		case *influxql.IntegerLiteral:
+			if arg.Val <= 1 {
+				return nil, fmt.Errorf("%s window must be greater than 1, got %d", expr.Name, arg.Val)
+			}
+		default:
+			return nil, fmt.Errorf("second argument for %s must be an integer, got %T", expr.Name, expr.Args[1])
+		}
+		return parseTransformation(expr)
+	case "holt_winters", "holt_winters_with_fit":
+		if exp, got := 3, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+
+		if _, ok := expr.Args[0].(*influxql.Call); !ok {
+			return nil, fmt.Errorf("must use aggregate function with %s", expr.Name)
+		}
+
+		switch arg := expr.Args[1].(type) {
+		case *influxql.IntegerLiteral:
+			if arg.Val <= 0 {
+				return nil, fmt.Errorf("second arg to %s must be greater than 0, got %d", expr.Name, arg.Val)
+			}
+		default:
+			return nil, fmt.Errorf("expected integer argument as second arg in %s", expr.Name)
+		}
+
+		switch arg := expr.Args[2].(type) {
+		case *influxql.IntegerLiteral:
+			if arg.Val < 0 {
+				return nil, fmt.Errorf("third arg to %s cannot be negative, got %d", expr.Name, arg.Val)
+			}
+		default:
+			return nil, fmt.Errorf("expected integer argument as third arg in %s", expr.Name)
+		}
+		return parseTransformation(expr)
+	case "sample":
+		if exp, got := 2, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+
+		switch arg := expr.Args[1].(type) {
+		case *influxql.IntegerLiteral:
+			if arg.Val <= 0 {
+				return nil, fmt.Errorf("sample window must be greater than 1, got %d", arg.Val)
+			}
+		default:
+			return nil, fmt.Errorf("expected integer argument in %s()", expr.Name)
+		}
+
+		switch ref := expr.Args[0].(type) {
+		case *influxql.VarRef:
+			return &function{
+				Ref:  ref,
+				call: expr,
+			}, nil
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			// The function is applied to each of the matching fields.
+			return &function{call: expr}, nil
+		default:
+			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+		}
+	default:
+		return nil, fmt.Errorf("undefined function %s()", expr.Name)
+	}
+}
+
+// parseTransformation parses the first argument of a function that transforms
+// the points of each series. The argument is either a field or an aggregate
+// that is computed for each interval of the GROUP BY before the series is
+// transformed.
+func parseTransformation(expr *influxql.Call) (*function, error) {
+	switch arg := expr.Args[0].(type) {
+	case *influxql.VarRef:
+		return &function{
+			Ref:  arg,
+			call: expr,
+		}, nil
+	case *influxql.Wildcard, *influxql.RegexLiteral:
+		// The function is applied to each of the matching fields.
+		return &function{call: expr}, nil
+	case *influxql.Call:
+		fn, err := parseFunction(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		// The aggregate must produce a single point for each interval.
+		switch arg.Name {
+		case "distinct", "top", "bottom":
+			return nil, fmt.Errorf("unimplemented: %s() inside of %s()", arg.Name, expr.Name)
+		}
+		if isTransformation(arg) {
+			return nil, fmt.Errorf("unimplemented: %s() inside of %s()", arg.Name, expr.Name)
+		}
+		return &function{
+			Ref:  fn.Ref,
+			call: expr,
+		}, nil
 	default:
-		return nil, fmt.Errorf("unimplemented function: %q", expr.Name)
+		return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 	}
+}
 
+// isTransformation returns true if the call transforms the points of a series
+// rather than aggregating them.
+func isTransformation(call *influxql.Call) bool {
+	switch call.Name {
+	case "derivative", "non_negative_derivative", "difference", "non_negative_difference",
+		"moving_average", "cumulative_sum", "elapsed", "holt_winters", "holt_winters_with_fit":
+		return true
+	}
+	return false
+}
+
+// fieldArg returns the argument of the call that selects the fields. When the
+// argument is another function, such as the aggregate of a transformation,
+// the argument of that function is returned.
+func fieldArg(call *influxql.Call) influxql.Expr {
+	if arg, ok := call.Args[0].(*influxql.Call); ok {
+		return fieldArg(arg)
+	}
+	return call.Args[0]
 }
 
 // createFunctionCursor creates a new cursor that calls a function on one of the columns
 // and returns the result.
 func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, normalize bool) (cursor, error) {
+	// A function that is called on the result of another function, such as a
+	// transformation of an aggregate, reads the cursor of the inner function.
+	if arg, ok := call.Args[0].(*influxql.Call); ok {
+		c, err := createFunctionCursor(t, arg, in, isTransformation(call))
+		if err != nil {
+			return nil, err
+		}
+		in = c
+
+		// The aggregate is computed for each window, but the transformation
+		// is computed over all of the windows of a series.
+		if isTransformation(call) {
+			in = &pipeCursor{
+				expr: pipeCall(in.Expr(), &ast.Identifier{Name: "window"}, &ast.Property{
+					Key:   &ast.Identifier{Name: "every"},
+					Value: &ast.Identifier{Name: "inf"},
+				}),
+				cursor: in,
+			}
+		}
+	}
+
 	cur := &functionCursor{
 		call:   call,
 		parent: in,
 	}
 	switch call.Name {
-	case "count", "min", "max", "sum", "first", "last", "mean":
+	case "count", "min", "max", "sum", "first", "last", "mean", "spread", "stddev":
 		value, ok := in.Value(call.Args[0])
 		if !ok {
 			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
@@ -122,6 +365,37 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		}
 		cur.value = value
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "mode":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		cur.expr = pipeCall(in.Expr(), t.packageMember(stdinfluxql.PackagePath, "mode"), &ast.Property{
+			Key:   &ast.Identifier{Name: "column"},
+			Value: &ast.StringLiteral{Value: value},
+		})
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "distinct":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The distinct values are returned in ascending order.
+		cur.expr = pipeCall(
+			pipeCall(in.Expr(), &ast.Identifier{Name: "distinct"}, &ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: value},
+			}),
+			&ast.Identifier{Name: "sort"},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "columns"},
+				Value: stringArray(execute.DefaultValueColLabel),
+			},
+		)
+		cur.value = execute.DefaultValueColLabel
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	case "median":
 		value, ok := in.Value(call.Args[0])
 		if !ok {
@@ -228,10 +502,289 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		}
 		cur.value = fieldName
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "top", "bottom":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		interval, err := t.stmt.GroupByInterval()
+		if err != nil {
+			return nil, err
+		}
+
+		expr := in.Expr()
+		tags := call.Args[1 : len(call.Args)-1]
+		if len(tags) > 0 {
+			// Select the point with the largest or smallest value for each
+			// combination of the tags before the points are compared with
+			// each other.
+			key := []string{"_measurement", execute.DefaultStartColLabel}
+			if interval > 0 {
+				key = append(key, execute.DefaultStopColLabel)
+			}
+			key = append(key, t.dimensionTags()...)
+
+			columns := make([]string, len(key), len(key)+len(tags))
+			copy(columns, key)
+			cur.tags = make(map[influxql.VarRef]struct{}, len(tags))
+			for _, tag := range tags {
+				ref := tag.(*influxql.VarRef)
+				columns = append(columns, ref.Val)
+				cur.tags[*ref] = struct{}{}
+			}
+
+			selector := "max"
+			if call.Name == "bottom" {
+				selector = "min"
+			}
+			expr = pipeCall(expr, &ast.Identifier{Name: "group"},
+				&ast.Property{
+					Key:   &ast.Identifier{Name: "columns"},
+					Value: stringArray(columns...),
+				},
+				&ast.Property{
+					Key:   &ast.Identifier{Name: "mode"},
+					Value: &ast.StringLiteral{Value: "by"},
+				},
+			)
+			expr = pipeCall(expr, &ast.Identifier{Name: selector}, &ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: value},
+			})
+			expr = pipeCall(expr, &ast.Identifier{Name: "group"},
+				&ast.Property{
+					Key:   &ast.Identifier{Name: "columns"},
+					Value: stringArray(key...),
+				},
+				&ast.Property{
+					Key:   &ast.Identifier{Name: "mode"},
+					Value: &ast.StringLiteral{Value: "by"},
+				},
+			)
+		}
+
+		// The selected points are returned in the order of their time.
+		limit := call.Args[len(call.Args)-1].(*influxql.IntegerLiteral)
+		expr = pipeCall(expr, &ast.Identifier{Name: call.Name},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "n"},
+				Value: &ast.IntegerLiteral{Value: limit.Val},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "columns"},
+				Value: stringArray(value),
+			},
+		)
+		cur.expr = sortByTime(expr)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "integral":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		unit := time.Second
+		if len(call.Args) == 2 {
+			unit = call.Args[1].(*influxql.DurationLiteral).Val
+		}
+		cur.expr = pipeCall(sortByTime(in.Expr()), &ast.Identifier{Name: "integral"},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "unit"},
+				Value: &ast.DurationLiteral{Values: durationLiteral(unit)},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "columns"},
+				Value: stringArray(value),
+			},
+		)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "derivative", "non_negative_derivative":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The rate is per second unless the derivative is computed for the
+		// aggregate of an interval. In that case it is per interval.
+		unit := time.Second
+		if len(call.Args) == 2 {
+			unit = call.Args[1].(*influxql.DurationLiteral).Val
+		} else if _, ok := call.Args[0].(*influxql.Call); ok {
+			interval, err := t.stmt.GroupByInterval()
+			if err != nil {
+				return nil, err
+			}
+			unit = interval
+		}
+		cur.expr = pipeCall(sortByTime(in.Expr()), &ast.Identifier{Name: "derivative"},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "unit"},
+				Value: &ast.DurationLiteral{Values: durationLiteral(unit)},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "nonNegative"},
+				Value: &ast.BooleanLiteral{Value: false},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "columns"},
+				Value: stringArray(value),
+			},
+		)
+		if call.Name == "non_negative_derivative" {
+			cur.expr = filterNonNegative(cur.expr, value, &ast.FloatLiteral{Value: 0})
+		}
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "difference", "non_negative_difference":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		cur.expr = pipeCall(sortByTime(in.Expr()), &ast.Identifier{Name: "difference"}, &ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray(value),
+		})
+		if call.Name == "non_negative_difference" {
+			cur.expr = filterNonNegative(cur.expr, value, &ast.IntegerLiteral{Value: 0})
+		}
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "cumulative_sum":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		cur.expr = pipeCall(sortByTime(in.Expr()), &ast.Identifier{Name: "cumulativeSum"}, &ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray(value),
+		})
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "moving_average":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		n := call.Args[1].(*influxql.IntegerLiteral)
+		cur.expr = pipeCall(sortByTime(in.Expr()), t.packageMember(stdinfluxql.PackagePath, "movingAverage"),
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "n"},
+				Value: &ast.IntegerLiteral{Value: n.Val},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: value},
+			},
+		)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "elapsed":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The elapsed time is the difference between the times of
+		// consecutive points in multiples of the unit.
+		unit := time.Nanosecond
+		if len(call.Args) == 2 {
+			unit = call.Args[1].(*influxql.DurationLiteral).Val
+		}
+		expr := mapValue(sortByTime(in.Expr()), value, &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "int"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key:   &ast.Identifier{Name: "v"},
+						Value: rowColumn(execute.DefaultTimeColLabel),
+					}},
+				},
+			},
+		})
+		expr = pipeCall(expr, &ast.Identifier{Name: "difference"}, &ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray(value),
+		})
+		if unit != time.Nanosecond {
+			expr = mapValue(expr, value, &ast.BinaryExpression{
+				Operator: ast.DivisionOperator,
+				Left:     rowColumn(value),
+				Right:    &ast.IntegerLiteral{Value: int64(unit)},
+			})
+		}
+		cur.expr = expr
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "sample":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The sampled points are returned in the order of their time.
+		n := call.Args[1].(*influxql.IntegerLiteral)
+		cur.expr = pipeCall(sortByTime(in.Expr()), t.packageMember(stdinfluxql.PackagePath, "sample"),
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "n"},
+				Value: &ast.IntegerLiteral{Value: n.Val},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: value},
+			},
+		)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "holt_winters", "holt_winters_with_fit":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The aggregate of each interval is a point of the series that is
+		// forecast, so the forecast points are an interval apart.
+		interval, err := t.stmt.GroupByInterval()
+		if err != nil {
+			return nil, err
+		}
+		n := call.Args[1].(*influxql.IntegerLiteral)
+		seasonality := call.Args[2].(*influxql.IntegerLiteral)
+		cur.expr = pipeCall(sortByTime(in.Expr()), t.packageMember(stdinfluxql.PackagePath, "holtWinters"),
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "n"},
+				Value: &ast.IntegerLiteral{Value: n.Val},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "seasonality"},
+				Value: &ast.IntegerLiteral{Value: seasonality.Val},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "interval"},
+				Value: &ast.DurationLiteral{Values: durationLiteral(interval)},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "withFit"},
+				Value: &ast.BooleanLiteral{Value: call.Name == "holt_winters_with_fit"},
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: value},
+			},
+		)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", call.Name)
 	}
 
+	// The points of a transformation keep their own time.
+	if isTransformation(call) {
+		return cur, nil
+	}
+
 	// If we have been told to normalize the time, we do it here.
 	if normalize {
 		if influxql.IsSelector(call) {
@@ -258,46 +811,156 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 				},
 			}
 		}
-		cur.expr = &ast.PipeExpression{
-			Argument: cur.expr,
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "duplicate",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
+
+		// Without a lower bound on the time, the start is the minimum time
+		// but influxql timestamps the points of the aggregates with the epoch.
+		interval, err := t.stmt.GroupByInterval()
+		if err != nil {
+			return nil, err
+		}
+		tr, err := t.queryTimeRange()
+		if err != nil {
+			return nil, err
+		}
+		if interval == 0 && tr.Min.IsZero() {
+			cur.expr = pipeCall(cur.expr, &ast.Identifier{Name: "map"}, &ast.Property{
+				Key: &ast.Identifier{Name: "fn"},
+				Value: &ast.FunctionExpression{
+					Params: []*ast.Property{{
+						Key: &ast.Identifier{Name: "r"},
+					}},
+					Body: &ast.ObjectExpression{
 						Properties: []*ast.Property{
 							{
-								Key: &ast.Identifier{
-									Name: "column",
-								},
-								Value: &ast.StringLiteral{
-									Value: execute.DefaultStartColLabel,
-								},
+								Key:   &ast.Identifier{Name: execute.DefaultTimeColLabel},
+								Value: &ast.DateTimeLiteral{Value: time.Unix(0, 0).UTC()},
 							},
 							{
-								Key: &ast.Identifier{
-									Name: "as",
+								Key:   &ast.Identifier{Name: execute.DefaultValueColLabel},
+								Value: rowColumn(execute.DefaultValueColLabel),
+							},
+						},
+					},
+				},
+			})
+		} else {
+			cur.expr = &ast.PipeExpression{
+				Argument: cur.expr,
+				Call: &ast.CallExpression{
+					Callee: &ast.Identifier{
+						Name: "duplicate",
+					},
+					Arguments: []ast.Expression{
+						&ast.ObjectExpression{
+							Properties: []*ast.Property{
+								{
+									Key: &ast.Identifier{
+										Name: "column",
+									},
+									Value: &ast.StringLiteral{
+										Value: execute.DefaultStartColLabel,
+									},
 								},
-								Value: &ast.StringLiteral{
-									Value: execute.DefaultTimeColLabel,
+								{
+									Key: &ast.Identifier{
+										Name: "as",
+									},
+									Value: &ast.StringLiteral{
+										Value: execute.DefaultTimeColLabel,
+									},
 								},
 							},
 						},
 					},
 				},
-			},
+			}
 		}
 	}
 	return cur, nil
 }
 
+// pipeCall pipes the expression into a call of the function with the properties
+// as its arguments.
+func pipeCall(expr, callee ast.Expression, properties ...*ast.Property) ast.Expression {
+	call := &ast.CallExpression{Callee: callee}
+	if len(properties) > 0 {
+		call.Arguments = []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: properties,
+			},
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: expr,
+		Call:     call,
+	}
+}
+
+// sortByTime sorts the rows of each table by their time.
+func sortByTime(expr ast.Expression) ast.Expression {
+	return sortBy(expr, execute.DefaultTimeColLabel)
+}
+
+// mapValue replaces the value column of each row with the expression.
+func mapValue(expr ast.Expression, value string, fn ast.Expression) ast.Expression {
+	return pipeCall(expr, &ast.Identifier{Name: "map"}, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.ObjectExpression{
+				Properties: []*ast.Property{
+					{
+						Key:   &ast.Identifier{Name: execute.DefaultTimeColLabel},
+						Value: rowColumn(execute.DefaultTimeColLabel),
+					},
+					{
+						Key:   columnKey(value),
+						Value: fn,
+					},
+				},
+			},
+		},
+	})
+}
+
+// filterNonNegative removes the rows with a negative value.
+func filterNonNegative(expr ast.Expression, value string, zero ast.Expression) ast.Expression {
+	return pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.BinaryExpression{
+				Operator: ast.GreaterThanEqualOperator,
+				Left:     rowColumn(value),
+				Right:    zero,
+			},
+		},
+	})
+}
+
+// stringArray creates an array of string literals.
+func stringArray(values ...string) *ast.ArrayExpression {
+	elements := make([]ast.Expression, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, &ast.StringLiteral{Value: v})
+	}
+	return &ast.ArrayExpression{Elements: elements}
+}
+
 type functionCursor struct {
 	expr    ast.Expression
 	call    *influxql.Call
 	value   string
 	exclude map[influxql.Expr]struct{}
 	parent  cursor
+
+	// tags holds the tags that are selected alongside the value by
+	// the top and bottom functions.
+	tags map[influxql.VarRef]struct{}
 }
 
 func (c *functionCursor) Expr() ast.Expression {
@@ -323,5 +986,10 @@ func (c *functionCursor) Value(expr influxql.Expr) (string, bool) {
 	} else if _, ok := c.exclude[expr]; ok {
 		return "", false
 	}
+	if ref, ok := expr.(*influxql.VarRef); ok {
+		if _, ok := c.tags[*ref]; ok {
+			return ref.Val, true
+		}
+	}
 	return c.parent.Value(expr)
 }
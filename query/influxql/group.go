@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 )
@@ -14,12 +15,17 @@ type groupInfo struct {
 	call     *influxql.Call
 	refs     []*influxql.VarRef
 	selector bool
+
+	// wildcards holds the field wildcards and regexes that are selected
+	// alongside the variable references.
+	wildcards []influxql.Expr
 }
 
 type groupVisitor struct {
-	calls []*function
-	refs  []*influxql.VarRef
-	err   error
+	calls     []*function
+	refs      []*influxql.VarRef
+	wildcards []influxql.Expr
+	err       error
 }
 
 func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
@@ -30,7 +36,20 @@ func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
 	// TODO(jsternberg): Identify duplicates so they are a single common instance.
 	switch expr := n.(type) {
 	case *influxql.Call:
-		// TODO(jsternberg): Identify math functions so we visit their arguments instead of recording them.
+		// The arguments of a math function are visited instead of recording the function.
+		if isMathFunction(expr) {
+			exp := 1
+			switch expr.Name {
+			case "atan2", "log", "pow":
+				exp = 2
+			}
+			if got := len(expr.Args); got != exp {
+				v.err = fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+				return nil
+			}
+			return v
+		}
+
 		fn, err := parseFunction(expr)
 		if err != nil {
 			v.err = err
@@ -38,9 +57,6 @@ func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
 		}
 		v.calls = append(v.calls, fn)
 		return nil
-	case *influxql.Distinct:
-		v.err = errors.New("unimplemented: distinct expression")
-		return nil
 	case *influxql.VarRef:
 		if expr.Val == "time" {
 			return nil
@@ -48,10 +64,10 @@ func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
 		v.refs = append(v.refs, expr)
 		return nil
 	case *influxql.Wildcard:
-		v.err = errors.New("unimplemented: field wildcard")
+		v.wildcards = append(v.wildcards, expr)
 		return nil
 	case *influxql.RegexLiteral:
-		v.err = errors.New("unimplemented: field regex wildcard")
+		v.wildcards = append(v.wildcards, expr)
 		return nil
 	}
 	return v
@@ -65,11 +81,17 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 		return nil, v.err
 	}
 
+	if err := validateCalls(stmt, v); err != nil {
+		return nil, err
+	}
+
 	// Attempt to take the calls and variables and put them into groups.
-	if len(v.refs) > 0 {
+	if len(v.refs) > 0 || len(v.wildcards) > 0 {
 		// If any of the calls are not selectors, we have an error message.
+		// A selector applied to a wildcard selects more than one point, so it is
+		// treated as an aggregate.
 		for _, fn := range v.calls {
-			if !influxql.IsSelector(fn.call) {
+			if !influxql.IsSelector(fn.call) || fn.Ref == nil {
 				return nil, errors.New("mixing aggregate and non-aggregate queries is not supported")
 			}
 		}
@@ -85,9 +107,10 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 			call = v.calls[0].call
 		}
 		return []*groupInfo{{
-			call:     call,
-			refs:     v.refs,
-			selector: true, // Always a selector if we are here.
+			call:      call,
+			refs:      v.refs,
+			selector:  true, // Always a selector if we are here.
+			wildcards: v.wildcards,
 		}}, nil
 	}
 
@@ -99,35 +122,150 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 	}
 
 	// If there is exactly one group and that contains a selector, then mark it as so.
-	if len(groups) == 1 && influxql.IsSelector(groups[0].call) {
+	// A selector applied to a wildcard selects a point from each of the fields so
+	// the time of the selected points is not kept.
+	if len(groups) == 1 && influxql.IsSelector(groups[0].call) && !groups[0].hasWildcard() {
 		groups[0].selector = true
 	}
 	return groups, nil
 }
 
+// validateCalls validates the combination of the function calls with each other
+// and with the fields of the statement.
+func validateCalls(stmt *influxql.SelectStatement, v *groupVisitor) error {
+	for _, fn := range v.calls {
+		switch fn.call.Name {
+		case "top", "bottom":
+			if len(v.calls) > 1 {
+				return fmt.Errorf("selector function %s() cannot be combined with other functions", fn.call.Name)
+			}
+			limit := fn.call.Args[len(fn.call.Args)-1].(*influxql.IntegerLiteral)
+			if stmt.Limit > 0 && int(limit.Val) > stmt.Limit {
+				return fmt.Errorf("limit (%d) in %s function can not be larger than the LIMIT (%d) in the select statement", limit.Val, fn.call.Name, stmt.Limit)
+			}
+		case "distinct":
+			if len(v.calls) > 1 || len(v.refs) > 0 || len(v.wildcards) > 0 {
+				return errors.New("aggregate function distinct() cannot be combined with other functions or fields")
+			}
+		}
+
+		if isTransformation(fn.call) {
+			interval, err := stmt.GroupByInterval()
+			if err != nil {
+				return err
+			}
+			if _, ok := fn.call.Args[0].(*influxql.Call); ok && interval == 0 {
+				return fmt.Errorf("%s aggregate requires a GROUP BY interval", fn.call.Name)
+			} else if !ok && interval > 0 {
+				return fmt.Errorf("aggregate function required inside the call to %s", fn.call.Name)
+			}
+		}
+	}
+
+	// The wildcards in a field are expanded into a field for each of the
+	// matched fields, which cannot be done within a binary expression.
+	for _, f := range stmt.Fields {
+		var wildcard, regex bool
+		influxql.WalkFunc(f.Expr, func(n influxql.Node) {
+			if expr, ok := n.(*influxql.BinaryExpr); ok {
+				influxql.WalkFunc(expr, func(n influxql.Node) {
+					switch n.(type) {
+					case *influxql.Wildcard:
+						wildcard = true
+					case *influxql.RegexLiteral:
+						regex = true
+					}
+				})
+			}
+		})
+		if wildcard {
+			return fmt.Errorf("unsupported expression with wildcard: %s", f.Expr)
+		} else if regex {
+			return fmt.Errorf("unsupported expression with regex field: %s", f.Expr)
+		}
+	}
+
+	// Each of the fields must read a variable.
+	for _, f := range stmt.Fields {
+		var found bool
+		influxql.WalkFunc(f.Expr, func(n influxql.Node) {
+			switch n := n.(type) {
+			case *influxql.VarRef, *influxql.Wildcard, *influxql.RegexLiteral:
+				found = true
+			case *influxql.Call:
+				if !isMathFunction(n) {
+					found = true
+				}
+			}
+		})
+		if !found {
+			return errors.New("field must contain at least one variable")
+		}
+	}
+	return nil
+}
+
 func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 	// Create all of the cursors for every variable reference.
 	// TODO(jsternberg): Determine which of these cursors are from fields and which are tags.
 	var cursors []cursor
-	if gr.call != nil {
-		ref, ok := gr.call.Args[0].(*influxql.VarRef)
-		if !ok {
+	if gr.call != nil && !gr.pivoted() {
+		var (
+			cur cursor
+			err error
+		)
+		switch arg := fieldArg(gr.call).(type) {
+		case *influxql.VarRef:
+			cur, err = createVarRefCursor(t, arg)
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			cur, err = createWildcardCursor(t, arg)
+		default:
 			// TODO(jsternberg): This should be validated and figured out somewhere else.
 			return nil, fmt.Errorf("first argument to %q must be a variable", gr.call.Name)
 		}
-		cur, err := createVarRefCursor(t, ref)
 		if err != nil {
 			return nil, err
 		}
 		cursors = append(cursors, cur)
 	}
 
-	for _, ref := range gr.refs {
-		cur, err := createVarRefCursor(t, ref)
+	if gr.pivoted() {
+		// The fields matched by a wildcard are pivoted into a single cursor
+		// together with the other variable references. The variable references
+		// are also pivoted when there is more than one of them so each of the
+		// selected fields and tags is a column of the same row. The field of a
+		// selector is pivoted too so it selects the rows of the other fields.
+		fields := make([]influxql.Expr, 0, len(gr.wildcards)+len(gr.refs)+1)
+		if gr.call != nil {
+			fields = append(fields, fieldArg(gr.call))
+		}
+		fields = append(fields, gr.wildcards...)
+		for _, ref := range gr.refs {
+			fields = append(fields, ref)
+		}
+
+		// Read the fields in the condition too so they can be filtered.
+		influxql.WalkFunc(t.stmt.Condition, func(node influxql.Node) {
+			if ref, ok := node.(*influxql.VarRef); ok && ref.Val != "time" {
+				fields = append(fields, ref)
+			}
+		})
+		cur, err := createPivotCursor(t, fields)
 		if err != nil {
 			return nil, err
 		}
+		if gr.call != nil {
+			cur = createSelectorCursor(cur, fieldArg(gr.call).(*influxql.VarRef))
+		}
 		cursors = append(cursors, cur)
+	} else if gr.call == nil {
+		for _, ref := range gr.refs {
+			cur, err := createVarRefCursor(t, ref)
+			if err != nil {
+				return nil, err
+			}
+			cursors = append(cursors, cur)
+		}
 	}
 
 	// TODO(jsternberg): Establish which variables in the condition are tags and which are fields.
@@ -242,7 +380,13 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 
 	// If a function call is present, evaluate the function call.
 	if gr.call != nil {
-		c, err := createFunctionCursor(t, gr.call, cur, !gr.selector || interval > 0)
+		// The top and bottom functions keep the time of the selected points when
+		// other fields are selected with them.
+		normalize := !gr.selector || interval > 0
+		if gr.pivoted() && (gr.call.Name == "top" || gr.call.Name == "bottom") {
+			normalize = false
+		}
+		c, err := createFunctionCursor(t, gr.call, cur, normalize)
 		if err != nil {
 			return nil, err
 		}
@@ -250,7 +394,8 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 
 		// If there was a window operation, we now need to undo that and sort by the start column
 		// so they stay in the same table and are joined in the correct order.
-		if interval > 0 {
+		// A transformation has already undone the window operation.
+		if interval > 0 && !isTransformation(gr.call) {
 			cur = &pipeCursor{
 				expr: &ast.PipeExpression{
 					Argument: cur.Expr(),
@@ -269,32 +414,50 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 				cursor: cur,
 			}
 		}
-	} else {
-		// If we do not have a function, but we have a field option,
-		// return the appropriate error message if there is something wrong with the flux.
-		if interval > 0 {
-			return nil, errors.New("using GROUP BY requires at least one aggregate function")
-		}
-
-		// TODO(jsternberg): Fill needs to be somewhere and it's probably here somewhere.
-		// Move this to the correct location once we've figured it out.
-		switch t.stmt.Fill {
-		case influxql.NoFill:
-			return nil, errors.New("fill(none) must be used with a function")
-		case influxql.LinearFill:
-			return nil, errors.New("fill(linear) must be used with a function")
-		}
+	} else if err := t.validateRawFields(); err != nil {
+		return nil, err
 	}
 	return cur, nil
 }
 
+// validateRawFields validates the options of a statement that selects the fields
+// without a function.
+func (t *transpilerState) validateRawFields() error {
+	interval, err := t.stmt.GroupByInterval()
+	if err != nil {
+		return err
+	}
+
+	// If we do not have a function, but we have a field option,
+	// return the appropriate error message if there is something wrong with the flux.
+	if interval > 0 {
+		return errors.New("using GROUP BY requires at least one aggregate function")
+	}
+
+	// TODO(jsternberg): Fill needs to be somewhere and it's probably here somewhere.
+	// Move this to the correct location once we've figured it out.
+	switch t.stmt.Fill {
+	case influxql.NoFill:
+		return errors.New("fill(none) must be used with a function")
+	case influxql.LinearFill:
+		return errors.New("fill(linear) must be used with a function")
+	}
+	return nil
+}
+
 func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	var windowEvery time.Duration
 	var windowStart time.Time
+	var allTags bool
 	tags := []ast.Expression{
 		&ast.StringLiteral{Value: "_measurement"},
 		&ast.StringLiteral{Value: "_start"},
 	}
+	if gr.call != nil && gr.wildcardArg() {
+		// Keep each of the fields matched by the wildcard in its own table
+		// so the function is applied to each field.
+		tags = append(tags, &ast.StringLiteral{Value: "_field"})
+	}
 	if len(t.stmt.Dimensions) > 0 {
 		// Maintain a set of the dimensions we have encountered.
 		// This is so we don't duplicate groupings, but we still maintain the
@@ -367,7 +530,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 					}
 				}
 			case *influxql.Wildcard:
-				return nil, errors.New("unimplemented: dimension wildcards")
+				allTags = true
 			case *influxql.RegexLiteral:
 				return nil, errors.New("unimplemented: dimension regex wildcards")
 			default:
@@ -377,40 +540,44 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	}
 
 	// Perform the grouping by the tags we found. There is always a group by because
-	// there is always something to group in influxql.
-	// TODO(jsternberg): A wildcard will skip this step.
-	in = &pipeCursor{
-		expr: &ast.PipeExpression{
-			Argument: in.Expr(),
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "group",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{
-									Name: "columns",
-								},
-								Value: &ast.ArrayExpression{
-									Elements: tags,
-								},
-							},
-							{
-								Key: &ast.Identifier{
-									Name: "mode",
+	// there is always something to group in influxql. A dimension wildcard groups by
+	// all of the tags, which are only known when the query is executed.
+	if allTags {
+		in = gr.groupAllTags(t, in)
+	} else {
+		in = &pipeCursor{
+			expr: &ast.PipeExpression{
+				Argument: in.Expr(),
+				Call: &ast.CallExpression{
+					Callee: &ast.Identifier{
+						Name: "group",
+					},
+					Arguments: []ast.Expression{
+						&ast.ObjectExpression{
+							Properties: []*ast.Property{
+								{
+									Key: &ast.Identifier{
+										Name: "columns",
+									},
+									Value: &ast.ArrayExpression{
+										Elements: tags,
+									},
 								},
-								Value: &ast.StringLiteral{
-									Value: "by",
+								{
+									Key: &ast.Identifier{
+										Name: "mode",
+									},
+									Value: &ast.StringLiteral{
+										Value: "by",
+									},
 								},
 							},
 						},
 					},
 				},
 			},
-		},
-		cursor: in,
+			cursor: in,
+		}
 	}
 
 	if windowEvery > 0 {
@@ -452,6 +619,70 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	return in, nil
 }
 
+// groupAllTags groups the tables by all of the tags. The tables read from storage
+// are already grouped by the series, so the field and the columns that are not
+// tags are removed from the group key. The field is kept when the function is
+// applied to each of the fields matched by a wildcard. The group key of pivoted
+// fields and of subqueries only has the tags of each series, so they are not
+// grouped again.
+func (gr *groupInfo) groupAllTags(t *transpilerState, in cursor) cursor {
+	if gr.pivoted() {
+		return in
+	}
+	for _, source := range t.stmt.Sources {
+		if _, ok := source.(*influxql.SubQuery); ok {
+			return in
+		}
+	}
+
+	columns := []string{execute.DefaultTimeColLabel, execute.DefaultStopColLabel, execute.DefaultValueColLabel}
+	if !gr.hasWildcard() {
+		columns = append(columns, "_field")
+	}
+	return &pipeCursor{
+		expr: pipeCall(in.Expr(), &ast.Identifier{Name: "group"},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "columns"},
+				Value: stringArray(columns...),
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "mode"},
+				Value: &ast.StringLiteral{Value: "except"},
+			},
+		),
+		cursor: in,
+	}
+}
+
+// hasWildcard returns true if the group selects fields with a wildcard or a regex.
+func (gr *groupInfo) hasWildcard() bool {
+	return len(gr.wildcards) > 0 || gr.wildcardArg()
+}
+
+// wildcardArg returns true if the function of the group is called on the fields
+// matched by a wildcard or a regex.
+func (gr *groupInfo) wildcardArg() bool {
+	if gr.call == nil {
+		return false
+	}
+	switch fieldArg(gr.call).(type) {
+	case *influxql.Wildcard, *influxql.RegexLiteral:
+		return true
+	}
+	return false
+}
+
+// pivoted returns true if the fields of the group are read into a single cursor
+// that pivots them into columns. A selector of a field is pivoted together with
+// the other fields and tags that are selected with it.
+func (gr *groupInfo) pivoted() bool {
+	if gr.call == nil {
+		return len(gr.wildcards) > 0 || len(gr.refs) > 1
+	}
+	_, ok := fieldArg(gr.call).(*influxql.VarRef)
+	return ok && (len(gr.wildcards) > 0 || len(gr.refs) > 0)
+}
+
 // tagsCursor is a pseudo-cursor that can be used to access tags within the cursor.
 type tagsCursor struct {
 	cursor
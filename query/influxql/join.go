@@ -33,10 +33,10 @@ func Join(t *transpilerState, cursors []cursor, on []string) cursor {
 			},
 		})
 		for _, k := range cur.Keys() {
-			// Combine the table name with the name to access this attribute so we can know
-			// what it will be mapped to.
+			// The join appends the table name to the name of a column that is in
+			// more than one of the tables so we know what it will be mapped to.
 			varName, _ := cur.Value(k)
-			name := fmt.Sprintf("%s_%s", tableName, varName)
+			name := fmt.Sprintf("%s_%s", varName, tableName)
 			exprs = append(exprs, k)
 			m[k] = name
 		}
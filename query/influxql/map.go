@@ -2,11 +2,14 @@ package influxql
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
+	stdinfluxql "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxql"
 )
 
@@ -34,14 +37,15 @@ func (c *mapCursor) Value(expr influxql.Expr) (string, bool) {
 // using the column names.
 func (t *transpilerState) mapFields(in cursor) (cursor, error) {
 	columns := t.stmt.ColumnNames()
-	if len(columns) != len(t.stmt.Fields) {
+	fields := t.columnFields()
+	if len(columns) != len(fields) {
 		// TODO(jsternberg): This scenario should not be possible. Replace the use of ColumnNames with a more
 		// statically verifiable list of columns when we process the fields from the select statement instead
 		// of doing this in the future.
 		panic("number of columns does not match the number of fields")
 	}
 
-	properties := make([]*ast.Property, 0, len(t.stmt.Fields)+1)
+	properties := make([]*ast.Property, 0, len(fields)+1)
 	properties = append(properties, &ast.Property{
 		Key: &ast.Identifier{
 			Name: execute.DefaultTimeColLabel,
@@ -55,67 +59,96 @@ func (t *transpilerState) mapFields(in cursor) (cursor, error) {
 			},
 		},
 	})
-	for i, f := range t.stmt.Fields {
-		if ref, ok := f.Expr.(*influxql.VarRef); ok && ref.Val == "time" {
+
+	// The map orders the columns by their names. When the columns are not selected
+	// in that order, they are mapped to placeholders in the order of the statement
+	// and renamed once they have been mapped.
+	placeholders := !sort.StringsAreSorted(columns)
+	var renames []*ast.Property
+	for i, f := range fields {
+		if ref, ok := f.(*influxql.VarRef); ok && ref.Val == "time" {
 			// Skip past any time columns.
 			continue
 		}
-		value, err := t.mapField(f.Expr, in)
+		value, err := t.mapField(f, in)
 		if err != nil {
 			return nil, err
 		}
+		name := columns[i]
+		if placeholders {
+			name = fmt.Sprintf("_%0*d", len(strconv.Itoa(len(fields)-1)), i)
+			renames = append(renames, &ast.Property{
+				Key:   &ast.Identifier{Name: name},
+				Value: &ast.StringLiteral{Value: columns[i]},
+			})
+		}
 		properties = append(properties, &ast.Property{
-			Key:   &ast.Identifier{Name: columns[i]},
+			Key:   &ast.Identifier{Name: name},
 			Value: value,
 		})
 	}
-	return &mapCursor{
-		expr: &ast.PipeExpression{
-			Argument: in.Expr(),
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "map",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{{
-							Key: &ast.Identifier{
-								Name: "fn",
-							},
-							Value: &ast.FunctionExpression{
-								Params: []*ast.Property{{
-									Key: &ast.Identifier{Name: "r"},
-								}},
-								Body: &ast.ObjectExpression{
-									Properties: properties,
-								},
+
+	// The map function of the standard library cannot call the math functions
+	// so the one from the influxql package is used when they are needed.
+	var callee ast.Expression = &ast.Identifier{Name: "map"}
+	if hasMathFunction(fields) {
+		callee = t.packageMember(stdinfluxql.PackagePath, "map")
+	}
+	var expr ast.Expression = &ast.PipeExpression{
+		Argument: in.Expr(),
+		Call: &ast.CallExpression{
+			Callee: callee,
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{
+							Name: "fn",
+						},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "r"},
+							}},
+							Body: &ast.ObjectExpression{
+								Properties: properties,
 							},
-						}},
-					},
+						},
+					}},
 				},
 			},
 		},
+	}
+	return &mapCursor{
+		expr: renameColumns(expr, renames),
 	}, nil
 }
 
+// columnFields returns the expression of each column in the same order as the
+// column names. The tags selected by the top and bottom functions are
+// returned as columns following the function.
+func (t *transpilerState) columnFields() []influxql.Expr {
+	fields := make([]influxql.Expr, 0, len(t.stmt.Fields))
+	for _, f := range t.stmt.Fields {
+		fields = append(fields, f.Expr)
+		if call, ok := f.Expr.(*influxql.Call); ok && (call.Name == "top" || call.Name == "bottom") {
+			for _, arg := range call.Args[1:] {
+				if ref, ok := arg.(*influxql.VarRef); ok {
+					fields = append(fields, ref)
+				}
+			}
+		}
+	}
+	return fields
+}
+
 func (t *transpilerState) mapField(expr influxql.Expr, in cursor) (ast.Expression, error) {
 	if sym, ok := in.Value(expr); ok {
-		var property ast.PropertyKey
-		if strings.HasPrefix(sym, "_") {
-			property = &ast.Identifier{Name: sym}
-		} else {
-			property = &ast.StringLiteral{Value: sym}
-		}
-		return &ast.MemberExpression{
-			Object:   &ast.Identifier{Name: "r"},
-			Property: property,
-		}, nil
+		return rowColumn(sym), nil
 	}
 
 	switch expr := expr.(type) {
 	case *influxql.Call:
 		if isMathFunction(expr) {
-			return nil, fmt.Errorf("unimplemented math function: %q", expr.Name)
+			return t.mapMathFunction(expr, in)
 		}
 		return nil, fmt.Errorf("missing symbol for %s", expr)
 	case *influxql.VarRef:
@@ -170,6 +203,10 @@ func (t *transpilerState) evalBinaryExpr(expr *influxql.BinaryExpr, in cursor) (
 			return b.eval(ast.AdditionOperator)
 		case influxql.SUB:
 			return b.eval(ast.SubtractionOperator)
+		case influxql.MUL:
+			return b.eval(ast.MultiplicationOperator)
+		case influxql.DIV:
+			return b.div
 		case influxql.AND:
 			return b.logical(ast.AndOperator)
 		case influxql.OR:
@@ -197,6 +234,23 @@ func (t *transpilerState) evalBinaryExpr(expr *influxql.BinaryExpr, in cursor) (
 	return fn(lhs, rhs), nil
 }
 
+// rowColumn returns the expression that reads the column from the row.
+func rowColumn(name string) *ast.MemberExpression {
+	return &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: "r"},
+		Property: columnKey(name),
+	}
+}
+
+// columnKey returns the property key for the column. The names of fields and
+// tags may not be valid identifiers so they are used as strings.
+func columnKey(name string) ast.PropertyKey {
+	if strings.HasPrefix(name, "_") {
+		return &ast.Identifier{Name: name}
+	}
+	return &ast.StringLiteral{Value: name}
+}
+
 // evalBuilder is used for namespacing the logical and eval wrapping functions.
 type evalBuilder struct{}
 
@@ -219,3 +273,26 @@ func (evalBuilder) eval(op ast.OperatorKind) func(left, right ast.Expression) as
 		}
 	}
 }
+
+// div divides the values as floats. The division of two integers in influxql
+// is not truncated.
+func (evalBuilder) div(left, right ast.Expression) ast.Expression {
+	float := func(expr ast.Expression) ast.Expression {
+		return &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "float"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key:   &ast.Identifier{Name: "v"},
+						Value: expr,
+					}},
+				},
+			},
+		}
+	}
+	return &ast.BinaryExpression{
+		Operator: ast.DivisionOperator,
+		Left:     float(left),
+		Right:    float(right),
+	}
+}
@@ -1,6 +1,10 @@
 package influxql
 
-import "github.com/influxdata/influxql"
+import (
+	"github.com/influxdata/flux/ast"
+	stdinfluxql "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxql"
+)
 
 // isMathFunction returns true if the call is a math function.
 func isMathFunction(expr *influxql.Call) bool {
@@ -10,3 +14,55 @@ func isMathFunction(expr *influxql.Call) bool {
 	}
 	return false
 }
+
+// hasMathFunction returns true if any of the expressions calls a math function.
+func hasMathFunction(exprs []influxql.Expr) bool {
+	found := false
+	for _, expr := range exprs {
+		influxql.WalkFunc(expr, func(n influxql.Node) {
+			if call, ok := n.(*influxql.Call); ok && isMathFunction(call) {
+				found = true
+			}
+		})
+	}
+	return found
+}
+
+// mathFunctionParams returns the names of the parameters of the math function.
+func mathFunctionParams(name string) []string {
+	switch name {
+	case "atan2":
+		return []string{"y", "x"}
+	case "log":
+		return []string{"x", "b"}
+	case "pow":
+		return []string{"x", "y"}
+	default:
+		return []string{"x"}
+	}
+}
+
+// mapMathFunction maps the math function to the function with the same name
+// in the influxql package of flux.
+func (t *transpilerState) mapMathFunction(expr *influxql.Call, in cursor) (ast.Expression, error) {
+	params := mathFunctionParams(expr.Name)
+	properties := make([]*ast.Property, 0, len(params))
+	for i, arg := range expr.Args {
+		value, err := t.mapField(arg, in)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: params[i]},
+			Value: value,
+		})
+	}
+	return &ast.CallExpression{
+		Callee: t.packageMember(stdinfluxql.PackagePath, expr.Name),
+		Arguments: []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: properties,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,947 @@
+package influxql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	stdinfluxql "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxql"
+)
+
+// wildcardCursor contains a cursor for the fields matched by a wildcard or a regex.
+// Each of the fields is read into its own table and the value of every field is in
+// the default value column.
+type wildcardCursor struct {
+	expr  ast.Expression
+	match influxql.Expr
+}
+
+// createWildcardCursor creates a new cursor for the fields matched by a wildcard
+// or a regex using the sources in the transpilerState.
+func createWildcardCursor(t *transpilerState, match influxql.Expr) (cursor, error) {
+	expr, err := createSourcesExpr(t, []influxql.Expr{match}, false)
+	if err != nil {
+		return nil, err
+	}
+	return &wildcardCursor{
+		expr:  expr,
+		match: match,
+	}, nil
+}
+
+func (c *wildcardCursor) Expr() ast.Expression {
+	return c.expr
+}
+
+func (c *wildcardCursor) Keys() []influxql.Expr {
+	return []influxql.Expr{c.match}
+}
+
+func (c *wildcardCursor) Value(expr influxql.Expr) (string, bool) {
+	if expr == c.match {
+		return execute.DefaultValueColLabel, true
+	}
+	return "", false
+}
+
+// pivotCursor contains the fields pivoted into a column for each field key.
+// The names of the fields matched by a wildcard are not known until the
+// query is executed, so every variable reference is assumed to be one of
+// the columns. The tags of each series are also columns so they can be
+// referenced in the same way.
+type pivotCursor struct {
+	expr ast.Expression
+	refs []influxql.Expr
+}
+
+// createPivotCursor creates a new cursor that pivots the fields matched by the
+// variable references, wildcards and regexes into columns.
+func createPivotCursor(t *transpilerState, fields []influxql.Expr) (cursor, error) {
+	expr, err := createSourcesExpr(t, fields, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []influxql.Expr
+	for _, f := range fields {
+		if ref, ok := f.(*influxql.VarRef); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return &pivotCursor{
+		expr: expr,
+		refs: refs,
+	}, nil
+}
+
+func (c *pivotCursor) Expr() ast.Expression {
+	return c.expr
+}
+
+func (c *pivotCursor) Keys() []influxql.Expr {
+	return c.refs
+}
+
+func (c *pivotCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+	return ref.Val, true
+}
+
+// selectorCursor contains the pivoted fields with the field of a selector in
+// the value column, so the selector selects the rows of the pivoted fields by the
+// value of that field. The rows where the field is null are removed.
+type selectorCursor struct {
+	cursor
+	expr ast.Expression
+	ref  *influxql.VarRef
+}
+
+// createSelectorCursor creates a new cursor that selects the pivoted fields of
+// the cursor by the field of the variable reference.
+func createSelectorCursor(in cursor, ref *influxql.VarRef) cursor {
+	expr := pipeCall(in.Expr(), &ast.Identifier{Name: "duplicate"},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "column"},
+			Value: &ast.StringLiteral{Value: ref.Val},
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "as"},
+			Value: &ast.StringLiteral{Value: execute.DefaultValueColLabel},
+		},
+	)
+	value := &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: "r"},
+		Property: &ast.Identifier{Name: execute.DefaultValueColLabel},
+	}
+	expr = pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left:     value,
+				Right:    value,
+			},
+		},
+	})
+	return &selectorCursor{
+		cursor: in,
+		expr:   expr,
+		ref:    ref,
+	}
+}
+
+func (c *selectorCursor) Expr() ast.Expression {
+	return c.expr
+}
+
+func (c *selectorCursor) Value(expr influxql.Expr) (string, bool) {
+	if expr == c.ref {
+		return execute.DefaultValueColLabel, true
+	}
+	return c.cursor.Value(expr)
+}
+
+// pivotFields produces a column for each of the fields when any of them
+// were selected with a wildcard. As the fields matched by the wildcard are
+// not known until the query is executed, the field keys are turned into the
+// columns with a pivot instead of mapping each field to a column.
+func (t *transpilerState) pivotFields(groups []*groupInfo, cursors []cursor) (cursor, error) {
+	// If there is no function, the fields have already been pivoted by the cursor
+	// and we only need to remove the columns that were not selected. The value
+	// of a selector is renamed to the column of the selector.
+	if len(groups) == 1 && (groups[0].call == nil || groups[0].pivoted()) {
+		gr := groups[0]
+		if t.unpivoted {
+			return nil, errors.New("unimplemented: field wildcard with a subquery that selects a function with other fields")
+		}
+		if gr.call != nil {
+			if j := t.fieldIndex(gr.call); j < 0 || t.columnFields()[j] != gr.call {
+				return nil, fmt.Errorf("unimplemented: expressions of %s() selected with other fields", gr.call.Name)
+			}
+		}
+		return &mapCursor{
+			expr: t.renameFields(t.keepFields(cursors[0].Expr(), gr), gr),
+		}, nil
+	}
+
+	// Each function is named after the column of the field with the name of
+	// the matched field appended to it such as mean_usage_user. The math of
+	// the field is evaluated on the values of the function before the pivot.
+	columns := t.stmt.ColumnNames()
+	fields := t.columnFields()
+
+	// The pivot orders the columns by their names. When the functions are not
+	// selected in that order, they are pivoted into placeholders in the order
+	// of the statement and renamed after the pivot, as is done by mapFields.
+	placeholders := !t.unpivoted && !sort.StringsAreSorted(columns)
+	for _, gr := range groups {
+		if gr.wildcardArg() {
+			placeholders = false
+		}
+	}
+	var renames []*ast.Property
+
+	exprs := make([]ast.Expression, 0, len(cursors))
+	for i, gr := range groups {
+		j := t.fieldIndex(gr.call)
+		if j < 0 {
+			return nil, errors.New("unimplemented: functions that are not selected by a field")
+		}
+
+		var name ast.Expression = &ast.StringLiteral{Value: columns[j]}
+		if placeholders {
+			placeholder := fmt.Sprintf("_%0*d", len(strconv.Itoa(len(fields)-1)), j)
+			renames = append(renames, &ast.Property{
+				Key:   &ast.Identifier{Name: placeholder},
+				Value: &ast.StringLiteral{Value: columns[j]},
+			})
+			name = &ast.StringLiteral{Value: placeholder}
+		} else if gr.wildcardArg() {
+			name = &ast.BinaryExpression{
+				Operator: ast.AdditionOperator,
+				Left:     &ast.StringLiteral{Value: columns[j] + "_"},
+				Right: &ast.MemberExpression{
+					Object:   &ast.Identifier{Name: "r"},
+					Property: &ast.Identifier{Name: "_field"},
+				},
+			}
+		}
+
+		// The values of all of the functions are pivoted from the same table so
+		// they must have the same type. A count is an integer even when the
+		// other functions are floats, so it is converted to a float.
+		toFloat := len(groups) > 1 && gr.call.Name == "count"
+
+		f := fields[j]
+		if f == gr.call && !toFloat {
+			exprs = append(exprs, renameField(cursors[i].Expr(), name))
+			continue
+		}
+
+		var value ast.Expression = &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: execute.DefaultValueColLabel},
+		}
+		if f != gr.call {
+			v, err := t.mapField(f, cursors[i])
+			if err != nil {
+				return nil, errors.New("unimplemented: expressions of more than one function with a field wildcard, a dimension wildcard or more than two functions")
+			}
+			value = v
+		}
+		if toFloat {
+			value = &ast.CallExpression{
+				Callee: &ast.Identifier{Name: "float"},
+				Arguments: []ast.Expression{&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key:   &ast.Identifier{Name: "v"},
+						Value: value,
+					}},
+				}},
+			}
+		}
+		var callee ast.Expression = &ast.Identifier{Name: "map"}
+		if hasMathFunction([]influxql.Expr{f}) {
+			callee = t.packageMember(stdinfluxql.PackagePath, "map")
+		}
+		exprs = append(exprs, mapFieldValue(callee, cursors[i].Expr(), name, value))
+	}
+
+	expr := exprs[0]
+	if len(exprs) > 1 {
+		expr = union(t, exprs)
+	}
+	if t.unpivoted {
+		return &mapCursor{expr: expr}, nil
+	}
+
+	// Remove the field from the group key so all of the fields of a series
+	// are pivoted into the same table. The tags are not known when the
+	// statement is grouped by all of them so only the field is removed.
+	if t.groupsByAllTags() {
+		expr = pipeCall(expr, &ast.Identifier{Name: "group"},
+			&ast.Property{
+				Key: &ast.Identifier{Name: "columns"},
+				Value: stringArray(
+					execute.DefaultTimeColLabel,
+					execute.DefaultStopColLabel,
+					execute.DefaultValueColLabel,
+					"_field",
+				),
+			},
+			&ast.Property{
+				Key:   &ast.Identifier{Name: "mode"},
+				Value: &ast.StringLiteral{Value: "except"},
+			},
+		)
+		expr = pipeCall(expr, &ast.Identifier{Name: "sort"}, &ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray(execute.DefaultTimeColLabel, "_field"),
+		})
+		return &mapCursor{
+			expr: renameColumns(pivot(expr), renames),
+		}, nil
+	}
+
+	tags := []ast.Expression{
+		&ast.StringLiteral{Value: "_measurement"},
+		&ast.StringLiteral{Value: "_start"},
+	}
+	for _, name := range t.dimensionTags() {
+		tags = append(tags, &ast.StringLiteral{Value: name})
+	}
+	expr = &ast.PipeExpression{
+		Argument: expr,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "group",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{
+								Name: "columns",
+							},
+							Value: &ast.ArrayExpression{
+								Elements: tags,
+							},
+						},
+						{
+							Key: &ast.Identifier{
+								Name: "mode",
+							},
+							Value: &ast.StringLiteral{
+								Value: "by",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Sort the fields so the columns of the pivot are in a consistent order.
+	expr = &ast.PipeExpression{
+		Argument: expr,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "sort",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{
+							Name: "columns",
+						},
+						Value: &ast.ArrayExpression{
+							Elements: []ast.Expression{
+								&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+								&ast.StringLiteral{Value: "_field"},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	return &mapCursor{
+		expr: renameColumns(pivot(expr), renames),
+	}, nil
+}
+
+// renameColumns renames the columns of the properties. The expression is
+// returned unchanged when there are no columns to rename.
+func renameColumns(expr ast.Expression, renames []*ast.Property) ast.Expression {
+	if len(renames) == 0 {
+		return expr
+	}
+	return pipeCall(expr, &ast.Identifier{Name: "rename"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: &ast.ObjectExpression{Properties: renames},
+	})
+}
+
+// hasWildcardExpr returns true if the fields of the statement are matched by a
+// wildcard or a regex and any of the fields is an expression, such as a math
+// function called on the matched fields.
+func (t *transpilerState) hasWildcardExpr() bool {
+	var wildcard, expr bool
+	for _, f := range t.stmt.Fields {
+		switch f.Expr.(type) {
+		case *influxql.VarRef:
+			continue
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			wildcard = true
+			continue
+		}
+		expr = true
+		influxql.WalkFunc(f.Expr, func(n influxql.Node) {
+			switch n.(type) {
+			case *influxql.Wildcard, *influxql.RegexLiteral:
+				wildcard = true
+			}
+		})
+	}
+	return wildcard && expr
+}
+
+// pivotRawFields evaluates the expressions of the fields on each of the fields
+// before they are pivoted, as the names of the fields matched by a wildcard are
+// not known until the query is executed. Each of the fields is read into its own
+// table with the column it is selected as for the field key, such as abs_usage_user.
+// The fields in the condition are read too so the condition can be evaluated
+// after the pivot.
+func (t *transpilerState) pivotRawFields(gr *groupInfo) (cursor, error) {
+	if err := t.validateRawFields(); err != nil {
+		return nil, err
+	}
+
+	var (
+		columns  = t.stmt.ColumnNames()
+		exprs    []ast.Expression
+		names    []string
+		patterns []*regexp.Regexp
+		read     = make(map[string]bool)
+		all      bool
+	)
+	for i, f := range t.stmt.Fields {
+		var args []influxql.Expr
+		influxql.WalkFunc(f.Expr, func(n influxql.Node) {
+			switch n := n.(type) {
+			case *influxql.VarRef:
+				if n.Val != "time" {
+					args = append(args, n)
+				}
+			case *influxql.Wildcard:
+				args = append(args, n)
+			case *influxql.RegexLiteral:
+				args = append(args, n)
+			}
+		})
+		if len(args) == 0 {
+			continue
+		} else if len(args) > 1 {
+			return nil, errors.New("unimplemented: expressions of more than one field with a field wildcard")
+		}
+
+		var (
+			cur cursor
+			err error
+		)
+		ref, ok := args[0].(*influxql.VarRef)
+		if ok {
+			cur, err = createVarRefCursor(t, ref)
+		} else {
+			cur, err = createWildcardCursor(t, args[0])
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// The fields that are selected without an expression keep their values.
+		if f.Expr == args[0] {
+			expr := cur.Expr()
+			switch arg := args[0].(type) {
+			case *influxql.Wildcard:
+				all = true
+			case *influxql.RegexLiteral:
+				patterns = append(patterns, arg.Val)
+			case *influxql.VarRef:
+				names = append(names, columns[i])
+				if arg.Val != columns[i] {
+					expr = renameField(expr, &ast.StringLiteral{Value: columns[i]})
+				} else {
+					read[arg.Val] = true
+				}
+			}
+			exprs = append(exprs, expr)
+			continue
+		}
+
+		value, err := t.mapField(f.Expr, cur)
+		if err != nil {
+			return nil, err
+		}
+		var name ast.Expression = &ast.StringLiteral{Value: columns[i]}
+		if ok {
+			names = append(names, columns[i])
+		} else {
+			name = &ast.BinaryExpression{
+				Operator: ast.AdditionOperator,
+				Left:     &ast.StringLiteral{Value: columns[i] + "_"},
+				Right: &ast.MemberExpression{
+					Object:   &ast.Identifier{Name: "r"},
+					Property: &ast.Identifier{Name: "_field"},
+				},
+			}
+			patterns = append(patterns, regexp.MustCompile("^"+regexp.QuoteMeta(columns[i]+"_")))
+		}
+		var callee ast.Expression = &ast.Identifier{Name: "map"}
+		if hasMathFunction([]influxql.Expr{f.Expr}) {
+			callee = t.packageMember(stdinfluxql.PackagePath, "map")
+		}
+		exprs = append(exprs, mapFieldValue(callee, cur.Expr(), name, value))
+	}
+
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	cond, _, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fields of a subquery that are read with a wildcard are not pivoted.
+	// The condition is evaluated on each of the fields so the variables in
+	// the condition are assumed to be tags.
+	if t.unpivoted {
+		if cond != nil {
+			value, err := t.mapField(cond, &pivotCursor{})
+			if err != nil {
+				return nil, err
+			}
+			for i, expr := range exprs {
+				exprs[i] = pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+					Key: &ast.Identifier{Name: "fn"},
+					Value: &ast.FunctionExpression{
+						Params: []*ast.Property{{
+							Key: &ast.Identifier{Name: "r"},
+						}},
+						Body: value,
+					},
+				})
+			}
+		}
+		if len(exprs) > 1 {
+			return &mapCursor{expr: union(t, exprs)}, nil
+		}
+		return &mapCursor{expr: exprs[0]}, nil
+	}
+
+	// Read the fields of the condition that are not read by any of the fields.
+	var (
+		unselected []string
+		condErr    error
+	)
+	influxql.WalkFunc(cond, func(n influxql.Node) {
+		ref, ok := n.(*influxql.VarRef)
+		if !ok || all || read[ref.Val] || condErr != nil {
+			return
+		}
+		for _, re := range patterns {
+			if re.MatchString(ref.Val) {
+				return
+			}
+		}
+		cur, err := createVarRefCursor(t, ref)
+		if err != nil {
+			condErr = err
+			return
+		}
+		exprs = append(exprs, cur.Expr())
+		unselected = append(unselected, ref.Val)
+		read[ref.Val] = true
+	})
+	if condErr != nil {
+		return nil, condErr
+	}
+
+	expr := exprs[0]
+	if len(exprs) > 1 {
+		expr = union(t, exprs)
+	}
+
+	// Pivot the fields of each series into the columns.
+	expr = pipeCall(expr, &ast.Identifier{Name: "group"},
+		&ast.Property{
+			Key: &ast.Identifier{Name: "columns"},
+			Value: stringArray(
+				execute.DefaultTimeColLabel,
+				execute.DefaultValueColLabel,
+				"_field",
+			),
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "mode"},
+			Value: &ast.StringLiteral{Value: "except"},
+		},
+	)
+	expr = pivot(pipeCall(expr, &ast.Identifier{Name: "sort"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray(execute.DefaultTimeColLabel, "_field"),
+	}))
+
+	var cur cursor = &pivotCursor{expr: expr}
+	if cond != nil {
+		value, err := t.mapField(cond, cur)
+		if err != nil {
+			return nil, err
+		}
+		cur = &pivotCursor{
+			expr: pipeCall(cur.Expr(), &ast.Identifier{Name: "filter"}, &ast.Property{
+				Key: &ast.Identifier{Name: "fn"},
+				Value: &ast.FunctionExpression{
+					Params: []*ast.Property{{
+						Key: &ast.Identifier{Name: "r"},
+					}},
+					Body: value,
+				},
+			}),
+		}
+	}
+	cur, err = gr.group(t, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the columns that were not selected. The tags are not known when
+	// the statement is grouped by all of them so only the columns that are
+	// known to not be selected are removed then.
+	column := &ast.Identifier{Name: "column"}
+	var body ast.Expression
+	if all || t.groupsByAllTags() {
+		drop := []string{execute.DefaultStartColLabel, execute.DefaultStopColLabel}
+		for _, name := range append(drop, unselected...) {
+			body = or(body, &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left:     column,
+				Right:    &ast.StringLiteral{Value: name},
+			})
+		}
+		return &mapCursor{
+			expr: pipeCall(cur.Expr(), &ast.Identifier{Name: "drop"}, &ast.Property{
+				Key: &ast.Identifier{Name: "fn"},
+				Value: &ast.FunctionExpression{
+					Params: []*ast.Property{{Key: column}},
+					Body:   body,
+				},
+			}),
+		}, nil
+	}
+
+	keep := []string{"_measurement", execute.DefaultTimeColLabel}
+	keep = append(keep, t.dimensionTags()...)
+	for _, name := range append(keep, names...) {
+		body = or(body, &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left:     column,
+			Right:    &ast.StringLiteral{Value: name},
+		})
+	}
+	for _, re := range patterns {
+		body = or(body, &ast.BinaryExpression{
+			Operator: ast.RegexpMatchOperator,
+			Left:     column,
+			Right:    &ast.RegexpLiteral{Value: re},
+		})
+	}
+	return &mapCursor{
+		expr: pipeCall(cur.Expr(), &ast.Identifier{Name: "keep"}, &ast.Property{
+			Key: &ast.Identifier{Name: "fn"},
+			Value: &ast.FunctionExpression{
+				Params: []*ast.Property{{Key: column}},
+				Body:   body,
+			},
+		}),
+	}, nil
+}
+
+// keepFields removes the columns that were not selected from the pivoted fields.
+// A wildcard selects all of the fields and tags so only the start and stop
+// columns are removed. The tags are not known when the statement is grouped
+// by all of them, so only the start and stop columns are removed then too.
+func (t *transpilerState) keepFields(expr ast.Expression, gr *groupInfo) ast.Expression {
+	for _, w := range gr.wildcards {
+		if _, ok := w.(*influxql.Wildcard); ok {
+			return &ast.PipeExpression{
+				Argument: expr,
+				Call: &ast.CallExpression{
+					Callee: &ast.Identifier{
+						Name: "drop",
+					},
+					Arguments: []ast.Expression{
+						&ast.ObjectExpression{
+							Properties: []*ast.Property{{
+								Key: &ast.Identifier{
+									Name: "columns",
+								},
+								Value: &ast.ArrayExpression{
+									Elements: []ast.Expression{
+										&ast.StringLiteral{Value: execute.DefaultStartColLabel},
+										&ast.StringLiteral{Value: execute.DefaultStopColLabel},
+									},
+								},
+							}},
+						},
+					},
+				},
+			}
+		}
+	}
+
+	if t.groupsByAllTags() {
+		return dropBounds(expr)
+	}
+
+	// Keep the columns that identify the series and each of the columns
+	// matched by the regexes or referenced by name.
+	column := &ast.Identifier{Name: "column"}
+	names := []string{"_measurement", execute.DefaultTimeColLabel}
+	names = append(names, t.dimensionTags()...)
+	for _, ref := range gr.refs {
+		names = append(names, ref.Val)
+	}
+	if gr.call != nil {
+		// The tags selected by the top and bottom functions are arguments.
+		names = append(names, execute.DefaultValueColLabel)
+		for _, arg := range gr.call.Args[1:] {
+			if ref, ok := arg.(*influxql.VarRef); ok {
+				names = append(names, ref.Val)
+			}
+		}
+	}
+
+	var body ast.Expression
+	for _, name := range names {
+		body = or(body, &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left:     column,
+			Right:    &ast.StringLiteral{Value: name},
+		})
+	}
+	for _, w := range gr.wildcards {
+		body = or(body, &ast.BinaryExpression{
+			Operator: ast.RegexpMatchOperator,
+			Left:     column,
+			Right:    &ast.RegexpLiteral{Value: w.(*influxql.RegexLiteral).Val},
+		})
+	}
+	return &ast.PipeExpression{
+		Argument: expr,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "keep",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{
+							Name: "fn",
+						},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: column,
+							}},
+							Body: body,
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// dropBounds removes the start and stop columns. They are matched by name
+// because they are not columns of the fields of subqueries that were pivoted
+// together.
+func dropBounds(expr ast.Expression) ast.Expression {
+	column := &ast.Identifier{Name: "column"}
+	var body ast.Expression
+	for _, name := range []string{execute.DefaultStartColLabel, execute.DefaultStopColLabel} {
+		body = or(body, &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left:     column,
+			Right:    &ast.StringLiteral{Value: name},
+		})
+	}
+	return pipeCall(expr, &ast.Identifier{Name: "drop"}, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{Key: column}},
+			Body:   body,
+		},
+	})
+}
+
+// renameFields renames the columns of the selected fields to the column names
+// of the statement when they are different, such as when a field has an alias.
+// The fields matched by a wildcard keep their names. The value of a selector
+// is renamed to the column of the selector.
+func (t *transpilerState) renameFields(expr ast.Expression, gr *groupInfo) ast.Expression {
+	columns := t.stmt.ColumnNames()
+	var properties []*ast.Property
+	for i, f := range t.columnFields() {
+		if gr.call != nil && f == gr.call {
+			properties = append(properties, &ast.Property{
+				Key:   columnKey(execute.DefaultValueColLabel),
+				Value: &ast.StringLiteral{Value: columns[i]},
+			})
+		} else if ref, ok := f.(*influxql.VarRef); ok && ref.Val != columns[i] && len(gr.wildcards) == 0 {
+			properties = append(properties, &ast.Property{
+				Key:   columnKey(ref.Val),
+				Value: &ast.StringLiteral{Value: columns[i]},
+			})
+		}
+	}
+	if len(properties) == 0 {
+		return expr
+	}
+	return pipeCall(expr, &ast.Identifier{Name: "rename"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: &ast.ObjectExpression{Properties: properties},
+	})
+}
+
+// groupsByAllTags returns true if the statement groups by a wildcard.
+func (t *transpilerState) groupsByAllTags() bool {
+	for _, d := range t.stmt.Dimensions {
+		if _, ok := d.Expr.(*influxql.Wildcard); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dimensionTags returns the names of the tags in the dimensions of the statement.
+func (t *transpilerState) dimensionTags() []string {
+	var tags []string
+	m := make(map[string]struct{})
+	for _, d := range t.stmt.Dimensions {
+		ref, ok := d.Expr.(*influxql.VarRef)
+		if !ok {
+			continue
+		} else if _, ok := m[ref.Val]; ok {
+			continue
+		}
+		tags = append(tags, ref.Val)
+		m[ref.Val] = struct{}{}
+	}
+	return tags
+}
+
+// or combines the expressions with a logical or. The left expression may be nil.
+func or(left, right ast.Expression) ast.Expression {
+	if left == nil {
+		return right
+	}
+	return &ast.LogicalExpression{
+		Operator: ast.OrOperator,
+		Left:     left,
+		Right:    right,
+	}
+}
+
+// renameField sets the field key of each table to name. The tables only keep
+// the time and value columns along with the group key.
+func renameField(expr, name ast.Expression) ast.Expression {
+	return mapFieldValue(&ast.Identifier{Name: "map"}, expr, name, &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: "r"},
+		Property: &ast.Identifier{Name: execute.DefaultValueColLabel},
+	})
+}
+
+// mapFieldValue sets the field key of each table to name and the value of each row
+// to value using the map function of the callee.
+func mapFieldValue(callee, expr, name, value ast.Expression) ast.Expression {
+	return pipeCall(expr, callee, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.ObjectExpression{
+				Properties: []*ast.Property{
+					{
+						Key: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+						Value: &ast.MemberExpression{
+							Object:   &ast.Identifier{Name: "r"},
+							Property: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+						},
+					},
+					{
+						Key:   &ast.Identifier{Name: "_field"},
+						Value: name,
+					},
+					{
+						Key:   &ast.Identifier{Name: execute.DefaultValueColLabel},
+						Value: value,
+					},
+				},
+			},
+		},
+	})
+}
+
+// fieldIndex returns the index of the column field that calls the function
+// or -1 when none of the fields call it.
+func (t *transpilerState) fieldIndex(call *influxql.Call) int {
+	for i, f := range t.columnFields() {
+		var found bool
+		influxql.WalkFunc(f, func(n influxql.Node) {
+			if n == influxql.Node(call) {
+				found = true
+			}
+		})
+		if found {
+			return i
+		}
+	}
+	return -1
+}
+
+// pivot turns the field keys of the tables into columns that hold the value
+// of each field at the same time.
+func pivot(expr ast.Expression) ast.Expression {
+	return &ast.PipeExpression{
+		Argument: expr,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "pivot",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{
+								Name: "rowKey",
+							},
+							Value: &ast.ArrayExpression{
+								Elements: []ast.Expression{
+									&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+								},
+							},
+						},
+						{
+							Key: &ast.Identifier{
+								Name: "columnKey",
+							},
+							Value: &ast.ArrayExpression{
+								Elements: []ast.Expression{
+									&ast.StringLiteral{Value: "_field"},
+								},
+							},
+						},
+						{
+							Key: &ast.Identifier{
+								Name: "valueColumn",
+							},
+							Value: &ast.StringLiteral{
+								Value: execute.DefaultValueColLabel,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
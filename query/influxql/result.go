@@ -1,6 +1,7 @@
 package influxql
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,10 +11,19 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/influxdb/models"
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	// TimeFormat is the format of the timestamps; defaults to RFC3339Nano.
+	// Timestamps are written as nanoseconds when the format is RFC3339Nano and
+	// the encoding is CSV like in 1.x.
+	TimeFormat TimeFormat
+
+	// Encoding is the format of the response; defaults to JSON.
+	Encoding EncodingFormat
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
 // Expectations/Assumptions:
@@ -154,7 +164,7 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 						vs := cr.Times(idx)
 						for i := 0; i < vs.Len(); i++ {
 							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
+								values[i][j] = e.formatTime(execute.Time(vs.Value(i)).Time())
 							}
 						}
 					default:
@@ -182,9 +192,133 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 		resp.error(err)
 	}
 
-	err := json.NewEncoder(wc).Encode(resp)
+	err := e.encode(wc, &resp)
 	return wc.Count(), err
 }
+
+// formatTime formats the timestamp with the time format of the encoder.
+func (e *MultiResultEncoder) formatTime(t time.Time) interface{} {
+	switch e.TimeFormat {
+	case Hour:
+		return t.UnixNano() / int64(time.Hour)
+	case Minute:
+		return t.UnixNano() / int64(time.Minute)
+	case Second:
+		return t.UnixNano() / int64(time.Second)
+	case Millisecond:
+		return t.UnixNano() / int64(time.Millisecond)
+	case Microsecond:
+		return t.UnixNano() / int64(time.Microsecond)
+	case Nanosecond:
+		return t.UnixNano()
+	}
+	if e.Encoding == CSV {
+		return t.UnixNano()
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// encode writes the response with the encoding of the encoder.
+func (e *MultiResultEncoder) encode(w io.Writer, resp *Response) error {
+	switch e.Encoding {
+	case JSONPretty:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(resp)
+	case CSV:
+		return encodeCSV(w, resp)
+	default:
+		return json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// encodeCSV writes the response in the 1.x CSV format. Each row starts with
+// the name and tags of its series and the header is written again whenever
+// the columns change. The results of the statements are separated by an
+// empty line.
+func encodeCSV(w io.Writer, resp *Response) error {
+	cw := csv.NewWriter(w)
+	if resp.Err != "" {
+		cw.Write([]string{"error"})
+		cw.Write([]string{resp.Err})
+		cw.Flush()
+		return cw.Error()
+	}
+
+	first := true
+	for _, result := range resp.Results {
+		if result.Err != "" {
+			cw.Write([]string{"error"})
+			cw.Write([]string{result.Err})
+			continue
+		}
+
+		var columns []string
+		for _, row := range result.Series {
+			if columns == nil || !stringsEqual(columns[2:], row.Columns) {
+				if !first {
+					cw.Flush()
+					if _, err := io.WriteString(w, "\n"); err != nil {
+						return err
+					}
+				}
+				first = false
+
+				columns = make([]string, 2+len(row.Columns))
+				columns[0], columns[1] = "name", "tags"
+				copy(columns[2:], row.Columns)
+				cw.Write(columns)
+			}
+
+			record := make([]string, len(columns))
+			record[0] = row.Name
+			if key := models.NewTags(row.Tags).HashKey(); len(key) > 0 {
+				record[1] = string(key[1:])
+			}
+			for _, values := range row.Values {
+				for i, v := range values {
+					record[i+2] = formatCSVValue(v)
+				}
+				cw.Write(record)
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCSVValue formats a value of a row for the CSV format.
+func formatCSVValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func NewMultiResultEncoder() *MultiResultEncoder {
 	return new(MultiResultEncoder)
 }
@@ -16,6 +16,7 @@ import (
 func TestMultiResultEncoder_Encode(t *testing.T) {
 	for _, tt := range []struct {
 		name string
+		enc  *influxql.MultiResultEncoder
 		in   flux.ResultIterator
 		out  string
 	}{
@@ -83,6 +84,68 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 			in:   &resultErrorIterator{Error: "expected"},
 			out:  `{"error":"expected"}`,
 		},
+		{
+			name: "Epoch",
+			enc:  &influxql.MultiResultEncoder{TimeFormat: influxql.Second},
+			in: flux.NewSliceResultIterator(
+				[]flux.Result{&executetest.Result{
+					Nm: "0",
+					Tbls: []*executetest.Table{{
+						KeyCols: []string{"_measurement"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{ts("2018-05-24T09:00:00Z"), "m0", float64(2)},
+						},
+					}},
+				}},
+			),
+			out: `{"results":[{"statement_id":0,"series":[{"name":"m0","columns":["time","value"],"values":[[1527152400,2]]}]}]}`,
+		},
+		{
+			name: "CSV",
+			enc:  &influxql.MultiResultEncoder{Encoding: influxql.CSV},
+			in: flux.NewSliceResultIterator(
+				[]flux.Result{&executetest.Result{
+					Nm: "0",
+					Tbls: []*executetest.Table{
+						{
+							KeyCols: []string{"_measurement", "host"},
+							ColMeta: []flux.ColMeta{
+								{Label: "_time", Type: flux.TTime},
+								{Label: "_measurement", Type: flux.TString},
+								{Label: "host", Type: flux.TString},
+								{Label: "value", Type: flux.TFloat},
+							},
+							Data: [][]interface{}{
+								{ts("2018-05-24T09:00:00Z"), "m0", "server01", float64(2)},
+								{ts("2018-05-24T09:00:10Z"), "m0", "server01", nil},
+							},
+						},
+						{
+							KeyCols: []string{"_measurement"},
+							ColMeta: []flux.ColMeta{
+								{Label: "_time", Type: flux.TTime},
+								{Label: "_measurement", Type: flux.TString},
+								{Label: "n", Type: flux.TInt},
+							},
+							Data: [][]interface{}{
+								{ts("2018-05-24T09:00:00Z"), "m1", int64(5)},
+							},
+						},
+					},
+				}},
+			),
+			out: `name,tags,time,value
+m0,host=server01,1527152400000000000,2
+m0,host=server01,1527152410000000000,
+
+name,tags,time,n
+m1,,1527152400000000000,5`,
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -90,7 +153,10 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 			tt.out += "\n"
 
 			var buf bytes.Buffer
-			enc := influxql.NewMultiResultEncoder()
+			enc := tt.enc
+			if enc == nil {
+				enc = influxql.NewMultiResultEncoder()
+			}
 			n, err := enc.Encode(&buf, tt.in)
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -0,0 +1,376 @@
+package influxql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	stdinfluxql "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxql"
+)
+
+func (t *transpilerState) transpileShowMeasurements(ctx context.Context, stmt *influxql.ShowMeasurementsStatement) (ast.Expression, error) {
+	var sources influxql.Sources
+	if stmt.Source != nil {
+		sources = influxql.Sources{stmt.Source}
+	}
+	expr, err := t.showSource(stmt.Database, sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the distinct measurement names and combine them into a single table
+	// sorted by the name. The table is named measurements like in 1.x. The other
+	// columns are dropped first since the series may have different value types.
+	expr = pipeCall(expr, &ast.Identifier{Name: "keep"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray("_measurement"),
+	})
+	expr = group(expr, "_measurement")
+	expr = pipeCall(expr, &ast.Identifier{Name: "distinct"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "column"},
+		Value: &ast.StringLiteral{Value: "_measurement"},
+	})
+	expr = pipeCall(expr, &ast.Identifier{Name: "group"})
+	expr = pipeCall(expr, &ast.Identifier{Name: "keep"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray(execute.DefaultValueColLabel),
+	})
+	expr = rename(expr, execute.DefaultValueColLabel, "name")
+	expr = sortBy(expr, "name")
+	expr = limit(expr, stmt.Limit, stmt.Offset)
+	expr = pipeCall(expr, &ast.Identifier{Name: "set"},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "key"},
+			Value: &ast.StringLiteral{Value: "_measurement"},
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "value"},
+			Value: &ast.StringLiteral{Value: "measurements"},
+		},
+	)
+	return group(expr, "_measurement"), nil
+}
+
+func (t *transpilerState) transpileShowTagKeys(ctx context.Context, stmt *influxql.ShowTagKeysStatement) (ast.Expression, error) {
+	if stmt.SLimit > 0 || stmt.SOffset > 0 {
+		return nil, fmt.Errorf("unimplemented: SLIMIT and SOFFSET in %s", stmt)
+	}
+	expr, err := t.showSource(stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tag keys of a single measurement are found with keys, keep and
+	// distinct, which storage answers from its index. The group keys include
+	// the columns that are not tags, so they are removed.
+	if name, ok := singleMeasurement(stmt.Sources); ok {
+		expr = pipeCall(expr, &ast.Identifier{Name: "keys"})
+		expr = pipeCall(expr, &ast.Identifier{Name: "keep"}, &ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray(execute.DefaultValueColLabel),
+		})
+		expr = pipeCall(expr, &ast.Identifier{Name: "distinct"})
+		expr = pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+			Key: &ast.Identifier{Name: "fn"},
+			Value: &ast.FunctionExpression{
+				Params: []*ast.Property{{
+					Key: &ast.Identifier{Name: "r"},
+				}},
+				Body: notAnyOf(rowColumn(execute.DefaultValueColLabel),
+					execute.DefaultStartColLabel, execute.DefaultStopColLabel, "_measurement", "_field"),
+			},
+		})
+		expr = setMeasurement(expr, name)
+		expr = pipeCall(expr, &ast.Identifier{Name: "distinct"})
+		expr = sortBy(expr, execute.DefaultValueColLabel)
+		expr = limit(expr, stmt.Limit, stmt.Offset)
+		return rename(expr, execute.DefaultValueColLabel, "tagKey"), nil
+	}
+
+	// Read the tags of each series and find the distinct tag keys of each measurement.
+	expr = pipeCall(expr, t.packageMember(stdinfluxql.PackagePath, "tags"))
+	expr = group(expr, "_measurement")
+	expr = pipeCall(expr, &ast.Identifier{Name: "distinct"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "column"},
+		Value: &ast.StringLiteral{Value: "_key"},
+	})
+	expr = sortBy(expr, execute.DefaultValueColLabel)
+	expr = limit(expr, stmt.Limit, stmt.Offset)
+	return rename(expr, execute.DefaultValueColLabel, "tagKey"), nil
+}
+
+func (t *transpilerState) transpileShowFieldKeys(ctx context.Context, stmt *influxql.ShowFieldKeysStatement) (ast.Expression, error) {
+	expr, err := t.showSource(stmt.Database, stmt.Sources, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the type of each series and keep one of each field key and type
+	// for every measurement.
+	expr = pipeCall(expr, t.packageMember(stdinfluxql.PackagePath, "fieldType"))
+	expr = group(expr, "_measurement", "_field", "fieldType")
+	expr = limit(expr, 1, 0)
+	expr = group(expr, "_measurement")
+	expr = pipeCall(expr, &ast.Identifier{Name: "keep"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray("_measurement", "_field", "fieldType"),
+	})
+	expr = rename(expr, "_field", "fieldKey")
+	expr = sortBy(expr, "fieldKey")
+	return limit(expr, stmt.Limit, stmt.Offset), nil
+}
+
+func (t *transpilerState) transpileShowSeries(ctx context.Context, stmt *influxql.ShowSeriesStatement) (ast.Expression, error) {
+	expr, err := t.showSource(stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the key of each series and combine the distinct keys into a single table.
+	expr = pipeCall(expr, t.packageMember(stdinfluxql.PackagePath, "seriesKey"))
+	expr = pipeCall(expr, &ast.Identifier{Name: "group"})
+	expr = pipeCall(expr, &ast.Identifier{Name: "distinct"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "column"},
+		Value: &ast.StringLiteral{Value: "key"},
+	})
+	expr = sortBy(expr, execute.DefaultValueColLabel)
+	expr = limit(expr, stmt.Limit, stmt.Offset)
+	return rename(expr, execute.DefaultValueColLabel, "key"), nil
+}
+
+// showSource reads the series of the sources for a meta query. The series are
+// filtered by the measurements of the sources and by the condition.
+func (t *transpilerState) showSource(database string, sources influxql.Sources, cond influxql.Expr) (ast.Expression, error) {
+	// While the sources of a meta query are measurements, they do not contain the database
+	// and we do not factor in retention policies. So we are always going to use the default
+	// retention policy when evaluating which bucket we are querying.
+	if database == "" {
+		if t.config.DefaultDatabase == "" {
+			return nil, errDatabaseNameRequired
+		}
+		database = t.config.DefaultDatabase
+	}
+
+	expr, err := t.from(&influxql.Measurement{Database: database})
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
+	// urgent to implement this functionality so we can use the default range.
+	expr = pipeCall(expr, &ast.Identifier{Name: "range"}, &ast.Property{
+		Key: &ast.Identifier{Name: "start"},
+		Value: &ast.DurationLiteral{
+			Values: []ast.Duration{{
+				Magnitude: -1,
+				Unit:      "h",
+			}},
+		},
+	})
+
+	filterExpr := measurementFilterExpr(sources)
+	if cond != nil {
+		condExpr, err := t.metaCondition(cond)
+		if err != nil {
+			return nil, err
+		}
+		if filterExpr == nil {
+			filterExpr = condExpr
+		} else if condExpr != nil {
+			filterExpr = &ast.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left:     filterExpr,
+				Right:    condExpr,
+			}
+		}
+	}
+	if filterExpr == nil {
+		return expr, nil
+	}
+	return pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: filterExpr,
+		},
+	}), nil
+}
+
+// measurementFilterExpr creates the expression that matches any of the
+// measurements in the sources. It returns nil if there are no sources.
+func measurementFilterExpr(sources influxql.Sources) ast.Expression {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	match := func(source influxql.Source) ast.Expression {
+		mm := source.(*influxql.Measurement)
+		if mm.Regex != nil {
+			return &ast.BinaryExpression{
+				Operator: ast.RegexpMatchOperator,
+				Left:     rowColumn("_measurement"),
+				Right:    &ast.RegexpLiteral{Value: mm.Regex.Val},
+			}
+		}
+		return &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left:     rowColumn("_measurement"),
+			Right:    &ast.StringLiteral{Value: mm.Name},
+		}
+	}
+
+	expr := match(sources[len(sources)-1])
+	for i := len(sources) - 2; i >= 0; i-- {
+		expr = &ast.LogicalExpression{
+			Operator: ast.OrOperator,
+			Left:     match(sources[i]),
+			Right:    expr,
+		}
+	}
+	return expr
+}
+
+// metaCondition creates the expression that evaluates the condition of a meta
+// query. The condition of a meta query only refers to tags and to the name of
+// the measurement. The time range within the condition is ignored like in 1.x.
+func (t *transpilerState) metaCondition(cond influxql.Expr) (ast.Expression, error) {
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	cond, _, err := influxql.ConditionExpr(cond, &valuer)
+	if err != nil {
+		return nil, err
+	} else if cond == nil {
+		return nil, nil
+	}
+	return t.mapField(cond, metaCursor{})
+}
+
+// metaCursor is a pseudo-cursor that reads every variable reference as a tag.
+// The _name variable refers to the measurement.
+type metaCursor struct{}
+
+func (metaCursor) Expr() ast.Expression  { return nil }
+func (metaCursor) Keys() []influxql.Expr { return nil }
+
+func (metaCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+	if ref.Val == "_name" {
+		return "_measurement", true
+	}
+	return ref.Val, true
+}
+
+// singleMeasurement returns the name of the measurement when the sources are
+// a single measurement that is not a regex.
+func singleMeasurement(sources influxql.Sources) (string, bool) {
+	if len(sources) != 1 {
+		return "", false
+	}
+	mm, ok := sources[0].(*influxql.Measurement)
+	if !ok || mm.Regex != nil {
+		return "", false
+	}
+	return mm.Name, true
+}
+
+// setMeasurement sets the _measurement column to the name and groups the
+// tables by it, for the schema of a single measurement.
+func setMeasurement(expr ast.Expression, name string) ast.Expression {
+	expr = pipeCall(expr, &ast.Identifier{Name: "set"},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "key"},
+			Value: &ast.StringLiteral{Value: "_measurement"},
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "value"},
+			Value: &ast.StringLiteral{Value: name},
+		},
+	)
+	return group(expr, "_measurement")
+}
+
+// notAnyOf creates the expression that is true when the value of the
+// expression is none of the strings.
+func notAnyOf(expr ast.Expression, values ...string) ast.Expression {
+	var cond ast.Expression
+	for _, v := range values {
+		ne := &ast.BinaryExpression{
+			Operator: ast.NotEqualOperator,
+			Left:     expr,
+			Right:    &ast.StringLiteral{Value: v},
+		}
+		if cond == nil {
+			cond = ne
+			continue
+		}
+		cond = &ast.LogicalExpression{
+			Operator: ast.AndOperator,
+			Left:     cond,
+			Right:    ne,
+		}
+	}
+	return cond
+}
+
+// group groups the tables by the columns.
+func group(expr ast.Expression, columns ...string) ast.Expression {
+	return pipeCall(expr, &ast.Identifier{Name: "group"},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray(columns...),
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "mode"},
+			Value: &ast.StringLiteral{Value: "by"},
+		},
+	)
+}
+
+// rename renames the column from to the name to.
+func rename(expr ast.Expression, from, to string) ast.Expression {
+	return pipeCall(expr, &ast.Identifier{Name: "rename"}, &ast.Property{
+		Key: &ast.Identifier{Name: "columns"},
+		Value: &ast.ObjectExpression{
+			Properties: []*ast.Property{{
+				Key:   columnKey(from),
+				Value: &ast.StringLiteral{Value: to},
+			}},
+		},
+	})
+}
+
+// sortBy sorts the rows of each table by the column.
+func sortBy(expr ast.Expression, column string) ast.Expression {
+	return pipeCall(expr, &ast.Identifier{Name: "sort"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray(column),
+	})
+}
+
+// limit limits the number of rows in each table with the LIMIT and OFFSET of
+// a statement. A limit of zero means the number of rows is not limited.
+func limit(expr ast.Expression, n, offset int) ast.Expression {
+	if n <= 0 && offset <= 0 {
+		return expr
+	}
+	if n <= 0 {
+		n = int(^uint(0) >> 1)
+	}
+	properties := []*ast.Property{{
+		Key:   &ast.Identifier{Name: "n"},
+		Value: &ast.IntegerLiteral{Value: int64(n)},
+	}}
+	if offset > 0 {
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: "offset"},
+			Value: &ast.IntegerLiteral{Value: int64(offset)},
+		})
+	}
+	return pipeCall(expr, &ast.Identifier{Name: "limit"}, properties...)
+}
@@ -10,6 +10,8 @@ var aggregateFuncNames = []string{
 	"count",
 	"mean",
 	"sum",
+	"spread",
+	"stddev",
 }
 
 func AggregateTest(fn func(name string) (stmt, want string)) Fixture {
@@ -37,7 +39,7 @@ func init() {
 	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
 	|> group(columns: ["_measurement", "_start"], mode: "by")
 	|> ` + name + `()
-	|> duplicate(column: "_start", as: "_time")
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
 	|> map(fn: (r) => ({_time: r._time, ` + name + `: r._value}))
 	|> yield(name: "0")
 `
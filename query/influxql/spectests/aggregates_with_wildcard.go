@@ -0,0 +1,23 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT mean(*) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> group(columns: ["_measurement", "_start", "_field"], mode: "by")
+	|> mean()
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> map(fn: (r) => ({_time: r._time, _field: "mean_" + r._field, _value: r._value}))
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time", "_field"])
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> yield(name: "0")
+`,
+		),
+	)
+}
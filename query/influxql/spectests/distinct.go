@@ -0,0 +1,52 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT distinct(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> distinct(column: "_value")
+	|> sort(columns: ["_value"])
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> map(fn: (r) => ({_time: r._time, distinct: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT count(distinct(value)) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> distinct(column: "_value")
+	|> sort(columns: ["_value"])
+	|> count()
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> map(fn: (r) => ({_time: r._time, count: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT DISTINCT value FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> distinct(column: "_value")
+	|> sort(columns: ["_value"])
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> map(fn: (r) => ({_time: r._time, distinct: r._value}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
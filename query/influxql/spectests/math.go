@@ -0,0 +1,36 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT abs(value) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> influxql.map(fn: (r) => ({_time: r._time, abs: influxql.abs(x: r._value)}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT sqrt(mean(value)) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> mean()
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> influxql.map(fn: (r) => ({_time: r._time, sqrt: influxql.sqrt(x: r._value)}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,65 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT abs(*) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> influxql.map(fn: (r) => ({_time: r._time, _field: "abs_" + r._field, _value: influxql.abs(x: r._value)}))
+	|> group(columns: ["_time", "_value", "_field"], mode: "except")
+	|> sort(columns: ["_time", "_field"])
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> keep(fn: (column) => column == "_measurement" or column == "_time" or column =~ /^abs_/)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT floor(mean(*)) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> group(columns: ["_measurement", "_start", "_field"], mode: "by")
+	|> mean()
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> influxql.map(fn: (r) => ({_time: r._time, _field: "floor_" + r._field, _value: influxql.floor(x: r._value)}))
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time", "_field"])
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT *, abs(usage_user) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+t0 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+t1 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "usage_user")
+	|> influxql.map(fn: (r) => ({_time: r._time, _field: "abs", _value: influxql.abs(x: r._value)}))
+union(tables: [t0, t1])
+	|> group(columns: ["_time", "_value", "_field"], mode: "except")
+	|> sort(columns: ["_time", "_field"])
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> drop(fn: (column) => column == "_start" or column == "_stop")
+	|> yield(name: "0")
+`,
+		),
+	)
+}
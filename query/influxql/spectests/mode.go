@@ -0,0 +1,22 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT mode(value) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> influxql.mode(column: "_value")
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> map(fn: (r) => ({_time: r._time, mode: r._value}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,36 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT max(/^usage/), sum(value) AS total FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m), host`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field =~ /^usage/)
+	|> group(columns: ["_measurement", "_start", "_field", "host"], mode: "by")
+	|> window(every: 1m)
+	|> max()
+	|> drop(columns: ["_time"])
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, _field: "max_" + r._field, _value: r._value}))
+t1 = from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> window(every: 1m)
+	|> sum()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, _field: "total", _value: r._value}))
+union(tables: [t0, t1])
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> sort(columns: ["_time", "_field"])
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> yield(name: "0")
+`,
+		),
+	)
+}
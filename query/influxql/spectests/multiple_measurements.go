@@ -0,0 +1,22 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT value FROM db0..cpu, db0..mem`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+t1 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "mem" and r._field == "value")
+union(tables: [t0, t1])
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> map(fn: (r) => ({_time: r._time, value: r._value}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
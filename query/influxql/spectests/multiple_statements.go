@@ -11,7 +11,7 @@ from(bucketID: "")
 	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
 	|> group(columns: ["_measurement", "_start"], mode: "by")
 	|> mean()
-	|> duplicate(column: "_start", as: "_time")
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
 	|> map(fn: (r) => ({_time: r._time, mean: r._value}))
 	|> yield(name: "0")
 from(bucketID: "")
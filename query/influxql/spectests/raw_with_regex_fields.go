@@ -0,0 +1,20 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT /^usage/ FROM db0..cpu WHERE host = 'server01' GROUP BY region`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and (r._field =~ /^usage/ or r._field == "host"))
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> filter(fn: (r) => r["host"] == "server01")
+	|> group(columns: ["_measurement", "_start", "region"], mode: "by")
+	|> keep(fn: (column) => column == "_measurement" or column == "_time" or column == "region" or column =~ /^usage/)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
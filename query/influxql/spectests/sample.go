@@ -0,0 +1,22 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT sample(value, 2) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time"])
+	|> influxql.sample(n: 2, column: "_value")
+	|> map(fn: (r) => ({_time: r._time, sample: r._value}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,39 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT max(usage_user), usage_system FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and (r._field == "usage_user" or r._field == "usage_system"))
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> duplicate(column: "usage_user", as: "_value")
+	|> filter(fn: (r) => r._value == r._value)
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> max()
+	|> map(fn: (r) => ({_time: r._time, max: r._value, usage_system: r["usage_system"]}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT max(usage_user), * FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> duplicate(column: "usage_user", as: "_value")
+	|> filter(fn: (r) => r._value == r._value)
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> max()
+	|> drop(columns: ["_start", "_stop"])
+	|> rename(columns: {_value: "max"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
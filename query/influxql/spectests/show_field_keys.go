@@ -0,0 +1,25 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW FIELD KEYS ON "db0" FROM "cpu", "mem"`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu" or r._measurement == "mem")
+	|> influxql.fieldType()
+	|> group(columns: ["_measurement", "_field", "fieldType"], mode: "by")
+	|> limit(n: 1)
+	|> group(columns: ["_measurement"], mode: "by")
+	|> keep(columns: ["_measurement", "_field", "fieldType"])
+	|> rename(columns: {_field: "fieldKey"})
+	|> sort(columns: ["fieldKey"])
+	|> yield(name: "0")
+`,
+		),
+	)
+}
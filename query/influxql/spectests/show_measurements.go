@@ -0,0 +1,44 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW MEASUREMENTS ON "db0"`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> keep(columns: ["_measurement"])
+	|> group(columns: ["_measurement"], mode: "by")
+	|> distinct(column: "_measurement")
+	|> group()
+	|> keep(columns: ["_value"])
+	|> rename(columns: {_value: "name"})
+	|> sort(columns: ["name"])
+	|> set(key: "_measurement", value: "measurements")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SHOW MEASUREMENTS ON "db0" WITH MEASUREMENT =~ /c.*/ WHERE "host" = 'server01' LIMIT 10`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement =~ /c.*/ and r["host"] == "server01")
+	|> keep(columns: ["_measurement"])
+	|> group(columns: ["_measurement"], mode: "by")
+	|> distinct(column: "_measurement")
+	|> group()
+	|> keep(columns: ["_value"])
+	|> rename(columns: {_value: "name"})
+	|> sort(columns: ["name"])
+	|> limit(n: 10)
+	|> set(key: "_measurement", value: "measurements")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> yield(name: "0")
+`,
+		),
+	)
+}
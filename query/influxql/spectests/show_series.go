@@ -0,0 +1,24 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW SERIES ON "db0" WHERE "region" = 'uswest' LIMIT 5 OFFSET 10`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r["region"] == "uswest")
+	|> influxql.seriesKey()
+	|> group()
+	|> distinct(column: "key")
+	|> sort(columns: ["_value"])
+	|> limit(n: 5, offset: 10)
+	|> rename(columns: {_value: "key"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,25 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG KEYS ON "db0" FROM "cpu"`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> keys()
+	|> keep(columns: ["_value"])
+	|> distinct()
+	|> filter(fn: (r) => r._value != "_start" and r._value != "_stop" and r._value != "_measurement" and r._value != "_field")
+	|> set(key: "_measurement", value: "cpu")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> distinct()
+	|> sort(columns: ["_value"])
+	|> rename(columns: {_value: "tagKey"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,23 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG KEYS ON "db0" FROM "cpu", "mem"`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu" or r._measurement == "mem")
+	|> influxql.tags()
+	|> group(columns: ["_measurement"], mode: "by")
+	|> distinct(column: "_key")
+	|> sort(columns: ["_value"])
+	|> rename(columns: {_value: "tagKey"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,25 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG VALUES ON "db0" FROM "cpu" WITH KEY = "host" LIMIT 2`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> group(columns: ["host"], mode: "by")
+	|> distinct(column: "host")
+	|> keep(columns: ["_value"])
+	|> filter(fn: (r) => r._value != "")
+	|> map(fn: (r) => ({key: "host", value: r._value}))
+	|> set(key: "_measurement", value: "cpu")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> sort(columns: ["value"])
+	|> limit(n: 2)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,25 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG VALUES ON "db0" WITH KEY =~ /ho.*/ WHERE "region" = 'uswest' LIMIT 2`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r["region"] == "uswest")
+	|> influxql.tags()
+	|> filter(fn: (r) => r._key =~ /ho.*/)
+	|> group(columns: ["_measurement", "_key"], mode: "by")
+	|> distinct()
+	|> group(columns: ["_measurement"], mode: "by")
+	|> rename(columns: {_key: "key", _value: "value"})
+	|> limit(n: 2)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
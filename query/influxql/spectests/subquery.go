@@ -0,0 +1,26 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT max(mean) FROM (SELECT mean(value) FROM db0..cpu GROUP BY host)`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> mean()
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}))
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+t0
+	|> duplicate(column: "mean", as: "_value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> max()
+	|> map(fn: (r) => ({_time: r._time, max: r._value}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
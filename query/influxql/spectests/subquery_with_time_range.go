@@ -0,0 +1,32 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT mean(max) FROM (SELECT max(value) FROM db0..cpu GROUP BY host) WHERE time >= now() - 1h GROUP BY time(10m)`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 2010-09-15T08:00:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> window(every: 10m)
+	|> max()
+	|> drop(columns: ["_time"])
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, max: r._value}))
+	|> range(start: 2010-09-15T08:00:00Z, stop: 2010-09-15T09:00:00Z)
+t0
+	|> duplicate(column: "max", as: "_value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 10m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
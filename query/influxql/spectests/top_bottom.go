@@ -0,0 +1,37 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT top(value, 2) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> top(n: 2, columns: ["_value"])
+	|> sort(columns: ["_time"])
+	|> map(fn: (r) => ({_time: r._time, top: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT bottom(value, host, 2) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> min(column: "_value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> bottom(n: 2, columns: ["_value"])
+	|> sort(columns: ["_time"])
+	|> map(fn: (r) => ({_time: r._time, bottom: r._value, host: r["host"]}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,155 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT derivative(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time"])
+	|> derivative(unit: 1s, nonNegative: false, columns: ["_value"])
+	|> map(fn: (r) => ({_time: r._time, derivative: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT non_negative_derivative(mean(value)) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m)`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> sort(columns: ["_time"])
+	|> derivative(unit: 1m, nonNegative: false, columns: ["_value"])
+	|> filter(fn: (r) => r._value >= 0.0)
+	|> map(fn: (r) => ({_time: r._time, non_negative_derivative: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT difference(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time"])
+	|> difference(columns: ["_value"])
+	|> map(fn: (r) => ({_time: r._time, difference: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT cumulative_sum(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time"])
+	|> cumulativeSum(columns: ["_value"])
+	|> map(fn: (r) => ({_time: r._time, cumulative_sum: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT moving_average(value, 3) FROM db0..cpu`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time"])
+	|> influxql.movingAverage(n: 3, column: "_value")
+	|> map(fn: (r) => ({_time: r._time, moving_average: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT elapsed(value, 1s) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time"])
+	|> map(fn: (r) => ({_time: r._time, _value: int(v: r._time)}))
+	|> difference(columns: ["_value"])
+	|> map(fn: (r) => ({_time: r._time, _value: r._value / 1000000000}))
+	|> map(fn: (r) => ({_time: r._time, elapsed: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT integral(value, 1m) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> sort(columns: ["_time"])
+	|> integral(unit: 1m, columns: ["_value"])
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, _value: r._value}))
+	|> map(fn: (r) => ({_time: r._time, integral: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT holt_winters(mean(value), 5, 4) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m)`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> sort(columns: ["_time"])
+	|> influxql.holtWinters(n: 5, seasonality: 4, interval: 1m, withFit: false, column: "_value")
+	|> map(fn: (r) => ({_time: r._time, holt_winters: r._value}))
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT holt_winters_with_fit(max(value), 3, 0) FROM db0..cpu WHERE time >= now() - 1h GROUP BY time(10m)`,
+			`package main
+
+import "influxdata/influxdb/influxql"
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:00:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 10m)
+	|> max()
+	|> drop(columns: ["_time"])
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> sort(columns: ["_time"])
+	|> influxql.holtWinters(n: 3, seasonality: 0, interval: 10m, withFit: true, column: "_value")
+	|> map(fn: (r) => ({_time: r._time, holt_winters_with_fit: r._value}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
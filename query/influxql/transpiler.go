@@ -6,10 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	platform "github.com/influxdata/influxdb"
+	stdinfluxql "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxql"
 )
 
@@ -57,6 +60,26 @@ type transpilerState struct {
 	file           *ast.File
 	assignments    map[string]ast.Expression
 	dbrpMappingSvc platform.DBRPMappingService
+
+	// timeRange is the time range of the query that contains a subquery.
+	timeRange influxql.TimeRange
+
+	// unpivoted is set when the results of a subquery are read with a wildcard.
+	// Each of the columns is then a table with the name of the column as the
+	// field key, as the columns matched by the wildcard are not known.
+	unpivoted bool
+
+	// subqueries are the variables that the results of each subquery are
+	// assigned to, so the subquery is only transpiled once for all of the
+	// fields that read it.
+	subqueries map[subQueryKey]*ast.Identifier
+}
+
+// subQueryKey identifies the results of a subquery. The results of a subquery
+// are different when they are read with a wildcard.
+type subQueryKey struct {
+	sq        *influxql.SubQuery
+	unpivoted bool
 }
 
 func newTranspilerState(dbrpMappingSvc platform.DBRPMappingService, config *Config) *transpilerState {
@@ -70,6 +93,7 @@ func newTranspilerState(dbrpMappingSvc platform.DBRPMappingService, config *Conf
 		},
 		assignments:    make(map[string]ast.Expression),
 		dbrpMappingSvc: dbrpMappingSvc,
+		subqueries:     make(map[subQueryKey]*ast.Identifier),
 	}
 	if config != nil {
 		state.config = *config
@@ -81,6 +105,20 @@ func newTranspilerState(dbrpMappingSvc platform.DBRPMappingService, config *Conf
 	return state
 }
 
+// subquery returns the state for transpiling a subquery within the time range
+// of the current statement. The subquery shares the file, assignments and
+// subqueries of the current statement.
+func (t *transpilerState) subquery(tr influxql.TimeRange) *transpilerState {
+	return &transpilerState{
+		config:         t.config,
+		file:           t.file,
+		assignments:    t.assignments,
+		dbrpMappingSvc: t.dbrpMappingSvc,
+		timeRange:      tr,
+		subqueries:     t.subqueries,
+	}
+}
+
 func (t *transpilerState) Transpile(ctx context.Context, id int, s influxql.Statement) error {
 	expr, err := t.transpile(ctx, s)
 	if err != nil {
@@ -119,6 +157,14 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 		return cur.Expr(), nil
 	case *influxql.ShowTagValuesStatement:
 		return t.transpileShowTagValues(ctx, stmt)
+	case *influxql.ShowTagKeysStatement:
+		return t.transpileShowTagKeys(ctx, stmt)
+	case *influxql.ShowMeasurementsStatement:
+		return t.transpileShowMeasurements(ctx, stmt)
+	case *influxql.ShowFieldKeysStatement:
+		return t.transpileShowFieldKeys(ctx, stmt)
+	case *influxql.ShowSeriesStatement:
+		return t.transpileShowSeries(ctx, stmt)
 	case *influxql.ShowDatabasesStatement:
 		return t.transpileShowDatabases(ctx, stmt)
 	case *influxql.ShowRetentionPoliciesStatement:
@@ -129,265 +175,137 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 }
 
 func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *influxql.ShowTagValuesStatement) (ast.Expression, error) {
-	// While the ShowTagValuesStatement contains a sources section and those sources are measurements, they do
-	// not actually contain the database and we do not factor in retention policies. So we are always going to use
-	// the default retention policy when evaluating which bucket we are querying and we do not have to consult
-	// the sources in the statement.
-	if stmt.Database == "" {
-		if t.config.DefaultDatabase == "" {
-			return nil, errDatabaseNameRequired
-		}
-		stmt.Database = t.config.DefaultDatabase
-	}
-
-	expr, err := t.from(&influxql.Measurement{Database: stmt.Database})
+	expr, err := t.showSource(stmt.Database, stmt.Sources, stmt.Condition)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
-	// urgent to implement this functionality so we can use the default range.
-	expr = &ast.PipeExpression{
-		Argument: expr,
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{
-				Name: "range",
-			},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "start",
-							},
-							Value: &ast.DurationLiteral{
-								Values: []ast.Duration{{
-									Magnitude: -1,
-									Unit:      "h",
-								}},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// If we have a list of sources, look through it and add each of the measurement names.
-	measurementNames := make([]string, 0, len(stmt.Sources))
-	for _, source := range stmt.Sources {
-		mm := source.(*influxql.Measurement)
-		measurementNames = append(measurementNames, mm.Name)
-	}
-
-	if len(measurementNames) > 0 {
-		var filterExpr ast.Expression = &ast.BinaryExpression{
-			Operator: ast.EqualOperator,
-			Left: &ast.MemberExpression{
-				Object:   &ast.Identifier{Name: "r"},
-				Property: &ast.Identifier{Name: "_measurement"},
-			},
-			Right: &ast.StringLiteral{
-				Value: measurementNames[len(measurementNames)-1],
-			},
-		}
-		for i := len(measurementNames) - 2; i >= 0; i-- {
-			filterExpr = &ast.LogicalExpression{
-				Operator: ast.OrOperator,
-				Left: &ast.BinaryExpression{
-					Operator: ast.EqualOperator,
-					Left: &ast.MemberExpression{
-						Object:   &ast.Identifier{Name: "r"},
-						Property: &ast.Identifier{Name: "_measurement"},
-					},
-					Right: &ast.StringLiteral{
-						Value: measurementNames[i],
-					},
-				},
-				Right: filterExpr,
-			}
-		}
-		expr = &ast.PipeExpression{
-			Argument: expr,
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "filter",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{Name: "fn"},
-								Value: &ast.FunctionExpression{
-									Params: []*ast.Property{
-										{
-											Key: &ast.Identifier{Name: "r"},
-										},
-									},
-									Body: filterExpr,
-								},
-							},
-						},
-					},
-				},
-			},
+	// The values of a single tag key of a single measurement are found with
+	// group, distinct and keep, which storage answers from its index.
+	if name, ok := singleMeasurement(stmt.Sources); ok && stmt.Op == influxql.EQ {
+		if str, ok := stmt.TagKeyExpr.(*influxql.StringLiteral); ok {
+			return showTagValuesOfKey(expr, name, str.Val, stmt), nil
 		}
 	}
 
-	// TODO(jsternberg): Add the condition filter for the where clause.
-
-	// Create the key values op spec from the
-	var keyColumns []ast.Expression
-	switch expr := stmt.TagKeyExpr.(type) {
+	// Create the key values op spec from the tag keys. The tag keys are only known
+	// when they are listed so the other operands read all of the tags and filter them.
+	switch lit := stmt.TagKeyExpr.(type) {
 	case *influxql.ListLiteral:
-		keyColumns = make([]ast.Expression, 0, len(expr.Vals))
-		for _, name := range expr.Vals {
-			keyColumns = append(keyColumns, &ast.StringLiteral{
-				Value: name,
+		expr = pipeCall(expr, &ast.Identifier{Name: "keyValues"}, &ast.Property{
+			Key:   &ast.Identifier{Name: "keyColumns"},
+			Value: stringArray(lit.Vals...),
+		})
+	case *influxql.StringLiteral, *influxql.RegexLiteral:
+		if str, ok := lit.(*influxql.StringLiteral); ok && stmt.Op == influxql.EQ {
+			expr = pipeCall(expr, &ast.Identifier{Name: "keyValues"}, &ast.Property{
+				Key:   &ast.Identifier{Name: "keyColumns"},
+				Value: stringArray(str.Val),
 			})
+			break
 		}
-	case *influxql.StringLiteral:
+
+		var op ast.OperatorKind
 		switch stmt.Op {
-		case influxql.EQ:
-			keyColumns = []ast.Expression{
-				&ast.StringLiteral{
-					Value: expr.Val,
-				},
-			}
-		case influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
-			return nil, fmt.Errorf("unimplemented: tag key operand: %s", stmt.Op)
+		case influxql.NEQ:
+			op = ast.NotEqualOperator
+		case influxql.EQREGEX:
+			op = ast.RegexpMatchOperator
+		case influxql.NEQREGEX:
+			op = ast.NotRegexpMatchOperator
 		default:
 			return nil, fmt.Errorf("unsupported operand: %s", stmt.Op)
 		}
-	default:
-		return nil, fmt.Errorf("unsupported literal type: %T", expr)
-	}
-	expr = &ast.PipeExpression{
-		Argument: expr,
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{
-				Name: "keyValues",
-			},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "keyColumns",
-							},
-							Value: &ast.ArrayExpression{
-								Elements: keyColumns,
-							},
-						},
-					},
+		key, err := t.mapField(lit, metaCursor{})
+		if err != nil {
+			return nil, err
+		}
+		expr = pipeCall(expr, t.packageMember(stdinfluxql.PackagePath, "tags"))
+		expr = pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+			Key: &ast.Identifier{Name: "fn"},
+			Value: &ast.FunctionExpression{
+				Params: []*ast.Property{{
+					Key: &ast.Identifier{Name: "r"},
+				}},
+				Body: &ast.BinaryExpression{
+					Operator: op,
+					Left:     rowColumn("_key"),
+					Right:    key,
 				},
 			},
-		},
+		})
+	default:
+		return nil, fmt.Errorf("unsupported literal type: %T", lit)
 	}
 
 	// Group by the measurement and key, find distinct values, then group by the measurement
 	// to join all of the different keys together. Finish by renaming the columns. This is static.
-	return &ast.PipeExpression{
-		Argument: &ast.PipeExpression{
-			Argument: &ast.PipeExpression{
-				Argument: &ast.PipeExpression{
-					Argument: expr,
-					Call: &ast.CallExpression{
-						Callee: &ast.Identifier{Name: "group"},
-						Arguments: []ast.Expression{
-							&ast.ObjectExpression{
-								Properties: []*ast.Property{
-									{
-										Key: &ast.Identifier{
-											Name: "columns",
-										},
-										Value: &ast.ArrayExpression{
-											Elements: []ast.Expression{
-												&ast.StringLiteral{Value: "_measurement"},
-												&ast.StringLiteral{Value: "_key"},
-											},
-										},
-									},
-									{
-										Key: &ast.Identifier{
-											Name: "mode",
-										},
-										Value: &ast.StringLiteral{
-											Value: "by",
-										},
-									},
-								},
-							},
-						},
-					},
+	expr = group(expr, "_measurement", "_key")
+	expr = pipeCall(expr, &ast.Identifier{Name: "distinct"})
+	expr = group(expr, "_measurement")
+	expr = pipeCall(expr, &ast.Identifier{Name: "rename"}, &ast.Property{
+		Key: &ast.Identifier{Name: "columns"},
+		Value: &ast.ObjectExpression{
+			Properties: []*ast.Property{
+				{
+					Key:   &ast.Identifier{Name: "_key"},
+					Value: &ast.StringLiteral{Value: "key"},
 				},
-				Call: &ast.CallExpression{
-					Callee: &ast.Identifier{Name: "distinct"},
-				},
-			},
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{Name: "group"},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{
-									Name: "columns",
-								},
-								Value: &ast.ArrayExpression{
-									Elements: []ast.Expression{
-										&ast.StringLiteral{Value: "_measurement"},
-									},
-								},
-							},
-							{
-								Key: &ast.Identifier{
-									Name: "mode",
-								},
-								Value: &ast.StringLiteral{
-									Value: "by",
-								},
-							},
-						},
-					},
+				{
+					Key:   &ast.Identifier{Name: "_value"},
+					Value: &ast.StringLiteral{Value: "value"},
 				},
 			},
 		},
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{Name: "rename"},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "columns",
-							},
-							Value: &ast.ObjectExpression{
-								Properties: []*ast.Property{
-									{
-										Key: &ast.Identifier{
-											Name: "_key",
-										},
-										Value: &ast.StringLiteral{
-											Value: "key",
-										},
-									},
-									{
-										Key: &ast.Identifier{
-											Name: "_value",
-										},
-										Value: &ast.StringLiteral{
-											Value: "value",
-										},
-									},
-								},
-							},
-						},
+	})
+	return limit(expr, stmt.Limit, stmt.Offset), nil
+}
+
+// showTagValuesOfKey finds the distinct values of the tag key of the series
+// of the measurement that the expression reads. Series without the tag key
+// have an empty value, which is not a valid tag value, and are skipped.
+func showTagValuesOfKey(expr ast.Expression, name, key string, stmt *influxql.ShowTagValuesStatement) ast.Expression {
+	expr = group(expr, key)
+	expr = pipeCall(expr, &ast.Identifier{Name: "distinct"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "column"},
+		Value: &ast.StringLiteral{Value: key},
+	})
+	expr = pipeCall(expr, &ast.Identifier{Name: "keep"}, &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray(execute.DefaultValueColLabel),
+	})
+	expr = pipeCall(expr, &ast.Identifier{Name: "filter"}, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: notAnyOf(rowColumn(execute.DefaultValueColLabel), ""),
+		},
+	})
+	expr = pipeCall(expr, &ast.Identifier{Name: "map"}, &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.ObjectExpression{
+				Properties: []*ast.Property{
+					{
+						Key:   &ast.Identifier{Name: "key"},
+						Value: &ast.StringLiteral{Value: key},
+					},
+					{
+						Key:   &ast.Identifier{Name: "value"},
+						Value: rowColumn(execute.DefaultValueColLabel),
 					},
 				},
 			},
 		},
-	}, nil
+	})
+	expr = setMeasurement(expr, name)
+	expr = sortBy(expr, "value")
+	return limit(expr, stmt.Limit, stmt.Offset)
 }
 
 func (t *transpilerState) transpileShowDatabases(ctx context.Context, stmt *influxql.ShowDatabasesStatement) (ast.Expression, error) {
@@ -608,6 +526,24 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 	t.stmt = stmt.Clone()
 	t.stmt.OmitTime = true
 
+	// A distinct expression is the same as calling the distinct function.
+	for _, f := range t.stmt.Fields {
+		f.Expr = influxql.RewriteExpr(f.Expr, func(expr influxql.Expr) influxql.Expr {
+			if d, ok := expr.(*influxql.Distinct); ok {
+				return d.NewCall()
+			}
+			return expr
+		})
+	}
+
+	// The wildcards of a statement that only reads subqueries can be expanded
+	// into the columns that the subqueries select.
+	stmt, err := expandSubQueryWildcards(t.stmt)
+	if err != nil {
+		return nil, err
+	}
+	t.stmt = stmt
+
 	groups, err := identifyGroups(t.stmt)
 	if err != nil {
 		return nil, err
@@ -615,6 +551,17 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 		return nil, errors.New("at least 1 non-time field must be queried")
 	}
 
+	// The expressions of the fields are evaluated on each of the fields before
+	// they are pivoted when any of the fields are matched by a wildcard. The
+	// fields of a subquery that are read with a wildcard are not pivoted.
+	if len(groups) == 1 && groups[0].call == nil && (t.hasWildcardExpr() || t.unpivoted) {
+		cur, err := t.pivotRawFields(groups[0])
+		if err != nil {
+			return nil, err
+		}
+		return t.sortAndLimit(cur), nil
+	}
+
 	cursors := make([]cursor, 0, len(groups))
 	for _, gr := range groups {
 		cur, err := gr.createCursor(t)
@@ -624,16 +571,68 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 		cursors = append(cursors, cur)
 	}
 
-	// Join the cursors together on the measurement name.
-	// TODO(jsternberg): This needs to join on all remaining group keys.
-	cur := Join(t, cursors, []string{"_time", "_measurement"})
+	// The fields matched by a wildcard are not known until the query is executed
+	// so they cannot be joined and mapped. Pivot the fields into columns instead.
+	// The selected fields are also kept without a map when they have all been
+	// pivoted as the map skips the rows where any of the fields are null. The
+	// tags are not known when grouping by all of them, so the functions cannot
+	// be joined on them and are pivoted too. A join only has two parents so
+	// more than two functions are also pivoted. The fields of a subquery that
+	// are read with a wildcard are left for the query to pivot.
+	var cur cursor
+	for _, gr := range groups {
+		if gr.hasWildcard() || (len(groups) == 1 && gr.pivoted() && t.selectsOnlyFields()) ||
+			(len(groups) > 1 && t.groupsByAllTags()) || len(groups) > 2 || t.unpivoted {
+			if cur, err = t.pivotFields(groups, cursors); err != nil {
+				return nil, err
+			}
+			return t.sortAndLimit(cur), nil
+		}
+	}
+
+	// Join the cursors together on the time and the series they were grouped into.
+	cur = Join(t, cursors, append([]string{"_time", "_measurement"}, t.dimensionTags()...))
 
 	// Map each of the fields into another cursor. This evaluates any lingering expressions.
 	cur, err = t.mapFields(cur)
 	if err != nil {
 		return nil, err
 	}
-	return cur, nil
+	return t.sortAndLimit(cur), nil
+}
+
+// selectsOnlyFields returns true if every column of the statement is a field
+// or a tag that is selected without an expression.
+func (t *transpilerState) selectsOnlyFields() bool {
+	for _, f := range t.stmt.Fields {
+		if _, ok := f.Expr.(*influxql.VarRef); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sortAndLimit sorts the points of each series in the time order of the
+// statement and applies its LIMIT and OFFSET, when the statement has them.
+func (t *transpilerState) sortAndLimit(cur cursor) cursor {
+	if t.stmt.TimeAscending() && t.stmt.Limit <= 0 && t.stmt.Offset <= 0 {
+		return cur
+	}
+
+	properties := []*ast.Property{{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray(execute.DefaultTimeColLabel),
+	}}
+	if !t.stmt.TimeAscending() {
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: "desc"},
+			Value: &ast.BooleanLiteral{Value: true},
+		})
+	}
+	expr := pipeCall(cur.Expr(), &ast.Identifier{Name: "sort"}, properties...)
+	return &mapCursor{
+		expr: limit(expr, t.stmt.Limit, t.stmt.Offset),
+	}
 }
 
 func (t *transpilerState) mapType(ref *influxql.VarRef) influxql.DataType {
@@ -665,6 +664,9 @@ func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error)
 	}
 	defaultRP := rp == ""
 	filter.Default = &defaultRP
+	if t.config.OrganizationID.Valid() {
+		filter.OrganizationID = &t.config.OrganizationID
+	}
 	mapping, err := t.dbrpMappingSvc.Find(context.TODO(), filter)
 	if err != nil {
 		return nil, err
@@ -691,6 +693,28 @@ func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error)
 	}, nil
 }
 
+// packageMember returns the expression that refers to the member of the package
+// at the path. The package is imported into the file the first time it is used.
+func (t *transpilerState) packageMember(path, name string) ast.Expression {
+	pkg := path[strings.LastIndex(path, "/")+1:]
+	imported := false
+	for _, imp := range t.file.Imports {
+		if imp.Path.Value == path {
+			imported = true
+			break
+		}
+	}
+	if !imported {
+		t.file.Imports = append(t.file.Imports, &ast.ImportDeclaration{
+			Path: &ast.StringLiteral{Value: path},
+		})
+	}
+	return &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: pkg},
+		Property: &ast.Identifier{Name: name},
+	}
+}
+
 func (t *transpilerState) assignment(expr ast.Expression) *ast.Identifier {
 	for i := 0; ; i++ {
 		key := fmt.Sprintf("t%d", i)
@@ -0,0 +1,247 @@
+package promql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/iocounter"
+)
+
+const DialectType = "promql"
+
+// ResultType is the type of the value a PromQL expression evaluates to.
+type ResultType string
+
+const (
+	// VectorResult is a set of series with a single sample each.
+	VectorResult ResultType = "vector"
+	// MatrixResult is a set of series with a range of samples each.
+	MatrixResult ResultType = "matrix"
+)
+
+// Dialect describes the output format of PromQL queries, the Prometheus
+// query API response.
+type Dialect struct {
+	ResultType ResultType // ResultType is the type of the encoded result.
+	TimeColumn string     // TimeColumn holds the timestamp of each sample; defaults to Time.
+	Time       time.Time  // Time is the timestamp of every sample if there is no TimeColumn.
+}
+
+func (d *Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+}
+
+func (d *Dialect) Encoder() flux.MultiResultEncoder {
+	return &MultiResultEncoder{
+		ResultType: d.ResultType,
+		TimeColumn: d.TimeColumn,
+		Time:       d.Time,
+	}
+}
+
+func (d *Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
+
+// Response is the Prometheus query API response.
+type Response struct {
+	Status    string        `json:"status"`
+	Data      *ResponseData `json:"data,omitempty"`
+	ErrorType string        `json:"errorType,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// ResponseData is the result of a successful query.
+type ResponseData struct {
+	ResultType ResultType `json:"resultType"`
+	Result     []*Series  `json:"result"`
+}
+
+// Series is a single series of a vector or matrix result.
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	Value  *Sample           `json:"value,omitempty"`
+	Values []Sample          `json:"values,omitempty"`
+}
+
+// Sample is a single value of a series.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// MarshalJSON encodes the sample as a pair of the timestamp in seconds and
+// the value as a string.
+func (s Sample) MarshalJSON() ([]byte, error) {
+	t := strconv.FormatFloat(float64(s.Time.UnixNano())/float64(time.Second), 'f', -1, 64)
+	v := strconv.FormatFloat(s.Value, 'f', -1, 64)
+	return []byte(fmt.Sprintf("[%s,%q]", t, v)), nil
+}
+
+// Error types of the Prometheus query API.
+const (
+	ErrorTypeBadData   = "bad_data"
+	ErrorTypeExecution = "execution"
+	ErrorTypeInternal  = "internal"
+)
+
+// NewErrorResponse returns a failed query response.
+func NewErrorResponse(errorType string, err error) *Response {
+	return &Response{
+		Status:    "error",
+		ErrorType: errorType,
+		Error:     err.Error(),
+	}
+}
+
+// MultiResultEncoder encodes results as a Prometheus query API response.
+type MultiResultEncoder struct {
+	ResultType ResultType
+	TimeColumn string
+	Time       time.Time
+}
+
+// Encode writes the results as the series of a Prometheus query API response.
+// The _value column is the sample value and the string columns of the group
+// key are the labels of a series, with _measurement as the metric name.
+// The samples of tables with the same labels belong to the same series.
+// Nothing is written if the results fail, so the caller can report the error.
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	defer results.Release()
+
+	var (
+		series = make([]*Series, 0)
+		index  = make(map[string]*Series)
+	)
+	for results.More() {
+		res := results.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			metric := make(map[string]string)
+			for j, c := range tbl.Key().Cols() {
+				if c.Type != flux.TString || c.Label == "_field" {
+					continue
+				}
+				label := c.Label
+				if label == "_measurement" {
+					label = "__name__"
+				}
+				metric[label] = tbl.Key().ValueString(j)
+			}
+
+			key := seriesKey(metric)
+			s, ok := index[key]
+			if !ok {
+				s = &Series{Metric: metric}
+				index[key] = s
+				series = append(series, s)
+			}
+
+			return tbl.Do(func(cr flux.ColReader) error {
+				samples, err := e.samples(cr)
+				if err != nil {
+					return err
+				}
+				s.Values = append(s.Values, samples...)
+				return nil
+			})
+		}); err != nil {
+			return 0, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, s := range series {
+		sort.Slice(s.Values, func(i, j int) bool {
+			return s.Values[i].Time.Before(s.Values[j].Time)
+		})
+		if e.ResultType == VectorResult && len(s.Values) > 0 {
+			// An instant vector has the last sample of each series.
+			s.Value = &s.Values[len(s.Values)-1]
+			s.Values = nil
+		}
+	}
+
+	resp := Response{
+		Status: "success",
+		Data: &ResponseData{
+			ResultType: e.ResultType,
+			Result:     series,
+		},
+	}
+
+	wc := &iocounter.Writer{Writer: w}
+	if err := json.NewEncoder(wc).Encode(resp); err != nil {
+		return wc.Count(), err
+	}
+	return wc.Count(), nil
+}
+
+func (e *MultiResultEncoder) samples(cr flux.ColReader) ([]Sample, error) {
+	valueIdx := execute.ColIdx(execute.DefaultValueColLabel, cr.Cols())
+	if valueIdx < 0 {
+		return nil, fmt.Errorf("no %s column in result", execute.DefaultValueColLabel)
+	}
+	timeIdx := -1
+	if e.TimeColumn != "" {
+		if timeIdx = execute.ColIdx(e.TimeColumn, cr.Cols()); timeIdx < 0 {
+			return nil, fmt.Errorf("no %s column in result", e.TimeColumn)
+		}
+	}
+
+	samples := make([]Sample, 0, cr.Len())
+	for i := 0; i < cr.Len(); i++ {
+		s := Sample{Time: e.Time}
+		if timeIdx >= 0 {
+			ts := cr.Times(timeIdx)
+			if !ts.IsValid(i) {
+				continue
+			}
+			s.Time = execute.Time(ts.Value(i)).Time()
+		}
+
+		switch c := cr.Cols()[valueIdx]; c.Type {
+		case flux.TFloat:
+			vs := cr.Floats(valueIdx)
+			if !vs.IsValid(i) {
+				continue
+			}
+			s.Value = vs.Value(i)
+		case flux.TInt:
+			vs := cr.Ints(valueIdx)
+			if !vs.IsValid(i) {
+				continue
+			}
+			s.Value = float64(vs.Value(i))
+		case flux.TUInt:
+			vs := cr.UInts(valueIdx)
+			if !vs.IsValid(i) {
+				continue
+			}
+			s.Value = float64(vs.Value(i))
+		default:
+			return nil, fmt.Errorf("unsupported value column type: %s", c.Type)
+		}
+		samples = append(samples, s)
+	}
+	return samples, nil
+}
+
+// seriesKey returns a key that is unique to the labels of a series.
+func seriesKey(metric map[string]string) string {
+	labels := make([]string, 0, len(metric))
+	for k, v := range metric {
+		labels = append(labels, k+"="+strconv.Quote(v))
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
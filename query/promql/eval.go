@@ -0,0 +1,289 @@
+package promql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+// DefaultLookbackDelta is how far back in time an instant vector selector
+// looks for the most recent sample of a series.
+const DefaultLookbackDelta = 5 * time.Minute
+
+// ValueFields are the fields that hold the sample value of counter, gauge
+// and untyped metrics written by the scraper, and of remote writes.
+var ValueFields = []string{"counter", "gauge", "value"}
+
+// Evaluation describes when, and against which bucket, an expression is evaluated.
+type Evaluation struct {
+	// Bucket is the name of the bucket the samples are read from.
+	Bucket string
+
+	// Start and End bound a range query. Both are the evaluation time for
+	// an instant query.
+	Start time.Time
+	End   time.Time
+
+	// Step is the resolution of a range query and zero for an instant query.
+	Step time.Duration
+
+	// LookbackDelta defaults to DefaultLookbackDelta.
+	LookbackDelta time.Duration
+}
+
+// Compile builds the flux query specification that evaluates promql and the
+// dialect that encodes its results in the Prometheus query API format.
+//
+// A range query takes the last sample of each series that is at most
+// LookbackDelta older than each step.
+func Compile(promql string, e Evaluation) (*flux.Spec, *Dialect, error) {
+	parsed, err := ParsePromQL(promql)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		sel *Selector
+		agg *AggregateExpr
+	)
+	switch expr := parsed.(type) {
+	case *Selector:
+		sel = expr
+	case *AggregateExpr:
+		sel, agg = expr.Selector, expr
+	default:
+		return nil, nil, fmt.Errorf("unsupported expression %q", promql)
+	}
+
+	instant := e.Step == 0
+	if sel.Range > 0 {
+		if agg != nil {
+			return nil, nil, fmt.Errorf("expected type instant vector in aggregation expression, got range vector")
+		}
+		if !instant {
+			return nil, nil, fmt.Errorf("invalid expression type range vector for range query, must be instant vector")
+		}
+	}
+
+	lookback := e.LookbackDelta
+	if lookback == 0 {
+		lookback = DefaultLookbackDelta
+	}
+
+	where, err := NewWhereOperation(sel.Name, sel.LabelMatchers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := &Dialect{
+		ResultType: VectorResult,
+		Time:       e.End,
+	}
+
+	ops := []*flux.Operation{
+		{
+			ID: "from",
+			Spec: &influxdb.FromOpSpec{
+				Bucket: e.Bucket,
+			},
+		},
+	}
+
+	// Range stops are exclusive, but Prometheus includes samples at the evaluation time.
+	end := e.End.Add(-sel.Offset).Add(time.Nanosecond)
+	switch {
+	case sel.Range > 0:
+		ops = append(ops, newAbsoluteRangeOp(end.Add(-sel.Range), end), where, newFieldsOp())
+		d.ResultType = MatrixResult
+		d.TimeColumn = execute.DefaultTimeColLabel
+	case instant:
+		ops = append(ops, newAbsoluteRangeOp(end.Add(-lookback), end), where, newFieldsOp(), newLastOp())
+	default:
+		// Each window ends just after a step so that, like the evaluation
+		// of the step, it includes samples at the time of the step. The
+		// windows look back LookbackDelta from their step, so a sample is
+		// carried forward to every step within the lookback.
+		start := e.Start.Add(-sel.Offset).Add(time.Nanosecond)
+		ops = append(ops,
+			newAbsoluteRangeOp(start.Add(-lookback), end),
+			where,
+			newFieldsOp(),
+			&flux.Operation{
+				ID: "window",
+				Spec: &universe.WindowOpSpec{
+					Every:       flux.Duration(e.Step),
+					Period:      flux.Duration(lookback),
+					Offset:      flux.Duration(start.UnixNano() % int64(e.Step)),
+					TimeColumn:  execute.DefaultTimeColLabel,
+					StartColumn: execute.DefaultStartColLabel,
+					StopColumn:  execute.DefaultStopColLabel,
+				},
+			},
+			newLastOp(),
+			newStepsOp(start, end.Add(-lookback)),
+		)
+		d.ResultType = MatrixResult
+		d.TimeColumn = execute.DefaultStopColLabel
+	}
+
+	if agg != nil {
+		// Aggregations merge every series unless grouped by labels, but
+		// never merge the samples of different steps.
+		merge := &flux.Operation{
+			ID: "merge",
+			Spec: &universe.GroupOpSpec{
+				Mode: "by",
+			},
+		}
+		if agg.Aggregate != nil {
+			if merge, err = agg.Aggregate.QuerySpec(); err != nil {
+				return nil, nil, err
+			}
+		}
+		group := merge.Spec.(*universe.GroupOpSpec)
+		group.Columns = append([]string{execute.DefaultStartColLabel, execute.DefaultStopColLabel}, group.Columns...)
+
+		op, err := agg.Op.QuerySpec()
+		if err != nil {
+			return nil, nil, err
+		}
+		ops = append(ops, merge, op)
+	}
+
+	if !instant {
+		// Shift the window stops to the time of each step.
+		ops = append(ops, &flux.Operation{
+			ID: "shift",
+			Spec: &universe.ShiftOpSpec{
+				Shift:   flux.Duration(sel.Offset - time.Nanosecond),
+				Columns: []string{execute.DefaultStartColLabel, execute.DefaultStopColLabel},
+			},
+		})
+	}
+
+	spec := &flux.Spec{
+		Operations: ops,
+	}
+	for i := 1; i < len(ops); i++ {
+		spec.Edges = append(spec.Edges, flux.Edge{
+			Parent: ops[i-1].ID,
+			Child:  ops[i].ID,
+		})
+	}
+	return spec, d, nil
+}
+
+func newAbsoluteRangeOp(start, stop time.Time) *flux.Operation {
+	return &flux.Operation{
+		ID: "range",
+		Spec: &universe.RangeOpSpec{
+			Start:       flux.Time{Absolute: start},
+			Stop:        flux.Time{Absolute: stop},
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+		},
+	}
+}
+
+func newLastOp() *flux.Operation {
+	return &flux.Operation{
+		ID: "last",
+		Spec: &universe.LastOpSpec{
+			SelectorConfig: execute.SelectorConfig{
+				Column: execute.DefaultValueColLabel,
+			},
+		},
+	}
+}
+
+// newStepsOp filters the windows to those that end at a step. The windows
+// clipped by the range either stop before the first step or start after the
+// last window that is not clipped.
+func newStepsOp(firstStop, lastStart time.Time) *flux.Operation {
+	// Times are compared as integers as filter does not compare times.
+	column := func(label string) semantic.Expression {
+		return &semantic.CallExpression{
+			Callee: &semantic.IdentifierExpression{Name: "int"},
+			Arguments: &semantic.ObjectExpression{
+				Properties: []*semantic.Property{{
+					Key: &semantic.Identifier{Name: "v"},
+					Value: &semantic.MemberExpression{
+						Object:   &semantic.IdentifierExpression{Name: "r"},
+						Property: label,
+					},
+				}},
+			},
+		}
+	}
+
+	return &flux.Operation{
+		ID: "steps",
+		Spec: &universe.FilterOpSpec{
+			Fn: &semantic.FunctionExpression{
+				Block: &semantic.FunctionBlock{
+					Parameters: &semantic.FunctionParameters{
+						List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
+					},
+					Body: &semantic.LogicalExpression{
+						Operator: ast.AndOperator,
+						Left: &semantic.BinaryExpression{
+							Operator: ast.GreaterThanEqualOperator,
+							Left:     column(execute.DefaultStopColLabel),
+							Right:    &semantic.IntegerLiteral{Value: firstStop.UnixNano()},
+						},
+						Right: &semantic.BinaryExpression{
+							Operator: ast.LessThanEqualOperator,
+							Left:     column(execute.DefaultStartColLabel),
+							Right:    &semantic.IntegerLiteral{Value: lastStart.UnixNano()},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newFieldsOp filters the samples to the value fields of the scraped metrics.
+func newFieldsOp() *flux.Operation {
+	var node semantic.Expression
+	for _, f := range ValueFields {
+		eq := &semantic.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left: &semantic.MemberExpression{
+				Object:   &semantic.IdentifierExpression{Name: "r"},
+				Property: "_field",
+			},
+			Right: &semantic.StringLiteral{Value: f},
+		}
+		if node == nil {
+			node = eq
+			continue
+		}
+		node = &semantic.LogicalExpression{
+			Operator: ast.OrOperator,
+			Left:     node,
+			Right:    eq,
+		}
+	}
+
+	return &flux.Operation{
+		ID: "fields",
+		Spec: &universe.FilterOpSpec{
+			Fn: &semantic.FunctionExpression{
+				Block: &semantic.FunctionBlock{
+					Parameters: &semantic.FunctionParameters{
+						List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
+					},
+					Body: node,
+				},
+			},
+		},
+	}
+}
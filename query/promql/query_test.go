@@ -8,6 +8,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/semantic/semantictest"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
@@ -384,7 +385,7 @@ func TestBuild(t *testing.T) {
 													Object: &semantic.IdentifierExpression{
 														Name: "r",
 													},
-													Property: "_metric",
+													Property: "_measurement",
 												},
 												Right: &semantic.StringLiteral{
 													Value: "node_cpu",
@@ -421,7 +422,7 @@ func TestBuild(t *testing.T) {
 						},
 					},
 					{
-						ID: flux.OperationID("count"), Spec: &universe.CountOpSpec{},
+						ID: flux.OperationID("count"), Spec: &universe.CountOpSpec{AggregateConfig: execute.DefaultAggregateConfig},
 					},
 				},
 				Edges: []flux.Edge{
@@ -467,7 +468,7 @@ func TestBuild(t *testing.T) {
 												Object: &semantic.IdentifierExpression{
 													Name: "r",
 												},
-												Property: "_metric",
+												Property: "_measurement",
 											},
 											Right: &semantic.StringLiteral{
 												Value: "node_cpu",
@@ -535,7 +536,7 @@ func TestBuild(t *testing.T) {
 												Object: &semantic.IdentifierExpression{
 													Name: "r",
 												},
-												Property: "_metric",
+												Property: "_measurement",
 											},
 											Right: &semantic.StringLiteral{
 												Value: "node_cpu",
@@ -559,7 +560,7 @@ func TestBuild(t *testing.T) {
 						},
 					},
 					{
-						ID: flux.OperationID("sum"), Spec: &universe.SumOpSpec{},
+						ID: flux.OperationID("sum"), Spec: &universe.SumOpSpec{AggregateConfig: execute.DefaultAggregateConfig},
 					},
 				},
 				Edges: []flux.Edge{
@@ -594,3 +595,88 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestCompile(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	tests := []struct {
+		name       string
+		promql     string
+		eval       Evaluation
+		ops        []flux.OperationID
+		resultType ResultType
+		timeColumn string
+		wantErr    bool
+	}{
+		{
+			name:       "instant vector",
+			promql:     `node_cpu{mode="user"}`,
+			eval:       Evaluation{Bucket: "telegraf", Start: now, End: now},
+			ops:        []flux.OperationID{"from", "range", "where", "fields", "last"},
+			resultType: VectorResult,
+		},
+		{
+			name:       "range vector",
+			promql:     `node_cpu[5m] offset 1m`,
+			eval:       Evaluation{Bucket: "telegraf", Start: now, End: now},
+			ops:        []flux.OperationID{"from", "range", "where", "fields"},
+			resultType: MatrixResult,
+			timeColumn: "_time",
+		},
+		{
+			name:       "instant aggregation",
+			promql:     `sum(node_cpu) by (mode)`,
+			eval:       Evaluation{Bucket: "telegraf", Start: now, End: now},
+			ops:        []flux.OperationID{"from", "range", "where", "fields", "last", "merge", "sum"},
+			resultType: VectorResult,
+		},
+		{
+			name:       "range aggregation",
+			promql:     `count(node_cpu)`,
+			eval:       Evaluation{Bucket: "telegraf", Start: now.Add(-time.Hour), End: now, Step: time.Minute},
+			ops:        []flux.OperationID{"from", "range", "where", "fields", "window", "last", "steps", "merge", "count", "shift"},
+			resultType: MatrixResult,
+			timeColumn: "_stop",
+		},
+		{
+			name:    "range vector in range query",
+			promql:  `node_cpu[5m]`,
+			eval:    Evaluation{Bucket: "telegraf", Start: now.Add(-time.Hour), End: now, Step: time.Minute},
+			wantErr: true,
+		},
+		{
+			name:    "range vector in aggregation",
+			promql:  `sum(node_cpu[5m])`,
+			eval:    Evaluation{Bucket: "telegraf", Start: now, End: now},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, d, err := Compile(tt.promql, tt.eval)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			ops := make([]flux.OperationID, len(spec.Operations))
+			for i, op := range spec.Operations {
+				ops[i] = op.ID
+			}
+			if !cmp.Equal(tt.ops, ops) {
+				t.Errorf("unexpected operations -want/+got:\n%s", cmp.Diff(tt.ops, ops))
+			}
+			if got := len(spec.Edges); got != len(ops)-1 {
+				t.Errorf("got %d edges, want %d", got, len(ops)-1)
+			}
+
+			if from := spec.Operations[0].Spec.(*influxdb.FromOpSpec); from.Bucket != tt.eval.Bucket {
+				t.Errorf("got bucket %q, want %q", from.Bucket, tt.eval.Bucket)
+			}
+			if d.ResultType != tt.resultType || d.TimeColumn != tt.timeColumn {
+				t.Errorf("unexpected dialect %+v", d)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/stdlib/universe"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
@@ -215,7 +216,7 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 			Object: &semantic.IdentifierExpression{
 				Name: "r",
 			},
-			Property: "_metric",
+			Property: "_measurement",
 		},
 		Right: &semantic.StringLiteral{
 			Value: metricName,
@@ -378,7 +379,7 @@ func (o *Operator) QuerySpec() (*flux.Operation, error) {
 	case CountKind:
 		return &flux.Operation{
 			ID:   "count",
-			Spec: &universe.CountOpSpec{},
+			Spec: &universe.CountOpSpec{AggregateConfig: execute.DefaultAggregateConfig},
 		}, nil
 	//case TopKind:
 	//	return &flux.Operation{
@@ -388,7 +389,7 @@ func (o *Operator) QuerySpec() (*flux.Operation, error) {
 	case SumKind:
 		return &flux.Operation{
 			ID:   "sum",
-			Spec: &universe.SumOpSpec{},
+			Spec: &universe.SumOpSpec{AggregateConfig: execute.DefaultAggregateConfig},
 		}, nil
 	//case MinKind:
 	//	return &flux.Operation{
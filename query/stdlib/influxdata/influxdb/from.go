@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
@@ -43,7 +44,10 @@ func init() {
 		FromDistinctRule{},
 		MergeFromGroupRule{},
 		FromKeysRule{},
+		PushDownReadTagKeysRule{},
+		PushDownReadTagValuesRule{},
 	)
+	plan.RegisterPhysicalRules(PushDownWindowAggregateRules()...)
 	execute.RegisterSource(PhysicalFromKind, createFromSource)
 }
 
@@ -162,8 +166,9 @@ type PhysicalFromProcedureSpec struct {
 	SeriesLimit  int64
 	SeriesOffset int64
 
-	WindowSet bool
-	Window    plan.WindowSpec
+	WindowSet   bool
+	Window      plan.WindowSpec
+	CreateEmpty bool
 
 	GroupingSet bool
 	OrderByTime bool
@@ -172,6 +177,10 @@ type PhysicalFromProcedureSpec struct {
 
 	AggregateSet    bool
 	AggregateMethod string
+
+	TagKeysSet   bool
+	TagValuesSet bool
+	TagKey       string
 }
 
 func (PhysicalFromProcedureSpec) Kind() plan.ProcedureKind {
@@ -200,6 +209,7 @@ func (s *PhysicalFromProcedureSpec) Copy() plan.ProcedureSpec {
 
 	ns.WindowSet = s.WindowSet
 	ns.Window = s.Window
+	ns.CreateEmpty = s.CreateEmpty
 
 	ns.GroupingSet = s.GroupingSet
 	ns.OrderByTime = s.OrderByTime
@@ -209,9 +219,19 @@ func (s *PhysicalFromProcedureSpec) Copy() plan.ProcedureSpec {
 	ns.AggregateSet = s.AggregateSet
 	ns.AggregateMethod = s.AggregateMethod
 
+	ns.TagKeysSet = s.TagKeysSet
+	ns.TagValuesSet = s.TagValuesSet
+	ns.TagKey = s.TagKey
+
 	return ns
 }
 
+// tagSchemaSet reports whether the from reads the tag keys or the tag values
+// of the series instead of their data.
+func (s *PhysicalFromProcedureSpec) tagSchemaSet() bool {
+	return s.TagKeysSet || s.TagValuesSet
+}
+
 // TimeBounds implements plan.BoundsAwareProcedureSpec.
 func (s *PhysicalFromProcedureSpec) TimeBounds(predecessorBounds *plan.Bounds) *plan.Bounds {
 	if s.BoundsSet {
@@ -279,6 +299,10 @@ func (rule MergeFromRangeRule) Pattern() plan.Pattern {
 func (rule MergeFromRangeRule) Rewrite(node plan.PlanNode) (plan.PlanNode, bool, error) {
 	from := node.Predecessors()[0]
 	fromSpec := from.ProcedureSpec().(*PhysicalFromProcedureSpec)
+	if fromSpec.AggregateSet || fromSpec.tagSchemaSet() {
+		// The range applies to the aggregated rows.
+		return node, false, nil
+	}
 	rangeSpec := node.ProcedureSpec().(*universe.RangeProcedureSpec)
 	fromRange := fromSpec.Copy().(*PhysicalFromProcedureSpec)
 
@@ -343,7 +367,7 @@ func (MergeFromFilterRule) Rewrite(filterNode plan.PlanNode) (plan.PlanNode, boo
 	fromNode := filterNode.Predecessors()[0]
 	fromSpec := fromNode.ProcedureSpec().(*PhysicalFromProcedureSpec)
 
-	if fromSpec.AggregateSet || fromSpec.GroupingSet {
+	if fromSpec.AggregateSet || fromSpec.GroupingSet || fromSpec.tagSchemaSet() {
 		return filterNode, false, nil
 	}
 
@@ -352,6 +376,16 @@ func (MergeFromFilterRule) Rewrite(filterNode plan.PlanNode) (plan.PlanNode, boo
 		return filterNode, false, nil
 	}
 
+	// A filter that keeps every row, such as the default predicate of the
+	// schema functions, is eliminated.
+	if lit, ok := bodyExpr.(*semantic.BooleanLiteral); ok && lit.Value {
+		mergedNode, err := plan.MergeToPhysicalPlanNode(filterNode, fromNode, fromSpec.Copy().(*PhysicalFromProcedureSpec))
+		if err != nil {
+			return nil, false, err
+		}
+		return mergedNode, true, nil
+	}
+
 	if len(filterSpec.Fn.Block.Parameters.List) != 1 {
 		// I would expect that type checking would catch this, but just to be safe...
 		return filterNode, false, nil
@@ -553,7 +587,7 @@ func (FromDistinctRule) Rewrite(distinctNode plan.PlanNode) (plan.PlanNode, bool
 	distinctSpec := distinctNode.ProcedureSpec().(*universe.DistinctProcedureSpec)
 	fromSpec := fromNode.ProcedureSpec().(*PhysicalFromProcedureSpec)
 
-	if fromSpec.LimitSet && fromSpec.PointsLimit == -1 {
+	if fromSpec.LimitSet && fromSpec.PointsLimit == -1 || fromSpec.AggregateSet || fromSpec.tagSchemaSet() {
 		return distinctNode, false, nil
 	}
 
@@ -592,6 +626,8 @@ func (MergeFromGroupRule) Rewrite(groupNode plan.PlanNode) (plan.PlanNode, bool,
 
 	if fromSpec.GroupingSet ||
 		fromSpec.LimitSet ||
+		fromSpec.AggregateSet ||
+		fromSpec.tagSchemaSet() ||
 		groupSpec.GroupMode != flux.GroupModeBy {
 		return groupNode, false, nil
 	}
@@ -630,7 +666,7 @@ func (FromKeysRule) Rewrite(keysNode plan.PlanNode) (plan.PlanNode, bool, error)
 	fromNode := keysNode.Predecessors()[0]
 	fromSpec := fromNode.ProcedureSpec().(*PhysicalFromProcedureSpec)
 
-	if fromSpec.LimitSet && fromSpec.PointsLimit == -1 {
+	if fromSpec.LimitSet && fromSpec.PointsLimit == -1 || fromSpec.AggregateSet || fromSpec.tagSchemaSet() {
 		return keysNode, false, nil
 	}
 
@@ -645,29 +681,305 @@ func (FromKeysRule) Rewrite(keysNode plan.PlanNode) (plan.PlanNode, bool, error)
 	return keysNode, true, nil
 }
 
+// windowAggregateKinds are the aggregates and selectors the storage layer
+// computes per window.
+var windowAggregateKinds = []plan.ProcedureKind{
+	universe.CountKind,
+	universe.SumKind,
+	universe.MeanKind,
+	universe.MinKind,
+	universe.MaxKind,
+	universe.FirstKind,
+	universe.LastKind,
+}
+
+// PushDownWindowAggregateRules returns the rules that push a `window`
+// followed by an aggregate or selector into a `from`.
+func PushDownWindowAggregateRules() []plan.Rule {
+	rules := make([]plan.Rule, len(windowAggregateKinds))
+	for i, kind := range windowAggregateKinds {
+		rules[i] = PushDownWindowAggregateRule{Kind: kind}
+	}
+	return rules
+}
+
+// PushDownWindowAggregateRule pushes a `window` followed by the aggregate or
+// selector of kind Kind into a `from`, so that the storage layer computes a
+// point per window instead of returning every point.
+type PushDownWindowAggregateRule struct {
+	Kind plan.ProcedureKind
+}
+
+func (rule PushDownWindowAggregateRule) Name() string {
+	return fmt.Sprintf("PushDownWindowAggregateRule(%s)", rule.Kind)
+}
+
+// Pattern returns the pattern that matches `from -> window -> <aggregate>`.
+func (rule PushDownWindowAggregateRule) Pattern() plan.Pattern {
+	return plan.Pat(rule.Kind, plan.Pat(universe.WindowKind, plan.Pat(PhysicalFromKind)))
+}
+
+func (rule PushDownWindowAggregateRule) Rewrite(aggNode plan.PlanNode) (plan.PlanNode, bool, error) {
+	windowNode := aggNode.Predecessors()[0]
+	fromNode := windowNode.Predecessors()[0]
+	windowSpec := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
+	fromSpec := fromNode.ProcedureSpec().(*PhysicalFromProcedureSpec)
+
+	if !fromSpec.BoundsSet ||
+		fromSpec.DescendingSet ||
+		fromSpec.LimitSet ||
+		fromSpec.WindowSet ||
+		fromSpec.GroupingSet ||
+		fromSpec.AggregateSet ||
+		fromSpec.tagSchemaSet() {
+		return aggNode, false, nil
+	}
+
+	// Storage computes fixed windows over the default columns only.
+	w := windowSpec.Window
+	if w.Every <= 0 || w.Every != w.Period || w.Every == flux.Duration(math.MaxInt64) ||
+		windowSpec.TimeColumn != execute.DefaultTimeColLabel ||
+		windowSpec.StartColumn != execute.DefaultStartColLabel ||
+		windowSpec.StopColumn != execute.DefaultStopColLabel {
+		return aggNode, false, nil
+	}
+
+	if !aggregatesValue(aggNode.ProcedureSpec()) {
+		return aggNode, false, nil
+	}
+
+	// The window and the from must only feed the aggregate.
+	if len(fromNode.Successors()) != 1 || len(windowNode.Successors()) != 1 {
+		return aggNode, false, nil
+	}
+
+	newFromSpec := fromSpec.Copy().(*PhysicalFromProcedureSpec)
+	newFromSpec.WindowSet = true
+	newFromSpec.Window = w
+	newFromSpec.CreateEmpty = windowSpec.CreateEmpty
+	newFromSpec.AggregateSet = true
+	newFromSpec.AggregateMethod = string(rule.Kind)
+
+	merged, err := plan.MergeToPhysicalPlanNode(aggNode, windowNode, newFromSpec)
+	if err != nil {
+		return nil, false, err
+	}
+	merged, err = plan.MergeToPhysicalPlanNode(merged, fromNode, newFromSpec.Copy().(*PhysicalFromProcedureSpec))
+	if err != nil {
+		return nil, false, err
+	}
+	return merged, true, nil
+}
+
+// aggregatesValue reports whether the aggregate or selector spec only
+// computes the _value column.
+func aggregatesValue(spec plan.ProcedureSpec) bool {
+	var columns []string
+	switch spec := spec.(type) {
+	case *universe.CountProcedureSpec:
+		columns = spec.Columns
+	case *universe.SumProcedureSpec:
+		columns = spec.Columns
+	case *universe.MeanProcedureSpec:
+		columns = spec.Columns
+	case *universe.MinProcedureSpec:
+		columns = []string{selectorColumn(spec.SelectorConfig)}
+	case *universe.MaxProcedureSpec:
+		columns = []string{selectorColumn(spec.SelectorConfig)}
+	case *universe.FirstProcedureSpec:
+		columns = []string{selectorColumn(spec.SelectorConfig)}
+	case *universe.LastProcedureSpec:
+		columns = []string{selectorColumn(spec.SelectorConfig)}
+	}
+	return len(columns) == 1 && columns[0] == execute.DefaultValueColLabel
+}
+
+// selectorColumn returns the column of a selector, which defaults to the value.
+func selectorColumn(config execute.SelectorConfig) string {
+	if config.Column == "" {
+		return execute.DefaultValueColLabel
+	}
+	return config.Column
+}
+
+// PushDownReadTagKeysRule pushes the `keys`, `keep` and `distinct` that find
+// the tag keys of the series into a `from`, so that the storage layer reads
+// the keys from its index instead of reading every series.
+type PushDownReadTagKeysRule struct{}
+
+func (PushDownReadTagKeysRule) Name() string {
+	return "PushDownReadTagKeysRule"
+}
+
+// Pattern returns the pattern that matches `from -> keys -> keep -> distinct`.
+func (PushDownReadTagKeysRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.DistinctKind,
+		plan.Pat(universe.SchemaMutationKind,
+			plan.Pat(universe.KeysKind,
+				plan.Pat(PhysicalFromKind))))
+}
+
+func (PushDownReadTagKeysRule) Rewrite(distinctNode plan.PlanNode) (plan.PlanNode, bool, error) {
+	keepNode := distinctNode.Predecessors()[0]
+	keysNode := keepNode.Predecessors()[0]
+	fromNode := keysNode.Predecessors()[0]
+	fromSpec := fromNode.ProcedureSpec().(*PhysicalFromProcedureSpec)
+
+	// The keys of grouped series are the group keys instead.
+	if !canReadTagSchema(fromSpec) || fromSpec.GroupingSet {
+		return distinctNode, false, nil
+	}
+
+	// The keys must be found in the _value column, and be the only column
+	// left to find the distinct values of.
+	if keysNode.ProcedureSpec().(*universe.KeysProcedureSpec).Column != execute.DefaultValueColLabel ||
+		!keepsOnly(keepNode.ProcedureSpec(), execute.DefaultValueColLabel) ||
+		distinctNode.ProcedureSpec().(*universe.DistinctProcedureSpec).Column != execute.DefaultValueColLabel {
+		return distinctNode, false, nil
+	}
+
+	if len(fromNode.Successors()) != 1 || len(keysNode.Successors()) != 1 || len(keepNode.Successors()) != 1 {
+		return distinctNode, false, nil
+	}
+
+	newFromSpec := fromSpec.Copy().(*PhysicalFromProcedureSpec)
+	newFromSpec.LimitSet = false
+	newFromSpec.PointsLimit = 0
+	newFromSpec.TagKeysSet = true
+	return mergeToFrom(newFromSpec, distinctNode, keepNode, keysNode, fromNode)
+}
+
+// PushDownReadTagValuesRule pushes the `distinct` and `keep` that find the
+// values of a tag key of the series grouped by the tag into a `from`, so that
+// the storage layer reads the values from its index instead of reading every
+// series.
+type PushDownReadTagValuesRule struct{}
+
+func (PushDownReadTagValuesRule) Name() string {
+	return "PushDownReadTagValuesRule"
+}
+
+// Pattern returns the pattern that matches `from -> distinct -> keep`, where
+// the `from` is grouped by the tag.
+func (PushDownReadTagValuesRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.SchemaMutationKind,
+		plan.Pat(universe.DistinctKind,
+			plan.Pat(PhysicalFromKind)))
+}
+
+func (PushDownReadTagValuesRule) Rewrite(keepNode plan.PlanNode) (plan.PlanNode, bool, error) {
+	distinctNode := keepNode.Predecessors()[0]
+	fromNode := distinctNode.Predecessors()[0]
+	fromSpec := fromNode.ProcedureSpec().(*PhysicalFromProcedureSpec)
+
+	// The series must be grouped by the tag, so that each table has one of
+	// the distinct values.
+	if !canReadTagSchema(fromSpec) ||
+		!fromSpec.GroupingSet ||
+		fromSpec.GroupMode != flux.GroupModeBy ||
+		len(fromSpec.GroupKeys) != 1 {
+		return keepNode, false, nil
+	}
+	tagKey := fromSpec.GroupKeys[0]
+	if tagKey == execute.DefaultValueColLabel || tagKey == execute.DefaultTimeColLabel ||
+		tagKey == execute.DefaultStartColLabel || tagKey == execute.DefaultStopColLabel {
+		return keepNode, false, nil
+	}
+
+	if distinctNode.ProcedureSpec().(*universe.DistinctProcedureSpec).Column != tagKey ||
+		!keepsOnly(keepNode.ProcedureSpec(), execute.DefaultValueColLabel) {
+		return keepNode, false, nil
+	}
+
+	if len(fromNode.Successors()) != 1 || len(distinctNode.Successors()) != 1 {
+		return keepNode, false, nil
+	}
+
+	newFromSpec := fromSpec.Copy().(*PhysicalFromProcedureSpec)
+	newFromSpec.LimitSet = false
+	newFromSpec.PointsLimit = 0
+	newFromSpec.GroupingSet = false
+	newFromSpec.GroupMode = flux.GroupModeNone
+	newFromSpec.GroupKeys = nil
+	newFromSpec.TagValuesSet = true
+	newFromSpec.TagKey = tagKey
+	return mergeToFrom(newFromSpec, keepNode, distinctNode, fromNode)
+}
+
+// canReadTagSchema reports whether the tag keys and values of the series the
+// from reads describe its tables. The from must only be bounded and filtered
+// by the tags, and only the points may be limited.
+func canReadTagSchema(spec *PhysicalFromProcedureSpec) bool {
+	if !spec.BoundsSet ||
+		spec.DescendingSet ||
+		spec.WindowSet ||
+		spec.AggregateSet ||
+		spec.tagSchemaSet() {
+		return false
+	}
+	if spec.LimitSet && (spec.PointsLimit != -1 || spec.SeriesLimit != 0 || spec.SeriesOffset != 0) {
+		return false
+	}
+	if spec.FilterSet {
+		paramName := spec.Filter.Block.Parameters.List[0].Key.Name
+		if filtersField(paramName, spec.Filter.Block.Body.(semantic.Expression)) {
+			return false
+		}
+	}
+	return true
+}
+
+// filtersField reports whether the pushed down filter expression compares the
+// field value.
+func filtersField(paramName string, expr semantic.Expression) bool {
+	switch e := expr.(type) {
+	case *semantic.LogicalExpression:
+		return filtersField(paramName, e.Left) || filtersField(paramName, e.Right)
+	case *semantic.BinaryExpression:
+		return isField(paramName, e.Left)
+	}
+	return false
+}
+
+// keepsOnly reports whether the spec is a `keep` of the column only.
+func keepsOnly(spec plan.ProcedureSpec, column string) bool {
+	mutations := spec.(*universe.SchemaMutationProcedureSpec).Mutations
+	if len(mutations) != 1 {
+		return false
+	}
+	keep, ok := mutations[0].(*universe.KeepOpSpec)
+	return ok && keep.Predicate == nil && len(keep.Columns) == 1 && keep.Columns[0] == column
+}
+
+// mergeToFrom merges the chain of nodes, from the last successor to the
+// `from`, into a single `from` node with the spec.
+func mergeToFrom(spec *PhysicalFromProcedureSpec, nodes ...plan.PlanNode) (plan.PlanNode, bool, error) {
+	merged := nodes[0]
+	for _, node := range nodes[1:] {
+		var err error
+		merged, err = plan.MergeToPhysicalPlanNode(merged, node, spec.Copy().(*PhysicalFromProcedureSpec))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return merged, true, nil
+}
+
 func createFromSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec := prSpec.(*PhysicalFromProcedureSpec)
-	var w execute.Window
 	bounds := a.StreamContext().Bounds()
 	if bounds == nil {
 		return nil, errors.New("nil bounds passed to from")
 	}
 
-	// Note: currently no planner rules will push a window() into from()
-	// so the following is dead code.
-	if spec.WindowSet {
-		w = execute.Window{
-			Every:  execute.Duration(spec.Window.Every),
-			Period: execute.Duration(spec.Window.Period),
-			Offset: execute.Duration(spec.Window.Offset),
-		}
-	} else {
-		duration := execute.Duration(bounds.Stop) - execute.Duration(bounds.Start)
-		w = execute.Window{
-			Every:  duration,
-			Period: duration,
-			Offset: bounds.Start.Remainder(duration),
-		}
+	// The source reads the bounds as a single window. A window pushed into
+	// from() comes with an aggregate, which storage computes per window in
+	// the same read, so it is passed on as the aggregate window below.
+	duration := execute.Duration(bounds.Stop) - execute.Duration(bounds.Start)
+	w := execute.Window{
+		Every:  duration,
+		Period: duration,
+		Offset: bounds.Start.Remainder(duration),
 	}
 	currentTime := bounds.Start + execute.Time(w.Period)
 
@@ -694,6 +1006,15 @@ func createFromSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execu
 		}
 	}
 
+	var aggWindow execute.Window
+	if spec.WindowSet {
+		aggWindow = execute.Window{
+			Every:  execute.Duration(spec.Window.Every),
+			Period: execute.Duration(spec.Window.Period),
+			Offset: execute.Duration(spec.Window.Offset),
+		}
+	}
+
 	return NewSource(
 		dsid,
 		deps.Reader,
@@ -709,10 +1030,16 @@ func createFromSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execu
 			GroupMode:       ToGroupMode(spec.GroupMode),
 			GroupKeys:       spec.GroupKeys,
 			AggregateMethod: spec.AggregateMethod,
+			AggregateWindow: aggWindow,
+			CreateEmpty:     spec.CreateEmpty,
+			TagKeys:         spec.TagKeysSet,
+			TagValues:       spec.TagValuesSet,
+			TagKey:          spec.TagKey,
 		},
 		*bounds,
 		w,
 		currentTime,
+		a.Allocator(),
 	), nil
 }
 
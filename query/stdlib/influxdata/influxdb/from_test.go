@@ -516,6 +516,25 @@ func TestFromFilterRule(t *testing.T) {
 			},
 			NoChange: true,
 		},
+		{
+			Name: "from true filter",
+			// from -> filter(true)  =>  from
+			Rules: []plan.Rule{influxdb.MergeFromFilterRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("from", physFrom),
+					plan.CreatePhysicalNode("filter", &universe.FilterProcedureSpec{Fn: makeFilterFn(&semantic.BooleanLiteral{Value: true})}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("merged_from_filter", physFrom),
+				},
+			},
+		},
 		{
 			Name: "from with statement filter",
 			// from -> filter(with statement function)  =>  from -> filter(with statement function)  (no change)
@@ -846,6 +865,337 @@ func TestFromKeysRule(t *testing.T) {
 	}
 }
 
+func TestPushDownWindowAggregateRule(t *testing.T) {
+	var (
+		bounds = flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		}
+		fromWithBounds = &influxdb.PhysicalFromProcedureSpec{
+			BoundsSet: true,
+			Bounds:    bounds,
+		}
+		windowSpec = func(every, period time.Duration, createEmpty bool) *universe.WindowProcedureSpec {
+			return &universe.WindowProcedureSpec{
+				Window: plan.WindowSpec{
+					Every:  flux.Duration(every),
+					Period: flux.Duration(period),
+				},
+				TimeColumn:  execute.DefaultTimeColLabel,
+				StartColumn: execute.DefaultStartColLabel,
+				StopColumn:  execute.DefaultStopColLabel,
+				CreateEmpty: createEmpty,
+			}
+		}
+		window      = windowSpec(time.Minute, time.Minute, true)
+		slidingWin  = windowSpec(time.Minute, 2*time.Minute, false)
+		count       = &universe.CountProcedureSpec{AggregateConfig: execute.AggregateConfig{Columns: []string{"_value"}}}
+		countOther  = &universe.CountProcedureSpec{AggregateConfig: execute.AggregateConfig{Columns: []string{"other"}}}
+		last        = &universe.LastProcedureSpec{SelectorConfig: execute.SelectorConfig{Column: "_value"}}
+		pushedCount = &influxdb.PhysicalFromProcedureSpec{
+			BoundsSet:       true,
+			Bounds:          bounds,
+			WindowSet:       true,
+			Window:          window.Window,
+			CreateEmpty:     true,
+			AggregateSet:    true,
+			AggregateMethod: universe.CountKind,
+		}
+		pushedLast = &influxdb.PhysicalFromProcedureSpec{
+			BoundsSet:       true,
+			Bounds:          bounds,
+			WindowSet:       true,
+			Window:          window.Window,
+			CreateEmpty:     true,
+			AggregateSet:    true,
+			AggregateMethod: universe.LastKind,
+		}
+	)
+
+	// unchanged returns the plan of from, window and the aggregate.
+	unchanged := func(from, window, agg plan.ProcedureSpec) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.PlanNode{
+				plan.CreatePhysicalNode("from", from.(plan.PhysicalProcedureSpec)),
+				plan.CreatePhysicalNode("window", window.(plan.PhysicalProcedureSpec)),
+				plan.CreatePhysicalNode("agg", agg.(plan.PhysicalProcedureSpec)),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+			},
+		}
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name:   "from window count",
+			Rules:  influxdb.PushDownWindowAggregateRules(),
+			Before: unchanged(fromWithBounds, window, count),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("merged_from_window_agg", pushedCount),
+				},
+			},
+		},
+		{
+			Name:  "from window last yield",
+			Rules: influxdb.PushDownWindowAggregateRules(),
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("from", fromWithBounds),
+					plan.CreatePhysicalNode("window", window),
+					plan.CreatePhysicalNode("agg", last),
+					plantest.CreatePhysicalMockNode("yield"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("merged_from_window_agg", pushedLast),
+					plantest.CreatePhysicalMockNode("yield"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			},
+		},
+		{
+			Name:   "sliding window",
+			Rules:  influxdb.PushDownWindowAggregateRules(),
+			Before: unchanged(fromWithBounds, slidingWin, count),
+			After:  unchanged(fromWithBounds, slidingWin, count),
+		},
+		{
+			Name:   "other column",
+			Rules:  influxdb.PushDownWindowAggregateRules(),
+			Before: unchanged(fromWithBounds, window, countOther),
+			After:  unchanged(fromWithBounds, window, countOther),
+		},
+		{
+			Name:   "from without bounds",
+			Rules:  influxdb.PushDownWindowAggregateRules(),
+			Before: unchanged(&influxdb.PhysicalFromProcedureSpec{}, window, count),
+			After:  unchanged(&influxdb.PhysicalFromProcedureSpec{}, window, count),
+		},
+		{
+			Name:  "window with other successors",
+			Rules: influxdb.PushDownWindowAggregateRules(),
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("from", fromWithBounds),
+					plan.CreatePhysicalNode("window", window),
+					plan.CreatePhysicalNode("agg", count),
+					plantest.CreatePhysicalMockNode("other"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("from", fromWithBounds),
+					plan.CreatePhysicalNode("window", window),
+					plan.CreatePhysicalNode("agg", count),
+					plantest.CreatePhysicalMockNode("other"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestPushDownReadTagKeysRule(t *testing.T) {
+	var (
+		bounds = flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		}
+		fromWithBounds = &influxdb.PhysicalFromProcedureSpec{
+			BoundsSet: true,
+			Bounds:    bounds,
+		}
+		fromWithLimit = &influxdb.PhysicalFromProcedureSpec{
+			BoundsSet:   true,
+			Bounds:      bounds,
+			LimitSet:    true,
+			PointsLimit: -1,
+		}
+		keys     = &universe.KeysProcedureSpec{Column: "_value"}
+		keep     = &universe.SchemaMutationProcedureSpec{Mutations: []universe.SchemaMutation{&universe.KeepOpSpec{Columns: []string{"_value"}}}}
+		distinct = &universe.DistinctProcedureSpec{Column: "_value"}
+		pushed   = &influxdb.PhysicalFromProcedureSpec{
+			BoundsSet:  true,
+			Bounds:     bounds,
+			TagKeysSet: true,
+		}
+	)
+
+	// unchanged returns the plan of from, keys, keep and distinct.
+	unchanged := func(from plan.ProcedureSpec) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.PlanNode{
+				plan.CreatePhysicalNode("from", from.(plan.PhysicalProcedureSpec)),
+				plan.CreatePhysicalNode("keys", keys),
+				plan.CreatePhysicalNode("keep", keep),
+				plan.CreatePhysicalNode("distinct", distinct),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+				{2, 3},
+			},
+		}
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name:   "from keys keep distinct",
+			Rules:  []plan.Rule{influxdb.PushDownReadTagKeysRule{}},
+			Before: unchanged(fromWithBounds),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("merged_from_keys_keep_distinct", pushed),
+				},
+			},
+		},
+		{
+			Name:   "from without points keys keep distinct",
+			Rules:  []plan.Rule{influxdb.PushDownReadTagKeysRule{}},
+			Before: unchanged(fromWithLimit),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("merged_from_keys_keep_distinct", pushed),
+				},
+			},
+		},
+		{
+			Name:   "from without bounds",
+			Rules:  []plan.Rule{influxdb.PushDownReadTagKeysRule{}},
+			Before: unchanged(&influxdb.PhysicalFromProcedureSpec{}),
+			After:  unchanged(&influxdb.PhysicalFromProcedureSpec{}),
+		},
+		{
+			Name:  "grouped from",
+			Rules: []plan.Rule{influxdb.PushDownReadTagKeysRule{}},
+			Before: unchanged(&influxdb.PhysicalFromProcedureSpec{
+				BoundsSet:   true,
+				Bounds:      bounds,
+				GroupingSet: true,
+				GroupMode:   flux.GroupModeBy,
+				GroupKeys:   []string{"host"},
+			}),
+			After: unchanged(&influxdb.PhysicalFromProcedureSpec{
+				BoundsSet:   true,
+				Bounds:      bounds,
+				GroupingSet: true,
+				GroupMode:   flux.GroupModeBy,
+				GroupKeys:   []string{"host"},
+			}),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestPushDownReadTagValuesRule(t *testing.T) {
+	var (
+		bounds = flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		}
+		fromGroupedBy = func(keys ...string) *influxdb.PhysicalFromProcedureSpec {
+			return &influxdb.PhysicalFromProcedureSpec{
+				BoundsSet:   true,
+				Bounds:      bounds,
+				GroupingSet: true,
+				GroupMode:   flux.GroupModeBy,
+				GroupKeys:   keys,
+			}
+		}
+		distinct = &universe.DistinctProcedureSpec{Column: "host"}
+		keep     = &universe.SchemaMutationProcedureSpec{Mutations: []universe.SchemaMutation{&universe.KeepOpSpec{Columns: []string{"_value"}}}}
+		pushed   = &influxdb.PhysicalFromProcedureSpec{
+			BoundsSet:    true,
+			Bounds:       bounds,
+			TagValuesSet: true,
+			TagKey:       "host",
+		}
+	)
+
+	// unchanged returns the plan of from, distinct and keep.
+	unchanged := func(from, distinct plan.ProcedureSpec) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.PlanNode{
+				plan.CreatePhysicalNode("from", from.(plan.PhysicalProcedureSpec)),
+				plan.CreatePhysicalNode("distinct", distinct.(plan.PhysicalProcedureSpec)),
+				plan.CreatePhysicalNode("keep", keep),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+			},
+		}
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name:   "from group distinct keep",
+			Rules:  []plan.Rule{influxdb.PushDownReadTagValuesRule{}},
+			Before: unchanged(fromGroupedBy("host"), distinct),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.PlanNode{
+					plan.CreatePhysicalNode("merged_from_distinct_keep", pushed),
+				},
+			},
+		},
+		{
+			Name:   "grouped by other columns",
+			Rules:  []plan.Rule{influxdb.PushDownReadTagValuesRule{}},
+			Before: unchanged(fromGroupedBy("host", "region"), distinct),
+			After:  unchanged(fromGroupedBy("host", "region"), distinct),
+		},
+		{
+			Name:   "distinct other column",
+			Rules:  []plan.Rule{influxdb.PushDownReadTagValuesRule{}},
+			Before: unchanged(fromGroupedBy("host"), &universe.DistinctProcedureSpec{Column: "region"}),
+			After:  unchanged(fromGroupedBy("host"), &universe.DistinctProcedureSpec{Column: "region"}),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
 func TestFromRangeValidation(t *testing.T) {
 	testSpec := plantest.PlanSpec{
 		//       3
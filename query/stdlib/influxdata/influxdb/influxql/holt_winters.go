@@ -0,0 +1,528 @@
+package influxql
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+)
+
+const HoltWintersKind = "influxqlHoltWinters"
+
+const (
+	// hwWeight is the weight of the first values in the initial guesses.
+	hwWeight = 0.5
+	// hwEpsilon is the difference bound for the minimization of the error.
+	hwEpsilon = 1.0e-4
+
+	// The initial guesses of alpha, beta, gamma and phi are taken from a grid
+	// between the lower and upper bound. The grid has N^4 points so it is small.
+	hwGuessLower = 0.3
+	hwGuessUpper = 1.0
+	hwGuessStep  = 0.4
+)
+
+// HoltWintersOpSpec forecasts the values of a column n intervals into the future
+// with the damped Holt-Winters method, in the same way as InfluxDB 1.x does.
+// The rows of each table are expected to be sorted by time and are placed on
+// a grid of the interval; a value is missing from an interval without a row.
+// When seasonality is at least 2, the values are seasonal with that many
+// intervals in a season. With withFit, the values that the model fits to the
+// existing rows are produced before the forecast values.
+// The tables only keep the group key, the time and the forecast value.
+type HoltWintersOpSpec struct {
+	N           int64         `json:"n"`
+	Seasonality int64         `json:"seasonality"`
+	Interval    flux.Duration `json:"interval"`
+	WithFit     bool          `json:"withFit"`
+	TimeColumn  string        `json:"timeColumn"`
+	Column      string        `json:"column"`
+}
+
+func init() {
+	holtWintersSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"n":           semantic.Int,
+			"seasonality": semantic.Int,
+			"interval":    semantic.Duration,
+			"withFit":     semantic.Bool,
+			"timeColumn":  semantic.String,
+			"column":      semantic.String,
+		},
+		[]string{"n", "interval"},
+	)
+
+	flux.RegisterPackageValue(PackagePath, "holtWinters", flux.FunctionValue(HoltWintersKind, createHoltWintersOpSpec, holtWintersSignature))
+	flux.RegisterOpSpec(HoltWintersKind, newHoltWintersOp)
+	plan.RegisterProcedureSpec(HoltWintersKind, newHoltWintersProcedure, HoltWintersKind)
+	execute.RegisterTransformation(HoltWintersKind, createHoltWintersTransformation)
+}
+
+func createHoltWintersOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := new(HoltWintersOpSpec)
+	n, err := args.GetRequiredInt("n")
+	if err != nil {
+		return nil, err
+	} else if n <= 0 {
+		return nil, fmt.Errorf("holt winters forecast must be greater than 0, got %d", n)
+	}
+	spec.N = n
+
+	interval, err := args.GetRequiredDuration("interval")
+	if err != nil {
+		return nil, err
+	} else if interval <= 0 {
+		return nil, fmt.Errorf("holt winters interval must be positive, got %v", interval)
+	}
+	spec.Interval = interval
+
+	if seasonality, ok, err := args.GetInt("seasonality"); err != nil {
+		return nil, err
+	} else if ok {
+		if seasonality < 0 {
+			return nil, fmt.Errorf("holt winters seasonality cannot be negative, got %d", seasonality)
+		}
+		spec.Seasonality = seasonality
+	}
+
+	if withFit, ok, err := args.GetBool("withFit"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.WithFit = withFit
+	}
+
+	if col, ok, err := args.GetString("timeColumn"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.TimeColumn = col
+	} else {
+		spec.TimeColumn = execute.DefaultTimeColLabel
+	}
+
+	if col, ok, err := args.GetString("column"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Column = col
+	} else {
+		spec.Column = execute.DefaultValueColLabel
+	}
+	return spec, nil
+}
+
+func newHoltWintersOp() flux.OperationSpec {
+	return new(HoltWintersOpSpec)
+}
+
+func (s *HoltWintersOpSpec) Kind() flux.OperationKind {
+	return HoltWintersKind
+}
+
+type HoltWintersProcedureSpec struct {
+	plan.DefaultCost
+	N           int64
+	Seasonality int64
+	Interval    time.Duration
+	WithFit     bool
+	TimeColumn  string
+	Column      string
+}
+
+func newHoltWintersProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*HoltWintersOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &HoltWintersProcedureSpec{
+		N:           spec.N,
+		Seasonality: spec.Seasonality,
+		Interval:    time.Duration(spec.Interval),
+		WithFit:     spec.WithFit,
+		TimeColumn:  spec.TimeColumn,
+		Column:      spec.Column,
+	}, nil
+}
+
+func (s *HoltWintersProcedureSpec) Kind() plan.ProcedureKind {
+	return HoltWintersKind
+}
+
+func (s *HoltWintersProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(HoltWintersProcedureSpec)
+	*ns = *s
+	return ns
+}
+
+func createHoltWintersTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*HoltWintersProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewHoltWintersTransformation(d, cache, s)
+	return t, d, nil
+}
+
+type holtWintersTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	spec  HoltWintersProcedureSpec
+}
+
+func NewHoltWintersTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *HoltWintersProcedureSpec) *holtWintersTransformation {
+	return &holtWintersTransformation{
+		d:     d,
+		cache: cache,
+		spec:  *spec,
+	}
+}
+
+func (t *holtWintersTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *holtWintersTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("holt winters found duplicate table with key: %v", tbl.Key())
+	}
+	if err := execute.AddTableKeyCols(tbl.Key(), builder); err != nil {
+		return err
+	}
+
+	cols := tbl.Cols()
+	timeIdx := execute.ColIdx(t.spec.TimeColumn, cols)
+	if timeIdx < 0 {
+		return fmt.Errorf("column %q does not exist", t.spec.TimeColumn)
+	} else if cols[timeIdx].Type != flux.TTime {
+		return fmt.Errorf("column %q is not a time, got %v", t.spec.TimeColumn, cols[timeIdx].Type)
+	}
+	valueIdx := execute.ColIdx(t.spec.Column, cols)
+	if valueIdx < 0 {
+		return fmt.Errorf("column %q does not exist", t.spec.Column)
+	}
+	switch cols[valueIdx].Type {
+	case flux.TFloat, flux.TInt, flux.TUInt:
+	default:
+		return fmt.Errorf("cannot forecast the values of %v", cols[valueIdx].Type)
+	}
+	if tbl.Key().HasCol(t.spec.TimeColumn) || tbl.Key().HasCol(t.spec.Column) {
+		return fmt.Errorf("cannot forecast columns that are part of the group key")
+	}
+
+	timeCol, err := builder.AddCol(flux.ColMeta{Label: t.spec.TimeColumn, Type: flux.TTime})
+	if err != nil {
+		return err
+	}
+	valueCol, err := builder.AddCol(flux.ColMeta{Label: t.spec.Column, Type: flux.TFloat})
+	if err != nil {
+		return err
+	}
+
+	hw := newHoltWinters(int(t.spec.N), int(t.spec.Seasonality), t.spec.WithFit, t.spec.Interval)
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		times := cr.Times(timeIdx)
+		for i, l := 0, cr.Len(); i < l; i++ {
+			if times.IsNull(i) {
+				continue
+			}
+			switch cols[valueIdx].Type {
+			case flux.TFloat:
+				vs := cr.Floats(valueIdx)
+				if vs.IsValid(i) {
+					hw.add(times.Value(i), vs.Value(i))
+				}
+			case flux.TInt:
+				vs := cr.Ints(valueIdx)
+				if vs.IsValid(i) {
+					hw.add(times.Value(i), float64(vs.Value(i)))
+				}
+			case flux.TUInt:
+				vs := cr.UInts(valueIdx)
+				if vs.IsValid(i) {
+					hw.add(times.Value(i), float64(vs.Value(i)))
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, p := range hw.forecastPoints() {
+		if err := execute.AppendKeyValues(tbl.Key(), builder); err != nil {
+			return err
+		}
+		if err := builder.AppendTime(timeCol, execute.Time(p.time)); err != nil {
+			return err
+		}
+		if err := builder.AppendFloat(valueCol, p.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *holtWintersTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+func (t *holtWintersTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+func (t *holtWintersTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+type hwPoint struct {
+	time  int64
+	value float64
+}
+
+// holtWinters forecasts a series into the future with the damped Holt-Winters method.
+// The initial values are fitted to the series by minimizing the sum of the squared errors
+// and the series is then forecast with the iterative relations of the method.
+type holtWinters struct {
+	// h is the number of intervals to forecast.
+	h int
+	// m is the number of intervals in a season.
+	m        int
+	seasonal bool
+	withFit  bool
+
+	interval     int64
+	halfInterval int64
+
+	points []hwPoint
+	// y holds the values on the grid of the interval with NaN for a missing value.
+	y []float64
+}
+
+func newHoltWinters(h, m int, withFit bool, interval time.Duration) *holtWinters {
+	return &holtWinters{
+		h:            h,
+		m:            m,
+		seasonal:     m >= 2,
+		withFit:      withFit,
+		interval:     int64(interval),
+		halfInterval: int64(interval) / 2,
+	}
+}
+
+func (r *holtWinters) add(t int64, v float64) {
+	r.points = append(r.points, hwPoint{time: t, value: v})
+}
+
+// roundTime rounds t to the nearest multiple of the interval.
+func (r *holtWinters) roundTime(t int64) int64 {
+	if remainder := t % r.interval; remainder > r.halfInterval {
+		return (t/r.interval + 1) * r.interval
+	}
+	return (t / r.interval) * r.interval
+}
+
+// forecastPoints returns the points forecast from the points that were added.
+func (r *holtWinters) forecastPoints() []hwPoint {
+	if l := len(r.points); l < 2 || r.seasonal && l < r.m || r.h <= 0 {
+		return nil
+	}
+
+	// Place the values on the grid of the interval and fill the missing values with NaN.
+	start, stop := r.roundTime(r.points[0].time), r.roundTime(r.points[len(r.points)-1].time)
+	count := (stop - start) / r.interval
+	if count <= 0 {
+		return nil
+	}
+	r.y = make([]float64, 1, count)
+	r.y[0] = r.points[0].value
+	t := start
+	for _, p := range r.points[1:] {
+		rounded := r.roundTime(p.time)
+		if rounded <= t {
+			// Drop the values that fall into the same interval.
+			continue
+		}
+		t += r.interval
+		for rounded != t {
+			r.y = append(r.y, math.NaN())
+			t += r.interval
+		}
+		r.y = append(r.y, p.value)
+	}
+
+	// The starting guesses skip the missing values.
+	m := r.m
+	l0 := 0.0
+	if r.seasonal {
+		for i := 0; i < m; i++ {
+			if !math.IsNaN(r.y[i]) {
+				l0 += (1 / float64(m)) * r.y[i]
+			}
+		}
+	} else {
+		l0 += hwWeight * r.y[0]
+	}
+
+	b0 := 0.0
+	if r.seasonal {
+		for i := 0; i < m && m+i < len(r.y); i++ {
+			if !math.IsNaN(r.y[i]) && !math.IsNaN(r.y[m+i]) {
+				b0 += 1 / float64(m*m) * (r.y[m+i] - r.y[i])
+			}
+		}
+	} else if !math.IsNaN(r.y[1]) {
+		b0 = hwWeight * (r.y[1] - r.y[0])
+	}
+
+	var s []float64
+	if r.seasonal {
+		s = make([]float64, m)
+		for i := 0; i < m; i++ {
+			if !math.IsNaN(r.y[i]) {
+				s[i] = r.y[i] / l0
+			}
+		}
+	}
+
+	// The parameters are alpha, beta, gamma, phi, the initial level, the initial trend
+	// and the initial seasonal values.
+	parameters := make([]float64, 6+len(s))
+	parameters[4] = l0
+	parameters[5] = b0
+	copy(parameters[6:], s)
+
+	minSSE := math.Inf(1)
+	var bestParams []float64
+	for alpha := hwGuessLower; alpha < hwGuessUpper; alpha += hwGuessStep {
+		for beta := hwGuessLower; beta < hwGuessUpper; beta += hwGuessStep {
+			for gamma := hwGuessLower; gamma < hwGuessUpper; gamma += hwGuessStep {
+				for phi := hwGuessLower; phi < hwGuessUpper; phi += hwGuessStep {
+					parameters[0] = alpha
+					parameters[1] = beta
+					parameters[2] = gamma
+					parameters[3] = phi
+					sse, params := nelderMead(r.sse, parameters, hwEpsilon, 1)
+					if sse < minSSE || bestParams == nil {
+						minSSE = sse
+						bestParams = params
+					}
+				}
+			}
+		}
+	}
+
+	forecasted := r.forecast(r.h, bestParams)
+	var points []hwPoint
+	if r.withFit {
+		start := r.points[0].time
+		points = make([]hwPoint, 0, len(forecasted))
+		for i, v := range forecasted {
+			if !math.IsNaN(v) {
+				points = append(points, hwPoint{time: start + r.interval*int64(i), value: v})
+			}
+		}
+	} else {
+		stop := r.points[len(r.points)-1].time
+		points = make([]hwPoint, 0, r.h)
+		for i, v := range forecasted[len(r.y):] {
+			if !math.IsNaN(v) {
+				points = append(points, hwPoint{time: stop + r.interval*int64(i+1), value: v})
+			}
+		}
+	}
+	return points
+}
+
+// next computes the next forecast value, level, trend and seasonal value with the recursive relations.
+func (r *holtWinters) next(alpha, beta, gamma, phi, phiH, yT, lTp, bTp, sTm, sTmh float64) (yTh, lT, bT, sT float64) {
+	lT = alpha*(yT/sTm) + (1-alpha)*(lTp+phi*bTp)
+	bT = beta*(lT-lTp) + (1-beta)*phi*bTp
+	sT = gamma*(yT/(lTp+phi*bTp)) + (1-gamma)*sTm
+	yTh = (lT + phiH*bT) * sTmh
+	return
+}
+
+// forecast returns the values of the series with the parameters followed by h forecast values.
+func (r *holtWinters) forecast(h int, params []float64) []float64 {
+	r.constrain(params)
+
+	yT := r.y[0]
+	phi := params[3]
+	phiH := phi
+	lT := params[4]
+	bT := params[5]
+
+	// The seasonal values are a ring buffer of the past seasonal values.
+	var seasonals []float64
+	var m, so int
+	if r.seasonal {
+		seasonals = make([]float64, len(params[6:]))
+		copy(seasonals, params[6:])
+		m = len(seasonals)
+		if m == 1 {
+			seasonals[0] = 1
+		}
+		// so is the offset of the season index.
+		so = m - 1
+	}
+
+	forecasted := make([]float64, len(r.y)+h)
+	forecasted[0] = yT
+	l := len(r.y)
+	stm, stmh := 1.0, 1.0
+	for t := 1; t < l+h; t++ {
+		if r.seasonal {
+			hm := t % m
+			stm = seasonals[(t-m+so)%m]
+			stmh = seasonals[(t-m+hm+so)%m]
+		}
+		var sT float64
+		yT, lT, bT, sT = r.next(params[0], params[1], params[2], phi, phiH, yT, lT, bT, stm, stmh)
+		phiH += math.Pow(phi, float64(t))
+
+		if r.seasonal {
+			seasonals[(t+so)%m] = sT
+			so++
+		}
+		forecasted[t] = yT
+	}
+	return forecasted
+}
+
+// sse computes the sum of the squared errors of the series with the parameters.
+func (r *holtWinters) sse(params []float64) float64 {
+	sse := 0.0
+	forecasted := r.forecast(0, params)
+	for i := range forecasted {
+		// A missing value cannot be used to compute the error.
+		if math.IsNaN(r.y[i]) {
+			continue
+		}
+		if math.IsNaN(forecasted[i]) {
+			// Penalize the parameters that forecast NaN.
+			return math.Inf(1)
+		}
+		diff := forecasted[i] - r.y[i]
+		sse += diff * diff
+	}
+	return sse
+}
+
+// constrain limits alpha, beta, gamma and phi to the range [0, 1].
+func (r *holtWinters) constrain(x []float64) {
+	for i := 0; i < 4; i++ {
+		if x[i] > 1 {
+			x[i] = 1
+		} else if x[i] < 0 {
+			x[i] = 0
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package influxql_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+)
+
+func TestHoltWinters_Process(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *influxql.HoltWintersProcedureSpec
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "constant",
+			spec: &influxql.HoltWintersProcedureSpec{
+				N:          3,
+				Interval:   time.Second,
+				TimeColumn: execute.DefaultTimeColLabel,
+				Column:     execute.DefaultValueColLabel,
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TInt},
+					{Label: "t0", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), int64(5), "a"},
+					{execute.Time(1 * time.Second), int64(5), "a"},
+					{execute.Time(2 * time.Second), nil, "a"},
+					{execute.Time(3 * time.Second), int64(5), "a"},
+					{execute.Time(4 * time.Second), int64(5), "a"},
+				},
+			}},
+			want: []*executetest.Table{{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "t0", Type: flux.TString},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{"a", execute.Time(5 * time.Second), 5.0},
+					{"a", execute.Time(6 * time.Second), 5.0},
+					{"a", execute.Time(7 * time.Second), 5.0},
+				},
+			}},
+		},
+		{
+			name: "fewer than two values",
+			spec: &influxql.HoltWintersProcedureSpec{
+				N:          3,
+				Interval:   time.Second,
+				TimeColumn: execute.DefaultTimeColLabel,
+				Column:     execute.DefaultValueColLabel,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), 1.0},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					return influxql.NewHoltWintersTransformation(d, c, tc.spec)
+				},
+			)
+		})
+	}
+}
+
+func TestHoltWinters_Seasonal(t *testing.T) {
+	season := []float64{1, 3, 5, 3}
+	data := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TFloat},
+		},
+	}
+	for i := 0; i < 3*len(season); i++ {
+		data.Data = append(data.Data, []interface{}{execute.Time(time.Duration(i) * time.Minute), season[i%len(season)]})
+	}
+
+	for _, withFit := range []bool{false, true} {
+		d := executetest.NewDataset(executetest.RandomDatasetID())
+		c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+		c.SetTriggerSpec(execute.DefaultTriggerSpec)
+		tx := influxql.NewHoltWintersTransformation(d, c, &influxql.HoltWintersProcedureSpec{
+			N:           4,
+			Seasonality: int64(len(season)),
+			Interval:    time.Minute,
+			WithFit:     withFit,
+			TimeColumn:  execute.DefaultTimeColLabel,
+			Column:      execute.DefaultValueColLabel,
+		})
+		if err := tx.Process(executetest.RandomDatasetID(), data); err != nil {
+			t.Fatal(err)
+		}
+		got, err := executetest.TablesFromCache(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The fitted values follow the rows of the table and the forecast values follow the fitted values.
+		first := len(data.Data)
+		if withFit {
+			first = 0
+		}
+		if want := len(data.Data) + 4 - first; len(got) != 1 || len(got[0].Data) != want {
+			t.Fatalf("withFit=%v: expected %d rows, got %v", withFit, want, got)
+		}
+		for i, row := range got[0].Data {
+			if got, want := row[0].(execute.Time), execute.Time(time.Duration(first+i)*time.Minute); got != want {
+				t.Errorf("withFit=%v: unexpected time at row %d: got=%v want=%v", withFit, i, got, want)
+			}
+			if got, want := row[1].(float64), season[(first+i)%len(season)]; math.Abs(got-want) > 0.01 {
+				t.Errorf("withFit=%v: unexpected value at row %d: got=%v want=%v", withFit, i, got, want)
+			}
+		}
+	}
+}
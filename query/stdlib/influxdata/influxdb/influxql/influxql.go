@@ -0,0 +1,61 @@
+// Package influxql implements the Flux functions that are needed to execute
+// InfluxQL queries with the semantics of InfluxQL but have no equivalent
+// in the standard library of Flux.
+package influxql
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// PackagePath is the import path of the package in Flux.
+const PackagePath = "influxdata/influxdb/influxql"
+
+const source = `package influxql
+
+// Transformation functions
+builtin holtWinters
+builtin mode
+builtin movingAverage
+builtin sample
+
+// Row functions
+builtin map
+
+// Schema functions
+builtin tags
+builtin seriesKey
+builtin fieldType
+
+// Math functions
+builtin abs
+builtin acos
+builtin asin
+builtin atan
+builtin atan2
+builtin ceil
+builtin cos
+builtin exp
+builtin floor
+builtin ln
+builtin log
+builtin log10
+builtin log2
+builtin pow
+builtin round
+builtin sin
+builtin sqrt
+builtin tan
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	if ast.Check(pkg) > 0 {
+		panic(fmt.Errorf("failed to parse the %s package: %v", PackagePath, ast.GetError(pkg)))
+	}
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+}
@@ -0,0 +1,350 @@
+package influxql
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/compiler"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const MapKind = "influxqlMap"
+
+// MapOpSpec applies a function to each row of a table like the map function
+// of the standard library. The function is also able to call the math
+// functions of this package through the influxql identifier, which is needed
+// because the map function of the standard library only sees the prelude.
+// The group key of a table is always kept.
+type MapOpSpec struct {
+	Fn *semantic.FunctionExpression `json:"fn"`
+}
+
+func init() {
+	mapSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"fn": semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+				Parameters: map[string]semantic.PolyType{
+					"r": semantic.Tvar(1),
+				},
+				Required: semantic.LabelSet{"r"},
+				Return:   semantic.Tvar(2),
+			}),
+		},
+		[]string{"fn"},
+	)
+
+	flux.RegisterPackageValue(PackagePath, "map", mapFunction{
+		function: flux.FunctionValue(MapKind, createMapOpSpec, mapSignature).Function(),
+	})
+	flux.RegisterOpSpec(MapKind, newMapOp)
+	plan.RegisterProcedureSpec(MapKind, newMapProcedure, MapKind)
+	execute.RegisterTransformation(MapKind, createMapTransformation)
+}
+
+// mapFunction reports an invalid type for the polymorphic map function instead
+// of no type. The type of the package is computed from the types of its
+// values when it is imported and that fails for a value without a type.
+type mapFunction struct {
+	function
+}
+
+type function = values.Function
+
+func (f mapFunction) Type() semantic.Type {
+	return semantic.Invalid
+}
+
+func createMapOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	f, err := args.GetRequiredFunction("fn")
+	if err != nil {
+		return nil, err
+	}
+	fn, err := interpreter.ResolveFunction(f)
+	if err != nil {
+		return nil, err
+	}
+	return &MapOpSpec{Fn: fn}, nil
+}
+
+func newMapOp() flux.OperationSpec {
+	return new(MapOpSpec)
+}
+
+func (s *MapOpSpec) Kind() flux.OperationKind {
+	return MapKind
+}
+
+type MapProcedureSpec struct {
+	plan.DefaultCost
+	Fn *semantic.FunctionExpression
+}
+
+func newMapProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*MapOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &MapProcedureSpec{
+		Fn: spec.Fn,
+	}, nil
+}
+
+func (s *MapProcedureSpec) Kind() plan.ProcedureKind {
+	return MapKind
+}
+
+func (s *MapProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(MapProcedureSpec)
+	*ns = *s
+	ns.Fn = s.Fn.Copy().(*semantic.FunctionExpression)
+	return ns
+}
+
+func createMapTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*MapProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t, err := NewMapTransformation(d, cache, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, d, nil
+}
+
+type mapTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+
+	fn *rowMapFn
+}
+
+func NewMapTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *MapProcedureSpec) (*mapTransformation, error) {
+	fn, err := newRowMapFn(spec.Fn)
+	if err != nil {
+		return nil, err
+	}
+	return &mapTransformation{
+		d:     d,
+		cache: cache,
+		fn:    fn,
+	}, nil
+}
+
+func (t *mapTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *mapTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	cols := tbl.Cols()
+	if err := t.fn.prepare(cols); err != nil {
+		return err
+	}
+	// Determine the columns returned by the function in sorted order.
+	properties := t.fn.typ().Properties()
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return tbl.Do(func(cr flux.ColReader) error {
+		for i, l := 0, cr.Len(); i < l; i++ {
+			m, err := t.fn.eval(i, cr)
+			if err != nil {
+				log.Printf("failed to evaluate map expression: %v", err)
+				continue
+			}
+			key := groupKeyForObject(i, cr, m, tbl.Key())
+			builder, created := t.cache.TableBuilder(key)
+			if created {
+				if err := execute.AddTableKeyCols(tbl.Key(), builder); err != nil {
+					return err
+				}
+				for _, k := range keys {
+					if tbl.Key().HasCol(k) {
+						continue
+					}
+					if _, err := builder.AddCol(flux.ColMeta{
+						Label: k,
+						Type:  execute.ConvertFromKind(properties[k].Nature()),
+					}); err != nil {
+						return err
+					}
+				}
+			}
+			for j, c := range builder.Cols() {
+				v, ok := m.Get(c.Label)
+				if !ok {
+					idx := execute.ColIdx(c.Label, tbl.Key().Cols())
+					if idx < 0 {
+						return fmt.Errorf("could not find value for column %q", c.Label)
+					}
+					v = tbl.Key().Value(idx)
+				}
+				if err := builder.AppendValue(j, v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// groupKeyForObject returns the group key of the row after the function was
+// applied. A key column that is returned by the function takes the new value.
+func groupKeyForObject(i int, cr flux.ColReader, obj values.Object, key flux.GroupKey) flux.GroupKey {
+	cols := make([]flux.ColMeta, 0, len(key.Cols()))
+	vs := make([]values.Value, 0, len(key.Cols()))
+	for j, c := range cr.Cols() {
+		if !key.HasCol(c.Label) {
+			continue
+		}
+		cols = append(cols, c)
+		if v, ok := obj.Get(c.Label); ok {
+			vs = append(vs, v)
+		} else {
+			vs = append(vs, execute.ValueForRow(cr, i, j))
+		}
+	}
+	return execute.NewGroupKey(cols, vs)
+}
+
+func (t *mapTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+func (t *mapTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+func (t *mapTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// rowMapFn compiles and evaluates the function of map for each row. It is the
+// same as the row functions of the execute package except that the scope of
+// the function includes the math functions of this package.
+type rowMapFn struct {
+	compilationCache *compiler.CompilationCache
+	preparedFn       compiler.Func
+
+	inRecord   values.Object
+	recordName string
+	record     *execute.Record
+	recordCols map[string]int
+	references []string
+
+	wrapObj *execute.Record
+}
+
+func newRowMapFn(fn *semantic.FunctionExpression) (*rowMapFn, error) {
+	if fn.Block.Parameters == nil || len(fn.Block.Parameters.List) != 1 {
+		return nil, errors.New("function should only have a single parameter")
+	}
+	scope := flux.BuiltIns()
+	scope["influxql"] = values.NewObjectWithValues(mathFunctions)
+
+	recordName := fn.Block.Parameters.List[0].Key.Name
+	return &rowMapFn{
+		compilationCache: compiler.NewCompilationCache(fn, scope),
+		inRecord:         values.NewObject(),
+		recordName:       recordName,
+		recordCols:       make(map[string]int),
+		references:       columnReferences(fn, recordName),
+	}, nil
+}
+
+func (f *rowMapFn) prepare(cols []flux.ColMeta) error {
+	propertyTypes := make(map[string]semantic.Type, len(f.references))
+	for _, r := range f.references {
+		j := execute.ColIdx(r, cols)
+		if j < 0 {
+			return fmt.Errorf("function references unknown column %q", r)
+		}
+		f.recordCols[r] = j
+		propertyTypes[r] = execute.ConvertToKind(cols[j].Type)
+	}
+	f.record = execute.NewRecord(semantic.NewObjectType(propertyTypes))
+	fn, err := f.compilationCache.Compile(
+		semantic.NewObjectType(map[string]semantic.Type{
+			f.recordName: f.record.Type(),
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	f.preparedFn = fn
+
+	// A function that does not return an object sets the value column.
+	f.wrapObj = nil
+	if t := fn.Type(); t.Nature() != semantic.Object {
+		f.wrapObj = execute.NewRecord(semantic.NewObjectType(map[string]semantic.Type{
+			execute.DefaultValueColLabel: t,
+		}))
+	}
+	return nil
+}
+
+func (f *rowMapFn) typ() semantic.Type {
+	if f.wrapObj != nil {
+		return f.wrapObj.Type()
+	}
+	return f.preparedFn.Type()
+}
+
+func (f *rowMapFn) eval(i int, cr flux.ColReader) (values.Object, error) {
+	for _, r := range f.references {
+		v := execute.ValueForRow(cr, i, f.recordCols[r])
+		if v.IsNull() {
+			return nil, errors.New("null reference used in row function: skipping evaluation until null support is provided")
+		}
+		f.record.Set(r, v)
+	}
+	f.inRecord.Set(f.recordName, f.record)
+	v, err := f.preparedFn.Eval(f.inRecord)
+	if err != nil {
+		return nil, err
+	}
+	if f.wrapObj != nil {
+		f.wrapObj.Set(execute.DefaultValueColLabel, v)
+		return f.wrapObj, nil
+	}
+	return v.Object(), nil
+}
+
+// columnReferences returns the columns of the record that are referenced by
+// the function.
+func columnReferences(fn *semantic.FunctionExpression, recordName string) []string {
+	v := &columnReferenceVisitor{recordName: recordName}
+	semantic.Walk(v, fn)
+	return v.refs
+}
+
+type columnReferenceVisitor struct {
+	recordName string
+	refs       []string
+}
+
+func (v *columnReferenceVisitor) Visit(node semantic.Node) semantic.Visitor {
+	if me, ok := node.(*semantic.MemberExpression); ok {
+		if obj, ok := me.Object.(*semantic.IdentifierExpression); ok && obj.Name == v.recordName {
+			v.refs = append(v.refs, me.Property)
+		}
+	}
+	return v
+}
+
+func (v *columnReferenceVisitor) Done(semantic.Node) {}
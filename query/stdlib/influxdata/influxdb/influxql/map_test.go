@@ -0,0 +1,147 @@
+package influxql_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/semantic"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+)
+
+// mathCall returns the call of a math function of the influxql package with
+// the value column as the argument.
+func mathCall(name string) *semantic.CallExpression {
+	return &semantic.CallExpression{
+		Callee: &semantic.MemberExpression{
+			Object:   &semantic.IdentifierExpression{Name: "influxql"},
+			Property: name,
+		},
+		Arguments: &semantic.ObjectExpression{
+			Properties: []*semantic.Property{{
+				Key: &semantic.Identifier{Name: "x"},
+				Value: &semantic.MemberExpression{
+					Object:   &semantic.IdentifierExpression{Name: "r"},
+					Property: "_value",
+				},
+			}},
+		},
+	}
+}
+
+func mapFn(value semantic.Expression) *semantic.FunctionExpression {
+	return &semantic.FunctionExpression{
+		Block: &semantic.FunctionBlock{
+			Parameters: &semantic.FunctionParameters{
+				List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
+			},
+			Body: &semantic.ObjectExpression{
+				Properties: []*semantic.Property{
+					{
+						Key: &semantic.Identifier{Name: "_time"},
+						Value: &semantic.MemberExpression{
+							Object:   &semantic.IdentifierExpression{Name: "r"},
+							Property: "_time",
+						},
+					},
+					{
+						Key:   &semantic.Identifier{Name: "_value"},
+						Value: value,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMap_Process(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *influxql.MapProcedureSpec
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "abs of an integer keeps the group key",
+			spec: &influxql.MapProcedureSpec{
+				Fn: mapFn(mathCall("abs")),
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TInt},
+					{Label: "t0", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), int64(-2), "a"},
+					{execute.Time(2), nil, "a"},
+					{execute.Time(3), int64(5), "a"},
+				},
+			}},
+			want: []*executetest.Table{{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "t0", Type: flux.TString},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TInt},
+				},
+				Data: [][]interface{}{
+					{"a", execute.Time(1), int64(2)},
+					{"a", execute.Time(3), int64(5)},
+				},
+			}},
+		},
+		{
+			name: "sqrt within an expression",
+			spec: &influxql.MapProcedureSpec{
+				Fn: mapFn(&semantic.BinaryExpression{
+					Operator: ast.MultiplicationOperator,
+					Left:     mathCall("sqrt"),
+					Right:    &semantic.FloatLiteral{Value: 2},
+				}),
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 4.0},
+					{execute.Time(2), 9.0},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 4.0},
+					{execute.Time(2), 6.0},
+				},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					f, err := influxql.NewMapTransformation(d, c, tc.spec)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return f
+				},
+			)
+		})
+	}
+}
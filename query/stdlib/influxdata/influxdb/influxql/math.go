@@ -0,0 +1,149 @@
+package influxql
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// mathFunctions holds the math functions of the package. They are also made
+// available to the function passed to map, which cannot see imported packages.
+var mathFunctions = map[string]values.Value{
+	// The functions that return an integer for an integer argument.
+	"abs":   numericFunction("abs", math.Abs, absInt),
+	"ceil":  numericFunction("ceil", math.Ceil, nil),
+	"floor": numericFunction("floor", math.Floor, nil),
+	"round": numericFunction("round", math.Round, nil),
+
+	// The functions that always return a float.
+	"acos":  floatFunction("acos", math.Acos),
+	"asin":  floatFunction("asin", math.Asin),
+	"atan":  floatFunction("atan", math.Atan),
+	"cos":   floatFunction("cos", math.Cos),
+	"exp":   floatFunction("exp", math.Exp),
+	"ln":    floatFunction("ln", math.Log),
+	"log10": floatFunction("log10", math.Log10),
+	"log2":  floatFunction("log2", math.Log2),
+	"sin":   floatFunction("sin", math.Sin),
+	"sqrt":  floatFunction("sqrt", math.Sqrt),
+	"tan":   floatFunction("tan", math.Tan),
+
+	// The functions with two arguments.
+	"atan2": binaryFloatFunction("atan2", "y", "x", math.Atan2),
+	"log": binaryFloatFunction("log", "x", "b", func(x, b float64) float64 {
+		return math.Log(x) / math.Log(b)
+	}),
+	"pow": binaryFloatFunction("pow", "x", "y", math.Pow),
+}
+
+func init() {
+	for name, fn := range mathFunctions {
+		flux.RegisterPackageValue(PackagePath, name, fn)
+	}
+}
+
+func absInt(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// numericFunction creates a function of one argument that returns a value of the
+// same type as the argument. An integer is passed to intFn or returned unchanged
+// if intFn is nil.
+func numericFunction(name string, floatFn func(float64) float64, intFn func(int64) int64) values.Function {
+	return values.NewFunction(
+		name,
+		semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: map[string]semantic.PolyType{"x": semantic.Tvar(1)},
+			Required:   semantic.LabelSet{"x"},
+			Return:     semantic.Tvar(1),
+		}),
+		func(args values.Object) (values.Value, error) {
+			v, ok := args.Get("x")
+			if !ok {
+				return nil, fmt.Errorf("missing argument %q", "x")
+			}
+			switch v.Type().Nature() {
+			case semantic.Float:
+				return values.NewFloat(floatFn(v.Float())), nil
+			case semantic.Int:
+				if intFn == nil {
+					return v, nil
+				}
+				return values.NewInt(intFn(v.Int())), nil
+			case semantic.UInt:
+				return v, nil
+			default:
+				return nil, fmt.Errorf("cannot call %s with an argument of type %v", name, v.Type())
+			}
+		}, false,
+	)
+}
+
+// floatFunction creates a function of one numeric argument that returns a float.
+func floatFunction(name string, fn func(float64) float64) values.Function {
+	return values.NewFunction(
+		name,
+		semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: map[string]semantic.PolyType{"x": semantic.Tvar(1)},
+			Required:   semantic.LabelSet{"x"},
+			Return:     semantic.Float,
+		}),
+		func(args values.Object) (values.Value, error) {
+			x, err := floatArg(name, args, "x")
+			if err != nil {
+				return nil, err
+			}
+			return values.NewFloat(fn(x)), nil
+		}, false,
+	)
+}
+
+// binaryFloatFunction creates a function of two numeric arguments that returns a float.
+func binaryFloatFunction(name, a, b string, fn func(float64, float64) float64) values.Function {
+	return values.NewFunction(
+		name,
+		semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: map[string]semantic.PolyType{
+				a: semantic.Tvar(1),
+				b: semantic.Tvar(2),
+			},
+			Required: semantic.LabelSet{a, b},
+			Return:   semantic.Float,
+		}),
+		func(args values.Object) (values.Value, error) {
+			x, err := floatArg(name, args, a)
+			if err != nil {
+				return nil, err
+			}
+			y, err := floatArg(name, args, b)
+			if err != nil {
+				return nil, err
+			}
+			return values.NewFloat(fn(x, y)), nil
+		}, false,
+	)
+}
+
+// floatArg reads a numeric argument as a float.
+func floatArg(name string, args values.Object, arg string) (float64, error) {
+	v, ok := args.Get(arg)
+	if !ok {
+		return 0, fmt.Errorf("missing argument %q", arg)
+	}
+	switch v.Type().Nature() {
+	case semantic.Float:
+		return v.Float(), nil
+	case semantic.Int:
+		return float64(v.Int()), nil
+	case semantic.UInt:
+		return float64(v.UInt()), nil
+	default:
+		return 0, fmt.Errorf("cannot call %s with an argument of type %v", name, v.Type())
+	}
+}
@@ -0,0 +1,220 @@
+package influxql
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const ModeKind = "influxqlMode"
+
+// ModeOpSpec selects the most frequent value of a column in each table.
+// When more than one value is the most frequent, the smallest of them is
+// selected. Null values are not counted.
+type ModeOpSpec struct {
+	Column string `json:"column"`
+}
+
+func init() {
+	modeSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"column": semantic.String,
+		},
+		nil,
+	)
+
+	flux.RegisterPackageValue(PackagePath, "mode", flux.FunctionValue(ModeKind, createModeOpSpec, modeSignature))
+	flux.RegisterOpSpec(ModeKind, newModeOp)
+	plan.RegisterProcedureSpec(ModeKind, newModeProcedure, ModeKind)
+	execute.RegisterTransformation(ModeKind, createModeTransformation)
+}
+
+func createModeOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := new(ModeOpSpec)
+	if col, ok, err := args.GetString("column"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Column = col
+	} else {
+		spec.Column = execute.DefaultValueColLabel
+	}
+	return spec, nil
+}
+
+func newModeOp() flux.OperationSpec {
+	return new(ModeOpSpec)
+}
+
+func (s *ModeOpSpec) Kind() flux.OperationKind {
+	return ModeKind
+}
+
+type ModeProcedureSpec struct {
+	plan.DefaultCost
+	Column string
+}
+
+func newModeProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ModeOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &ModeProcedureSpec{
+		Column: spec.Column,
+	}, nil
+}
+
+func (s *ModeProcedureSpec) Kind() plan.ProcedureKind {
+	return ModeKind
+}
+
+func (s *ModeProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(ModeProcedureSpec)
+	*ns = *s
+	return ns
+}
+
+func createModeTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ModeProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewModeTransformation(d, cache, s)
+	return t, d, nil
+}
+
+type modeTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	spec  ModeProcedureSpec
+}
+
+func NewModeTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *ModeProcedureSpec) *modeTransformation {
+	return &modeTransformation{
+		d:     d,
+		cache: cache,
+		spec:  *spec,
+	}
+}
+
+func (t *modeTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *modeTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("mode found duplicate table with key: %v", tbl.Key())
+	}
+	if err := execute.AddTableKeyCols(tbl.Key(), builder); err != nil {
+		return err
+	}
+
+	cols := tbl.Cols()
+	valueIdx := execute.ColIdx(t.spec.Column, cols)
+	if valueIdx < 0 {
+		return fmt.Errorf("column %q does not exist", t.spec.Column)
+	} else if tbl.Key().HasCol(t.spec.Column) {
+		return fmt.Errorf("cannot aggregate columns that are part of the group key")
+	}
+	j, err := builder.AddCol(cols[valueIdx])
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[interface{}]int)
+	var distinct []values.Value
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		for i, l := 0, cr.Len(); i < l; i++ {
+			v := execute.ValueForRow(cr, i, valueIdx)
+			if v.IsNull() {
+				continue
+			}
+			k := modeKey(v)
+			if counts[k] == 0 {
+				distinct = append(distinct, v)
+			}
+			counts[k]++
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(distinct) == 0 {
+		return nil
+	}
+
+	mode := distinct[0]
+	for _, v := range distinct[1:] {
+		if n, m := counts[modeKey(v)], counts[modeKey(mode)]; n > m || n == m && lessValue(v, mode) {
+			mode = v
+		}
+	}
+
+	if err := execute.AppendKeyValues(tbl.Key(), builder); err != nil {
+		return err
+	}
+	return builder.AppendValue(j, mode)
+}
+
+func (t *modeTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+func (t *modeTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+func (t *modeTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// modeKey returns the value as a comparable Go value so it can be counted.
+func modeKey(v values.Value) interface{} {
+	switch v.Type().Nature() {
+	case semantic.Bool:
+		return v.Bool()
+	case semantic.Int:
+		return v.Int()
+	case semantic.UInt:
+		return v.UInt()
+	case semantic.Float:
+		return v.Float()
+	case semantic.String:
+		return v.Str()
+	case semantic.Time:
+		return v.Time()
+	default:
+		execute.PanicUnknownType(flux.ColumnType(v.Type()))
+		return nil
+	}
+}
+
+// lessValue reports whether a is less than b. Both values have the same type.
+func lessValue(a, b values.Value) bool {
+	switch a.Type().Nature() {
+	case semantic.Bool:
+		return !a.Bool() && b.Bool()
+	case semantic.Int:
+		return a.Int() < b.Int()
+	case semantic.UInt:
+		return a.UInt() < b.UInt()
+	case semantic.Float:
+		return a.Float() < b.Float()
+	case semantic.String:
+		return a.Str() < b.Str()
+	case semantic.Time:
+		return a.Time() < b.Time()
+	default:
+		return false
+	}
+}
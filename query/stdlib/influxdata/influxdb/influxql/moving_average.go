@@ -0,0 +1,217 @@
+package influxql
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+)
+
+const MovingAverageKind = "influxqlMovingAverage"
+
+// MovingAverageOpSpec computes the mean of a window of n values that slides
+// over the rows of a table. A row is produced for every value starting at the
+// nth value and keeps the time of that value. Null values are skipped.
+type MovingAverageOpSpec struct {
+	N      int64  `json:"n"`
+	Column string `json:"column"`
+}
+
+func init() {
+	movingAverageSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"n":      semantic.Int,
+			"column": semantic.String,
+		},
+		[]string{"n"},
+	)
+
+	flux.RegisterPackageValue(PackagePath, "movingAverage", flux.FunctionValue(MovingAverageKind, createMovingAverageOpSpec, movingAverageSignature))
+	flux.RegisterOpSpec(MovingAverageKind, newMovingAverageOp)
+	plan.RegisterProcedureSpec(MovingAverageKind, newMovingAverageProcedure, MovingAverageKind)
+	execute.RegisterTransformation(MovingAverageKind, createMovingAverageTransformation)
+}
+
+func createMovingAverageOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := new(MovingAverageOpSpec)
+	n, err := args.GetRequiredInt("n")
+	if err != nil {
+		return nil, err
+	} else if n <= 0 {
+		return nil, fmt.Errorf("moving average window must be greater than 0, got %d", n)
+	}
+	spec.N = n
+
+	if col, ok, err := args.GetString("column"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Column = col
+	} else {
+		spec.Column = execute.DefaultValueColLabel
+	}
+	return spec, nil
+}
+
+func newMovingAverageOp() flux.OperationSpec {
+	return new(MovingAverageOpSpec)
+}
+
+func (s *MovingAverageOpSpec) Kind() flux.OperationKind {
+	return MovingAverageKind
+}
+
+type MovingAverageProcedureSpec struct {
+	plan.DefaultCost
+	N      int64
+	Column string
+}
+
+func newMovingAverageProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*MovingAverageOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &MovingAverageProcedureSpec{
+		N:      spec.N,
+		Column: spec.Column,
+	}, nil
+}
+
+func (s *MovingAverageProcedureSpec) Kind() plan.ProcedureKind {
+	return MovingAverageKind
+}
+
+func (s *MovingAverageProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(MovingAverageProcedureSpec)
+	*ns = *s
+	return ns
+}
+
+func createMovingAverageTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*MovingAverageProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewMovingAverageTransformation(d, cache, s)
+	return t, d, nil
+}
+
+type movingAverageTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	spec  MovingAverageProcedureSpec
+}
+
+func NewMovingAverageTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *MovingAverageProcedureSpec) *movingAverageTransformation {
+	return &movingAverageTransformation{
+		d:     d,
+		cache: cache,
+		spec:  *spec,
+	}
+}
+
+func (t *movingAverageTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *movingAverageTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("moving average found duplicate table with key: %v", tbl.Key())
+	}
+
+	cols := tbl.Cols()
+	valueIdx := execute.ColIdx(t.spec.Column, cols)
+	if valueIdx < 0 {
+		return fmt.Errorf("column %q does not exist", t.spec.Column)
+	}
+	for j, c := range cols {
+		if j == valueIdx {
+			switch c.Type {
+			case flux.TFloat, flux.TInt, flux.TUInt:
+			default:
+				return fmt.Errorf("cannot compute the moving average of %v", c.Type)
+			}
+			c = flux.ColMeta{Label: c.Label, Type: flux.TFloat}
+		}
+		if _, err := builder.AddCol(c); err != nil {
+			return err
+		}
+	}
+
+	window := make([]float64, 0, t.spec.N)
+	var (
+		next int
+		sum  float64
+	)
+	return tbl.Do(func(cr flux.ColReader) error {
+		for i, l := 0, cr.Len(); i < l; i++ {
+			var v float64
+			switch cols[valueIdx].Type {
+			case flux.TFloat:
+				vs := cr.Floats(valueIdx)
+				if vs.IsNull(i) {
+					continue
+				}
+				v = vs.Value(i)
+			case flux.TInt:
+				vs := cr.Ints(valueIdx)
+				if vs.IsNull(i) {
+					continue
+				}
+				v = float64(vs.Value(i))
+			case flux.TUInt:
+				vs := cr.UInts(valueIdx)
+				if vs.IsNull(i) {
+					continue
+				}
+				v = float64(vs.Value(i))
+			}
+
+			// Replace the oldest value in the window once it is full.
+			if int64(len(window)) < t.spec.N {
+				window = append(window, v)
+			} else {
+				sum -= window[next]
+				window[next] = v
+				next = (next + 1) % len(window)
+			}
+			sum += v
+			if int64(len(window)) < t.spec.N {
+				continue
+			}
+
+			for j := range cols {
+				if j == valueIdx {
+					if err := builder.AppendFloat(j, sum/float64(len(window))); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := builder.AppendValue(j, execute.ValueForRow(cr, i, j)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (t *movingAverageTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+func (t *movingAverageTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+func (t *movingAverageTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
@@ -0,0 +1,121 @@
+package influxql_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+)
+
+func TestMovingAverage_Process(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *influxql.MovingAverageProcedureSpec
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "float",
+			spec: &influxql.MovingAverageProcedureSpec{
+				N:      2,
+				Column: execute.DefaultValueColLabel,
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "t0", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 2.0, "a"},
+					{execute.Time(2), 4.0, "a"},
+					{execute.Time(3), 3.0, "a"},
+					{execute.Time(4), 7.0, "a"},
+				},
+			}},
+			want: []*executetest.Table{{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "t0", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(2), 3.0, "a"},
+					{execute.Time(3), 3.5, "a"},
+					{execute.Time(4), 5.0, "a"},
+				},
+			}},
+		},
+		{
+			name: "int with nulls",
+			spec: &influxql.MovingAverageProcedureSpec{
+				N:      3,
+				Column: execute.DefaultValueColLabel,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TInt},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), int64(1)},
+					{execute.Time(2), nil},
+					{execute.Time(3), int64(2)},
+					{execute.Time(4), int64(6)},
+					{execute.Time(5), int64(10)},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(4), 3.0},
+					{execute.Time(5), 6.0},
+				},
+			}},
+		},
+		{
+			name: "fewer values than the window",
+			spec: &influxql.MovingAverageProcedureSpec{
+				N:      3,
+				Column: execute.DefaultValueColLabel,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 1.0},
+					{execute.Time(2), 2.0},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					return influxql.NewMovingAverageTransformation(d, c, tc.spec)
+				},
+			)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package influxql
+
+import "math"
+
+const (
+	nelderMeadMaxIterations = 1000
+	// nelderMeadReflection is the reflection coefficient.
+	nelderMeadReflection = 1.0
+	// nelderMeadContraction is the contraction coefficient.
+	nelderMeadContraction = 0.5
+	// nelderMeadExpansion is the expansion coefficient.
+	nelderMeadExpansion = 2.0
+)
+
+// nelderMead minimizes objfunc with the Nelder-Mead simplex method, starting
+// from the simplex around start of the given scale. The minimization stops once
+// the standard deviation of the values at the vertices is less than epsilon.
+// It returns the smallest value that was found and the parameters for it.
+// This is the same optimizer that InfluxDB 1.x uses to fit the Holt-Winters model,
+// which is based on the work by Michael F. Hutt: http://www.mikehutt.com/neldermead.html
+func nelderMead(objfunc func([]float64) float64, start []float64, epsilon, scale float64) (float64, []float64) {
+	n := len(start)
+
+	// The vertices of the simplex and the value of the function at each vertex.
+	v := make([][]float64, n+1)
+	for i := range v {
+		v[i] = make([]float64, n)
+	}
+	f := make([]float64, n+1)
+
+	// The coordinates of the reflection, expansion, contraction and centroid.
+	vr := make([]float64, n)
+	ve := make([]float64, n)
+	vc := make([]float64, n)
+	vm := make([]float64, n)
+
+	// Create the initial simplex with start as one of the vertices.
+	pn := scale * (math.Sqrt(float64(n+1)) - 1 + float64(n)) / (float64(n) * math.Sqrt(2))
+	qn := scale * (math.Sqrt(float64(n+1)) - 1) / (float64(n) * math.Sqrt(2))
+	copy(v[0], start)
+	for i := 1; i <= n; i++ {
+		for j := 0; j < n; j++ {
+			if i-1 == j {
+				v[i][j] = pn + start[j]
+			} else {
+				v[i][j] = qn + start[j]
+			}
+		}
+	}
+	for j := 0; j <= n; j++ {
+		f[j] = objfunc(v[j])
+	}
+
+	for itr := 1; itr <= nelderMeadMaxIterations; itr++ {
+		// Find the indexes of the largest, second largest and smallest values.
+		vg, vs := 0, 0
+		for i := 0; i <= n; i++ {
+			if f[i] > f[vg] {
+				vg = i
+			}
+			if f[i] < f[vs] {
+				vs = i
+			}
+		}
+		vh := vs
+		for i := 0; i <= n; i++ {
+			if f[i] > f[vh] && f[i] < f[vg] {
+				vh = i
+			}
+		}
+
+		// Calculate the centroid of all of the vertices except the largest.
+		for i := 0; i < n; i++ {
+			cent := 0.0
+			for m := 0; m <= n; m++ {
+				if m != vg {
+					cent += v[m][i]
+				}
+			}
+			vm[i] = cent / float64(n)
+		}
+
+		// Reflect the largest vertex through the centroid.
+		for i := 0; i < n; i++ {
+			vr[i] = vm[i] + nelderMeadReflection*(vm[i]-v[vg][i])
+		}
+		fr := objfunc(vr)
+		if fr < f[vh] && fr >= f[vs] {
+			copy(v[vg], vr)
+			f[vg] = fr
+		}
+
+		// Investigate a step further in the direction of the reflection.
+		if fr < f[vs] {
+			for i := 0; i < n; i++ {
+				ve[i] = vm[i] + nelderMeadExpansion*(vr[i]-vm[i])
+			}
+			if fe := objfunc(ve); fe < fr {
+				copy(v[vg], ve)
+				f[vg] = fe
+			} else {
+				copy(v[vg], vr)
+				f[vg] = fr
+			}
+		}
+
+		// Contract the simplex if the reflection did not improve on the second largest vertex.
+		if fr >= f[vh] {
+			if fr < f[vg] {
+				// Outside contraction.
+				for i := 0; i < n; i++ {
+					vc[i] = vm[i] + nelderMeadContraction*(vr[i]-vm[i])
+				}
+			} else {
+				// Inside contraction.
+				for i := 0; i < n; i++ {
+					vc[i] = vm[i] - nelderMeadContraction*(vm[i]-v[vg][i])
+				}
+			}
+
+			if fc := objfunc(vc); fc < f[vg] {
+				copy(v[vg], vc)
+				f[vg] = fc
+			} else {
+				// The contraction did not succeed so halve the distance
+				// from the smallest vertex to all of the other vertices.
+				for row := 0; row <= n; row++ {
+					if row == vs {
+						continue
+					}
+					for i := 0; i < n; i++ {
+						v[row][i] = v[vs][i] + (v[row][i]-v[vs][i])/2.0
+					}
+				}
+				f[vg] = objfunc(v[vg])
+				f[vh] = objfunc(v[vh])
+			}
+		}
+
+		// Test for convergence.
+		fsum := 0.0
+		for i := 0; i <= n; i++ {
+			fsum += f[i]
+		}
+		favg := fsum / float64(n+1)
+		s := 0.0
+		for i := 0; i <= n; i++ {
+			s += math.Pow(f[i]-favg, 2.0) / float64(n)
+		}
+		if math.Sqrt(s) < epsilon {
+			break
+		}
+	}
+
+	vs := 0
+	for i := 0; i <= n; i++ {
+		if f[i] < f[vs] {
+			vs = i
+		}
+	}
+	params := make([]float64, n)
+	copy(params, v[vs])
+	return objfunc(params), params
+}
@@ -0,0 +1,204 @@
+package influxql
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const SampleKind = "influxqlSample"
+
+// SampleOpSpec selects n random rows of each table with a reservoir sample.
+// The selected rows keep the order they have in the table. A row with a null
+// value in the column is never selected.
+type SampleOpSpec struct {
+	N      int64  `json:"n"`
+	Column string `json:"column"`
+}
+
+func init() {
+	sampleSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"n":      semantic.Int,
+			"column": semantic.String,
+		},
+		[]string{"n"},
+	)
+
+	flux.RegisterPackageValue(PackagePath, "sample", flux.FunctionValue(SampleKind, createSampleOpSpec, sampleSignature))
+	flux.RegisterOpSpec(SampleKind, newSampleOp)
+	plan.RegisterProcedureSpec(SampleKind, newSampleProcedure, SampleKind)
+	execute.RegisterTransformation(SampleKind, createSampleTransformation)
+}
+
+func createSampleOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := new(SampleOpSpec)
+	n, err := args.GetRequiredInt("n")
+	if err != nil {
+		return nil, err
+	} else if n <= 0 {
+		return nil, fmt.Errorf("sample size must be greater than 0, got %d", n)
+	}
+	spec.N = n
+
+	if col, ok, err := args.GetString("column"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Column = col
+	} else {
+		spec.Column = execute.DefaultValueColLabel
+	}
+	return spec, nil
+}
+
+func newSampleOp() flux.OperationSpec {
+	return new(SampleOpSpec)
+}
+
+func (s *SampleOpSpec) Kind() flux.OperationKind {
+	return SampleKind
+}
+
+type SampleProcedureSpec struct {
+	plan.DefaultCost
+	N      int64
+	Column string
+}
+
+func newSampleProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*SampleOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &SampleProcedureSpec{
+		N:      spec.N,
+		Column: spec.Column,
+	}, nil
+}
+
+func (s *SampleProcedureSpec) Kind() plan.ProcedureKind {
+	return SampleKind
+}
+
+func (s *SampleProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(SampleProcedureSpec)
+	*ns = *s
+	return ns
+}
+
+func createSampleTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*SampleProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewSampleTransformation(d, cache, s)
+	return t, d, nil
+}
+
+type sampleTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	spec  SampleProcedureSpec
+	rng   *rand.Rand
+}
+
+func NewSampleTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *SampleProcedureSpec) *sampleTransformation {
+	return &sampleTransformation{
+		d:     d,
+		cache: cache,
+		spec:  *spec,
+		rng:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (t *sampleTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+// sampleRow is a row that was selected along with its position in the table.
+type sampleRow struct {
+	index  int
+	values []values.Value
+}
+
+func (t *sampleTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("sample found duplicate table with key: %v", tbl.Key())
+	}
+	if err := execute.AddTableCols(tbl, builder); err != nil {
+		return err
+	}
+
+	cols := tbl.Cols()
+	valueIdx := execute.ColIdx(t.spec.Column, cols)
+	if valueIdx < 0 {
+		return fmt.Errorf("column %q does not exist", t.spec.Column)
+	}
+
+	rows := make([]sampleRow, 0, t.spec.N)
+	var n int
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		for i, l := 0, cr.Len(); i < l; i++ {
+			if execute.ValueForRow(cr, i, valueIdx).IsNull() {
+				continue
+			}
+
+			// The first n rows fill the reservoir. Every row after that
+			// replaces one of the selected rows with a probability of n
+			// divided by the number of rows so far.
+			n++
+			slot := len(rows)
+			if len(rows) < cap(rows) {
+				rows = append(rows, sampleRow{})
+			} else if slot = t.rng.Intn(n); slot >= len(rows) {
+				continue
+			}
+
+			vs := make([]values.Value, len(cols))
+			for j := range cols {
+				vs[j] = execute.ValueForRow(cr, i, j)
+			}
+			rows[slot] = sampleRow{index: n, values: vs}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].index < rows[j].index
+	})
+	for _, row := range rows {
+		for j, v := range row.values {
+			if err := builder.AppendValue(j, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *sampleTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+func (t *sampleTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+func (t *sampleTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
@@ -0,0 +1,132 @@
+package influxql_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+)
+
+func TestSample_Process(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *influxql.SampleProcedureSpec
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "fewer rows than the sample",
+			spec: &influxql.SampleProcedureSpec{
+				N:      3,
+				Column: execute.DefaultValueColLabel,
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "t0", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 2.0, "a"},
+					{execute.Time(2), nil, "a"},
+					{execute.Time(3), 3.0, "a"},
+				},
+			}},
+			want: []*executetest.Table{{
+				KeyCols: []string{"t0"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "t0", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 2.0, "a"},
+					{execute.Time(3), 3.0, "a"},
+				},
+			}},
+		},
+		{
+			name: "only nulls",
+			spec: &influxql.SampleProcedureSpec{
+				N:      1,
+				Column: execute.DefaultValueColLabel,
+			},
+			data: []flux.Table{&executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TInt},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), nil},
+					{execute.Time(2), nil},
+				},
+			}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TInt},
+				},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					return influxql.NewSampleTransformation(d, c, tc.spec)
+				},
+			)
+		})
+	}
+}
+
+func TestSample_Subset(t *testing.T) {
+	data := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TInt},
+		},
+	}
+	for i := 0; i < 100; i++ {
+		data.Data = append(data.Data, []interface{}{execute.Time(i), int64(i)})
+	}
+
+	d := executetest.NewDataset(executetest.RandomDatasetID())
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(execute.DefaultTriggerSpec)
+	tx := influxql.NewSampleTransformation(d, c, &influxql.SampleProcedureSpec{
+		N:      10,
+		Column: execute.DefaultValueColLabel,
+	})
+	if err := tx.Process(executetest.RandomDatasetID(), data); err != nil {
+		t.Fatal(err)
+	}
+	got, err := executetest.TablesFromCache(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The selected rows are distinct rows of the table in the order of the table.
+	if len(got) != 1 || len(got[0].Data) != 10 {
+		t.Fatalf("expected 10 rows, got %v", got)
+	}
+	prev := int64(-1)
+	for _, row := range got[0].Data {
+		ts, v := row[0].(execute.Time), row[1].(int64)
+		if int64(ts) != v {
+			t.Errorf("row is not one of the rows of the table: %v", row)
+		}
+		if v <= prev {
+			t.Errorf("rows are not in the order of the table: %d after %d", v, prev)
+		}
+		prev = v
+	}
+}
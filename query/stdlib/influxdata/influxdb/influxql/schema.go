@@ -0,0 +1,246 @@
+package influxql
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/models"
+)
+
+const (
+	TagsKind      = "influxqlTags"
+	SeriesKeyKind = "influxqlSeriesKey"
+	FieldTypeKind = "influxqlFieldType"
+)
+
+// The schema functions describe the series that each table was read from
+// instead of the data in the table. They produce rows for each table with the
+// same group key as the table and are used by the InfluxQL meta queries.
+//
+// tags produces a row for each tag in the group key with the name of the tag
+// in the _key column and its value in the _value column.
+//
+// seriesKey produces a row with the key of the series in the key column.
+//
+// fieldType produces a row with the InfluxQL type of the _value column in the
+// fieldType column.
+type SchemaOpSpec struct {
+	kind flux.OperationKind
+}
+
+func init() {
+	for _, kind := range []flux.OperationKind{TagsKind, SeriesKeyKind, FieldTypeKind} {
+		kind := kind
+		createOpSpec := func(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+			if err := a.AddParentFromArgs(args); err != nil {
+				return nil, err
+			}
+			return &SchemaOpSpec{kind: kind}, nil
+		}
+		newOp := func() flux.OperationSpec {
+			return &SchemaOpSpec{kind: kind}
+		}
+
+		signature := flux.FunctionSignature(map[string]semantic.PolyType{}, nil)
+		flux.RegisterPackageValue(PackagePath, schemaFunctionName(kind), flux.FunctionValue(string(kind), createOpSpec, signature))
+		flux.RegisterOpSpec(kind, newOp)
+		plan.RegisterProcedureSpec(plan.ProcedureKind(kind), newSchemaProcedure, kind)
+		execute.RegisterTransformation(plan.ProcedureKind(kind), createSchemaTransformation)
+	}
+}
+
+// schemaFunctionName returns the name of the function in flux.
+func schemaFunctionName(kind flux.OperationKind) string {
+	switch kind {
+	case TagsKind:
+		return "tags"
+	case SeriesKeyKind:
+		return "seriesKey"
+	default:
+		return "fieldType"
+	}
+}
+
+func (s *SchemaOpSpec) Kind() flux.OperationKind {
+	return s.kind
+}
+
+type SchemaProcedureSpec struct {
+	plan.DefaultCost
+	kind plan.ProcedureKind
+}
+
+// NewSchemaProcedureSpec creates the procedure spec of the schema function
+// with the kind.
+func NewSchemaProcedureSpec(kind plan.ProcedureKind) *SchemaProcedureSpec {
+	return &SchemaProcedureSpec{kind: kind}
+}
+
+func newSchemaProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*SchemaOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return NewSchemaProcedureSpec(plan.ProcedureKind(spec.kind)), nil
+}
+
+func (s *SchemaProcedureSpec) Kind() plan.ProcedureKind {
+	return s.kind
+}
+
+func (s *SchemaProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(SchemaProcedureSpec)
+	*ns = *s
+	return ns
+}
+
+func createSchemaTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*SchemaProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewSchemaTransformation(d, cache, s)
+	return t, d, nil
+}
+
+type schemaTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	kind  plan.ProcedureKind
+}
+
+func NewSchemaTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *SchemaProcedureSpec) *schemaTransformation {
+	return &schemaTransformation{
+		d:     d,
+		cache: cache,
+		kind:  spec.kind,
+	}
+}
+
+func (t *schemaTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *schemaTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	// The data in the table is not used, but a table without any rows does
+	// not describe a series that was read.
+	var n int
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		n += cr.Len()
+		return nil
+	}); err != nil {
+		return err
+	} else if n == 0 {
+		return nil
+	}
+
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("%s found duplicate table with key: %v", schemaFunctionName(flux.OperationKind(t.kind)), tbl.Key())
+	}
+	if err := execute.AddTableKeyCols(tbl.Key(), builder); err != nil {
+		return err
+	}
+
+	var (
+		labels []string
+		rows   [][]string
+	)
+	switch t.kind {
+	case TagsKind:
+		labels = []string{"_key", execute.DefaultValueColLabel}
+		for j, c := range tbl.Key().Cols() {
+			if isTag(c) {
+				rows = append(rows, []string{c.Label, tbl.Key().ValueString(j)})
+			}
+		}
+	case SeriesKeyKind:
+		labels = []string{"key"}
+		rows = [][]string{{seriesKey(tbl.Key())}}
+	case FieldTypeKind:
+		idx := execute.ColIdx(execute.DefaultValueColLabel, tbl.Cols())
+		if idx < 0 {
+			return fmt.Errorf("column %q does not exist", execute.DefaultValueColLabel)
+		}
+		labels = []string{"fieldType"}
+		rows = [][]string{{fieldType(tbl.Cols()[idx].Type)}}
+	}
+
+	for _, label := range labels {
+		if _, err := builder.AddCol(flux.ColMeta{Label: label, Type: flux.TString}); err != nil {
+			return err
+		}
+	}
+	offset := len(tbl.Key().Cols())
+	for _, row := range rows {
+		if err := execute.AppendKeyValues(tbl.Key(), builder); err != nil {
+			return err
+		}
+		for j, v := range row {
+			if err := builder.AppendValue(offset+j, values.NewString(v)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *schemaTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+func (t *schemaTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+func (t *schemaTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// isTag reports whether the column of the group key is a tag of the series.
+func isTag(c flux.ColMeta) bool {
+	if c.Type != flux.TString {
+		return false
+	}
+	switch c.Label {
+	case execute.DefaultStartColLabel, execute.DefaultStopColLabel, "_measurement", "_field":
+		return false
+	}
+	return true
+}
+
+// seriesKey returns the key of the series in the line protocol format.
+func seriesKey(key flux.GroupKey) string {
+	var name string
+	tags := make(map[string]string)
+	for j, c := range key.Cols() {
+		if c.Label == "_measurement" {
+			name = key.ValueString(j)
+		} else if isTag(c) {
+			tags[c.Label] = key.ValueString(j)
+		}
+	}
+	return string(models.MakeKey([]byte(name), models.NewTags(tags)))
+}
+
+// fieldType returns the name of the InfluxQL type for the column type.
+func fieldType(typ flux.ColType) string {
+	switch typ {
+	case flux.TFloat:
+		return "float"
+	case flux.TInt:
+		return "integer"
+	case flux.TUInt:
+		return "unsigned"
+	case flux.TString:
+		return "string"
+	case flux.TBool:
+		return "boolean"
+	default:
+		return typ.String()
+	}
+}
@@ -0,0 +1,114 @@
+package influxql_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/influxql"
+)
+
+func TestSchema_Process(t *testing.T) {
+	data := func() []flux.Table {
+		return []flux.Table{
+			&executetest.Table{
+				KeyCols: []string{"_measurement", "_field", "host", "region"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TInt},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "region", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), int64(2), "cpu", "n", "a", "west"},
+					{execute.Time(2), int64(4), "cpu", "n", "a", "west"},
+				},
+			},
+			// A table without rows does not describe a series.
+			&executetest.Table{
+				KeyCols: []string{"_measurement", "_field", "host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+				},
+				KeyValues: []interface{}{"mem", "free", "b"},
+			},
+		}
+	}
+	keyCols := []flux.ColMeta{
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "_field", Type: flux.TString},
+		{Label: "host", Type: flux.TString},
+		{Label: "region", Type: flux.TString},
+	}
+
+	testCases := []struct {
+		name string
+		kind flux.OperationKind
+		want []*executetest.Table
+	}{
+		{
+			name: "tags",
+			kind: influxql.TagsKind,
+			want: []*executetest.Table{{
+				KeyCols: []string{"_measurement", "_field", "host", "region"},
+				ColMeta: append(keyCols,
+					flux.ColMeta{Label: "_key", Type: flux.TString},
+					flux.ColMeta{Label: "_value", Type: flux.TString},
+				),
+				Data: [][]interface{}{
+					{"cpu", "n", "a", "west", "host", "a"},
+					{"cpu", "n", "a", "west", "region", "west"},
+				},
+			}},
+		},
+		{
+			name: "series key",
+			kind: influxql.SeriesKeyKind,
+			want: []*executetest.Table{{
+				KeyCols: []string{"_measurement", "_field", "host", "region"},
+				ColMeta: append(keyCols,
+					flux.ColMeta{Label: "key", Type: flux.TString},
+				),
+				Data: [][]interface{}{
+					{"cpu", "n", "a", "west", "cpu,host=a,region=west"},
+				},
+			}},
+		},
+		{
+			name: "field type",
+			kind: influxql.FieldTypeKind,
+			want: []*executetest.Table{{
+				KeyCols: []string{"_measurement", "_field", "host", "region"},
+				ColMeta: append(keyCols,
+					flux.ColMeta{Label: "fieldType", Type: flux.TString},
+				),
+				Data: [][]interface{}{
+					{"cpu", "n", "a", "west", "integer"},
+				},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				data(),
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					spec := influxql.NewSchemaProcedureSpec(plan.ProcedureKind(tc.kind))
+					return influxql.NewSchemaTransformation(d, c, spec)
+				},
+			)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+const MeasurementFieldsKind = "measurementFields"
+
+// MeasurementFieldsOpSpec reads the keys and types of the fields of the
+// series of a measurement within a time range. The keys are in the _value
+// column and the types in the type column of a table grouped by the
+// measurement. The range is between the times start and stop, which default
+// to the last 30 days. Unlike range, they may not be durations, since the
+// type variables that range uses are not supported for the functions of a
+// package that is imported.
+type MeasurementFieldsOpSpec struct {
+	Bucket      string    `json:"bucket,omitempty"`
+	BucketID    string    `json:"bucketID,omitempty"`
+	Measurement string    `json:"measurement"`
+	Start       flux.Time `json:"start"`
+	Stop        flux.Time `json:"stop"`
+}
+
+func init() {
+	measurementFieldsSignature := semantic.FunctionPolySignature{
+		Parameters: map[string]semantic.PolyType{
+			"bucket":      semantic.String,
+			"bucketID":    semantic.String,
+			"measurement": semantic.String,
+			"start":       semantic.Time,
+			"stop":        semantic.Time,
+		},
+		Required: semantic.LabelSet{"measurement"},
+		Return:   flux.TableObjectType,
+	}
+
+	flux.RegisterPackageValue(PackagePath, MeasurementFieldsKind, flux.FunctionValue(MeasurementFieldsKind, createMeasurementFieldsOpSpec, measurementFieldsSignature))
+	flux.RegisterOpSpec(MeasurementFieldsKind, newMeasurementFieldsOp)
+	plan.RegisterProcedureSpec(MeasurementFieldsKind, newMeasurementFieldsProcedure, MeasurementFieldsKind)
+	execute.RegisterSource(MeasurementFieldsKind, createMeasurementFieldsSource)
+}
+
+func createMeasurementFieldsOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := &MeasurementFieldsOpSpec{
+		Start: flux.Time{IsRelative: true, Relative: DefaultStart},
+		Stop:  flux.Now,
+	}
+
+	if bucket, ok, err := args.GetString("bucket"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Bucket = bucket
+	}
+
+	if bucketID, ok, err := args.GetString("bucketID"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.BucketID = bucketID
+	}
+
+	if spec.Bucket == "" && spec.BucketID == "" {
+		return nil, errors.New("must specify one of bucket or bucketID")
+	}
+	if spec.Bucket != "" && spec.BucketID != "" {
+		return nil, errors.New("must specify only one of bucket or bucketID")
+	}
+
+	measurement, err := args.GetRequiredString("measurement")
+	if err != nil {
+		return nil, err
+	}
+	spec.Measurement = measurement
+
+	if start, ok, err := args.GetTime("start"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Start = start
+	}
+
+	if stop, ok, err := args.GetTime("stop"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Stop = stop
+	}
+	return spec, nil
+}
+
+func newMeasurementFieldsOp() flux.OperationSpec {
+	return new(MeasurementFieldsOpSpec)
+}
+
+func (s *MeasurementFieldsOpSpec) Kind() flux.OperationKind {
+	return MeasurementFieldsKind
+}
+
+// BucketsAccessed makes MeasurementFieldsOpSpec a query.BucketAwareOperationSpec
+func (s *MeasurementFieldsOpSpec) BucketsAccessed() (readBuckets, writeBuckets []platform.BucketFilter) {
+	bf := platform.BucketFilter{}
+	if s.Bucket != "" {
+		bf.Name = &s.Bucket
+	}
+
+	if len(s.BucketID) > 0 {
+		if id, err := platform.IDFromString(s.BucketID); err != nil {
+			invalidID := platform.InvalidID()
+			bf.ID = &invalidID
+		} else {
+			bf.ID = id
+		}
+	}
+
+	if bf.ID != nil || bf.Name != nil {
+		readBuckets = append(readBuckets, bf)
+	}
+	return readBuckets, writeBuckets
+}
+
+type MeasurementFieldsProcedureSpec struct {
+	plan.DefaultCost
+
+	Bucket      string
+	BucketID    string
+	Measurement string
+	Start       flux.Time
+	Stop        flux.Time
+}
+
+func newMeasurementFieldsProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*MeasurementFieldsOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+
+	return &MeasurementFieldsProcedureSpec{
+		Bucket:      spec.Bucket,
+		BucketID:    spec.BucketID,
+		Measurement: spec.Measurement,
+		Start:       spec.Start,
+		Stop:        spec.Stop,
+	}, nil
+}
+
+func (s *MeasurementFieldsProcedureSpec) Kind() plan.ProcedureKind {
+	return MeasurementFieldsKind
+}
+
+func (s *MeasurementFieldsProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createMeasurementFieldsSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*MeasurementFieldsProcedureSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", prSpec)
+	}
+
+	// The dependencies of from are the ones needed to read from storage.
+	deps := a.Dependencies()[influxdb.FromKind].(influxdb.Dependencies)
+	req := query.RequestFromContext(a.Context())
+	if req == nil {
+		return nil, errors.New("missing request on context")
+	}
+	orgID := req.OrganizationID
+
+	var bucketID platform.ID
+	switch {
+	case spec.Bucket != "":
+		b, ok := deps.BucketLookup.Lookup(orgID, spec.Bucket)
+		if !ok {
+			return nil, fmt.Errorf("could not find bucket %q", spec.Bucket)
+		}
+		bucketID = b
+	case len(spec.BucketID) != 0:
+		if err := bucketID.DecodeFromString(spec.BucketID); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds := execute.Bounds{
+		Start: a.ResolveTime(spec.Start),
+		Stop:  a.ResolveTime(spec.Stop),
+	}
+	// The fields are read in a single window over the whole range, which
+	// must not be empty for the source to advance past it.
+	if bounds.Stop <= bounds.Start {
+		return nil, errors.New("stop must be after start")
+	}
+	duration := execute.Duration(bounds.Stop - bounds.Start)
+	w := execute.Window{
+		Every:  duration,
+		Period: duration,
+	}
+	return influxdb.NewSource(
+		dsid,
+		deps.Reader,
+		influxdb.ReadSpec{
+			OrganizationID:    orgID,
+			BucketID:          bucketID,
+			MeasurementFields: true,
+			Measurement:       spec.Measurement,
+		},
+		bounds,
+		w,
+		bounds.Stop,
+		a.Allocator(),
+	), nil
+}
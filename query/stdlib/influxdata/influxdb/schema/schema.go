@@ -0,0 +1,89 @@
+// Package schema implements the Flux functions that describe the schema of
+// the series in a bucket. The functions are planned onto the schema reads of
+// the storage layer, which answer them from the index instead of reading every
+// series.
+package schema
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/values"
+)
+
+// PackagePath is the import path of the package in Flux.
+const PackagePath = "influxdata/influxdb/schema"
+
+// DefaultStart is the start of the time range of the functions, relative to
+// now, when no start is given.
+const DefaultStart = -30 * 24 * time.Hour
+
+const source = `package schema
+
+// defaultStart is the start of the time range when no start is given.
+builtin defaultStart
+
+// tagValues returns the distinct values of the tag of the series that match
+// the predicate within the time range. The values are in the _value column.
+tagValues = (bucket, tag, predicate=(r) => true, start=defaultStart) =>
+    from(bucket: bucket)
+        |> range(start: start)
+        |> filter(fn: predicate)
+        |> group(columns: [tag])
+        |> distinct(column: tag)
+        |> keep(columns: ["_value"])
+
+// measurementTagValues returns the distinct values of the tag of the series
+// of the measurement.
+measurementTagValues = (bucket, measurement, tag) =>
+    tagValues(bucket: bucket, tag: tag, predicate: (r) => r._measurement == measurement)
+
+// tagKeys returns the distinct tag keys of the series that match the
+// predicate within the time range. The keys are in the _value column.
+tagKeys = (bucket, predicate=(r) => true, start=defaultStart) =>
+    from(bucket: bucket)
+        |> range(start: start)
+        |> filter(fn: predicate)
+        |> keys()
+        |> keep(columns: ["_value"])
+        |> distinct()
+
+// measurementTagKeys returns the distinct tag keys of the series of the
+// measurement.
+measurementTagKeys = (bucket, measurement) =>
+    tagKeys(bucket: bucket, predicate: (r) => r._measurement == measurement)
+
+// fieldKeys returns the distinct field keys of the series that match the
+// predicate within the time range. The keys are in the _value column.
+fieldKeys = (bucket, predicate=(r) => true, start=defaultStart) =>
+    tagValues(bucket: bucket, tag: "_field", predicate: predicate, start: start)
+
+// measurementFieldKeys returns the distinct field keys of the series of the
+// measurement.
+measurementFieldKeys = (bucket, measurement) =>
+    fieldKeys(bucket: bucket, predicate: (r) => r._measurement == measurement)
+
+// measurementFields returns the keys of the fields of the series of the
+// measurement within the time range in the _value column, and their types in
+// the type column.
+builtin measurementFields
+
+// measurements returns the distinct measurements of the bucket.
+measurements = (bucket) =>
+    tagValues(bucket: bucket, tag: "_measurement")
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	if ast.Check(pkg) > 0 {
+		panic(fmt.Errorf("failed to parse the %s package: %v", PackagePath, ast.GetError(pkg)))
+	}
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+
+	// The default start is shared with the functions that are implemented in Go.
+	flux.RegisterPackageValue(PackagePath, "defaultStart", values.NewDuration(values.Duration(DefaultStart)))
+}
@@ -0,0 +1,107 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Names of the organization quotas, as they appear in quota errors.
+const (
+	QuotaWriteBytesPerSecond = "writeBytesPerSecond"
+	QuotaConcurrentQueries   = "concurrentQueries"
+	QuotaQueryMemoryBytes    = "queryMemoryBytes"
+	QuotaMaxBuckets          = "maxBuckets"
+	QuotaMaxTasks            = "maxTasks"
+)
+
+// OrgQuotas are the resource quotas of an organization. A zero quota is not
+// limited.
+type OrgQuotas struct {
+	// WriteBytesPerSecond is the rate at which the organization may write
+	// line protocol.
+	WriteBytesPerSecond int64 `json:"writeBytesPerSecond,omitempty"`
+	// ConcurrentQueries is the number of queries of the organization that
+	// may run at the same time.
+	ConcurrentQueries int `json:"concurrentQueries,omitempty"`
+	// QueryMemoryBytes is the memory each query of the organization may use.
+	QueryMemoryBytes int64 `json:"queryMemoryBytes,omitempty"`
+	// MaxBuckets is the number of buckets the organization may have.
+	MaxBuckets int `json:"maxBuckets,omitempty"`
+	// MaxTasks is the number of tasks the organization may have.
+	MaxTasks int `json:"maxTasks,omitempty"`
+}
+
+// Valid returns an error if a quota is negative.
+func (q *OrgQuotas) Valid() error {
+	quotas := []struct {
+		name string
+		v    int64
+	}{
+		{QuotaWriteBytesPerSecond, q.WriteBytesPerSecond},
+		{QuotaConcurrentQueries, int64(q.ConcurrentQueries)},
+		{QuotaQueryMemoryBytes, q.QueryMemoryBytes},
+		{QuotaMaxBuckets, int64(q.MaxBuckets)},
+		{QuotaMaxTasks, int64(q.MaxTasks)},
+	}
+	for _, quota := range quotas {
+		if quota.v < 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("organization quota %s must not be negative", quota.name),
+			}
+		}
+	}
+	return nil
+}
+
+// IsZero returns true if none of the quotas is limited.
+func (q *OrgQuotas) IsZero() bool {
+	return q == nil || *q == OrgQuotas{}
+}
+
+// QuotaExceededError is the error of a request that exceeds a quota of its
+// organization.
+type QuotaExceededError struct {
+	// Quota is the name of the exceeded quota.
+	Quota string
+	// Limit is the value of the exceeded quota.
+	Limit int64
+	// RetryAfter is how long the request should be delayed before it is
+	// retried. It is zero if retrying alone does not help, such as when the
+	// organization has too many buckets.
+	RetryAfter time.Duration
+}
+
+// NewQuotaExceededError returns a too many requests error for the exceeded
+// quota of the organization.
+func NewQuotaExceededError(op, quota string, limit int64, retryAfter time.Duration) *Error {
+	return &Error{
+		Code: ETooManyRequests,
+		Op:   op,
+		Msg:  fmt.Sprintf("organization quota %s of %d exceeded", quota, limit),
+		Err: &QuotaExceededError{
+			Quota:      quota,
+			Limit:      limit,
+			RetryAfter: retryAfter,
+		},
+	}
+}
+
+// Error implements the error interface.
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("organization quota %s of %d exceeded", e.Quota, e.Limit)
+}
+
+// RetryAfter returns the retry delay of the quota error wrapped by err, or
+// zero if err does not wrap a quota error.
+func RetryAfter(err error) time.Duration {
+	switch e := err.(type) {
+	case *QuotaExceededError:
+		return e.RetryAfter
+	case *Error:
+		if e != nil && e.Err != nil {
+			return RetryAfter(e.Err)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+// Package quota enforces the resource quotas of organizations.
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/limiter"
+)
+
+// queryRetryAfter is how long a query that exceeds the concurrent queries
+// quota of its organization should be delayed before it is retried.
+const queryRetryAfter = time.Second
+
+// Enforcer limits the rate of the writes and the number and memory of the
+// queries of each organization to its quotas.
+type Enforcer struct {
+	// OrganizationService finds the quotas of the organizations of queries.
+	OrganizationService platform.OrganizationService
+
+	mu      sync.Mutex
+	writes  map[platform.ID]*limiter.Budget
+	queries map[platform.ID]limiter.Fixed
+}
+
+// NewEnforcer returns an Enforcer that finds the quotas of organizations with s.
+func NewEnforcer(s platform.OrganizationService) *Enforcer {
+	return &Enforcer{
+		OrganizationService: s,
+		writes:              make(map[platform.ID]*limiter.Budget),
+		queries:             make(map[platform.ID]limiter.Fixed),
+	}
+}
+
+// AllowWrite returns a too many requests error if the organization wrote more
+// than its write bytes per second quota allows.
+func (e *Enforcer) AllowWrite(org *platform.Organization) error {
+	b := e.writeBudget(org)
+	if b == nil {
+		return nil
+	}
+	if d := b.Wait(); d > 0 {
+		return platform.NewQuotaExceededError("quota/AllowWrite", platform.QuotaWriteBytesPerSecond, b.Rate(), d)
+	}
+	return nil
+}
+
+// RecordWrite spends the n bytes written by the organization from its write
+// bytes per second quota.
+func (e *Enforcer) RecordWrite(org *platform.Organization, n int64) {
+	if b := e.writeBudget(org); b != nil {
+		b.Spend(n)
+	}
+}
+
+// writeBudget returns the write budget of the organization, or nil if its
+// writes are not limited. The budget is replaced when the quota changes.
+func (e *Enforcer) writeBudget(org *platform.Organization) *limiter.Budget {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if org.Quotas == nil || org.Quotas.WriteBytesPerSecond <= 0 {
+		delete(e.writes, org.ID)
+		return nil
+	}
+
+	rate := org.Quotas.WriteBytesPerSecond
+	b, ok := e.writes[org.ID]
+	if !ok || b.Rate() != rate {
+		// The burst is a second of writes.
+		b = limiter.NewBudget(rate, rate)
+		e.writes[org.ID] = b
+	}
+	return b
+}
+
+// StartQuery starts a query of the organization. It returns a too many
+// requests error if the organization already runs as many queries as its
+// concurrent queries quota allows. Otherwise it returns the function that
+// must be called when the query is done, and the memory the query may use,
+// which is zero if it is not limited.
+func (e *Enforcer) StartQuery(ctx context.Context, orgID platform.ID) (func(), int64, error) {
+	org, err := e.OrganizationService.FindOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	release := func() {}
+	if q := e.queryLimiter(org); q != nil {
+		if !q.TryTake() {
+			return nil, 0, platform.NewQuotaExceededError("quota/StartQuery", platform.QuotaConcurrentQueries, int64(q.Capacity()), queryRetryAfter)
+		}
+		release = q.Release
+	}
+
+	var memory int64
+	if org.Quotas != nil {
+		memory = org.Quotas.QueryMemoryBytes
+	}
+	return release, memory, nil
+}
+
+// queryLimiter returns the concurrent queries limiter of the organization,
+// or nil if its queries are not limited. The limiter is replaced when the
+// quota changes. Queries started with the previous limiter release it.
+func (e *Enforcer) queryLimiter(org *platform.Organization) limiter.Fixed {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if org.Quotas == nil || org.Quotas.ConcurrentQueries <= 0 {
+		delete(e.queries, org.ID)
+		return nil
+	}
+
+	n := org.Quotas.ConcurrentQueries
+	q, ok := e.queries[org.ID]
+	if !ok || q.Capacity() != n {
+		q = limiter.NewFixed(n)
+		e.queries[org.ID] = q
+	}
+	return q
+}
@@ -0,0 +1,79 @@
+package quota_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/quota"
+)
+
+func TestEnforcer_AllowWrite(t *testing.T) {
+	e := quota.NewEnforcer(nil)
+
+	org := &platform.Organization{ID: 1}
+	e.RecordWrite(org, 1<<20)
+	if err := e.AllowWrite(org); err != nil {
+		t.Fatalf("unexpected error for organization without quotas: %v", err)
+	}
+
+	org.Quotas = &platform.OrgQuotas{WriteBytesPerSecond: 100}
+	if err := e.AllowWrite(org); err != nil {
+		t.Fatalf("unexpected error for first write: %v", err)
+	}
+
+	e.RecordWrite(org, 150)
+	err := e.AllowWrite(org)
+	if code := platform.ErrorCode(err); code != platform.ETooManyRequests {
+		t.Fatalf("unexpected error code: got %q, want %q", code, platform.ETooManyRequests)
+	}
+	if d := platform.RetryAfter(err); d <= 0 {
+		t.Fatalf("unexpected retry after: %v", d)
+	}
+
+	// A higher quota replaces the spent budget.
+	org.Quotas = &platform.OrgQuotas{WriteBytesPerSecond: 1000}
+	if err := e.AllowWrite(org); err != nil {
+		t.Fatalf("unexpected error after the quota was raised: %v", err)
+	}
+}
+
+func TestEnforcer_StartQuery(t *testing.T) {
+	quotas := &platform.OrgQuotas{ConcurrentQueries: 2, QueryMemoryBytes: 1024}
+	e := quota.NewEnforcer(&mock.OrganizationService{
+		FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			return &platform.Organization{ID: id, Quotas: quotas}, nil
+		},
+	})
+
+	ctx := context.Background()
+	release1, memory, err := e.StartQuery(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memory != 1024 {
+		t.Fatalf("unexpected query memory: got %d, want %d", memory, 1024)
+	}
+	if _, _, err := e.StartQuery(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = e.StartQuery(ctx, 1)
+	if code := platform.ErrorCode(err); code != platform.ETooManyRequests {
+		t.Fatalf("unexpected error code: got %q, want %q", code, platform.ETooManyRequests)
+	}
+	if d := platform.RetryAfter(err); d <= 0 {
+		t.Fatalf("unexpected retry after: %v", d)
+	}
+
+	// Queries of other organizations are limited separately.
+	if _, _, err := e.StartQuery(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	release1()
+	if _, _, err := e.StartQuery(ctx, 1); err != nil {
+		t.Fatalf("unexpected error after a query was done: %v", err)
+	}
+}
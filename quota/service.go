@@ -0,0 +1,113 @@
+package quota
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.BucketService = (*BucketService)(nil)
+
+// BucketService wraps a platform.BucketService and rejects the buckets that
+// would take their organization over its max buckets quota.
+type BucketService struct {
+	platform.BucketService
+	OrganizationService platform.OrganizationService
+}
+
+// NewBucketService returns a BucketService that limits the buckets of s.
+func NewBucketService(s platform.BucketService, orgs platform.OrganizationService) *BucketService {
+	return &BucketService{
+		BucketService:       s,
+		OrganizationService: orgs,
+	}
+}
+
+// CreateBucket creates the bucket if its organization has fewer buckets than
+// its quota allows.
+func (s *BucketService) CreateBucket(ctx context.Context, b *platform.Bucket) error {
+	org, err := findOrganization(ctx, s.OrganizationService, b.OrganizationID, b.Organization)
+	if err != nil {
+		return err
+	}
+
+	if org.Quotas != nil && org.Quotas.MaxBuckets > 0 {
+		_, n, err := s.BucketService.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &org.ID})
+		if err != nil {
+			return err
+		}
+		if n >= org.Quotas.MaxBuckets {
+			return platform.NewQuotaExceededError("quota/CreateBucket", platform.QuotaMaxBuckets, int64(org.Quotas.MaxBuckets), 0)
+		}
+	}
+	return s.BucketService.CreateBucket(ctx, b)
+}
+
+var _ platform.TaskService = (*TaskService)(nil)
+
+// TaskService wraps a platform.TaskService and rejects the tasks that would
+// take their organization over its max tasks quota.
+type TaskService struct {
+	platform.TaskService
+	OrganizationService platform.OrganizationService
+}
+
+// NewTaskService returns a TaskService that limits the tasks of s.
+func NewTaskService(s platform.TaskService, orgs platform.OrganizationService) *TaskService {
+	return &TaskService{
+		TaskService:         s,
+		OrganizationService: orgs,
+	}
+}
+
+// CreateTask creates the task if its organization has fewer tasks than its
+// quota allows.
+func (s *TaskService) CreateTask(ctx context.Context, t platform.TaskCreate) (*platform.Task, error) {
+	org, err := findOrganization(ctx, s.OrganizationService, t.OrganizationID, t.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	if org.Quotas != nil && org.Quotas.MaxTasks > 0 {
+		n, err := s.countTasks(ctx, org.ID, org.Quotas.MaxTasks)
+		if err != nil {
+			return nil, err
+		}
+		if n >= org.Quotas.MaxTasks {
+			return nil, platform.NewQuotaExceededError("quota/CreateTask", platform.QuotaMaxTasks, int64(org.Quotas.MaxTasks), 0)
+		}
+	}
+	return s.TaskService.CreateTask(ctx, t)
+}
+
+// countTasks returns the number of tasks of the organization, counting no
+// further than max.
+func (s *TaskService) countTasks(ctx context.Context, orgID platform.ID, max int) (int, error) {
+	filter := platform.TaskFilter{
+		OrganizationID: &orgID,
+		Limit:          platform.TaskMaxPageSize,
+	}
+
+	var n int
+	for n < max {
+		tasks, _, err := s.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		n += len(tasks)
+		if len(tasks) < filter.Limit {
+			break
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+	return n, nil
+}
+
+// findOrganization finds an organization by its ID or, if it is not valid,
+// by its name.
+func findOrganization(ctx context.Context, s platform.OrganizationService, id platform.ID, name string) (*platform.Organization, error) {
+	if id.Valid() {
+		return s.FindOrganizationByID(ctx, id)
+	}
+	return s.FindOrganization(ctx, platform.OrganizationFilter{Name: &name})
+}
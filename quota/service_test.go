@@ -0,0 +1,84 @@
+package quota_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/quota"
+)
+
+func newOrganizationService(quotas *platform.OrgQuotas) *mock.OrganizationService {
+	return &mock.OrganizationService{
+		FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			return &platform.Organization{ID: id, Name: "o", Quotas: quotas}, nil
+		},
+		FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+			return &platform.Organization{ID: 1, Name: *filter.Name, Quotas: quotas}, nil
+		},
+	}
+}
+
+func TestBucketService_CreateBucket(t *testing.T) {
+	var created int
+	buckets := mock.NewBucketService()
+	buckets.FindBucketsFn = func(ctx context.Context, filter platform.BucketFilter, opt ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+		if filter.OrganizationID == nil || *filter.OrganizationID != 1 {
+			t.Fatalf("unexpected bucket filter: %+v", filter)
+		}
+		return make([]*platform.Bucket, created), created, nil
+	}
+	buckets.CreateBucketFn = func(ctx context.Context, b *platform.Bucket) error {
+		created++
+		return nil
+	}
+
+	s := quota.NewBucketService(buckets, newOrganizationService(&platform.OrgQuotas{MaxBuckets: 2}))
+
+	ctx := context.Background()
+	if err := s.CreateBucket(ctx, &platform.Bucket{OrganizationID: 1, Name: "b1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateBucket(ctx, &platform.Bucket{Organization: "o", Name: "b2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.CreateBucket(ctx, &platform.Bucket{OrganizationID: 1, Name: "b3"})
+	if code := platform.ErrorCode(err); code != platform.ETooManyRequests {
+		t.Fatalf("unexpected error code: got %q, want %q", code, platform.ETooManyRequests)
+	}
+	if created != 2 {
+		t.Fatalf("unexpected number of created buckets: got %d, want %d", created, 2)
+	}
+}
+
+func TestTaskService_CreateTask(t *testing.T) {
+	var tasks []*platform.Task
+	s := quota.NewTaskService(&mock.TaskService{
+		FindTasksFn: func(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+			if filter.OrganizationID == nil || *filter.OrganizationID != 1 {
+				t.Fatalf("unexpected task filter: %+v", filter)
+			}
+			return tasks, len(tasks), nil
+		},
+		CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+			task := &platform.Task{ID: platform.ID(len(tasks) + 1), OrganizationID: tc.OrganizationID}
+			tasks = append(tasks, task)
+			return task, nil
+		},
+	}, newOrganizationService(&platform.OrgQuotas{MaxTasks: 1}))
+
+	ctx := context.Background()
+	if _, err := s.CreateTask(ctx, platform.TaskCreate{OrganizationID: 1, Flux: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s.CreateTask(ctx, platform.TaskCreate{OrganizationID: 1, Flux: "x"})
+	if code := platform.ErrorCode(err); code != platform.ETooManyRequests {
+		t.Fatalf("unexpected error code: got %q, want %q", code, platform.ETooManyRequests)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("unexpected number of created tasks: got %d, want %d", len(tasks), 1)
+	}
+}
@@ -0,0 +1,401 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidBackupFile is returned when a backup file name does not refer to
+// a file within the backup.
+var ErrInvalidBackupFile = errors.New("invalid backup file")
+
+// BackupExpiry is how long a backup that was never finished is kept before it
+// is removed.
+var BackupExpiry = 24 * time.Hour
+
+// restoreBatchSize is the number of series created in the index at once when
+// rebuilding it from restored TSM files.
+const restoreBatchSize = 10000
+
+// CreateBackup creates a "snapshot" of all TSM data in the Engine.
+//  1. Snapshot the cache to ensure the backup includes all data written before now.
+//  2. Create hard links to all TSM files, in a new directory within the engine root directory.
+//  3. Return a unique backup ID (invalid after the process terminates) and list of files.
+func (e *Engine) CreateBackup(ctx context.Context) (int, []string, error) {
+	e.mu.RLock()
+	closing := e.closing
+	e.mu.RUnlock()
+	if closing == nil {
+		return 0, nil, ErrEngineClosed
+	}
+
+	e.removeExpiredBackups()
+
+	path, err := e.engine.CreateSnapshot()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	id, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(path), "."+tsm1.TmpTSMFileExtension))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	fis, err := ioutil.ReadDir(path)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	files := make([]string, 0, len(fis))
+	for _, fi := range fis {
+		files = append(files, fi.Name())
+	}
+
+	return id, files, nil
+}
+
+// FetchBackupFile writes a given backup file to the provided writer.
+// The file is kept, so that a failed download can be retried, until the backup
+// is finished or expires.
+func (e *Engine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	if backupFile == "" || filepath.Base(backupFile) != backupFile {
+		return ErrInvalidBackupFile
+	}
+
+	backupPath, err := e.InternalBackupPath(backupID)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(backupPath, backupFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &platform.Error{
+				Code: platform.ENotFound,
+				Op:   "storage/FetchBackupFile",
+				Msg:  fmt.Sprintf("backup file %q not found", backupFile),
+			}
+		}
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// FinishBackup removes the internal copy of the backup.
+func (e *Engine) FinishBackup(ctx context.Context, backupID int) error {
+	backupPath, err := e.InternalBackupPath(backupID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+		return &platform.Error{
+			Code: platform.ENotFound,
+			Op:   "storage/FinishBackup",
+			Msg:  fmt.Sprintf("backup %d not found", backupID),
+		}
+	}
+	return os.RemoveAll(backupPath)
+}
+
+// InternalBackupPath provides the internal, full path directory name of the backup.
+// This should not be exposed via API.
+func (e *Engine) InternalBackupPath(backupID int) (string, error) {
+	return e.engine.FileStore.InternalBackupPath(backupID), nil
+}
+
+// removeExpiredBackups removes the backups created more than BackupExpiry ago,
+// which were never finished by their client.
+func (e *Engine) removeExpiredBackups() {
+	paths, err := filepath.Glob(filepath.Join(e.engine.Path(), "*."+tsm1.TmpTSMFileExtension))
+	if err != nil {
+		e.logger.Error("Failed to list backups", zap.Error(err))
+		return
+	}
+
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil || !fi.IsDir() || time.Since(fi.ModTime()) < BackupExpiry {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			e.logger.Error("Failed to remove expired backup", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		e.logger.Info("Removed expired backup", zap.String("path", path))
+	}
+}
+
+// RestoreFilter limits a restore to the data of a single bucket.
+type RestoreFilter struct {
+	OrganizationID platform.ID
+	BucketID       platform.ID
+}
+
+// RestoreEngine restores the backup files found in src into a new engine
+// rooted at path. The engine data directory must not already contain any data.
+//
+// Only TSM data is restored; the series file and index are rebuilt from the
+// restored TSM files. If filter is not nil, only the data belonging to the
+// filtered bucket is restored.
+func RestoreEngine(path string, c Config, src string, files []string, filter *RestoreFilter, log *zap.Logger) error {
+	dataPath := c.GetEnginePath(path)
+	if fis, err := ioutil.ReadDir(dataPath); err == nil && len(fis) > 0 {
+		return fmt.Errorf("engine path %q is not empty", dataPath)
+	} else if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(dataPath, 0777); err != nil {
+		return err
+	}
+
+	var prefix []byte
+	if filter != nil {
+		name := tsdb.EncodeName(filter.OrganizationID, filter.BucketID)
+		prefix = models.EscapeMeasurement(name[:])
+	}
+
+	var tsmPaths []string
+	for _, file := range files {
+		if file == "" || filepath.Base(file) != file {
+			return ErrInvalidBackupFile
+		}
+
+		ext := filepath.Ext(file)
+		if filter != nil {
+			// Tombstones are applied while filtering, so only TSM files are needed.
+			if ext != "."+tsm1.TSMFileExtension {
+				continue
+			}
+
+			dst := filepath.Join(dataPath, file)
+			ok, err := restoreTSMFilePrefix(filepath.Join(src, file), dst, prefix)
+			if err != nil {
+				return err
+			} else if ok {
+				tsmPaths = append(tsmPaths, dst)
+			}
+			continue
+		}
+
+		dst := filepath.Join(dataPath, file)
+		if err := copyFile(filepath.Join(src, file), dst); err != nil {
+			return err
+		}
+		if ext == "."+tsm1.TSMFileExtension {
+			tsmPaths = append(tsmPaths, dst)
+		}
+	}
+
+	log.Info("Restored TSM files", zap.Int("count", len(tsmPaths)), zap.String("path", dataPath))
+
+	return rebuildIndex(path, c, tsmPaths, log)
+}
+
+// restoreTSMFilePrefix writes the keys of the TSM file src beginning with
+// prefix to a new TSM file at dst. It returns false if no data was written.
+func restoreTSMFilePrefix(src, dst string, prefix []byte) (bool, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	fd, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return false, err
+	}
+	defer fd.Close()
+
+	w, err := tsm1.NewTSMWriter(fd)
+	if err != nil {
+		return false, err
+	}
+
+	var n int
+	iter := r.Iterator(prefix)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+
+		values, err := r.ReadAll(key)
+		if err != nil {
+			return false, err
+		}
+
+		for len(values) > 0 {
+			sz := len(values)
+			if sz > tsm1.MaxPointsPerBlock {
+				sz = tsm1.MaxPointsPerBlock
+			}
+			if err := w.Write(key, values[:sz]); err != nil {
+				return false, err
+			}
+			values = values[sz:]
+			n++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return false, err
+	}
+
+	if n == 0 {
+		fd.Close()
+		return false, os.Remove(dst)
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		return false, err
+	}
+	return true, w.Close()
+}
+
+// rebuildIndex creates the series file and index for an engine rooted at path
+// from the keys of the provided TSM files.
+func rebuildIndex(path string, c Config, tsmPaths []string, log *zap.Logger) error {
+	sfile := tsdb.NewSeriesFile(c.GetSeriesFilePath(path))
+	sfile.WithLogger(log)
+	if err := sfile.Open(); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	index := tsi1.NewIndex(sfile, c.Index, tsi1.WithPath(c.GetIndexPath(path)))
+	index.WithLogger(log)
+	if err := index.Open(); err != nil {
+		return err
+	}
+	defer index.Close()
+
+	for _, tsmPath := range tsmPaths {
+		if err := indexTSMFile(index, tsmPath); err != nil {
+			return err
+		}
+	}
+
+	index.Compact()
+	index.Wait()
+
+	if err := index.Close(); err != nil {
+		return err
+	}
+	return sfile.Close()
+}
+
+// indexTSMFile adds the series of every key in the TSM file at path to index.
+func indexTSMFile(index *tsi1.Index, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	collection := &tsdb.SeriesCollection{
+		Keys:  make([][]byte, 0, restoreBatchSize),
+		Names: make([][]byte, 0, restoreBatchSize),
+		Tags:  make([]models.Tags, 0, restoreBatchSize),
+		Types: make([]models.FieldType, 0, restoreBatchSize),
+	}
+
+	iter := r.Iterator(nil)
+	for iter.Next() {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(iter.Key())
+		name, tags := models.ParseKeyBytes(seriesKey)
+
+		collection.Keys = append(collection.Keys, append([]byte(nil), seriesKey...))
+		collection.Names = append(collection.Names, name)
+		collection.Tags = append(collection.Tags, tags)
+		collection.Types = append(collection.Types, blockToFieldType(iter.Type()))
+
+		// Flush batch?
+		if collection.Length() == restoreBatchSize {
+			if err := index.CreateSeriesListIfNotExists(collection); err != nil {
+				return fmt.Errorf("problem creating series: (%s)", err)
+			}
+			collection.Truncate(0)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if collection.Length() > 0 {
+		if err := index.CreateSeriesListIfNotExists(collection); err != nil {
+			return fmt.Errorf("problem creating series: (%s)", err)
+		}
+	}
+	return nil
+}
+
+func blockToFieldType(block byte) models.FieldType {
+	switch block {
+	case tsm1.BlockFloat64:
+		return models.Float
+	case tsm1.BlockInteger:
+		return models.Integer
+	case tsm1.BlockBoolean:
+		return models.Boolean
+	case tsm1.BlockString:
+		return models.String
+	case tsm1.BlockUnsigned:
+		return models.Unsigned
+	default:
+		return models.Empty
+	}
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,134 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+func TestEngine_BackupRestore(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	otherBucket := influxdb.ID(0x3333333333333333)
+
+	var points []models.Point
+	for _, bucket := range []influxdb.ID{engine.bucket, otherBucket} {
+		name := tsdb.EncodeNameString(engine.org, bucket)
+		points = append(points,
+			models.MustNewPoint(
+				name,
+				models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": "a"}),
+				map[string]interface{}{"value": 1.0},
+				time.Unix(1, 2),
+			),
+			models.MustNewPoint(
+				name,
+				models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": "b"}),
+				map[string]interface{}{"value": 1.0},
+				time.Unix(1, 2),
+			),
+		)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	id, files, err := engine.CreateBackup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("expected backup files")
+	}
+
+	src, err := ioutil.TempDir("", "storage_backup_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	for _, f := range files {
+		var buf bytes.Buffer
+		if err := engine.FetchBackupFile(context.Background(), id, f, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(src, f), buf.Bytes(), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Fetched files are kept, so a failed download can be retried.
+	if err := engine.FetchBackupFile(context.Background(), id, files[0], ioutil.Discard); err != nil {
+		t.Fatalf("expected backup file to be fetched again: %v", err)
+	}
+
+	// Finishing the backup removes the backup directory.
+	if err := engine.FinishBackup(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	backupPath, err := engine.InternalBackupPath(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(backupPath); !os.IsNotExist(err) {
+		t.Fatalf("expected backup directory to be removed: %v", err)
+	}
+	if err := engine.FinishBackup(context.Background(), id); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected finishing a removed backup to be not found, got %v", err)
+	}
+
+	if err := engine.FetchBackupFile(context.Background(), id, "../"+files[0], ioutil.Discard); err != storage.ErrInvalidBackupFile {
+		t.Fatalf("unexpected error: got %v, exp %v", err, storage.ErrInvalidBackupFile)
+	}
+
+	tests := []struct {
+		name   string
+		filter *storage.RestoreFilter
+		exp    int64
+	}{
+		{name: "all buckets", exp: 4},
+		{name: "single bucket", filter: &storage.RestoreFilter{OrganizationID: engine.org, BucketID: otherBucket}, exp: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ioutil.TempDir("", "storage_restore_test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(path)
+
+			c := storage.NewConfig()
+			if err := storage.RestoreEngine(path, c, src, files, tt.filter, zap.NewNop()); err != nil {
+				t.Fatal(err)
+			}
+
+			// Restoring into a non-empty engine path fails.
+			if err := storage.RestoreEngine(path, c, src, files, tt.filter, zap.NewNop()); err == nil {
+				t.Fatal("expected error: got nil")
+			}
+
+			restored := storage.NewEngine(path, c)
+			if err := restored.Open(); err != nil {
+				t.Fatal(err)
+			}
+			defer restored.Close()
+
+			if got, exp := restored.SeriesCardinality(), tt.exp; got != exp {
+				t.Fatalf("got %d series, exp %d series in index", got, exp)
+			}
+		})
+	}
+}
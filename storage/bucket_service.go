@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
 )
 
 // BucketDeleter defines the behaviour of deleting a bucket.
@@ -12,6 +14,19 @@ type BucketDeleter interface {
 	DeleteBucket(platform.ID, platform.ID) error
 }
 
+// PredicateDeleter defines the behaviour of deleting the series within a
+// bucket and time range that match a predicate.
+type PredicateDeleter interface {
+	DeleteBucketRangePredicate(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error
+}
+
+// SchemaReader defines the behaviour of reading the measurements and the
+// fields of the series within a bucket and time range that match a predicate.
+type SchemaReader interface {
+	MeasurementNames(ctx context.Context, orgID, bucketID platform.ID, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error)
+	MeasurementFields(ctx context.Context, orgID, bucketID platform.ID, measurement string, start, end int64, predicate influxql.Expr) (cursors.MeasurementFieldIterator, error)
+}
+
 // BucketService wraps an existing platform.BucketService implementation.
 //
 // BucketService ensures that when a bucket is deleted, all stored data
@@ -0,0 +1,306 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+)
+
+// Names of the cardinality limits, as they appear in drop reasons and metrics.
+const (
+	maxSeriesPerBucketLimit = "max-series-per-bucket"
+	maxSeriesPerOrgLimit    = "max-series-per-org"
+	maxValuesPerTagLimit    = "max-values-per-tag"
+)
+
+// tagValuesKey identifies a tag key of a bucket. The name is the encoded org
+// and bucket ID of the series.
+type tagValuesKey struct {
+	name string
+	key  string
+}
+
+// tagValue is a tag value of a bucket.
+type tagValue struct {
+	tagValuesKey
+	value string
+}
+
+// cardinalityLimiter drops the points of new series that would take a bucket
+// or an organization over its series limit, or a tag key of a bucket over its
+// limit of values. Points of existing series are never dropped.
+type cardinalityLimiter struct {
+	maxSeriesPerBucket int
+	maxSeriesPerOrg    int
+	maxValuesPerTag    int
+
+	// mu serializes the writes of new series, so that concurrent writes cannot
+	// together exceed a limit, and protects the series counts.
+	mu sync.Mutex
+
+	// bucketSeries and orgSeries are the number of series of the buckets, by
+	// encoded name, and of the organizations. They are loaded from the index
+	// the first time a series limit is checked, and kept up to date as new
+	// series are admitted.
+	bucketSeries map[string]int
+	orgSeries    map[platform.ID]int
+
+	// tagValues are the number of values of the tag keys of buckets. They are
+	// loaded from the index the first time a new value of a tag key is written.
+	tagValuesMu sync.Mutex
+	tagValues   map[tagValuesKey]int
+
+	metrics *cardinalityMetrics
+}
+
+func newCardinalityLimiter(c Config) *cardinalityLimiter {
+	return &cardinalityLimiter{
+		maxSeriesPerBucket: c.MaxSeriesPerBucket,
+		maxSeriesPerOrg:    c.MaxSeriesPerOrg,
+		maxValuesPerTag:    c.MaxValuesPerTag,
+		tagValues:          make(map[tagValuesKey]int),
+		metrics:            newCardinalityMetrics(nil),
+	}
+}
+
+// enabled returns true if any limit is set.
+func (l *cardinalityLimiter) enabled() bool {
+	return l.maxSeriesPerBucket > 0 || l.maxSeriesPerOrg > 0 || l.maxValuesPerTag > 0
+}
+
+// check drops the points of new series of the collection that would exceed a
+// limit. The caller must hold l.mu until the series of the collection exist.
+func (l *cardinalityLimiter) check(collection *tsdb.SeriesCollection, index *tsi1.Index, sfile *tsdb.SeriesFile) error {
+	var (
+		buf       []byte
+		newSeries = make(map[string]struct{})
+		newValues = make(map[tagValue]struct{})
+	)
+	if (l.maxSeriesPerBucket > 0 || l.maxSeriesPerOrg > 0) && l.bucketSeries == nil {
+		l.loadSeries(index)
+	}
+
+	j := 0
+	for iter := collection.Iterator(); iter.Next(); {
+		name, tags := iter.Name(), iter.Tags()
+		if _, ok := newSeries[string(iter.Key())]; ok || sfile.HasSeries(name, tags, buf) {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+
+		values, reason, err := l.exceeded(name, tags, index, newValues)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			if collection.Reason == "" {
+				collection.Reason = reason
+			}
+			collection.Dropped++
+			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
+			continue
+		}
+
+		// The series will be created, so it counts towards the limits of the
+		// remaining points.
+		newSeries[string(iter.Key())] = struct{}{}
+		if l.bucketSeries != nil {
+			l.bucketSeries[string(name)]++
+			if org, _, ok := decodeName(name); ok {
+				l.orgSeries[org]++
+			}
+		}
+		for _, v := range values {
+			newValues[v] = struct{}{}
+			l.addTagValue(v.tagValuesKey)
+		}
+
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+	return nil
+}
+
+// exceeded returns the reason the new series with the name and tags would
+// exceed a limit, or the new tag values of the series if it would not.
+func (l *cardinalityLimiter) exceeded(name []byte, tags models.Tags, index *tsi1.Index, newValues map[tagValue]struct{}) ([]tagValue, string, error) {
+	if l.maxSeriesPerBucket > 0 {
+		if n := l.bucketSeries[string(name)]; n >= l.maxSeriesPerBucket {
+			l.metrics.drop(maxSeriesPerBucketLimit)
+			return nil, fmt.Sprintf("%s limit exceeded: (%d/%d)", maxSeriesPerBucketLimit, n, l.maxSeriesPerBucket), nil
+		}
+	}
+
+	if l.maxSeriesPerOrg > 0 {
+		org, _, _ := decodeName(name)
+		if n := l.orgSeries[org]; n >= l.maxSeriesPerOrg {
+			l.metrics.drop(maxSeriesPerOrgLimit)
+			return nil, fmt.Sprintf("%s limit exceeded: (%d/%d)", maxSeriesPerOrgLimit, n, l.maxSeriesPerOrg), nil
+		}
+	}
+
+	if l.maxValuesPerTag <= 0 {
+		return nil, "", nil
+	}
+
+	var values []tagValue
+	for _, t := range tags {
+		// The measurement and field of a series are not tags of the bucket.
+		if bytes.Equal(t.Key, tsdb.MeasurementTagKeyBytes) || bytes.Equal(t.Key, tsdb.FieldKeyTagKeyBytes) {
+			continue
+		}
+
+		v := tagValue{tagValuesKey{name: string(name), key: string(t.Key)}, string(t.Value)}
+		if _, ok := newValues[v]; ok {
+			continue
+		}
+		if ok, err := index.HasTagValue(name, t.Key, t.Value); err != nil {
+			return nil, "", err
+		} else if ok {
+			continue
+		}
+
+		n, err := l.tagValueCount(v.tagValuesKey, index)
+		if err != nil {
+			return nil, "", err
+		}
+		if n >= l.maxValuesPerTag {
+			l.metrics.drop(maxValuesPerTagLimit)
+			return nil, fmt.Sprintf("%s limit exceeded (%d/%d): tag=%q value=%q", maxValuesPerTagLimit, n, l.maxValuesPerTag, t.Key, t.Value), nil
+		}
+		values = append(values, v)
+	}
+	return values, "", nil
+}
+
+// tagValueCount returns the number of values of the tag key of a bucket.
+func (l *cardinalityLimiter) tagValueCount(key tagValuesKey, index *tsi1.Index) (int, error) {
+	l.tagValuesMu.Lock()
+	n, ok := l.tagValues[key]
+	l.tagValuesMu.Unlock()
+	if ok {
+		return n, nil
+	}
+
+	n, err := countTagValues(index, []byte(key.name), []byte(key.key))
+	if err != nil {
+		return 0, err
+	}
+
+	l.tagValuesMu.Lock()
+	l.tagValues[key] = n
+	l.tagValuesMu.Unlock()
+	return n, nil
+}
+
+func (l *cardinalityLimiter) addTagValue(key tagValuesKey) {
+	l.tagValuesMu.Lock()
+	l.tagValues[key]++
+	l.tagValuesMu.Unlock()
+}
+
+// loadSeries loads the number of series of each bucket and organization from
+// the index. l.mu must be held.
+func (l *cardinalityLimiter) loadSeries(index *tsi1.Index) {
+	stats := index.MeasurementCardinalityStats()
+	l.bucketSeries = make(map[string]int, len(stats))
+	l.orgSeries = make(map[platform.ID]int)
+	for name, n := range stats {
+		l.bucketSeries[name] = n
+		if org, _, ok := decodeName([]byte(name)); ok {
+			l.orgSeries[org] += n
+		}
+	}
+}
+
+// reset forgets the number of series and the number of values of the tag keys
+// of the bucket with the encoded name, because series of the bucket were
+// deleted. The series counts are loaded again by the next check.
+func (l *cardinalityLimiter) reset(name []byte) {
+	l.mu.Lock()
+	l.bucketSeries, l.orgSeries = nil, nil
+	l.mu.Unlock()
+
+	l.tagValuesMu.Lock()
+	defer l.tagValuesMu.Unlock()
+	for key := range l.tagValues {
+		if key.name == string(name) {
+			delete(l.tagValues, key)
+		}
+	}
+}
+
+// countTagValues returns the number of values of a tag key of the bucket with
+// the encoded name.
+func countTagValues(index *tsi1.Index, name, key []byte) (int, error) {
+	itr, err := index.TagValueIterator(name, key)
+	if err != nil {
+		return 0, err
+	} else if itr == nil {
+		return 0, nil
+	}
+	defer itr.Close()
+
+	var n int
+	for {
+		v, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if v == nil {
+			return n, nil
+		}
+		n++
+	}
+}
+
+// maxTagValues returns the largest number of values of a tag key of the
+// bucket with the encoded name, ignoring the measurement and field keys.
+func maxTagValues(index *tsi1.Index, name []byte) (int, error) {
+	itr, err := index.TagKeyIterator(name)
+	if err != nil {
+		return 0, err
+	} else if itr == nil {
+		return 0, nil
+	}
+	defer itr.Close()
+
+	var max int
+	for {
+		key, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if key == nil {
+			return max, nil
+		}
+		if bytes.Equal(key, tsdb.MeasurementTagKeyBytes) || bytes.Equal(key, tsdb.FieldKeyTagKeyBytes) {
+			continue
+		}
+
+		n, err := countTagValues(index, name, key)
+		if err != nil {
+			return 0, err
+		}
+		if n > max {
+			max = n
+		}
+	}
+}
+
+// decodeName returns the organization and bucket of an encoded name, and
+// false if name is not an encoded name.
+func decodeName(name []byte) (org, bucket platform.ID, ok bool) {
+	var encoded [16]byte
+	if len(name) != len(encoded) {
+		return 0, 0, false
+	}
+	copy(encoded[:], name)
+	org, bucket = tsdb.DecodeName(encoded)
+	return org, bucket, true
+}
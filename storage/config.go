@@ -31,6 +31,18 @@ type Config struct {
 	// Enables trace logging for the engine.
 	TraceLoggingEnabled bool `toml:"trace-logging-enabled"`
 
+	// Maximum number of series of a bucket. Points of new series past the
+	// limit are dropped. Zero is no limit.
+	MaxSeriesPerBucket int `toml:"max-series-per-bucket"`
+
+	// Maximum number of series of an organization, across all of its buckets.
+	// Zero is no limit.
+	MaxSeriesPerOrg int `toml:"max-series-per-org"`
+
+	// Maximum number of values of a tag key of a bucket. Points of new series
+	// with a new value past the limit are dropped. Zero is no limit.
+	MaxValuesPerTag int `toml:"max-values-per-tag"`
+
 	// Series file config.
 	SeriesFilePath string `toml:"series-file-path"` // Overrides the default path.
 
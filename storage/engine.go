@@ -14,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
 	"github.com/influxdata/influxdb/tsdb/tsi1"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/influxdata/influxdb/tsdb/value"
@@ -25,6 +26,12 @@ import (
 // Static objects to prevent small allocs.
 var timeBytes = []byte("time")
 
+// Keys used by callers to refer to the measurement and field of a series.
+const (
+	measurementKey = "_measurement"
+	fieldKey       = "_field"
+)
+
 // ErrEngineClosed is returned when a caller attempts to use the engine while
 // it's closed.
 var ErrEngineClosed = errors.New("engine is closed")
@@ -41,6 +48,9 @@ type Engine struct {
 	sfile             *tsdb.SeriesFile
 	engine            *tsm1.Engine
 	wal               *wal.WAL
+	fieldTypes        *fieldTypes
+	limits            *cardinalityLimiter
+	usage             platform.UsageRecorder
 	retentionEnforcer *retentionEnforcer
 
 	defaultMetricLabels prometheus.Labels
@@ -111,6 +121,7 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 		path:                path,
 		defaultMetricLabels: prometheus.Labels{},
 		logger:              zap.NewNop(),
+		limits:              newCardinalityLimiter(c),
 	}
 
 	// Initialize series file.
@@ -144,6 +155,12 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 		e.wal.SetDefaultMetricLabels(e.defaultMetricLabels)
 	}
 
+	e.limits.metrics = newCardinalityMetrics(e.defaultMetricLabels)
+	e.limits.metrics.seriesStats = func() map[string]int { return e.index.MeasurementCardinalityStats() }
+	e.limits.metrics.setLimit(maxSeriesPerBucketLimit, c.MaxSeriesPerBucket)
+	e.limits.metrics.setLimit(maxSeriesPerOrgLimit, c.MaxSeriesPerOrg)
+	e.limits.metrics.setLimit(maxValuesPerTagLimit, c.MaxValuesPerTag)
+
 	return e
 }
 
@@ -167,6 +184,12 @@ func (e *Engine) WithLogger(log *zap.Logger) {
 	e.retentionEnforcer.WithLogger(e.logger)
 }
 
+// WithUsageRecorder sets the recorder of the values and series written to the
+// engine. It must be called before Open.
+func (e *Engine) WithUsageRecorder(r platform.UsageRecorder) {
+	e.usage = r
+}
+
 // PrometheusCollectors returns all the prometheus collectors associated with
 // the engine and its components.
 func (e *Engine) PrometheusCollectors() []prometheus.Collector {
@@ -176,6 +199,7 @@ func (e *Engine) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, tsm1.PrometheusCollectors()...)
 	metrics = append(metrics, wal.PrometheusCollectors()...)
 	metrics = append(metrics, e.retentionEnforcer.PrometheusCollectors()...)
+	metrics = append(metrics, e.limits.metrics.PrometheusCollectors()...)
 	return metrics
 }
 
@@ -203,6 +227,13 @@ func (e *Engine) Open() (err error) {
 		return err
 	}
 
+	// Load the types of the stored fields so that writes can be checked for
+	// field type conflicts.
+	e.fieldTypes = newFieldTypes()
+	if err := e.fieldTypes.load(e.engine); err != nil {
+		return err
+	}
+
 	e.closing = make(chan struct{})
 
 	// TODO(edd) background tasks will be run in priority order via a scheduler.
@@ -247,7 +278,15 @@ func (e *Engine) replayWAL() error {
 			return err
 
 		case *wal.DeleteBucketRangeWALEntry:
-			return e.deleteBucketRangeLocked(en.OrgID, en.BucketID, en.Min, en.Max)
+			if len(en.Predicate) == 0 {
+				return e.deleteBucketRangeLocked(en.OrgID, en.BucketID, en.Min, en.Max)
+			}
+
+			pred, err := influxql.ParseExpr(string(en.Predicate))
+			if err != nil {
+				return err
+			}
+			return e.deleteBucketRangePredicateLocked(en.OrgID, en.BucketID, en.Min, en.Max, pred)
 		}
 
 		return nil
@@ -298,6 +337,7 @@ func (e *Engine) runRetentionEnforcer() {
 				return
 			case <-ticker.C:
 				e.retentionEnforcer.run()
+				e.removeExpiredBackups()
 			}
 		}
 	}()
@@ -350,6 +390,63 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 	return e.engine.CreateCursorIterator(ctx)
 }
 
+// TagKeys returns an iterator over the sorted tag keys of the series of the
+// bucket that match the predicate and have data between start and end. The
+// measurement and field are the _m and _f keys, which the predicate must use
+// too. A nil predicate matches every series of the bucket.
+func (e *Engine) TagKeys(ctx context.Context, orgID, bucketID platform.ID, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	return e.engine.TagKeys(ctx, models.EscapeMeasurement(encoded[:]), start, end, predicate)
+}
+
+// TagValues returns an iterator over the sorted values of the tag key of the
+// series of the bucket that match the predicate and have data between start
+// and end. The key and the predicate are like those of TagKeys.
+func (e *Engine) TagValues(ctx context.Context, orgID, bucketID platform.ID, key string, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	return e.engine.TagValues(ctx, models.EscapeMeasurement(encoded[:]), []byte(key), start, end, predicate)
+}
+
+// MeasurementNames returns an iterator over the sorted names of the
+// measurements of the series of the bucket that match the predicate and have
+// data between start and end.
+func (e *Engine) MeasurementNames(ctx context.Context, orgID, bucketID platform.ID, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	return e.engine.MeasurementNames(ctx, models.EscapeMeasurement(encoded[:]), start, end, predicate)
+}
+
+// MeasurementFields returns an iterator over the keys and types of the fields
+// of the series of the measurement of the bucket that match the predicate and
+// have data between start and end.
+func (e *Engine) MeasurementFields(ctx context.Context, orgID, bucketID platform.ID, measurement string, start, end int64, predicate influxql.Expr) (cursors.MeasurementFieldIterator, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	return e.engine.MeasurementFields(ctx, models.EscapeMeasurement(encoded[:]), []byte(measurement), start, end, predicate)
+}
+
 // WritePoints writes the provided points to the engine.
 //
 // The Engine expects all points to have been correctly validated by the caller.
@@ -402,18 +499,76 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return ErrEngineClosed
 	}
 
+	// Drop any points whose field already exists with another type. The types
+	// of new fields are kept only if their points are stored.
+	fields := e.fieldTypes.begin()
+	j = 0
+	for iter := collection.Iterator(); iter.Next(); {
+		tags := iter.Tags()
+		measurement, field := tags.Get(tsdb.MeasurementTagKeyBytes), tags.Get(tsdb.FieldKeyTagKeyBytes)
+		if err := fields.check(iter.Name(), measurement, field, iter.Type()); err != nil {
+			if collection.Reason == "" {
+				collection.Reason = err.Error()
+			}
+			collection.Dropped++
+			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
+			continue
+		}
+
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+
+	// Drop any points of new series that would exceed a cardinality limit, and
+	// add the new series to the index and series file. The limiter is held
+	// until the series are created so that concurrent writes see them.
+	dropped := len(collection.DroppedKeys)
+	if err := e.createSeries(collection); err != nil {
+		fields.rollback()
+		return err
+	}
+	for _, key := range collection.DroppedKeys[dropped:] {
+		fields.drop(key)
+	}
+
 	// Convert the points to values for adding to the WAL/Cache.
 	values, err := tsm1.PointsToValues(collection.Points)
 	if err != nil {
+		fields.rollback()
 		return err
 	}
 
 	// Add the write to the WAL to be replayed if there is a crash or shutdown.
 	if _, err := e.wal.WriteMulti(values); err != nil {
+		fields.rollback()
 		return err
 	}
 
-	return e.writePointsLocked(collection, values)
+	err = e.writeValuesLocked(collection, values)
+	if _, ok := err.(tsdb.PartialWriteError); err == nil || ok {
+		fields.commit()
+		e.recordUsage(collection)
+	} else {
+		fields.rollback()
+	}
+	return err
+}
+
+// createSeries adds the new series of the collection that do not exceed a
+// cardinality limit to the index and series file.
+func (e *Engine) createSeries(collection *tsdb.SeriesCollection) error {
+	if !e.limits.enabled() {
+		return e.index.CreateSeriesListIfNotExists(collection)
+	}
+
+	e.limits.mu.Lock()
+	defer e.limits.mu.Unlock()
+
+	if err := e.limits.check(collection, e.index, e.sfile); err != nil {
+		return err
+	}
+	return e.index.CreateSeriesListIfNotExists(collection)
 }
 
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
@@ -437,6 +592,12 @@ func (e *Engine) writePointsLocked(collection *tsdb.SeriesCollection, values map
 		}
 	}
 
+	return e.writeValuesLocked(collection, values)
+}
+
+// writeValuesLocked writes the values of the points of the collection, whose
+// series must exist, and must be called under some sort of lock.
+func (e *Engine) writeValuesLocked(collection *tsdb.SeriesCollection, values map[string][]value.Value) error {
 	// Write the values to the engine.
 	if err := e.engine.WriteValues(values); err != nil {
 		return err
@@ -505,7 +666,114 @@ func (e *Engine) deleteBucketRangeLocked(orgID, bucketID platform.ID, min, max i
 	encoded := tsdb.EncodeName(orgID, bucketID)
 	name := models.EscapeMeasurement(encoded[:])
 
-	return e.engine.DeleteBucketRange(name, min, max)
+	if err := e.engine.DeleteBucketRange(name, min, max); err != nil {
+		return err
+	}
+	e.limits.reset(encoded[:])
+
+	// The fields of the bucket may be written with other types once all of
+	// its data is deleted.
+	if min == math.MinInt64 && max == math.MaxInt64 && e.fieldTypes != nil {
+		e.fieldTypes.deleteBucket(encoded[:])
+	}
+	return nil
+}
+
+// DeleteBucketRangePredicate deletes the data in a bucket between min and max
+// for the series matching pred. The predicate may reference tag keys, as well
+// as the _measurement and _field keys, and may only use the =, !=, =~, !~, AND
+// and OR operators. A nil predicate deletes all series within the range.
+func (e *Engine) DeleteBucketRangePredicate(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error {
+	if pred == nil {
+		return e.DeleteBucketRange(orgID, bucketID, min, max)
+	}
+
+	pred, err := rewriteDeletePredicate(pred)
+	if err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
+	if _, err := e.wal.DeleteBucketRangePredicate(orgID, bucketID, min, max, []byte(pred.String())); err != nil {
+		return err
+	}
+
+	return e.deleteBucketRangePredicateLocked(orgID, bucketID, min, max, pred)
+}
+
+// deleteBucketRangePredicateLocked does the work of deleting a bucket range for
+// a predicate and must be called under some sort of lock. The predicate must
+// already have been rewritten to the internal tag keys.
+func (e *Engine) deleteBucketRangePredicateLocked(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error {
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := models.EscapeMeasurement(encoded[:])
+
+	if err := e.engine.DeleteBucketRangePredicate(name, min, max, pred); err != nil {
+		return err
+	}
+	e.limits.reset(encoded[:])
+	return nil
+}
+
+func errInvalidDeletePredicate(msg string) error {
+	return &platform.Error{
+		Code: platform.EInvalid,
+		Op:   "storage/DeleteBucketRangePredicate",
+		Msg:  msg,
+	}
+}
+
+// rewriteDeletePredicate validates a delete predicate and rewrites the
+// _measurement and _field keys to the tag keys used by the index.
+func rewriteDeletePredicate(pred influxql.Expr) (influxql.Expr, error) {
+	var err error
+	influxql.WalkFunc(pred, func(node influxql.Node) {
+		if err != nil {
+			return
+		}
+
+		switch n := node.(type) {
+		case *influxql.BinaryExpr:
+			switch n.Op {
+			case influxql.AND, influxql.OR:
+			case influxql.EQ, influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
+				if _, ok := n.LHS.(*influxql.VarRef); !ok {
+					err = errInvalidDeletePredicate(fmt.Sprintf("invalid delete predicate: left side of %s must be a tag key", n.Op))
+				}
+				switch n.RHS.(type) {
+				case *influxql.StringLiteral, *influxql.RegexLiteral:
+				default:
+					err = errInvalidDeletePredicate(fmt.Sprintf("invalid delete predicate: right side of %s must be a string or regex", n.Op))
+				}
+			default:
+				err = errInvalidDeletePredicate(fmt.Sprintf("invalid delete predicate: unsupported operator %s", n.Op))
+			}
+		case *influxql.ParenExpr, *influxql.VarRef, *influxql.StringLiteral, *influxql.RegexLiteral:
+		default:
+			err = errInvalidDeletePredicate(fmt.Sprintf("invalid delete predicate: unsupported expression %s", n))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return influxql.RewriteExpr(influxql.CloneExpr(pred), func(expr influxql.Expr) influxql.Expr {
+		if ref, ok := expr.(*influxql.VarRef); ok {
+			switch ref.Val {
+			case measurementKey:
+				return &influxql.VarRef{Val: tsdb.MeasurementTagKey}
+			case fieldKey:
+				return &influxql.VarRef{Val: tsdb.FieldKeyTagKey}
+			}
+		}
+		return expr
+	}), nil
 }
 
 // SeriesCardinality returns the number of series in the engine.
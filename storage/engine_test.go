@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxql"
 )
 
 func TestEngine_WriteAndIndex(t *testing.T) {
@@ -165,6 +167,247 @@ func TestEngine_WriteAddNewField(t *testing.T) {
 	}
 }
 
+func TestEngine_WriteFieldTypeConflict(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{models.MustNewPoint(
+		name,
+		models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": "a"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The field has another type in a new series of the measurement.
+	points := []models.Point{
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": "b"}),
+			map[string]interface{}{"value": int64(1)},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{"_f": "value", "_m": "mem", "host": "b"}),
+			map[string]interface{}{"value": int64(1)},
+			time.Unix(1, 2),
+		),
+	}
+
+	expErr := `partial write: field type conflict: input field "value" on measurement "cpu" is type integer, already exists as type float dropped=1`
+	if err := engine.Engine.WritePoints(context.TODO(), points); err == nil || err.Error() != expErr {
+		t.Fatalf("unexpected error: got %v, exp %s", err, expErr)
+	}
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// The field may have another type once its bucket is deleted.
+	if err := engine.DeleteBucket(engine.org, engine.bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), points[:1]); err != nil {
+		t.Fatalf("unexpected error after delete: %v", err)
+	}
+
+	// The types of the fields are loaded when the engine is reopened.
+	if err := engine.Engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	engine.MustOpen()
+
+	expErr = `partial write: field type conflict: input field "value" on measurement "cpu" is type float, already exists as type integer dropped=1`
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{models.MustNewPoint(
+		name,
+		models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": "c"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)}); err == nil || err.Error() != expErr {
+		t.Fatalf("unexpected error after reopen: got %v, exp %s", err, expErr)
+	}
+}
+
+func TestEngine_WriteFieldTypeOfDroppedPoint(t *testing.T) {
+	c := storage.NewConfig()
+	c.MaxValuesPerTag = 1
+	engine := NewEngine(c)
+	defer engine.Close()
+	engine.MustOpen()
+
+	point := func(host, field string, value interface{}) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{"_f": field, "_m": "cpu", "host": host}),
+			map[string]interface{}{field: value},
+			time.Unix(1, 2),
+		)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a", "value", 1.0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The point of the new field is dropped by the cardinality limiter, so
+	// the field does not keep its type.
+	expErr := `partial write: max-values-per-tag limit exceeded (1/1): tag="host" value="b" dropped=1`
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("b", "other", int64(1))}); err == nil || err.Error() != expErr {
+		t.Fatalf("unexpected error: got %v, exp %s", err, expErr)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a", "other", 1.0)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The field keeps the type of the stored point.
+	expErr = `partial write: field type conflict: input field "other" on measurement "cpu" is type integer, already exists as type float dropped=1`
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a", "other", int64(1))}); err == nil || err.Error() != expErr {
+		t.Fatalf("unexpected error: got %v, exp %s", err, expErr)
+	}
+}
+
+func TestEngine_WriteCardinalityLimits(t *testing.T) {
+	point := func(engine *Engine, host, region string) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": host, "region": region}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	tests := []struct {
+		name   string
+		config func(c *storage.Config)
+		expErr string
+		usage  map[influxdb.UsageMetric]float64
+	}{
+		{
+			name:   "max series per bucket",
+			config: func(c *storage.Config) { c.MaxSeriesPerBucket = 2 },
+			expErr: "partial write: max-series-per-bucket limit exceeded: (2/2) dropped=1",
+			usage: map[influxdb.UsageMetric]float64{
+				influxdb.UsageSeriesCardinality:      2,
+				influxdb.UsageSeriesCardinalityLimit: 2,
+				influxdb.UsageTagValues:              2,
+			},
+		},
+		{
+			name:   "max series per org",
+			config: func(c *storage.Config) { c.MaxSeriesPerOrg = 2 },
+			expErr: "partial write: max-series-per-org limit exceeded: (2/2) dropped=1",
+			usage: map[influxdb.UsageMetric]float64{
+				influxdb.UsageSeriesCardinality: 2,
+				influxdb.UsageTagValues:         2,
+			},
+		},
+		{
+			name:   "max values per tag",
+			config: func(c *storage.Config) { c.MaxValuesPerTag = 2 },
+			expErr: `partial write: max-values-per-tag limit exceeded (2/2): tag="host" value="c" dropped=1`,
+			usage: map[influxdb.UsageMetric]float64{
+				influxdb.UsageSeriesCardinality: 2,
+				influxdb.UsageTagValues:         2,
+				influxdb.UsageTagValuesLimit:    2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := storage.NewConfig()
+			tt.config(&c)
+			engine := NewEngine(c)
+			defer engine.Close()
+			engine.MustOpen()
+
+			if err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+				point(engine, "a", "west"),
+				point(engine, "b", "west"),
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			// The new series is dropped, points of existing series are written.
+			err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+				point(engine, "c", "west"),
+				point(engine, "a", "west"),
+			})
+			if err == nil || err.Error() != tt.expErr {
+				t.Fatalf("unexpected error: got %v, exp %s", err, tt.expErr)
+			}
+			if _, ok := err.(tsdb.PartialWriteError); !ok {
+				t.Fatalf("unexpected error type: %T", err)
+			}
+			if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+				t.Fatalf("got %d series, exp %d series in index", got, exp)
+			}
+
+			usage, err := engine.GetUsage(context.TODO(), influxdb.UsageFilter{OrgID: &engine.org, BucketID: &engine.bucket})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[influxdb.UsageMetric]float64, len(usage))
+			for typ, u := range usage {
+				got[typ] = u.Value
+			}
+			if !reflect.DeepEqual(got, tt.usage) {
+				t.Fatalf("unexpected usage: got %v, exp %v", got, tt.usage)
+			}
+
+			// The bucket accepts new series again once its series are deleted.
+			if err := engine.DeleteBucket(engine.org, engine.bucket); err != nil {
+				t.Fatal(err)
+			}
+			if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point(engine, "c", "west")}); err != nil {
+				t.Fatalf("unexpected error after delete: %v", err)
+			}
+		})
+	}
+}
+
+func TestEngine_WriteUsage(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+
+	usage := make(usageRecorder)
+	engine.WithUsageRecorder(usage)
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(host string, t int64) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(t, 0),
+		)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a", 1), point("a", 2), point("b", 1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := usageRecorder{
+		influxdb.UsageValues: 3,
+		influxdb.UsageSeries: 2,
+	}
+	if !reflect.DeepEqual(usage, exp) {
+		t.Fatalf("unexpected usage: got %v, exp %v", usage, exp)
+	}
+}
+
+// usageRecorder sums the usage recorded for each metric.
+type usageRecorder map[influxdb.UsageMetric]float64
+
+func (r usageRecorder) RecordUsage(orgID, bucketID influxdb.ID, metric influxdb.UsageMetric, value float64) {
+	r[metric] += value
+}
+
 func TestEngine_DeleteBucket(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -217,6 +460,64 @@ func TestEngine_DeleteBucket(t *testing.T) {
 	}
 }
 
+func TestEngine_DeleteBucketRangePredicate(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": "a"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{"_f": "value", "_m": "cpu", "host": "b"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{"_f": "value", "_m": "mem", "host": "a"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(3); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// Unsupported operators are rejected.
+	pred := influxql.MustParseExpr(`host > 'a'`)
+	if err := engine.DeleteBucketRangePredicate(engine.org, engine.bucket, math.MinInt64, math.MaxInt64, pred); err == nil {
+		t.Fatal("expected error: got nil")
+	}
+
+	pred = influxql.MustParseExpr(`_measurement = 'cpu' AND host = 'a'`)
+	if err := engine.DeleteBucketRangePredicate(engine.org, engine.bucket, math.MinInt64, math.MaxInt64, pred); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// Ensure the delete is replayed from the WAL.
+	engine.Engine.Close() // Don't remove the data
+	engine.MustOpen()
+
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
 func TestEngine_OpenClose(t *testing.T) {
 	engine := NewDefaultEngine()
 	engine.MustOpen()
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// fieldTypeKey identifies a field of a measurement in a bucket. The name is
+// the encoded org and bucket ID of the series.
+type fieldTypeKey struct {
+	name        string
+	measurement string
+	field       string
+}
+
+// fieldTypes is the registry of the type of every field of every measurement
+// stored by the engine. A field keeps the type of its first write across all
+// of its series, so that queries never see a field with values of more than
+// one type.
+type fieldTypes struct {
+	mu    sync.RWMutex
+	types map[fieldTypeKey]*fieldType
+}
+
+// fieldType is the registered type of a field. The type of a new field is
+// reserved by the writes of its first points, and is kept once one of them
+// stores its points.
+type fieldType struct {
+	typ      models.FieldType
+	stored   bool // values of the field are stored
+	reserved int  // number of writes in progress that reserved the type
+}
+
+func newFieldTypes() *fieldTypes {
+	return &fieldTypes{types: make(map[fieldTypeKey]*fieldType)}
+}
+
+// begin returns the registration of the fields of a new write.
+func (f *fieldTypes) begin() *fieldTypeWrite {
+	return &fieldTypeWrite{f: f, reserved: make(map[fieldTypeKey]*fieldTypeReservation)}
+}
+
+// fieldTypeWrite registers the fields of a single write. The types of the new
+// fields are reserved while the write is in progress, so that concurrent
+// writes cannot give a field two types. The write keeps the types of the
+// fields it stored with commit, or releases them with rollback if it failed.
+type fieldTypeWrite struct {
+	f        *fieldTypes
+	reserved map[fieldTypeKey]*fieldTypeReservation
+}
+
+// fieldTypeReservation is the reservation of the type of a new field by a write.
+type fieldTypeReservation struct {
+	ft     *fieldType
+	points int // number of points of the write of the field
+}
+
+// check reserves typ as the type of the field if the field is new. It returns
+// a non-nil error if the field already exists with a different type.
+func (w *fieldTypeWrite) check(name, measurement, field []byte, typ models.FieldType) error {
+	key := fieldTypeKey{name: string(name), measurement: string(measurement), field: string(field)}
+
+	w.f.mu.RLock()
+	ft, ok := w.f.types[key]
+	var existing models.FieldType
+	if ok && ft.stored {
+		existing = ft.typ
+	}
+	w.f.mu.RUnlock()
+
+	if !ok || !ft.stored {
+		w.f.mu.Lock()
+		if ft, ok = w.f.types[key]; !ok {
+			ft = &fieldType{typ: typ}
+			w.f.types[key] = ft
+		}
+		existing = ft.typ
+		if existing == typ && !ft.stored {
+			r, ok := w.reserved[key]
+			if !ok {
+				r = &fieldTypeReservation{ft: ft}
+				w.reserved[key] = r
+				ft.reserved++
+			}
+			r.points++
+		}
+		w.f.mu.Unlock()
+	}
+
+	if existing != typ {
+		return fmt.Errorf("%s: input field %q on measurement %q is type %s, already exists as type %s",
+			tsdb.ErrFieldTypeConflict, field, measurement, fieldTypeName(typ), fieldTypeName(existing))
+	}
+	return nil
+}
+
+// drop removes a point of the series key that passed check from the write,
+// for example because it exceeds a cardinality limit.
+func (w *fieldTypeWrite) drop(seriesKey []byte) {
+	name, tags := models.ParseKeyBytes(seriesKey)
+	key := fieldTypeKey{
+		name:        string(name),
+		measurement: string(tags.Get(tsdb.MeasurementTagKeyBytes)),
+		field:       string(tags.Get(tsdb.FieldKeyTagKeyBytes)),
+	}
+	if r, ok := w.reserved[key]; ok {
+		r.points--
+	}
+}
+
+// commit keeps the types of the new fields whose points the write stored and
+// releases the others.
+func (w *fieldTypeWrite) commit() {
+	w.release(true)
+}
+
+// rollback releases the types of the new fields of a write that stored none
+// of its points.
+func (w *fieldTypeWrite) rollback() {
+	w.release(false)
+}
+
+func (w *fieldTypeWrite) release(stored bool) {
+	w.f.mu.Lock()
+	defer w.f.mu.Unlock()
+	for key, r := range w.reserved {
+		// The field was removed meanwhile with its bucket.
+		if w.f.types[key] != r.ft {
+			continue
+		}
+		r.ft.reserved--
+		if stored && r.points > 0 {
+			r.ft.stored = true
+		}
+		if !r.ft.stored && r.ft.reserved == 0 {
+			delete(w.f.types, key)
+		}
+	}
+	w.reserved = nil
+}
+
+// add registers typ as the type of the field of the series key. Keys that are
+// not series keys of the engine are ignored.
+func (f *fieldTypes) add(seriesKey []byte, typ models.FieldType) {
+	name, tags := models.ParseKeyBytes(seriesKey)
+	measurement, field := tags.Get(tsdb.MeasurementTagKeyBytes), tags.Get(tsdb.FieldKeyTagKeyBytes)
+	if measurement == nil || field == nil {
+		return
+	}
+
+	f.mu.Lock()
+	f.types[fieldTypeKey{name: string(name), measurement: string(measurement), field: string(field)}] = &fieldType{typ: typ, stored: true}
+	f.mu.Unlock()
+}
+
+// load registers the types of the fields stored in the TSM files and the cache
+// of the engine.
+func (f *fieldTypes) load(e *tsm1.Engine) error {
+	if err := e.FileStore.WalkKeys(nil, func(key []byte, typ byte) error {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+		if ft := blockToFieldType(typ); ft != models.Empty {
+			f.add(seriesKey, ft)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, key := range e.Cache.Keys() {
+		if typ, err := e.Cache.Type(key); err == nil {
+			seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+			f.add(seriesKey, typ)
+		}
+	}
+	return nil
+}
+
+// deleteBucket removes the fields of the bucket with the encoded name.
+func (f *fieldTypes) deleteBucket(name []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key := range f.types {
+		if key.name == string(name) {
+			delete(f.types, key)
+		}
+	}
+}
+
+func fieldTypeName(typ models.FieldType) string {
+	switch typ {
+	case models.Float:
+		return "float"
+	case models.Integer:
+		return "integer"
+	case models.Unsigned:
+		return "unsigned"
+	case models.Boolean:
+		return "boolean"
+	case models.String:
+		return "string"
+	}
+	return "unknown"
+}
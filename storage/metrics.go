@@ -64,3 +64,118 @@ func (rm *retentionMetrics) PrometheusCollectors() []prometheus.Collector {
 		rm.CheckDuration,
 	}
 }
+
+const cardinalitySubsystem = "cardinality" // sub-system associated with metrics for series cardinality limits.
+
+// cardinalityMetrics is a set of metrics concerned with tracking the series
+// cardinality of buckets and its limits.
+type cardinalityMetrics struct {
+	labels      prometheus.Labels
+	Drops       *prometheus.CounterVec
+	Limits      *prometheus.GaugeVec
+	seriesDesc  *prometheus.Desc
+	seriesStats func() map[string]int
+}
+
+func newCardinalityMetrics(labels prometheus.Labels) *cardinalityMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	limitNames := append(append([]string(nil), names...), "limit")
+	sort.Strings(limitNames)
+
+	seriesNames := append(append([]string(nil), names...), "org_id", "bucket_id")
+	sort.Strings(seriesNames)
+
+	return &cardinalityMetrics{
+		labels: labels,
+		Drops: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: cardinalitySubsystem,
+			Name:      "dropped_points_total",
+			Help:      "Number of points of new series dropped because of a cardinality limit.",
+		}, limitNames),
+
+		Limits: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: cardinalitySubsystem,
+			Name:      "limit",
+			Help:      "Configured cardinality limits. A limit of zero is no limit.",
+		}, limitNames),
+
+		seriesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, cardinalitySubsystem, "bucket_series"),
+			"Number of series by org/bucket id.",
+			seriesNames, nil),
+	}
+}
+
+// Labels returns a copy of labels for use with cardinality metrics.
+func (m *cardinalityMetrics) Labels() prometheus.Labels {
+	l := make(map[string]string, len(m.labels))
+	for k, v := range m.labels {
+		l[k] = v
+	}
+	return l
+}
+
+// drop records a point dropped because of the limit.
+func (m *cardinalityMetrics) drop(limit string) {
+	labels := m.Labels()
+	labels["limit"] = limit
+	m.Drops.With(labels).Inc()
+}
+
+// setLimit records the value of a limit.
+func (m *cardinalityMetrics) setLimit(limit string, v int) {
+	labels := m.Labels()
+	labels["limit"] = limit
+	m.Limits.With(labels).Set(float64(v))
+}
+
+// Describe satisfies the prometheus.Collector interface for the series of
+// the buckets.
+func (m *cardinalityMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.seriesDesc
+}
+
+// Collect satisfies the prometheus.Collector interface for the series of the
+// buckets.
+func (m *cardinalityMetrics) Collect(ch chan<- prometheus.Metric) {
+	if m.seriesStats == nil {
+		return
+	}
+
+	for name, n := range m.seriesStats() {
+		org, bucket, ok := decodeName([]byte(name))
+		if !ok {
+			continue
+		}
+
+		labels := m.Labels()
+		labels["org_id"] = org.String()
+		labels["bucket_id"] = bucket.String()
+		names := make([]string, 0, len(labels))
+		for k := range labels {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		values := make([]string, 0, len(names))
+		for _, k := range names {
+			values = append(values, labels[k])
+		}
+		ch <- prometheus.MustNewConstMetric(m.seriesDesc, prometheus.GaugeValue, float64(n), values...)
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *cardinalityMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.Drops,
+		m.Limits,
+		m,
+	}
+}
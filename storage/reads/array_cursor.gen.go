@@ -9,6 +9,7 @@ package reads
 import (
 	"errors"
 
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
 
@@ -237,6 +238,260 @@ func (c *integerFloatCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// floatWindowCountArrayCursor counts the points of a float array cursor per window.
+type floatWindowCountArrayCursor struct {
+	cursors.FloatArrayCursor
+	w   aggregateWindow
+	a   *cursors.FloatArray
+	i   int
+	acc int64
+	res *cursors.IntegerArray
+}
+
+func newFloatWindowCountArrayCursor(cur cursors.FloatArrayCursor, w aggregateWindow) *floatWindowCountArrayCursor {
+	return &floatWindowCountArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		a:                &cursors.FloatArray{},
+		res:              &cursors.IntegerArray{},
+	}
+}
+
+func (c *floatWindowCountArrayCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.FloatArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		c.acc += int64(j - c.i)
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *floatWindowCountArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// floatWindowSumArrayCursor sums the values of a float array cursor per window.
+type floatWindowSumArrayCursor struct {
+	cursors.FloatArrayCursor
+	w   aggregateWindow
+	a   *cursors.FloatArray
+	i   int
+	acc float64
+	res *cursors.FloatArray
+}
+
+func newFloatWindowSumArrayCursor(cur cursors.FloatArrayCursor, w aggregateWindow) *floatWindowSumArrayCursor {
+	return &floatWindowSumArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		a:                &cursors.FloatArray{},
+		res:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowSumArrayCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatWindowSumArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.FloatArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		for _, v := range c.a.Values[c.i:j] {
+			c.acc += v
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *floatWindowSumArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// floatWindowMeanArrayCursor averages the values of a float array cursor per window.
+type floatWindowMeanArrayCursor struct {
+	cursors.FloatArrayCursor
+	w   aggregateWindow
+	a   *cursors.FloatArray
+	i   int
+	sum float64
+	n   int
+	res *cursors.FloatArray
+}
+
+func newFloatWindowMeanArrayCursor(cur cursors.FloatArrayCursor, w aggregateWindow) *floatWindowMeanArrayCursor {
+	return &floatWindowMeanArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		a:                &cursors.FloatArray{},
+		res:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowMeanArrayCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.FloatArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.sum, c.n = 0, 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		for _, v := range c.a.Values[c.i:j] {
+			c.sum += float64(v)
+		}
+		c.n += j - c.i
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *floatWindowMeanArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.sum/float64(c.n))
+}
+
+// floatWindowSelectorArrayCursor selects a point of a float array cursor per window.
+type floatWindowSelectorArrayCursor struct {
+	cursors.FloatArrayCursor
+	agg datatypes.Aggregate_AggregateType
+	w   aggregateWindow
+	a   *cursors.FloatArray
+	i   int
+	ts  int64
+	v   float64
+	res *cursors.FloatArray
+}
+
+func newFloatWindowSelectorArrayCursor(cur cursors.FloatArrayCursor, agg datatypes.Aggregate_AggregateType, w aggregateWindow) *floatWindowSelectorArrayCursor {
+	return &floatWindowSelectorArrayCursor{
+		FloatArrayCursor: cur,
+		agg:              agg,
+		w:                w,
+		a:                &cursors.FloatArray{},
+		res:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowSelectorArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.FloatArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.ts, c.v = c.a.Timestamps[c.i], c.a.Values[c.i]
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		switch c.agg {
+		case datatypes.AggregateTypeLast:
+			c.ts, c.v = c.a.Timestamps[j-1], c.a.Values[j-1]
+		case datatypes.AggregateTypeMin:
+			for k := c.i; k < j; k++ {
+				if v := c.a.Values[k]; v < c.v {
+					c.ts, c.v = c.a.Timestamps[k], v
+				}
+			}
+		case datatypes.AggregateTypeMax:
+			for k := c.i; k < j; k++ {
+				if v := c.a.Values[k]; v > c.v {
+					c.ts, c.v = c.a.Timestamps[k], v
+				}
+			}
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *floatWindowSelectorArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.ts)
+	c.res.Values = append(c.res.Values, c.v)
+}
+
 type floatEmptyArrayCursor struct {
 	res cursors.FloatArray
 }
@@ -466,6 +721,266 @@ func (c *integerIntegerCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// integerWindowCountArrayCursor counts the points of a integer array cursor per window.
+type integerWindowCountArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w   aggregateWindow
+	a   *cursors.IntegerArray
+	i   int
+	acc int64
+	res *cursors.IntegerArray
+}
+
+func newIntegerWindowCountArrayCursor(cur cursors.IntegerArrayCursor, w aggregateWindow) *integerWindowCountArrayCursor {
+	return &integerWindowCountArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		a:                  &cursors.IntegerArray{},
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.IntegerArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		c.acc += int64(j - c.i)
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *integerWindowCountArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// integerWindowSumArrayCursor sums the values of a integer array cursor per window.
+type integerWindowSumArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w   aggregateWindow
+	a   *cursors.IntegerArray
+	i   int
+	acc int64
+	res *cursors.IntegerArray
+}
+
+func newIntegerWindowSumArrayCursor(cur cursors.IntegerArrayCursor, w aggregateWindow) *integerWindowSumArrayCursor {
+	return &integerWindowSumArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		a:                  &cursors.IntegerArray{},
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowSumArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowSumArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.IntegerArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		for _, v := range c.a.Values[c.i:j] {
+			c.acc += v
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *integerWindowSumArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// integerWindowMeanArrayCursor averages the values of a integer array cursor per window.
+type integerWindowMeanArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w   aggregateWindow
+	a   *cursors.IntegerArray
+	i   int
+	sum float64
+	n   int
+	res *cursors.FloatArray
+}
+
+func newIntegerWindowMeanArrayCursor(cur cursors.IntegerArrayCursor, w aggregateWindow) *integerWindowMeanArrayCursor {
+	return &integerWindowMeanArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		a:                  &cursors.IntegerArray{},
+		res:                &cursors.FloatArray{},
+	}
+}
+
+func (c *integerWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.IntegerArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.sum, c.n = 0, 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		for _, v := range c.a.Values[c.i:j] {
+			c.sum += float64(v)
+		}
+		c.n += j - c.i
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *integerWindowMeanArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.sum/float64(c.n))
+}
+
+// integerWindowSelectorArrayCursor selects a point of a integer array cursor per window.
+type integerWindowSelectorArrayCursor struct {
+	cursors.IntegerArrayCursor
+	agg datatypes.Aggregate_AggregateType
+	w   aggregateWindow
+	a   *cursors.IntegerArray
+	i   int
+	ts  int64
+	v   int64
+	res *cursors.IntegerArray
+}
+
+func newIntegerWindowSelectorArrayCursor(cur cursors.IntegerArrayCursor, agg datatypes.Aggregate_AggregateType, w aggregateWindow) *integerWindowSelectorArrayCursor {
+	return &integerWindowSelectorArrayCursor{
+		IntegerArrayCursor: cur,
+		agg:                agg,
+		w:                  w,
+		a:                  &cursors.IntegerArray{},
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowSelectorArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.IntegerArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.ts, c.v = c.a.Timestamps[c.i], c.a.Values[c.i]
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		switch c.agg {
+		case datatypes.AggregateTypeLast:
+			c.ts, c.v = c.a.Timestamps[j-1], c.a.Values[j-1]
+		case datatypes.AggregateTypeMin:
+			for k := c.i; k < j; k++ {
+				if v := c.a.Values[k]; v < c.v {
+					c.ts, c.v = c.a.Timestamps[k], v
+				}
+			}
+		case datatypes.AggregateTypeMax:
+			for k := c.i; k < j; k++ {
+				if v := c.a.Values[k]; v > c.v {
+					c.ts, c.v = c.a.Timestamps[k], v
+				}
+			}
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *integerWindowSelectorArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.ts)
+	c.res.Values = append(c.res.Values, c.v)
+}
+
 type integerEmptyArrayCursor struct {
 	res cursors.IntegerArray
 }
@@ -695,6 +1210,266 @@ func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// unsignedWindowCountArrayCursor counts the points of a unsigned array cursor per window.
+type unsignedWindowCountArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w   aggregateWindow
+	a   *cursors.UnsignedArray
+	i   int
+	acc int64
+	res *cursors.IntegerArray
+}
+
+func newUnsignedWindowCountArrayCursor(cur cursors.UnsignedArrayCursor, w aggregateWindow) *unsignedWindowCountArrayCursor {
+	return &unsignedWindowCountArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		a:                   &cursors.UnsignedArray{},
+		res:                 &cursors.IntegerArray{},
+	}
+}
+
+func (c *unsignedWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.UnsignedArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		c.acc += int64(j - c.i)
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *unsignedWindowCountArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// unsignedWindowSumArrayCursor sums the values of a unsigned array cursor per window.
+type unsignedWindowSumArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w   aggregateWindow
+	a   *cursors.UnsignedArray
+	i   int
+	acc uint64
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedWindowSumArrayCursor(cur cursors.UnsignedArrayCursor, w aggregateWindow) *unsignedWindowSumArrayCursor {
+	return &unsignedWindowSumArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		a:                   &cursors.UnsignedArray{},
+		res:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowSumArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowSumArrayCursor) Next() *cursors.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.UnsignedArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		for _, v := range c.a.Values[c.i:j] {
+			c.acc += v
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *unsignedWindowSumArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// unsignedWindowMeanArrayCursor averages the values of a unsigned array cursor per window.
+type unsignedWindowMeanArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w   aggregateWindow
+	a   *cursors.UnsignedArray
+	i   int
+	sum float64
+	n   int
+	res *cursors.FloatArray
+}
+
+func newUnsignedWindowMeanArrayCursor(cur cursors.UnsignedArrayCursor, w aggregateWindow) *unsignedWindowMeanArrayCursor {
+	return &unsignedWindowMeanArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		a:                   &cursors.UnsignedArray{},
+		res:                 &cursors.FloatArray{},
+	}
+}
+
+func (c *unsignedWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.UnsignedArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.sum, c.n = 0, 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		for _, v := range c.a.Values[c.i:j] {
+			c.sum += float64(v)
+		}
+		c.n += j - c.i
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *unsignedWindowMeanArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.sum/float64(c.n))
+}
+
+// unsignedWindowSelectorArrayCursor selects a point of a unsigned array cursor per window.
+type unsignedWindowSelectorArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	agg datatypes.Aggregate_AggregateType
+	w   aggregateWindow
+	a   *cursors.UnsignedArray
+	i   int
+	ts  int64
+	v   uint64
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedWindowSelectorArrayCursor(cur cursors.UnsignedArrayCursor, agg datatypes.Aggregate_AggregateType, w aggregateWindow) *unsignedWindowSelectorArrayCursor {
+	return &unsignedWindowSelectorArrayCursor{
+		UnsignedArrayCursor: cur,
+		agg:                 agg,
+		w:                   w,
+		a:                   &cursors.UnsignedArray{},
+		res:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowSelectorArrayCursor) Next() *cursors.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.UnsignedArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.ts, c.v = c.a.Timestamps[c.i], c.a.Values[c.i]
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		switch c.agg {
+		case datatypes.AggregateTypeLast:
+			c.ts, c.v = c.a.Timestamps[j-1], c.a.Values[j-1]
+		case datatypes.AggregateTypeMin:
+			for k := c.i; k < j; k++ {
+				if v := c.a.Values[k]; v < c.v {
+					c.ts, c.v = c.a.Timestamps[k], v
+				}
+			}
+		case datatypes.AggregateTypeMax:
+			for k := c.i; k < j; k++ {
+				if v := c.a.Values[k]; v > c.v {
+					c.ts, c.v = c.a.Timestamps[k], v
+				}
+			}
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *unsignedWindowSelectorArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.ts)
+	c.res.Values = append(c.res.Values, c.v)
+}
+
 type unsignedEmptyArrayCursor struct {
 	res cursors.UnsignedArray
 }
@@ -884,6 +1659,130 @@ func (c *integerStringCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// stringWindowCountArrayCursor counts the points of a string array cursor per window.
+type stringWindowCountArrayCursor struct {
+	cursors.StringArrayCursor
+	w   aggregateWindow
+	a   *cursors.StringArray
+	i   int
+	acc int64
+	res *cursors.IntegerArray
+}
+
+func newStringWindowCountArrayCursor(cur cursors.StringArrayCursor, w aggregateWindow) *stringWindowCountArrayCursor {
+	return &stringWindowCountArrayCursor{
+		StringArrayCursor: cur,
+		w:                 w,
+		a:                 &cursors.StringArray{},
+		res:               &cursors.IntegerArray{},
+	}
+}
+
+func (c *stringWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.StringArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		c.acc += int64(j - c.i)
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *stringWindowCountArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// stringWindowSelectorArrayCursor selects a point of a string array cursor per window.
+type stringWindowSelectorArrayCursor struct {
+	cursors.StringArrayCursor
+	agg datatypes.Aggregate_AggregateType
+	w   aggregateWindow
+	a   *cursors.StringArray
+	i   int
+	ts  int64
+	v   string
+	res *cursors.StringArray
+}
+
+func newStringWindowSelectorArrayCursor(cur cursors.StringArrayCursor, agg datatypes.Aggregate_AggregateType, w aggregateWindow) *stringWindowSelectorArrayCursor {
+	return &stringWindowSelectorArrayCursor{
+		StringArrayCursor: cur,
+		agg:               agg,
+		w:                 w,
+		a:                 &cursors.StringArray{},
+		res:               &cursors.StringArray{},
+	}
+}
+
+func (c *stringWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowSelectorArrayCursor) Next() *cursors.StringArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.StringArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.ts, c.v = c.a.Timestamps[c.i], c.a.Values[c.i]
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		switch c.agg {
+		case datatypes.AggregateTypeLast:
+			c.ts, c.v = c.a.Timestamps[j-1], c.a.Values[j-1]
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *stringWindowSelectorArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.ts)
+	c.res.Values = append(c.res.Values, c.v)
+}
+
 type stringEmptyArrayCursor struct {
 	res cursors.StringArray
 }
@@ -1073,6 +1972,130 @@ func (c *integerBooleanCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// booleanWindowCountArrayCursor counts the points of a boolean array cursor per window.
+type booleanWindowCountArrayCursor struct {
+	cursors.BooleanArrayCursor
+	w   aggregateWindow
+	a   *cursors.BooleanArray
+	i   int
+	acc int64
+	res *cursors.IntegerArray
+}
+
+func newBooleanWindowCountArrayCursor(cur cursors.BooleanArrayCursor, w aggregateWindow) *booleanWindowCountArrayCursor {
+	return &booleanWindowCountArrayCursor{
+		BooleanArrayCursor: cur,
+		w:                  w,
+		a:                  &cursors.BooleanArray{},
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *booleanWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.BooleanArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.acc = 0
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		c.acc += int64(j - c.i)
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *booleanWindowCountArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.w.ts)
+	c.res.Values = append(c.res.Values, c.acc)
+}
+
+// booleanWindowSelectorArrayCursor selects a point of a boolean array cursor per window.
+type booleanWindowSelectorArrayCursor struct {
+	cursors.BooleanArrayCursor
+	agg datatypes.Aggregate_AggregateType
+	w   aggregateWindow
+	a   *cursors.BooleanArray
+	i   int
+	ts  int64
+	v   bool
+	res *cursors.BooleanArray
+}
+
+func newBooleanWindowSelectorArrayCursor(cur cursors.BooleanArrayCursor, agg datatypes.Aggregate_AggregateType, w aggregateWindow) *booleanWindowSelectorArrayCursor {
+	return &booleanWindowSelectorArrayCursor{
+		BooleanArrayCursor: cur,
+		agg:                agg,
+		w:                  w,
+		a:                  &cursors.BooleanArray{},
+		res:                &cursors.BooleanArray{},
+	}
+}
+
+func (c *booleanWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowSelectorArrayCursor) Next() *cursors.BooleanArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a, c.i = c.BooleanArrayCursor.Next(), 0
+			if len(c.a.Timestamps) == 0 {
+				if c.w.open {
+					c.appendWindow()
+				}
+				break
+			}
+		}
+
+		if !c.w.open {
+			c.w.start(c.a.Timestamps[c.i])
+			c.ts, c.v = c.a.Timestamps[c.i], c.a.Values[c.i]
+		}
+
+		j := c.w.end(c.a.Timestamps, c.i)
+		switch c.agg {
+		case datatypes.AggregateTypeLast:
+			c.ts, c.v = c.a.Timestamps[j-1], c.a.Values[j-1]
+		}
+		c.i = j
+		if j < len(c.a.Timestamps) {
+			c.appendWindow()
+		}
+	}
+	return c.res
+}
+
+func (c *booleanWindowSelectorArrayCursor) appendWindow() {
+	c.w.open = false
+	c.res.Timestamps = append(c.res.Timestamps, c.ts)
+	c.res.Values = append(c.res.Values, c.v)
+}
+
 type booleanEmptyArrayCursor struct {
 	res cursors.BooleanArray
 }
@@ -3,6 +3,8 @@ package reads
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
@@ -16,33 +18,32 @@ func (v *singleValue) Value(key string) (interface{}, bool) {
 	return v.v, true
 }
 
-func newAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor {
+func newAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) (cursors.Cursor, error) {
 	if cursor == nil {
-		return nil
+		return nil, nil
 	}
 
-	switch agg.Type {
-	case datatypes.AggregateTypeSum:
-		return newSumArrayCursor(cursor)
-	case datatypes.AggregateTypeCount:
-		return newCountArrayCursor(cursor)
-	default:
-		// TODO(sgc): should be validated higher up
-		panic("invalid aggregate")
+	if agg.WindowEvery == 0 {
+		switch agg.Type {
+		case datatypes.AggregateTypeSum:
+			return newSumArrayCursor(cursor)
+		case datatypes.AggregateTypeCount:
+			return newCountArrayCursor(cursor), nil
+		}
 	}
+	return newWindowAggregateArrayCursor(agg, cursor)
 }
 
-func newSumArrayCursor(cur cursors.Cursor) cursors.Cursor {
+func newSumArrayCursor(cur cursors.Cursor) (cursors.Cursor, error) {
 	switch cur := cur.(type) {
 	case cursors.FloatArrayCursor:
-		return newFloatArraySumCursor(cur)
+		return newFloatArraySumCursor(cur), nil
 	case cursors.IntegerArrayCursor:
-		return newIntegerArraySumCursor(cur)
+		return newIntegerArraySumCursor(cur), nil
 	case cursors.UnsignedArrayCursor:
-		return newUnsignedArraySumCursor(cur)
+		return newUnsignedArraySumCursor(cur), nil
 	default:
-		// TODO(sgc): propagate an error instead?
-		return nil
+		return nil, unsupportedAggregateError(datatypes.AggregateTypeSum, cur)
 	}
 }
 
@@ -63,6 +64,131 @@ func newCountArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	}
 }
 
+// newWindowAggregateArrayCursor returns a cursor with one point per window
+// of agg that has points in cur. A window of zero every is the whole time
+// range of cur. The points of count, sum and mean are at the start of their
+// window, or at the first point when there are no windows, and the points of
+// selectors are the selected points. cur must be ascending.
+func newWindowAggregateArrayCursor(agg *datatypes.Aggregate, cur cursors.Cursor) (cursors.Cursor, error) {
+	w := aggregateWindow{every: agg.WindowEvery, offset: agg.WindowOffset}
+	switch agg.Type {
+	case datatypes.AggregateTypeCount:
+		switch cur := cur.(type) {
+		case cursors.FloatArrayCursor:
+			return newFloatWindowCountArrayCursor(cur, w), nil
+		case cursors.IntegerArrayCursor:
+			return newIntegerWindowCountArrayCursor(cur, w), nil
+		case cursors.UnsignedArrayCursor:
+			return newUnsignedWindowCountArrayCursor(cur, w), nil
+		case cursors.StringArrayCursor:
+			return newStringWindowCountArrayCursor(cur, w), nil
+		case cursors.BooleanArrayCursor:
+			return newBooleanWindowCountArrayCursor(cur, w), nil
+		}
+	case datatypes.AggregateTypeSum:
+		switch cur := cur.(type) {
+		case cursors.FloatArrayCursor:
+			return newFloatWindowSumArrayCursor(cur, w), nil
+		case cursors.IntegerArrayCursor:
+			return newIntegerWindowSumArrayCursor(cur, w), nil
+		case cursors.UnsignedArrayCursor:
+			return newUnsignedWindowSumArrayCursor(cur, w), nil
+		}
+	case datatypes.AggregateTypeMean:
+		switch cur := cur.(type) {
+		case cursors.FloatArrayCursor:
+			return newFloatWindowMeanArrayCursor(cur, w), nil
+		case cursors.IntegerArrayCursor:
+			return newIntegerWindowMeanArrayCursor(cur, w), nil
+		case cursors.UnsignedArrayCursor:
+			return newUnsignedWindowMeanArrayCursor(cur, w), nil
+		}
+	case datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		switch cur := cur.(type) {
+		case cursors.FloatArrayCursor:
+			return newFloatWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		case cursors.IntegerArrayCursor:
+			return newIntegerWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		case cursors.UnsignedArrayCursor:
+			return newUnsignedWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		}
+	case datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+		switch cur := cur.(type) {
+		case cursors.FloatArrayCursor:
+			return newFloatWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		case cursors.IntegerArrayCursor:
+			return newIntegerWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		case cursors.UnsignedArrayCursor:
+			return newUnsignedWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		case cursors.StringArrayCursor:
+			return newStringWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		case cursors.BooleanArrayCursor:
+			return newBooleanWindowSelectorArrayCursor(cur, agg.Type, w), nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid aggregate %s", agg.Type)
+	}
+	return nil, unsupportedAggregateError(agg.Type, cur)
+}
+
+func unsupportedAggregateError(agg datatypes.Aggregate_AggregateType, cur cursors.Cursor) error {
+	var typ string
+	switch cur.(type) {
+	case cursors.FloatArrayCursor:
+		typ = "float"
+	case cursors.IntegerArrayCursor:
+		typ = "integer"
+	case cursors.UnsignedArrayCursor:
+		typ = "unsigned"
+	case cursors.StringArrayCursor:
+		typ = "string"
+	case cursors.BooleanArrayCursor:
+		typ = "boolean"
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+	return fmt.Errorf("unsupported aggregate %s for %s values", strings.ToLower(agg.String()), typ)
+}
+
+// aggregateWindow is the window of the points a windowed aggregate cursor
+// reads. The windows are computed as flux computes them.
+type aggregateWindow struct {
+	every  int64
+	offset int64
+
+	// open is true while the window has points.
+	open bool
+	// ts is the start of the window, or its first point when every is zero.
+	ts   int64
+	stop int64
+}
+
+// start opens the window of the point at t.
+func (w *aggregateWindow) start(t int64) {
+	w.open = true
+	if w.every <= 0 {
+		w.ts, w.stop = t, math.MaxInt64
+		return
+	}
+	// Floor the remainder, so that the window of a point before the epoch
+	// starts at or before it.
+	r := (t - w.offset) % w.every
+	if r < 0 {
+		r += w.every
+	}
+	w.ts = t - r
+	w.stop = w.ts + w.every
+}
+
+// end returns the index of the first timestamp from i on that is past the
+// window, or len(ts) if there is none.
+func (w *aggregateWindow) end(ts []int64, i int) int {
+	for i < len(ts) && ts[i] < w.stop {
+		i++
+	}
+	return i
+}
+
 type cursorContext struct {
 	ctx   context.Context
 	req   *cursors.CursorRequest
@@ -158,6 +284,6 @@ func (m *multiShardArrayCursors) createCursor(row SeriesRow) cursors.Cursor {
 	}
 }
 
-func (m *multiShardArrayCursors) newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor {
+func (m *multiShardArrayCursors) newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) (cursors.Cursor, error) {
 	return newAggregateArrayCursor(ctx, agg, cursor)
 }
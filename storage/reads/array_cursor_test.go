@@ -0,0 +1,208 @@
+package reads
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// floatArrayCursor returns the points of its arrays, one array per call.
+type floatArrayCursor struct {
+	arrays []*cursors.FloatArray
+}
+
+func (c *floatArrayCursor) Close()                     {}
+func (c *floatArrayCursor) Err() error                 { return nil }
+func (c *floatArrayCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *floatArrayCursor) Next() *cursors.FloatArray {
+	if len(c.arrays) == 0 {
+		return &cursors.FloatArray{}
+	}
+	a := c.arrays[0]
+	c.arrays = c.arrays[1:]
+	return a
+}
+
+func TestWindowAggregateArrayCursor(t *testing.T) {
+	// Points at 0 to 9 in two arrays, which split the window [4, 8).
+	newCursor := func() cursors.Cursor {
+		return &floatArrayCursor{arrays: []*cursors.FloatArray{
+			{Timestamps: []int64{0, 1, 2, 5}, Values: []float64{3, 1, 2, 4}},
+			{Timestamps: []int64{6, 9}, Values: []float64{1, 8}},
+		}}
+	}
+
+	tests := []struct {
+		name string
+		agg  datatypes.Aggregate
+		ts   []int64
+		vs   interface{}
+	}{
+		{
+			name: "count",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeCount, WindowEvery: 4},
+			ts:   []int64{0, 4, 8},
+			vs:   []int64{3, 2, 1},
+		},
+		{
+			name: "sum",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeSum, WindowEvery: 4},
+			ts:   []int64{0, 4, 8},
+			vs:   []float64{6, 5, 8},
+		},
+		{
+			name: "mean",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeMean, WindowEvery: 4},
+			ts:   []int64{0, 4, 8},
+			vs:   []float64{2, 2.5, 8},
+		},
+		{
+			name: "min",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeMin, WindowEvery: 4},
+			ts:   []int64{1, 6, 9},
+			vs:   []float64{1, 1, 8},
+		},
+		{
+			name: "max",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeMax, WindowEvery: 4},
+			ts:   []int64{0, 5, 9},
+			vs:   []float64{3, 4, 8},
+		},
+		{
+			name: "first",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeFirst, WindowEvery: 4},
+			ts:   []int64{0, 5, 9},
+			vs:   []float64{3, 4, 8},
+		},
+		{
+			name: "last",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeLast, WindowEvery: 4},
+			ts:   []int64{2, 6, 9},
+			vs:   []float64{2, 1, 8},
+		},
+		{
+			name: "offset",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeCount, WindowEvery: 4, WindowOffset: -2},
+			ts:   []int64{-2, 2, 6},
+			vs:   []int64{2, 2, 2},
+		},
+		{
+			name: "no window",
+			agg:  datatypes.Aggregate{Type: datatypes.AggregateTypeMax},
+			ts:   []int64{9},
+			vs:   []float64{8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur, err := newAggregateArrayCursor(context.Background(), &tt.agg, newCursor())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ts []int64
+			var vs interface{}
+			switch cur := cur.(type) {
+			case cursors.IntegerArrayCursor:
+				var ivs []int64
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					ts = append(ts, a.Timestamps...)
+					ivs = append(ivs, a.Values...)
+				}
+				vs = ivs
+			case cursors.FloatArrayCursor:
+				var fvs []float64
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					ts = append(ts, a.Timestamps...)
+					fvs = append(fvs, a.Values...)
+				}
+				vs = fvs
+			default:
+				t.Fatalf("unexpected cursor type %T", cur)
+			}
+
+			if !reflect.DeepEqual(ts, tt.ts) {
+				t.Errorf("unexpected timestamps: got %v, want %v", ts, tt.ts)
+			}
+			if !reflect.DeepEqual(vs, tt.vs) {
+				t.Errorf("unexpected values: got %v, want %v", vs, tt.vs)
+			}
+		})
+	}
+}
+
+func TestWindowAggregateArrayCursor_BeforeEpoch(t *testing.T) {
+	cur, err := newAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: datatypes.AggregateTypeCount, WindowEvery: 4}, &floatArrayCursor{
+		arrays: []*cursors.FloatArray{
+			{Timestamps: []int64{-7, -5, -1, 0, 3}, Values: []float64{1, 2, 3, 4, 5}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := cur.(cursors.IntegerArrayCursor).Next()
+	if want := []int64{-8, -4, 0}; !reflect.DeepEqual(a.Timestamps, want) {
+		t.Errorf("unexpected timestamps: got %v, want %v", a.Timestamps, want)
+	}
+	if want := []int64{2, 1, 2}; !reflect.DeepEqual(a.Values, want) {
+		t.Errorf("unexpected values: got %v, want %v", a.Values, want)
+	}
+}
+
+func TestWindowAggregateArrayCursor_Unsupported(t *testing.T) {
+	cur := &stringEmptyArrayCursor{}
+	for _, typ := range []datatypes.Aggregate_AggregateType{
+		datatypes.AggregateTypeSum,
+		datatypes.AggregateTypeMean,
+		datatypes.AggregateTypeMin,
+		datatypes.AggregateTypeMax,
+	} {
+		_, err := newAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: typ, WindowEvery: 4}, cur)
+		if err == nil {
+			t.Errorf("expected an error for %s of strings", typ)
+		}
+	}
+
+	if _, err := newAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: datatypes.AggregateTypeLast, WindowEvery: 4}, cur); err != nil {
+		t.Errorf("unexpected error for last of strings: %v", err)
+	}
+}
+
+func TestWindowAggregateArrayCursor_ManyWindows(t *testing.T) {
+	// More windows than fit in an array.
+	const n = 2*MaxPointsPerBlock + 500
+	var arrays []*cursors.FloatArray
+	for i := 0; i < n; i += MaxPointsPerBlock {
+		a := &cursors.FloatArray{}
+		for j := i; j < i+MaxPointsPerBlock && j < n; j++ {
+			a.Timestamps = append(a.Timestamps, int64(j))
+			a.Values = append(a.Values, float64(j))
+		}
+		arrays = append(arrays, a)
+	}
+
+	agg := &datatypes.Aggregate{Type: datatypes.AggregateTypeCount, WindowEvery: 1}
+	cur, err := newAggregateArrayCursor(context.Background(), agg, &floatArrayCursor{arrays: arrays})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got int
+	for a := cur.(cursors.IntegerArrayCursor).Next(); a.Len() > 0; a = cur.(cursors.IntegerArrayCursor).Next() {
+		for i, ts := range a.Timestamps {
+			if ts != int64(got) || a.Values[i] != 1 {
+				t.Fatalf("unexpected window %d: got %d with count %d", got, ts, a.Values[i])
+			}
+			got++
+		}
+	}
+	if got != n {
+		t.Fatalf("unexpected number of windows: got %d, want %d", got, n)
+	}
+}
@@ -59,7 +59,7 @@ func (x ReadRequest_Group) String() string {
 	return proto.EnumName(ReadRequest_Group_name, int32(x))
 }
 func (ReadRequest_Group) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{0, 0}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{0, 0}
 }
 
 type ReadRequest_HintFlags int32
@@ -89,7 +89,7 @@ func (x ReadRequest_HintFlags) String() string {
 	return proto.EnumName(ReadRequest_HintFlags_name, int32(x))
 }
 func (ReadRequest_HintFlags) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{0, 1}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{0, 1}
 }
 
 type Aggregate_AggregateType int32
@@ -98,24 +98,39 @@ const (
 	AggregateTypeNone  Aggregate_AggregateType = 0
 	AggregateTypeSum   Aggregate_AggregateType = 1
 	AggregateTypeCount Aggregate_AggregateType = 2
+	AggregateTypeMin   Aggregate_AggregateType = 3
+	AggregateTypeMax   Aggregate_AggregateType = 4
+	AggregateTypeMean  Aggregate_AggregateType = 5
+	AggregateTypeFirst Aggregate_AggregateType = 6
+	AggregateTypeLast  Aggregate_AggregateType = 7
 )
 
 var Aggregate_AggregateType_name = map[int32]string{
 	0: "NONE",
 	1: "SUM",
 	2: "COUNT",
+	3: "MIN",
+	4: "MAX",
+	5: "MEAN",
+	6: "FIRST",
+	7: "LAST",
 }
 var Aggregate_AggregateType_value = map[string]int32{
 	"NONE":  0,
 	"SUM":   1,
 	"COUNT": 2,
+	"MIN":   3,
+	"MAX":   4,
+	"MEAN":  5,
+	"FIRST": 6,
+	"LAST":  7,
 }
 
 func (x Aggregate_AggregateType) String() string {
 	return proto.EnumName(Aggregate_AggregateType_name, int32(x))
 }
 func (Aggregate_AggregateType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{1, 0}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{1, 0}
 }
 
 type ReadResponse_FrameType int32
@@ -138,7 +153,7 @@ func (x ReadResponse_FrameType) String() string {
 	return proto.EnumName(ReadResponse_FrameType_name, int32(x))
 }
 func (ReadResponse_FrameType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 0}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 0}
 }
 
 type ReadResponse_DataType int32
@@ -170,7 +185,7 @@ func (x ReadResponse_DataType) String() string {
 	return proto.EnumName(ReadResponse_DataType_name, int32(x))
 }
 func (ReadResponse_DataType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 1}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 1}
 }
 
 // Request message for Storage.Read.
@@ -208,7 +223,7 @@ func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
 func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadRequest) ProtoMessage()    {}
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{0}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{0}
 }
 func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -239,6 +254,8 @@ var xxx_messageInfo_ReadRequest proto.InternalMessageInfo
 
 type Aggregate struct {
 	Type                 Aggregate_AggregateType `protobuf:"varint,1,opt,name=type,proto3,enum=influxdata.platform.storage.Aggregate_AggregateType" json:"type,omitempty"`
+	WindowEvery          int64                   `protobuf:"varint,2,opt,name=window_every,json=windowEvery,proto3" json:"window_every,omitempty"`
+	WindowOffset         int64                   `protobuf:"varint,3,opt,name=window_offset,json=windowOffset,proto3" json:"window_offset,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
 	XXX_sizecache        int32                   `json:"-"`
 }
@@ -247,7 +264,7 @@ func (m *Aggregate) Reset()         { *m = Aggregate{} }
 func (m *Aggregate) String() string { return proto.CompactTextString(m) }
 func (*Aggregate) ProtoMessage()    {}
 func (*Aggregate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{1}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{1}
 }
 func (m *Aggregate) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -287,7 +304,7 @@ func (m *Tag) Reset()         { *m = Tag{} }
 func (m *Tag) String() string { return proto.CompactTextString(m) }
 func (*Tag) ProtoMessage()    {}
 func (*Tag) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{2}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{2}
 }
 func (m *Tag) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -327,7 +344,7 @@ func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
 func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse) ProtoMessage()    {}
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3}
 }
 func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -374,7 +391,7 @@ func (m *ReadResponse_Frame) Reset()         { *m = ReadResponse_Frame{} }
 func (m *ReadResponse_Frame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_Frame) ProtoMessage()    {}
 func (*ReadResponse_Frame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 0}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 0}
 }
 func (m *ReadResponse_Frame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -677,7 +694,7 @@ func (m *ReadResponse_GroupFrame) Reset()         { *m = ReadResponse_GroupFrame
 func (m *ReadResponse_GroupFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_GroupFrame) ProtoMessage()    {}
 func (*ReadResponse_GroupFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 1}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 1}
 }
 func (m *ReadResponse_GroupFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -717,7 +734,7 @@ func (m *ReadResponse_SeriesFrame) Reset()         { *m = ReadResponse_SeriesFra
 func (m *ReadResponse_SeriesFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_SeriesFrame) ProtoMessage()    {}
 func (*ReadResponse_SeriesFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 2}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 2}
 }
 func (m *ReadResponse_SeriesFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -757,7 +774,7 @@ func (m *ReadResponse_FloatPointsFrame) Reset()         { *m = ReadResponse_Floa
 func (m *ReadResponse_FloatPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_FloatPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_FloatPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 3}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 3}
 }
 func (m *ReadResponse_FloatPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -797,7 +814,7 @@ func (m *ReadResponse_IntegerPointsFrame) Reset()         { *m = ReadResponse_In
 func (m *ReadResponse_IntegerPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_IntegerPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_IntegerPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 4}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 4}
 }
 func (m *ReadResponse_IntegerPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -837,7 +854,7 @@ func (m *ReadResponse_UnsignedPointsFrame) Reset()         { *m = ReadResponse_U
 func (m *ReadResponse_UnsignedPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_UnsignedPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_UnsignedPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 5}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 5}
 }
 func (m *ReadResponse_UnsignedPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -877,7 +894,7 @@ func (m *ReadResponse_BooleanPointsFrame) Reset()         { *m = ReadResponse_Bo
 func (m *ReadResponse_BooleanPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_BooleanPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_BooleanPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 6}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 6}
 }
 func (m *ReadResponse_BooleanPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -917,7 +934,7 @@ func (m *ReadResponse_StringPointsFrame) Reset()         { *m = ReadResponse_Str
 func (m *ReadResponse_StringPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_StringPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_StringPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{3, 7}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{3, 7}
 }
 func (m *ReadResponse_StringPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -956,7 +973,7 @@ func (m *CapabilitiesResponse) Reset()         { *m = CapabilitiesResponse{} }
 func (m *CapabilitiesResponse) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesResponse) ProtoMessage()    {}
 func (*CapabilitiesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{4}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{4}
 }
 func (m *CapabilitiesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -994,7 +1011,7 @@ func (m *HintsResponse) Reset()         { *m = HintsResponse{} }
 func (m *HintsResponse) String() string { return proto.CompactTextString(m) }
 func (*HintsResponse) ProtoMessage()    {}
 func (*HintsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{5}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{5}
 }
 func (m *HintsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1037,7 +1054,7 @@ func (m *TimestampRange) Reset()         { *m = TimestampRange{} }
 func (m *TimestampRange) String() string { return proto.CompactTextString(m) }
 func (*TimestampRange) ProtoMessage()    {}
 func (*TimestampRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_storage_common_01b6ac29b3fb8162, []int{6}
+	return fileDescriptor_storage_common_887507314fdc9ab2, []int{6}
 }
 func (m *TimestampRange) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1405,6 +1422,16 @@ func (m *Aggregate) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Type))
 	}
+	if m.WindowEvery != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.WindowEvery))
+	}
+	if m.WindowOffset != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.WindowOffset))
+	}
 	return i, nil
 }
 
@@ -2016,6 +2043,12 @@ func (m *Aggregate) Size() (n int) {
 	if m.Type != 0 {
 		n += 1 + sovStorageCommon(uint64(m.Type))
 	}
+	if m.WindowEvery != 0 {
+		n += 1 + sovStorageCommon(uint64(m.WindowEvery))
+	}
+	if m.WindowOffset != 0 {
+		n += 1 + sovStorageCommon(uint64(m.WindowOffset))
+	}
 	return n
 }
 
@@ -2746,6 +2779,44 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowEvery", wireType)
+			}
+			m.WindowEvery = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WindowEvery |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowOffset", wireType)
+			}
+			m.WindowOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WindowOffset |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorageCommon(dAtA[iNdEx:])
@@ -4585,106 +4656,113 @@ var (
 )
 
 func init() {
-	proto.RegisterFile("storage_common.proto", fileDescriptor_storage_common_01b6ac29b3fb8162)
-}
-
-var fileDescriptor_storage_common_01b6ac29b3fb8162 = []byte{
-	// 1539 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0x5f, 0x8f, 0x2a, 0x49,
-	0x15, 0xa7, 0xf9, 0xcf, 0xe1, 0xcf, 0xf4, 0xad, 0xc5, 0x09, 0xdb, 0xd7, 0x85, 0x5e, 0x62, 0x36,
-	0xa8, 0x2b, 0xa3, 0xec, 0x6e, 0xbc, 0xb9, 0xea, 0x03, 0xcc, 0x70, 0x07, 0x9c, 0x19, 0x98, 0x14,
-	0x8c, 0x71, 0x4d, 0x0c, 0xa9, 0x81, 0xa2, 0xb7, 0xb3, 0x4d, 0x77, 0xdb, 0xdd, 0x6c, 0x86, 0xc4,
-	0x77, 0x37, 0x3c, 0x5d, 0x5f, 0x4d, 0x48, 0x4c, 0x7c, 0xf4, 0xdd, 0xcf, 0x70, 0xdf, 0xf4, 0x13,
-	0x10, 0xc5, 0x0f, 0x61, 0xe2, 0x93, 0xa9, 0xaa, 0x6e, 0x68, 0x66, 0xae, 0x13, 0x78, 0xab, 0x3a,
-	0x7f, 0x7e, 0xbf, 0x53, 0xa7, 0xcf, 0x39, 0x55, 0x0d, 0x45, 0xd7, 0xb3, 0x1c, 0xa2, 0xd1, 0xd1,
-	0xd8, 0x9a, 0xcd, 0x2c, 0xb3, 0x6e, 0x3b, 0x96, 0x67, 0xa1, 0x97, 0xba, 0x39, 0x35, 0xe6, 0x0f,
-	0x13, 0xe2, 0x91, 0xba, 0x6d, 0x10, 0x6f, 0x6a, 0x39, 0xb3, 0xba, 0x6f, 0xa9, 0x14, 0x35, 0x4b,
-	0xb3, 0xb8, 0xdd, 0x19, 0x5b, 0x09, 0x17, 0xe5, 0xa5, 0x66, 0x59, 0x9a, 0x41, 0xcf, 0xf8, 0xee,
-	0x7e, 0x3e, 0x3d, 0xa3, 0x33, 0xdb, 0x5b, 0xf8, 0xca, 0x0f, 0x1f, 0x2b, 0x89, 0x19, 0xa8, 0x4e,
-	0x6c, 0x87, 0x4e, 0xf4, 0x31, 0xf1, 0xa8, 0x10, 0x54, 0xff, 0x93, 0x86, 0x2c, 0xa6, 0x64, 0x82,
-	0xe9, 0xef, 0xe6, 0xd4, 0xf5, 0x90, 0x01, 0x27, 0x9e, 0x3e, 0xa3, 0xae, 0x47, 0x66, 0xf6, 0xc8,
-	0x21, 0xa6, 0x46, 0x4b, 0x51, 0x55, 0xaa, 0x65, 0x1b, 0x3f, 0xac, 0x3f, 0x13, 0x65, 0x7d, 0x18,
-	0xf8, 0x60, 0xe6, 0xd2, 0x3a, 0x7d, 0xb7, 0xae, 0x44, 0x36, 0xeb, 0x4a, 0x61, 0x5f, 0x8e, 0x0b,
-	0xde, 0xde, 0x1e, 0x95, 0x01, 0x26, 0xd4, 0x1d, 0x53, 0x73, 0xa2, 0x9b, 0x5a, 0x29, 0xa6, 0x4a,
-	0xb5, 0x34, 0x0e, 0x49, 0xd0, 0xa7, 0x00, 0x9a, 0x63, 0xcd, 0xed, 0xd1, 0xd7, 0x74, 0xe1, 0x96,
-	0xe2, 0x6a, 0xac, 0x96, 0x69, 0xe5, 0x37, 0xeb, 0x4a, 0xe6, 0x92, 0x49, 0xaf, 0xe8, 0xc2, 0xc5,
-	0x19, 0x2d, 0x58, 0xa2, 0x0b, 0xc8, 0x6c, 0x8f, 0x57, 0x4a, 0xf0, 0xa8, 0x3f, 0x79, 0x36, 0xea,
-	0xdb, 0xc0, 0x1a, 0xef, 0x1c, 0x51, 0x03, 0x72, 0x2e, 0x75, 0x74, 0xea, 0x8e, 0x0c, 0x7d, 0xa6,
-	0x7b, 0xa5, 0xa4, 0x2a, 0xd5, 0x62, 0xad, 0x93, 0xcd, 0xba, 0x92, 0x1d, 0x70, 0xf9, 0x35, 0x13,
-	0xe3, 0xac, 0xbb, 0xdb, 0xa0, 0x2f, 0x20, 0xef, 0xfb, 0x58, 0xd3, 0xa9, 0x4b, 0xbd, 0x52, 0x8a,
-	0x3b, 0xc9, 0x9b, 0x75, 0x25, 0x27, 0x9c, 0xfa, 0x5c, 0x8e, 0x7d, 0x68, 0xb1, 0x63, 0x54, 0xb6,
-	0xa5, 0x9b, 0x5e, 0x40, 0x95, 0xde, 0x51, 0xdd, 0x72, 0xb9, 0x4f, 0x65, 0xef, 0x36, 0xec, 0x90,
-	0x44, 0xd3, 0x1c, 0xaa, 0xb1, 0x43, 0x66, 0x0e, 0x38, 0x64, 0x33, 0xb0, 0xc6, 0x3b, 0x47, 0x34,
-	0x84, 0x84, 0xe7, 0x90, 0x31, 0x2d, 0x81, 0x1a, 0xab, 0x65, 0x1b, 0x9f, 0x3d, 0x8b, 0x10, 0xaa,
-	0x8f, 0xfa, 0x90, 0x79, 0xb5, 0x4d, 0xcf, 0x59, 0xb4, 0x32, 0x9b, 0x75, 0x25, 0xc1, 0xf7, 0x58,
-	0x80, 0xa1, 0x0b, 0x48, 0xf0, 0xaf, 0x51, 0xca, 0xaa, 0x52, 0xad, 0xd0, 0xa8, 0x1f, 0x8c, 0xca,
-	0x3f, 0x27, 0x16, 0xce, 0xe8, 0x53, 0x48, 0x7c, 0xc5, 0xce, 0x5b, 0xca, 0xa9, 0x52, 0x2d, 0xd5,
-	0x3a, 0x65, 0x34, 0x1d, 0x26, 0xf8, 0xef, 0xba, 0x92, 0x61, 0x8b, 0x37, 0x06, 0xd1, 0x5c, 0x2c,
-	0x8c, 0x50, 0x1b, 0xb2, 0x0e, 0x25, 0x93, 0x91, 0x6b, 0xcd, 0x9d, 0x31, 0x2d, 0xe5, 0x79, 0x46,
-	0x8a, 0x75, 0xd1, 0x02, 0xf5, 0xa0, 0x05, 0xea, 0x4d, 0x73, 0xd1, 0x2a, 0x6c, 0xd6, 0x15, 0x60,
-	0xb4, 0x03, 0x6e, 0x8b, 0xc1, 0xd9, 0xae, 0x95, 0x57, 0x00, 0xbb, 0xa3, 0x21, 0x19, 0x62, 0x5f,
-	0xd3, 0x45, 0x49, 0x52, 0xa5, 0x5a, 0x06, 0xb3, 0x25, 0x2a, 0x42, 0xe2, 0x1b, 0x62, 0xcc, 0x45,
-	0x37, 0x64, 0xb0, 0xd8, 0xbc, 0x8e, 0xbe, 0x92, 0xaa, 0x7f, 0x90, 0x20, 0xc1, 0xe3, 0x47, 0x1f,
-	0x01, 0x5c, 0xe2, 0xfe, 0xdd, 0xed, 0xa8, 0xd7, 0xef, 0xb5, 0xe5, 0x88, 0x92, 0x5f, 0xae, 0x54,
-	0x51, 0xa9, 0x3d, 0xcb, 0xa4, 0xe8, 0x25, 0x64, 0x84, 0xba, 0x79, 0x7d, 0x2d, 0x4b, 0x4a, 0x6e,
-	0xb9, 0x52, 0xd3, 0x5c, 0xdb, 0x34, 0x0c, 0xf4, 0x21, 0xa4, 0x85, 0xb2, 0xf5, 0xa5, 0x1c, 0x55,
-	0xb2, 0xcb, 0x95, 0x9a, 0xe2, 0xba, 0xd6, 0x02, 0x7d, 0x0c, 0x39, 0xa1, 0x6a, 0xff, 0xfa, 0xbc,
-	0x7d, 0x3b, 0x94, 0x63, 0xca, 0xc9, 0x72, 0xa5, 0x66, 0xb9, 0xba, 0xfd, 0x30, 0xa6, 0xb6, 0xa7,
-	0xc4, 0xbf, 0xfd, 0x4b, 0x39, 0x52, 0xfd, 0xab, 0x04, 0xbb, 0xfc, 0x30, 0xba, 0x4e, 0xb7, 0x37,
-	0x0c, 0x82, 0xe1, 0x74, 0x4c, 0xcb, 0x63, 0xf9, 0x1e, 0x14, 0x7c, 0xe5, 0xe8, 0xb6, 0xdf, 0xed,
-	0x0d, 0x07, 0xb2, 0xa4, 0xc8, 0xcb, 0x95, 0x9a, 0x13, 0x16, 0xa2, 0xfa, 0xc2, 0x56, 0x83, 0x36,
-	0xee, 0xb6, 0x07, 0x72, 0x34, 0x6c, 0x25, 0x2a, 0x1b, 0x9d, 0x41, 0x91, 0x5b, 0x0d, 0xce, 0x3b,
-	0xed, 0x9b, 0x26, 0x3b, 0xdd, 0x68, 0xd8, 0xbd, 0x69, 0xcb, 0x71, 0xe5, 0x3b, 0xcb, 0x95, 0xfa,
-	0x82, 0xd9, 0x0e, 0xc6, 0x5f, 0xd1, 0x19, 0x69, 0x1a, 0x06, 0x9b, 0x07, 0x7e, 0xb4, 0x7f, 0x97,
-	0x20, 0xb3, 0xad, 0x4d, 0xd4, 0x81, 0xb8, 0xb7, 0xb0, 0x29, 0x4f, 0x79, 0xa1, 0xf1, 0xf9, 0x61,
-	0x15, 0xbd, 0x5b, 0x0d, 0x17, 0x36, 0xc5, 0x1c, 0xa1, 0xfa, 0x00, 0xf9, 0x3d, 0x31, 0xaa, 0x40,
-	0xdc, 0xcf, 0x01, 0x8f, 0x67, 0x4f, 0xc9, 0x93, 0xf1, 0x11, 0xc4, 0x06, 0x77, 0x37, 0xb2, 0xa4,
-	0x14, 0x97, 0x2b, 0x55, 0xde, 0xd3, 0x0f, 0xe6, 0x33, 0xf4, 0x31, 0x24, 0xce, 0xfb, 0x77, 0xbd,
-	0xa1, 0x1c, 0x55, 0x4e, 0x97, 0x2b, 0x15, 0xed, 0x19, 0x9c, 0x5b, 0x73, 0x33, 0xc8, 0xff, 0x8f,
-	0x20, 0x36, 0x24, 0x5a, 0xb8, 0x78, 0x72, 0xef, 0x29, 0x9e, 0x9c, 0x5f, 0x3c, 0xd5, 0x3f, 0x16,
-	0x20, 0x27, 0x9a, 0xc0, 0xb5, 0x2d, 0xd3, 0xa5, 0xe8, 0x06, 0x92, 0x53, 0x87, 0xcc, 0xa8, 0x5b,
-	0x92, 0x78, 0x57, 0x9e, 0x1d, 0xd0, 0x3f, 0xc2, 0xb5, 0xfe, 0x86, 0xf9, 0xb5, 0xe2, 0x6c, 0xec,
-	0x62, 0x1f, 0x44, 0xf9, 0x36, 0x09, 0x09, 0x2e, 0x47, 0x7d, 0x48, 0x8a, 0xb9, 0xc3, 0x83, 0xca,
-	0x36, 0xbe, 0x38, 0x1c, 0x58, 0x7c, 0x63, 0x0e, 0xd3, 0x89, 0x60, 0x1f, 0x06, 0xd9, 0x90, 0x9b,
-	0x1a, 0x16, 0xf1, 0x46, 0x62, 0x32, 0xf9, 0x57, 0xc4, 0xeb, 0x23, 0xe2, 0x65, 0xde, 0xa2, 0xca,
-	0x44, 0xe8, 0x7c, 0xe8, 0x85, 0xa4, 0x9d, 0x08, 0xce, 0x4e, 0x77, 0x5b, 0xf4, 0x00, 0x05, 0xdd,
-	0xf4, 0xa8, 0x46, 0x9d, 0x80, 0x33, 0xc6, 0x39, 0x7f, 0x7e, 0x38, 0x67, 0x57, 0xf8, 0x87, 0x59,
-	0x5f, 0x6c, 0xd6, 0x95, 0xfc, 0x9e, 0xbc, 0x13, 0xc1, 0x79, 0x3d, 0x2c, 0x40, 0xbf, 0x87, 0x93,
-	0xb9, 0xe9, 0xea, 0x9a, 0x49, 0x27, 0x01, 0x75, 0x9c, 0x53, 0xff, 0xe2, 0x70, 0xea, 0x3b, 0x1f,
-	0x20, 0xcc, 0x8d, 0xd8, 0xfd, 0xb8, 0xaf, 0xe8, 0x44, 0x70, 0x61, 0xbe, 0x27, 0x61, 0xe7, 0xbe,
-	0xb7, 0x2c, 0x83, 0x12, 0x33, 0x20, 0x4f, 0x1c, 0x7b, 0xee, 0x96, 0xf0, 0x7f, 0x72, 0xee, 0x3d,
-	0x39, 0x3b, 0xf7, 0x7d, 0x58, 0x80, 0x3c, 0xc8, 0xbb, 0x9e, 0xa3, 0x9b, 0x5a, 0x40, 0x9c, 0xe4,
-	0xc4, 0x3f, 0x3b, 0xa2, 0x76, 0xb8, 0x7b, 0x98, 0x57, 0x5c, 0x88, 0x21, 0x71, 0x27, 0x82, 0x73,
-	0x6e, 0x68, 0x8f, 0xae, 0x83, 0x2b, 0x24, 0xc5, 0xd9, 0x3e, 0x3f, 0x9c, 0x8d, 0xcf, 0xc3, 0xa0,
-	0x50, 0x05, 0x48, 0x2b, 0x09, 0x71, 0xe6, 0xa9, 0x3c, 0x00, 0xec, 0xd4, 0xe8, 0x13, 0x48, 0x7b,
-	0x44, 0x13, 0x6f, 0x0a, 0xd6, 0x69, 0xb9, 0x56, 0x76, 0xb3, 0xae, 0xa4, 0x86, 0x44, 0xe3, 0x2f,
-	0x8a, 0x94, 0x27, 0x16, 0xa8, 0x05, 0xc8, 0x26, 0x8e, 0xa7, 0x7b, 0xba, 0x65, 0x32, 0xeb, 0xd1,
-	0x37, 0xc4, 0x60, 0xb5, 0xce, 0x3c, 0x8a, 0x9b, 0x75, 0x45, 0xbe, 0x0d, 0xb4, 0x57, 0x74, 0xf1,
-	0x2b, 0x62, 0xb8, 0x58, 0xb6, 0x1f, 0x49, 0x94, 0x3f, 0x49, 0x90, 0x0d, 0xf5, 0x10, 0x7a, 0x0d,
-	0x71, 0x8f, 0x68, 0x41, 0x87, 0xab, 0xcf, 0x3f, 0xaa, 0x88, 0xe6, 0xb7, 0x34, 0xf7, 0x41, 0x7d,
-	0xc8, 0x30, 0xc3, 0x11, 0x1f, 0x94, 0x51, 0x3e, 0x28, 0x1b, 0x87, 0xe7, 0xe7, 0x82, 0x78, 0x84,
-	0x8f, 0xc9, 0xf4, 0xc4, 0x5f, 0x29, 0xbf, 0x04, 0xf9, 0x71, 0x23, 0xb2, 0x27, 0xd9, 0xf6, 0x91,
-	0x26, 0xc2, 0x94, 0x71, 0x48, 0x82, 0x4e, 0x21, 0xc9, 0xc7, 0x97, 0x48, 0x84, 0x84, 0xfd, 0x9d,
-	0x72, 0x0d, 0xe8, 0x69, 0x83, 0x1d, 0x89, 0x16, 0xdb, 0xa2, 0xdd, 0xc0, 0x07, 0xef, 0xe9, 0x99,
-	0x23, 0xe1, 0xe2, 0xe1, 0xe0, 0x9e, 0x76, 0xc1, 0x91, 0x68, 0xe9, 0x2d, 0xda, 0x15, 0xbc, 0x78,
-	0x52, 0xda, 0x47, 0x82, 0x65, 0x02, 0xb0, 0xea, 0x00, 0x32, 0x1c, 0xc0, 0xbf, 0xaa, 0x92, 0xfe,
-	0x45, 0x1b, 0x51, 0x3e, 0x58, 0xae, 0xd4, 0x93, 0xad, 0xca, 0xbf, 0x6b, 0x2b, 0x90, 0xdc, 0xde,
-	0xd7, 0xfb, 0x06, 0x22, 0x16, 0xff, 0x26, 0xfa, 0x9b, 0x04, 0xe9, 0xe0, 0x7b, 0xa3, 0xef, 0x42,
-	0xe2, 0xcd, 0x75, 0xbf, 0x39, 0x94, 0x23, 0xca, 0x8b, 0xe5, 0x4a, 0xcd, 0x07, 0x0a, 0xfe, 0xe9,
-	0x91, 0x0a, 0xa9, 0x6e, 0x6f, 0xd8, 0xbe, 0x6c, 0xe3, 0x00, 0x32, 0xd0, 0xfb, 0x9f, 0x13, 0x55,
-	0x21, 0x7d, 0xd7, 0x1b, 0x74, 0x2f, 0x7b, 0xed, 0x0b, 0x39, 0x2a, 0xee, 0xc8, 0xc0, 0x24, 0xf8,
-	0x46, 0x0c, 0xa5, 0xd5, 0xef, 0x5f, 0xb7, 0x9b, 0x3d, 0x39, 0xb6, 0x8f, 0xe2, 0xe7, 0x1d, 0x95,
-	0x21, 0x39, 0x18, 0xe2, 0x6e, 0xef, 0x52, 0x8e, 0x2b, 0x68, 0xb9, 0x52, 0x0b, 0x81, 0x81, 0x48,
-	0xa5, 0x1f, 0xf8, 0x9f, 0x25, 0x28, 0x9e, 0x13, 0x9b, 0xdc, 0xeb, 0x86, 0xee, 0xe9, 0xd4, 0xdd,
-	0xde, 0x8d, 0x7d, 0x88, 0x8f, 0x89, 0x1d, 0xf4, 0xcd, 0xf3, 0x43, 0xe8, 0x7d, 0x00, 0x4c, 0xe8,
-	0xf2, 0xc7, 0x1d, 0xe6, 0x40, 0xca, 0x4f, 0x21, 0xb3, 0x15, 0x1d, 0xf5, 0xde, 0x3b, 0x81, 0x3c,
-	0x7f, 0x8d, 0x06, 0xc8, 0xd5, 0x57, 0xf0, 0xe8, 0x37, 0x87, 0x39, 0xbb, 0x1e, 0x71, 0x3c, 0x0e,
-	0x18, 0xc3, 0x62, 0xc3, 0x48, 0xa8, 0x39, 0xe1, 0x80, 0x31, 0xcc, 0x96, 0x8d, 0xb7, 0x51, 0x48,
-	0x0d, 0x44, 0xd0, 0xe8, 0xb7, 0x10, 0x67, 0xed, 0x8a, 0x6a, 0x87, 0x3e, 0x9a, 0x95, 0xef, 0x1f,
-	0xdc, 0xfb, 0x3f, 0x96, 0xd0, 0x97, 0x90, 0x0b, 0xa7, 0x05, 0x9d, 0x3e, 0x79, 0x21, 0xb7, 0xd9,
-	0x1f, 0xa4, 0xf2, 0x93, 0xa3, 0x33, 0x8b, 0xae, 0x40, 0x3c, 0xcf, 0xff, 0x2f, 0xe6, 0x0f, 0x9e,
-	0xc5, 0xdc, 0x4b, 0x66, 0xab, 0xf2, 0xee, 0x5f, 0xe5, 0xc8, 0xbb, 0x4d, 0x59, 0xfa, 0xc7, 0xa6,
-	0x2c, 0xfd, 0x73, 0x53, 0x96, 0xde, 0xfe, 0xbb, 0x1c, 0xf9, 0x0d, 0x9f, 0x7b, 0x6c, 0xec, 0xb9,
-	0xf7, 0x49, 0x0e, 0xfe, 0xd9, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0x62, 0x34, 0xad, 0x1c, 0x4b,
-	0x0f, 0x00, 0x00,
+	proto.RegisterFile("storage_common.proto", fileDescriptor_storage_common_887507314fdc9ab2)
+}
+
+var fileDescriptor_storage_common_887507314fdc9ab2 = []byte{
+	// 1649 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0xcd, 0x6f, 0x23, 0x49,
+	0x15, 0x77, 0xfb, 0xdb, 0xcf, 0x1f, 0xe9, 0xa9, 0x0d, 0x91, 0xb7, 0x87, 0xb5, 0x7b, 0x23, 0xb4,
+	0x18, 0x58, 0x1c, 0xc8, 0xee, 0x8a, 0xd1, 0x00, 0x07, 0x3b, 0xe3, 0xc4, 0x66, 0xfc, 0x11, 0x95,
+	0x1d, 0xd8, 0x45, 0x42, 0x56, 0x25, 0xae, 0xf4, 0xb6, 0xd6, 0xee, 0x6e, 0xba, 0xcb, 0x33, 0xb1,
+	0xc4, 0x9d, 0x95, 0x4f, 0xcb, 0x15, 0x64, 0x09, 0x89, 0x23, 0x77, 0xfe, 0x86, 0x39, 0xee, 0x91,
+	0x93, 0x05, 0x9e, 0x3f, 0x02, 0x89, 0x13, 0xaa, 0xaa, 0x6e, 0xbb, 0x3d, 0x09, 0x91, 0x7d, 0xab,
+	0xf7, 0xf5, 0x7b, 0xaf, 0xaa, 0xdf, 0xaf, 0xea, 0x35, 0x1c, 0x7a, 0xcc, 0x76, 0x89, 0x41, 0x87,
+	0x37, 0xf6, 0x64, 0x62, 0x5b, 0x55, 0xc7, 0xb5, 0x99, 0x8d, 0x9e, 0x9a, 0xd6, 0xed, 0x78, 0x7a,
+	0x37, 0x22, 0x8c, 0x54, 0x9d, 0x31, 0x61, 0xb7, 0xb6, 0x3b, 0xa9, 0xfa, 0x9e, 0xda, 0xa1, 0x61,
+	0x1b, 0xb6, 0xf0, 0x3b, 0xe1, 0x2b, 0x19, 0xa2, 0x3d, 0x35, 0x6c, 0xdb, 0x18, 0xd3, 0x13, 0x21,
+	0x5d, 0x4f, 0x6f, 0x4f, 0xe8, 0xc4, 0x61, 0x33, 0xdf, 0xf8, 0xfe, 0xbb, 0x46, 0x62, 0x05, 0xa6,
+	0x03, 0xc7, 0xa5, 0x23, 0xf3, 0x86, 0x30, 0x2a, 0x15, 0xc7, 0xff, 0x49, 0x43, 0x16, 0x53, 0x32,
+	0xc2, 0xf4, 0xf7, 0x53, 0xea, 0x31, 0x34, 0x86, 0x03, 0x66, 0x4e, 0xa8, 0xc7, 0xc8, 0xc4, 0x19,
+	0xba, 0xc4, 0x32, 0x68, 0x31, 0xaa, 0x2b, 0x95, 0xec, 0xe9, 0x8f, 0xaa, 0x8f, 0x54, 0x59, 0x1d,
+	0x04, 0x31, 0x98, 0x87, 0xd4, 0x8f, 0xde, 0x2c, 0xcb, 0x91, 0xd5, 0xb2, 0x5c, 0xd8, 0xd6, 0xe3,
+	0x02, 0xdb, 0x92, 0x51, 0x09, 0x60, 0x44, 0xbd, 0x1b, 0x6a, 0x8d, 0x4c, 0xcb, 0x28, 0xc6, 0x74,
+	0xa5, 0x92, 0xc6, 0x21, 0x0d, 0xfa, 0x18, 0xc0, 0x70, 0xed, 0xa9, 0x33, 0xfc, 0x8a, 0xce, 0xbc,
+	0x62, 0x5c, 0x8f, 0x55, 0x32, 0xf5, 0xfc, 0x6a, 0x59, 0xce, 0x5c, 0x70, 0xed, 0x4b, 0x3a, 0xf3,
+	0x70, 0xc6, 0x08, 0x96, 0xe8, 0x05, 0x64, 0xd6, 0xdb, 0x2b, 0x26, 0x44, 0xd5, 0x1f, 0x3d, 0x5a,
+	0xf5, 0x65, 0xe0, 0x8d, 0x37, 0x81, 0xe8, 0x14, 0x72, 0x1e, 0x75, 0x4d, 0xea, 0x0d, 0xc7, 0xe6,
+	0xc4, 0x64, 0xc5, 0xa4, 0xae, 0x54, 0x62, 0xf5, 0x83, 0xd5, 0xb2, 0x9c, 0xed, 0x0b, 0x7d, 0x9b,
+	0xab, 0x71, 0xd6, 0xdb, 0x08, 0xe8, 0x33, 0xc8, 0xfb, 0x31, 0xf6, 0xed, 0xad, 0x47, 0x59, 0x31,
+	0x25, 0x82, 0xd4, 0xd5, 0xb2, 0x9c, 0x93, 0x41, 0x3d, 0xa1, 0xc7, 0x39, 0x2f, 0x24, 0xf1, 0x54,
+	0x8e, 0x6d, 0x5a, 0x2c, 0x48, 0x95, 0xde, 0xa4, 0xba, 0x14, 0x7a, 0x3f, 0x95, 0xb3, 0x11, 0xf8,
+	0x26, 0x89, 0x61, 0xb8, 0xd4, 0xe0, 0x9b, 0xcc, 0xec, 0xb0, 0xc9, 0x5a, 0xe0, 0x8d, 0x37, 0x81,
+	0x68, 0x00, 0x09, 0xe6, 0x92, 0x1b, 0x5a, 0x04, 0x3d, 0x56, 0xc9, 0x9e, 0x7e, 0xf2, 0x28, 0x42,
+	0xa8, 0x3f, 0xaa, 0x03, 0x1e, 0xd5, 0xb0, 0x98, 0x3b, 0xab, 0x67, 0x56, 0xcb, 0x72, 0x42, 0xc8,
+	0x58, 0x82, 0xa1, 0x17, 0x90, 0x10, 0x5f, 0xa3, 0x98, 0xd5, 0x95, 0x4a, 0xe1, 0xb4, 0xba, 0x33,
+	0xaa, 0xf8, 0x9c, 0x58, 0x06, 0xa3, 0x8f, 0x21, 0xf1, 0x25, 0xdf, 0x6f, 0x31, 0xa7, 0x2b, 0x95,
+	0x54, 0xfd, 0x88, 0xa7, 0x69, 0x72, 0xc5, 0x7f, 0x97, 0xe5, 0x0c, 0x5f, 0x9c, 0x8f, 0x89, 0xe1,
+	0x61, 0xe9, 0x84, 0x1a, 0x90, 0x75, 0x29, 0x19, 0x0d, 0x3d, 0x7b, 0xea, 0xde, 0xd0, 0x62, 0x5e,
+	0x9c, 0xc8, 0x61, 0x55, 0x52, 0xa0, 0x1a, 0x50, 0xa0, 0x5a, 0xb3, 0x66, 0xf5, 0xc2, 0x6a, 0x59,
+	0x06, 0x9e, 0xb6, 0x2f, 0x7c, 0x31, 0xb8, 0xeb, 0xb5, 0xf6, 0x0c, 0x60, 0xb3, 0x35, 0xa4, 0x42,
+	0xec, 0x2b, 0x3a, 0x2b, 0x2a, 0xba, 0x52, 0xc9, 0x60, 0xbe, 0x44, 0x87, 0x90, 0x78, 0x45, 0xc6,
+	0x53, 0xc9, 0x86, 0x0c, 0x96, 0xc2, 0xf3, 0xe8, 0x33, 0xe5, 0xf8, 0x8f, 0x0a, 0x24, 0x44, 0xfd,
+	0xe8, 0x03, 0x80, 0x0b, 0xdc, 0xbb, 0xba, 0x1c, 0x76, 0x7b, 0xdd, 0x86, 0x1a, 0xd1, 0xf2, 0xf3,
+	0x85, 0x2e, 0x3b, 0xb5, 0x6b, 0x5b, 0x14, 0x3d, 0x85, 0x8c, 0x34, 0xd7, 0xda, 0x6d, 0x55, 0xd1,
+	0x72, 0xf3, 0x85, 0x9e, 0x16, 0xd6, 0xda, 0x78, 0x8c, 0xde, 0x87, 0xb4, 0x34, 0xd6, 0xbf, 0x50,
+	0xa3, 0x5a, 0x76, 0xbe, 0xd0, 0x53, 0xc2, 0x56, 0x9f, 0xa1, 0x0f, 0x21, 0x27, 0x4d, 0x8d, 0xcf,
+	0xcf, 0x1a, 0x97, 0x03, 0x35, 0xa6, 0x1d, 0xcc, 0x17, 0x7a, 0x56, 0x98, 0x1b, 0x77, 0x37, 0xd4,
+	0x61, 0x5a, 0xfc, 0xeb, 0xbf, 0x95, 0x22, 0xc7, 0x7f, 0x57, 0x60, 0x73, 0x3e, 0x3c, 0x5d, 0xb3,
+	0xd5, 0x1d, 0x04, 0xc5, 0x88, 0x74, 0xdc, 0x2a, 0x6a, 0xf9, 0x1e, 0x14, 0x7c, 0xe3, 0xf0, 0xb2,
+	0xd7, 0xea, 0x0e, 0xfa, 0xaa, 0xa2, 0xa9, 0xf3, 0x85, 0x9e, 0x93, 0x1e, 0xb2, 0xfb, 0xc2, 0x5e,
+	0xfd, 0x06, 0x6e, 0x35, 0xfa, 0x6a, 0x34, 0xec, 0x25, 0x3b, 0x1b, 0x9d, 0xc0, 0xa1, 0xf0, 0xea,
+	0x9f, 0x35, 0x1b, 0x9d, 0x1a, 0xdf, 0xdd, 0x70, 0xd0, 0xea, 0x34, 0xd4, 0xb8, 0xf6, 0x9d, 0xf9,
+	0x42, 0x7f, 0xc2, 0x7d, 0xfb, 0x37, 0x5f, 0xd2, 0x09, 0xa9, 0x8d, 0xc7, 0xfc, 0x3e, 0xf0, 0xab,
+	0x5d, 0xc6, 0x20, 0xb3, 0xee, 0x4d, 0xd4, 0x84, 0x38, 0x9b, 0x39, 0x54, 0x1c, 0x79, 0xe1, 0xf4,
+	0xd3, 0xdd, 0x3a, 0x7a, 0xb3, 0x1a, 0xcc, 0x1c, 0x8a, 0x05, 0x02, 0x27, 0xd5, 0x6b, 0xd3, 0x1a,
+	0xd9, 0xaf, 0x87, 0xf4, 0x15, 0x75, 0x67, 0xc5, 0xe8, 0x86, 0x54, 0xbf, 0x11, 0xfa, 0x06, 0x57,
+	0xe3, 0xec, 0xeb, 0x8d, 0xc0, 0xf9, 0xeb, 0xc7, 0xf8, 0xfc, 0x8d, 0x6d, 0xf8, 0x2b, 0x83, 0x02,
+	0xfe, 0xbe, 0x0e, 0x49, 0xc7, 0x7f, 0x89, 0x42, 0x7e, 0xab, 0x04, 0x54, 0x86, 0xb8, 0x7f, 0xde,
+	0x62, 0xef, 0x5b, 0x46, 0x71, 0xf0, 0x1f, 0x40, 0xac, 0x7f, 0xd5, 0x51, 0x15, 0xed, 0x70, 0xbe,
+	0xd0, 0xd5, 0x2d, 0x7b, 0x7f, 0x3a, 0x41, 0x1f, 0x42, 0xe2, 0xac, 0x77, 0xd5, 0x1d, 0xa8, 0x51,
+	0xed, 0x68, 0xbe, 0xd0, 0xd1, 0x96, 0xc3, 0x99, 0x3d, 0xb5, 0x18, 0x47, 0xe8, 0xb4, 0xba, 0x6a,
+	0xec, 0x01, 0x84, 0x8e, 0x69, 0x09, 0x73, 0xed, 0x73, 0x35, 0xfe, 0x90, 0x99, 0xdc, 0xf1, 0x02,
+	0x3b, 0x8d, 0x5a, 0x57, 0x4d, 0x3c, 0x50, 0x60, 0x87, 0x12, 0x8b, 0x57, 0x70, 0xde, 0xc2, 0xfd,
+	0x81, 0x9a, 0x7c, 0xa0, 0x82, 0x73, 0xd3, 0xf5, 0x18, 0xc7, 0x68, 0xd7, 0xfa, 0x03, 0x35, 0xf5,
+	0x00, 0x46, 0x9b, 0x78, 0x41, 0x3b, 0xfe, 0x18, 0x62, 0x03, 0x62, 0x84, 0xb9, 0x94, 0x7b, 0x80,
+	0x4b, 0x39, 0x9f, 0x4b, 0xc7, 0x7f, 0x2a, 0x40, 0x4e, 0xde, 0x09, 0x9e, 0x63, 0x5b, 0x1e, 0x45,
+	0x1d, 0x48, 0xde, 0xba, 0x64, 0x42, 0xbd, 0xa2, 0x22, 0x2e, 0xa9, 0x93, 0x1d, 0xae, 0x13, 0x19,
+	0x5a, 0x3d, 0xe7, 0x71, 0xf5, 0x38, 0x7f, 0x85, 0xb0, 0x0f, 0xa2, 0x7d, 0x9d, 0x84, 0x84, 0xd0,
+	0xa3, 0x1e, 0x24, 0xe5, 0x35, 0x2c, 0x8a, 0xca, 0x9e, 0x7e, 0xb6, 0x3b, 0xb0, 0x6c, 0x79, 0x01,
+	0xd3, 0x8c, 0x60, 0x1f, 0x06, 0x39, 0x90, 0xbb, 0x1d, 0xdb, 0x84, 0x0d, 0xe5, 0x45, 0xed, 0xbf,
+	0x98, 0xcf, 0xf7, 0xa8, 0x97, 0x47, 0x4b, 0xd2, 0xc9, 0xd2, 0x45, 0xbb, 0x86, 0xb4, 0xcd, 0x08,
+	0xce, 0xde, 0x6e, 0x44, 0x74, 0x07, 0x05, 0xd3, 0x62, 0xd4, 0xa0, 0x6e, 0x90, 0x33, 0x26, 0x72,
+	0xfe, 0x62, 0xf7, 0x9c, 0x2d, 0x19, 0x1f, 0xce, 0xfa, 0x64, 0xb5, 0x2c, 0xe7, 0xb7, 0xf4, 0xcd,
+	0x08, 0xce, 0x9b, 0x61, 0x05, 0xfa, 0x03, 0x1c, 0x4c, 0x2d, 0xcf, 0x34, 0x2c, 0x3a, 0x0a, 0x52,
+	0xc7, 0x45, 0xea, 0x5f, 0xee, 0x9e, 0xfa, 0xca, 0x07, 0x08, 0xe7, 0x46, 0x7c, 0x5c, 0xd8, 0x36,
+	0x34, 0x23, 0xb8, 0x30, 0xdd, 0xd2, 0xf0, 0x7d, 0x5f, 0xdb, 0xf6, 0x98, 0x12, 0x2b, 0x48, 0x9e,
+	0xd8, 0x77, 0xdf, 0x75, 0x19, 0x7f, 0x6f, 0xdf, 0x5b, 0x7a, 0xbe, 0xef, 0xeb, 0xb0, 0x02, 0x31,
+	0xc8, 0x7b, 0xcc, 0x35, 0x2d, 0x23, 0x48, 0x9c, 0x14, 0x89, 0x7f, 0xbe, 0x47, 0xef, 0x88, 0xf0,
+	0x70, 0x5e, 0x39, 0x1f, 0x84, 0xd4, 0xcd, 0x08, 0xce, 0x79, 0x21, 0x19, 0xb5, 0x83, 0x17, 0x35,
+	0x25, 0xb2, 0x7d, 0xba, 0x7b, 0x36, 0xf1, 0x3c, 0x04, 0x8d, 0x2a, 0x41, 0xea, 0x49, 0x88, 0xf3,
+	0x48, 0xed, 0x0e, 0x60, 0x63, 0x46, 0x1f, 0x41, 0x9a, 0x11, 0x43, 0x8e, 0x58, 0x9c, 0x69, 0xb9,
+	0x7a, 0x76, 0xb5, 0x2c, 0xa7, 0x06, 0xc4, 0x10, 0x03, 0x56, 0x8a, 0xc9, 0x05, 0xaa, 0x03, 0x72,
+	0x88, 0xcb, 0x4c, 0x66, 0xda, 0x16, 0xf7, 0x1e, 0xbe, 0x22, 0x63, 0xde, 0xeb, 0x3c, 0xe2, 0x70,
+	0xb5, 0x2c, 0xab, 0x97, 0x81, 0xf5, 0x25, 0x9d, 0xfd, 0x9a, 0x8c, 0x3d, 0xac, 0x3a, 0xef, 0x68,
+	0xb4, 0x3f, 0x2b, 0x90, 0x0d, 0x71, 0x08, 0x3d, 0x87, 0x38, 0x23, 0x46, 0xc0, 0x70, 0xfd, 0xf1,
+	0x19, 0x93, 0x18, 0x3e, 0xa5, 0x45, 0x0c, 0xea, 0x41, 0x86, 0x3b, 0x0e, 0xc5, 0xbb, 0x11, 0x15,
+	0xef, 0xc6, 0xe9, 0xee, 0xe7, 0xf3, 0x82, 0x30, 0x22, 0x5e, 0x8d, 0xf4, 0xc8, 0x5f, 0x69, 0xbf,
+	0x02, 0xf5, 0x5d, 0x22, 0xf2, 0x09, 0x75, 0x3d, 0xb3, 0xca, 0x32, 0x55, 0x1c, 0xd2, 0xa0, 0x23,
+	0x48, 0x8a, 0xeb, 0x4b, 0x1e, 0x84, 0x82, 0x7d, 0x49, 0x6b, 0x03, 0xba, 0x4f, 0xb0, 0x3d, 0xd1,
+	0x62, 0x6b, 0xb4, 0x0e, 0xbc, 0xf7, 0x00, 0x67, 0xf6, 0x84, 0x8b, 0x87, 0x8b, 0xbb, 0xcf, 0x82,
+	0x3d, 0xd1, 0xd2, 0x6b, 0xb4, 0x97, 0xf0, 0xe4, 0x5e, 0x6b, 0xef, 0x09, 0x96, 0x09, 0xc0, 0x8e,
+	0xfb, 0x90, 0x11, 0x00, 0xfe, 0x6b, 0x9a, 0xf4, 0xe7, 0x8e, 0x88, 0xf6, 0xde, 0x7c, 0xa1, 0x1f,
+	0xac, 0x4d, 0xfe, 0xe8, 0x51, 0x86, 0xe4, 0x7a, 0x7c, 0xd9, 0x76, 0x90, 0xb5, 0xf8, 0x2f, 0xd1,
+	0x3f, 0x14, 0x48, 0x07, 0xdf, 0x1b, 0x7d, 0x17, 0x12, 0xe7, 0xed, 0x5e, 0x6d, 0xa0, 0x46, 0xb4,
+	0x27, 0xf3, 0x85, 0x9e, 0x0f, 0x0c, 0xe2, 0xd3, 0x23, 0x1d, 0x52, 0xad, 0xee, 0xa0, 0x71, 0xd1,
+	0xc0, 0x01, 0x64, 0x60, 0xf7, 0x3f, 0x27, 0x3a, 0x86, 0xf4, 0x55, 0xb7, 0xdf, 0xba, 0xe8, 0x36,
+	0x5e, 0xa8, 0x51, 0xf9, 0xca, 0x06, 0x2e, 0xc1, 0x37, 0xe2, 0x28, 0xf5, 0x5e, 0xaf, 0xcd, 0x1f,
+	0xda, 0xd8, 0x36, 0x8a, 0x7f, 0xee, 0xa8, 0x04, 0xc9, 0xfe, 0x00, 0xb7, 0xba, 0x17, 0x6a, 0x5c,
+	0x43, 0xf3, 0x85, 0x5e, 0x08, 0x1c, 0xe4, 0x51, 0xfa, 0x85, 0xff, 0x55, 0x81, 0xc3, 0x33, 0xe2,
+	0x90, 0x6b, 0x73, 0x6c, 0x32, 0x93, 0x7a, 0xeb, 0xb7, 0xb1, 0x07, 0xf1, 0x1b, 0xe2, 0x04, 0xbc,
+	0x79, 0xfc, 0x12, 0x7a, 0x08, 0x80, 0x2b, 0x3d, 0x31, 0xeb, 0x62, 0x01, 0xa4, 0xfd, 0x0c, 0x32,
+	0x6b, 0xd5, 0x5e, 0xe3, 0xef, 0x01, 0xe4, 0xc5, 0x70, 0x1e, 0x20, 0x1f, 0x3f, 0x83, 0x77, 0xfe,
+	0xfa, 0x78, 0xb0, 0xc7, 0x88, 0xcb, 0x04, 0x60, 0x0c, 0x4b, 0x81, 0x27, 0xa1, 0xd6, 0x48, 0x8e,
+	0x67, 0x98, 0x2f, 0x4f, 0xbf, 0x89, 0x42, 0xaa, 0x2f, 0x8b, 0x46, 0xbf, 0x83, 0x38, 0xa7, 0x2b,
+	0xaa, 0xec, 0xfa, 0x0f, 0xa1, 0xfd, 0x60, 0x67, 0xee, 0xff, 0x44, 0x41, 0x5f, 0x40, 0x2e, 0x7c,
+	0x2c, 0xe8, 0xe8, 0xde, 0x0f, 0x43, 0x83, 0xff, 0x50, 0x6b, 0x3f, 0xdd, 0xfb, 0x64, 0xd1, 0x4b,
+	0x90, 0x7f, 0x2b, 0xff, 0x17, 0xf3, 0x87, 0x8f, 0x62, 0x6e, 0x1d, 0x66, 0xfd, 0xfb, 0x6f, 0xfe,
+	0x5d, 0x8a, 0xbc, 0x59, 0x95, 0x94, 0x6f, 0x57, 0x25, 0xe5, 0x5f, 0xab, 0x92, 0xf2, 0xcd, 0xdb,
+	0x52, 0xe4, 0xdb, 0xb7, 0xa5, 0xc8, 0x3f, 0xdf, 0x96, 0x22, 0xbf, 0x15, 0xf7, 0x1f, 0xbf, 0xfe,
+	0xbc, 0xeb, 0xa4, 0x48, 0xf2, 0xc9, 0xff, 0x06, 0x00, 0x39, 0xeb, 0x0f, 0xc8, 0x62, 0x10, 0x00,
+	0x00,
 }
@@ -297,9 +297,10 @@ type groupNoneCursor struct {
 	cur  SeriesCursor
 	row  SeriesRow
 	keys [][]byte
+	err  error
 }
 
-func (c *groupNoneCursor) Err() error                 { return nil }
+func (c *groupNoneCursor) Err() error                 { return c.err }
 func (c *groupNoneCursor) Tags() models.Tags          { return c.row.Tags }
 func (c *groupNoneCursor) Keys() [][]byte             { return c.keys }
 func (c *groupNoneCursor) PartitionKeyVals() [][]byte { return nil }
@@ -307,6 +308,10 @@ func (c *groupNoneCursor) Close()                     { c.cur.Close() }
 func (c *groupNoneCursor) Stats() cursors.CursorStats { return c.row.Query.Stats() }
 
 func (c *groupNoneCursor) Next() bool {
+	if c.err != nil {
+		return false
+	}
+
 	row := c.cur.Next()
 	if row == nil {
 		return false
@@ -320,7 +325,7 @@ func (c *groupNoneCursor) Next() bool {
 func (c *groupNoneCursor) Cursor() cursors.Cursor {
 	cur := c.mb.createCursor(c.row)
 	if c.agg != nil {
-		cur = c.mb.newAggregateCursor(c.ctx, c.agg, cur)
+		cur, c.err = c.mb.newAggregateCursor(c.ctx, c.agg, cur)
 	}
 	return cur
 }
@@ -333,21 +338,23 @@ type groupByCursor struct {
 	rows []*SeriesRow
 	keys [][]byte
 	vals [][]byte
+	err  error
 }
 
 func (c *groupByCursor) reset(rows []*SeriesRow) {
 	c.i = 0
 	c.rows = rows
+	c.err = nil
 }
 
-func (c *groupByCursor) Err() error                 { return nil }
+func (c *groupByCursor) Err() error                 { return c.err }
 func (c *groupByCursor) Keys() [][]byte             { return c.keys }
 func (c *groupByCursor) PartitionKeyVals() [][]byte { return c.vals }
 func (c *groupByCursor) Tags() models.Tags          { return c.rows[c.i-1].Tags }
 func (c *groupByCursor) Close()                     {}
 
 func (c *groupByCursor) Next() bool {
-	if c.i < len(c.rows) {
+	if c.err == nil && c.i < len(c.rows) {
 		c.i++
 		return true
 	}
@@ -357,7 +364,7 @@ func (c *groupByCursor) Next() bool {
 func (c *groupByCursor) Cursor() cursors.Cursor {
 	cur := c.mb.createCursor(*c.rows[c.i-1])
 	if c.agg != nil {
-		cur = c.mb.newAggregateCursor(c.ctx, c.agg, cur)
+		cur, c.err = c.mb.newAggregateCursor(c.ctx, c.agg, cur)
 	}
 	return cur
 }
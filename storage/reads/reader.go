@@ -3,12 +3,16 @@ package reads
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
@@ -31,7 +35,7 @@ func NewReader(s Store) influxdb.Reader {
 	return &storeReader{s: s}
 }
 
-func (r *storeReader) Read(ctx context.Context, rs influxdb.ReadSpec, start, stop execute.Time) (influxdb.TableIterator, error) {
+func (r *storeReader) Read(ctx context.Context, rs influxdb.ReadSpec, start, stop execute.Time, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	var predicate *datatypes.Predicate
 	if rs.Predicate != nil {
 		p, err := toStoragePredicate(rs.Predicate)
@@ -47,6 +51,7 @@ func (r *storeReader) Read(ctx context.Context, rs influxdb.ReadSpec, start, sto
 		s:         r.s,
 		readSpec:  rs,
 		predicate: predicate,
+		alloc:     alloc,
 	}, nil
 }
 
@@ -59,6 +64,7 @@ type tableIterator struct {
 	readSpec  influxdb.ReadSpec
 	predicate *datatypes.Predicate
 	stats     cursors.CursorStats
+	alloc     *memory.Allocator
 }
 
 func (bi *tableIterator) Statistics() cursors.CursorStats { return bi.stats }
@@ -80,6 +86,14 @@ func (bi *tableIterator) Do(f func(flux.Table) error) error {
 	req.Descending = bi.readSpec.Descending
 	req.TimestampRange.Start = int64(bi.bounds.Start)
 	req.TimestampRange.End = int64(bi.bounds.Stop)
+
+	if bi.readSpec.TagKeys || bi.readSpec.TagValues {
+		return bi.handleTagSchemaRead(f, src, &req)
+	}
+	if bi.readSpec.MeasurementFields {
+		return bi.handleMeasurementFieldsRead(f, src, &req)
+	}
+
 	req.Group = convertGroupMode(bi.readSpec.GroupMode)
 	req.GroupKeys = bi.readSpec.GroupKeys
 	req.SeriesLimit = bi.readSpec.SeriesLimit
@@ -93,11 +107,19 @@ func (bi *tableIterator) Do(f func(flux.Table) error) error {
 	if agg, err := determineAggregateMethod(bi.readSpec.AggregateMethod); err != nil {
 		return err
 	} else if agg != datatypes.AggregateTypeNone {
-		req.Aggregate = &datatypes.Aggregate{Type: agg}
+		req.Aggregate = &datatypes.Aggregate{
+			Type:         agg,
+			WindowEvery:  int64(bi.readSpec.AggregateWindow.Every),
+			WindowOffset: int64(bi.readSpec.AggregateWindow.Offset),
+		}
 	}
 
 	switch {
 	case req.Group != datatypes.GroupAll:
+		if req.Aggregate != nil && req.Aggregate.WindowEvery > 0 {
+			return errors.New("windowed aggregates are not supported for grouped reads")
+		}
+
 		rs, err := bi.s.GroupRead(bi.ctx, &req)
 		if err != nil {
 			return err
@@ -125,6 +147,9 @@ func (bi *tableIterator) Do(f func(flux.Table) error) error {
 		if req.Hints.NoPoints() {
 			return bi.handleReadNoPoints(f, rs)
 		}
+		if req.Aggregate != nil && req.Aggregate.WindowEvery > 0 {
+			return bi.handleWindowAggregateRead(f, rs, req.Aggregate.Type)
+		}
 		return bi.handleRead(f, rs)
 	}
 }
@@ -201,6 +226,314 @@ READ:
 	return rs.Err()
 }
 
+// handleTagSchemaRead produces a single table with the tag keys, or the values
+// of the tag key, of the series in the _value column, as flux produces them
+// for the distinct keys or values of the tables of the series.
+func (bi *tableIterator) handleTagSchemaRead(f func(flux.Table) error, src proto.Message, req *datatypes.ReadRequest) error {
+	var (
+		itr cursors.StringIterator
+		err error
+	)
+	if bi.readSpec.TagKeys {
+		itr, err = bi.s.TagKeys(bi.ctx, src, req.TimestampRange, req.Predicate)
+	} else {
+		itr, err = bi.s.TagValues(bi.ctx, src, bi.readSpec.TagKey, req.TimestampRange, req.Predicate)
+	}
+	if err != nil {
+		return err
+	}
+
+	values := cursors.StringIteratorToSlice(itr)
+	if len(values) == 0 {
+		return nil
+	}
+	if bi.readSpec.TagKeys {
+		// The tables of the series have the bounds in their group key too.
+		values = append(values, execute.DefaultStartColLabel, execute.DefaultStopColLabel)
+		sort.Strings(values)
+	}
+
+	builder := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), bi.alloc)
+	if _, err := builder.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.TString}); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := builder.AppendString(0, v); err != nil {
+			return err
+		}
+	}
+	table, err := builder.Table()
+	if err != nil {
+		return err
+	}
+	return f(table)
+}
+
+// handleMeasurementFieldsRead produces a single table, grouped by the
+// measurement, with the keys of the fields of the series of the measurement
+// in the _value column and their types in the type column.
+func (bi *tableIterator) handleMeasurementFieldsRead(f func(flux.Table) error, src proto.Message, req *datatypes.ReadRequest) error {
+	itr, err := bi.s.MeasurementFields(bi.ctx, src, bi.readSpec.Measurement, req.TimestampRange, req.Predicate)
+	if err != nil {
+		return err
+	}
+
+	fields := cursors.MeasurementFieldIteratorToSlice(itr)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	key := execute.NewGroupKey(
+		[]flux.ColMeta{{Label: measurementKey, Type: flux.TString}},
+		[]values.Value{values.NewString(bi.readSpec.Measurement)},
+	)
+	builder := execute.NewColListTableBuilder(key, bi.alloc)
+	if err := execute.AddTableKeyCols(key, builder); err != nil {
+		return err
+	}
+	valueIdx, err := builder.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.TString})
+	if err != nil {
+		return err
+	}
+	typeIdx, err := builder.AddCol(flux.ColMeta{Label: "type", Type: flux.TString})
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if err := execute.AppendKeyValues(key, builder); err != nil {
+			return err
+		}
+		if err := builder.AppendString(valueIdx, field.Key); err != nil {
+			return err
+		}
+		if err := builder.AppendString(typeIdx, field.Type.String()); err != nil {
+			return err
+		}
+	}
+	table, err := builder.Table()
+	if err != nil {
+		return err
+	}
+	return f(table)
+}
+
+// handleWindowAggregateRead produces a table per window of each series, as
+// flux produces them when the aggregate follows a window. The cursors of rs
+// have a point per window with data.
+func (bi *tableIterator) handleWindowAggregateRead(f func(flux.Table) error, rs ResultSet, agg datatypes.Aggregate_AggregateType) error {
+	defer rs.Close()
+
+	w := bi.readSpec.AggregateWindow
+	var empty []execute.Bounds
+	if bi.readSpec.CreateEmpty {
+		empty = w.GetOverlappingBounds(bi.bounds)
+		for i := range empty {
+			empty[i] = bi.bounds.Intersect(empty[i])
+		}
+	}
+
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		wt := &windowAggregateTables{
+			bi:       bi,
+			agg:      agg,
+			tags:     rs.Tags(),
+			window:   w,
+			empty:    empty,
+			f:        f,
+			selector: isSelector(agg),
+		}
+		err := wt.read(cur)
+		stats := cur.Stats()
+		bi.stats.ScannedValues += stats.ScannedValues
+		bi.stats.ScannedBytes += stats.ScannedBytes
+		cur.Close()
+		if err != nil {
+			return err
+		}
+
+		if bi.ctx.Err() != nil {
+			break
+		}
+	}
+	return rs.Err()
+}
+
+// isSelector reports whether the aggregate selects a point of the window,
+// rather than computing a value from its points.
+func isSelector(agg datatypes.Aggregate_AggregateType) bool {
+	switch agg {
+	case datatypes.AggregateTypeMin, datatypes.AggregateTypeMax, datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+		return true
+	default:
+		return false
+	}
+}
+
+// windowAggregateTables produces the tables of the windows of a series.
+type windowAggregateTables struct {
+	bi       *tableIterator
+	agg      datatypes.Aggregate_AggregateType
+	tags     models.Tags
+	window   execute.Window
+	empty    []execute.Bounds
+	f        func(flux.Table) error
+	selector bool
+
+	// typ is the type of the aggregated values.
+	typ flux.ColType
+	// n is the number of windows produced so far.
+	n int
+}
+
+func (wt *windowAggregateTables) read(cur cursors.Cursor) error {
+	switch cur := cur.(type) {
+	case cursors.IntegerArrayCursor:
+		wt.typ = flux.TInt
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wt.produce(ts, values.NewInt(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.FloatArrayCursor:
+		wt.typ = flux.TFloat
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wt.produce(ts, values.NewFloat(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		wt.typ = flux.TUInt
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wt.produce(ts, values.NewUInt(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.BooleanArrayCursor:
+		wt.typ = flux.TBool
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wt.produce(ts, values.NewBool(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.StringArrayCursor:
+		wt.typ = flux.TString
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := wt.produce(ts, values.NewString(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+
+	if wt.n == 0 {
+		// The series has no data, so it has no empty windows either.
+		return nil
+	}
+	for _, bnds := range wt.empty {
+		if err := wt.emit(bnds, 0, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// produce produces the table of the window of the point at ts, after the
+// tables of the empty windows before it.
+func (wt *windowAggregateTables) produce(ts int64, v values.Value) error {
+	bnds := wt.bi.bounds.Intersect(wt.window.GetEarliestBounds(execute.Time(ts)))
+	for len(wt.empty) > 0 && wt.empty[0].Start <= bnds.Start {
+		if wt.empty[0].Start < bnds.Start {
+			if err := wt.emit(wt.empty[0], 0, nil); err != nil {
+				return err
+			}
+		}
+		wt.empty = wt.empty[1:]
+	}
+	return wt.emit(bnds, ts, v)
+}
+
+// emit passes the table of the window with the point at ts to f. A nil
+// value is an empty window.
+func (wt *windowAggregateTables) emit(bnds execute.Bounds, ts int64, v values.Value) error {
+	wt.n++
+	key := groupKeyForSeries(wt.tags, &wt.bi.readSpec, bnds)
+	builder := execute.NewColListTableBuilder(key, wt.bi.alloc)
+
+	if wt.selector {
+		cols, _ := determineTableColsForSeries(wt.tags, wt.typ)
+		for _, c := range cols {
+			if _, err := builder.AddCol(c); err != nil {
+				return err
+			}
+		}
+		if v != nil {
+			for j, c := range cols {
+				var cv values.Value
+				switch j {
+				case startColIdx:
+					cv = values.NewTime(bnds.Start)
+				case stopColIdx:
+					cv = values.NewTime(bnds.Stop)
+				case timeColIdx:
+					cv = values.NewTime(values.Time(ts))
+				case valueColIdx:
+					cv = v
+				default:
+					cv = key.LabelValue(c.Label)
+				}
+				if err := builder.AppendValue(j, cv); err != nil {
+					return err
+				}
+			}
+		}
+	} else {
+		if err := execute.AddTableKeyCols(key, builder); err != nil {
+			return err
+		}
+		j, err := builder.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: wt.typ})
+		if err != nil {
+			return err
+		}
+		switch {
+		case v != nil:
+			err = builder.AppendValue(j, v)
+		case wt.agg == datatypes.AggregateTypeCount:
+			err = builder.AppendInt(j, 0)
+		default:
+			err = builder.AppendNil(j)
+		}
+		if err != nil {
+			return err
+		}
+		if err := execute.AppendKeyValues(key, builder); err != nil {
+			return err
+		}
+	}
+
+	table, err := builder.Table()
+	if err != nil {
+		return err
+	}
+	return wt.f(table)
+}
+
 func (bi *tableIterator) handleReadNoPoints(f func(flux.Table) error, rs ResultSet) error {
 	// these resources must be closed if not nil on return
 	var table storageTable
@@ -273,6 +606,9 @@ READ:
 				break
 			}
 		}
+		if err := gc.Err(); err != nil {
+			return err
+		}
 
 		if cur == nil {
 			gc.Close()
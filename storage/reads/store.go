@@ -78,4 +78,17 @@ type Store interface {
 	Read(ctx context.Context, req *datatypes.ReadRequest) (ResultSet, error)
 	GroupRead(ctx context.Context, req *datatypes.ReadRequest) (GroupResultSet, error)
 	GetSource(rs influxdb.ReadSpec) (proto.Message, error)
+
+	// TagKeys returns the sorted tag keys of the series of the source that
+	// match the predicate and have data within the time range.
+	TagKeys(ctx context.Context, src proto.Message, rng datatypes.TimestampRange, predicate *datatypes.Predicate) (cursors.StringIterator, error)
+
+	// TagValues returns the sorted values of the tag key of the series of the
+	// source that match the predicate and have data within the time range.
+	TagValues(ctx context.Context, src proto.Message, key string, rng datatypes.TimestampRange, predicate *datatypes.Predicate) (cursors.StringIterator, error)
+
+	// MeasurementFields returns the keys and types, sorted by the keys, of the
+	// fields of the series of the measurement of the source that match the
+	// predicate and have data within the time range.
+	MeasurementFields(ctx context.Context, src proto.Message, measurement string, rng datatypes.TimestampRange, predicate *datatypes.Predicate) (cursors.MeasurementFieldIterator, error)
 }
@@ -3,15 +3,21 @@ package readservice
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
+	"sort"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
 )
 
 type store struct {
@@ -22,6 +28,11 @@ func newStore(engine *storage.Engine) *store {
 	return &store{engine: engine}
 }
 
+// NewStore returns a reads.Store that reads series from engine.
+func NewStore(engine *storage.Engine) reads.Store {
+	return newStore(engine)
+}
+
 func (s *store) Read(ctx context.Context, req *datatypes.ReadRequest) (reads.ResultSet, error) {
 	if len(req.GroupKeys) > 0 {
 		panic("Read: len(Grouping) > 0")
@@ -120,6 +131,105 @@ func (s *store) GetSource(rs influxdb.ReadSpec) (proto.Message, error) {
 	}, nil
 }
 
+func (s *store) TagKeys(ctx context.Context, src proto.Message, rng datatypes.TimestampRange, predicate *datatypes.Predicate) (cursors.StringIterator, error) {
+	source, ok := src.(*readSource)
+	if !ok {
+		return nil, fmt.Errorf("unexpected read source %T", src)
+	}
+
+	cond, err := tagsCondition(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := timestampRange(rng)
+	itr, err := s.engine.TagKeys(ctx, platform.ID(source.OrganizationID), platform.ID(source.BucketID), start, end, cond)
+	if err != nil {
+		return nil, err
+	}
+
+	// The measurement and field keys sort differently once they are renamed.
+	keys := cursors.StringIteratorToSlice(itr)
+	for i, key := range keys {
+		switch key {
+		case tsdb.MeasurementTagKey:
+			keys[i] = measurementKey
+		case tsdb.FieldKeyTagKey:
+			keys[i] = fieldKey
+		}
+	}
+	sort.Strings(keys)
+	return cursors.NewStringSliceIterator(keys), nil
+}
+
+func (s *store) TagValues(ctx context.Context, src proto.Message, key string, rng datatypes.TimestampRange, predicate *datatypes.Predicate) (cursors.StringIterator, error) {
+	source, ok := src.(*readSource)
+	if !ok {
+		return nil, fmt.Errorf("unexpected read source %T", src)
+	}
+
+	cond, err := tagsCondition(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key {
+	case measurementKey:
+		key = tsdb.MeasurementTagKey
+	case fieldKey:
+		key = tsdb.FieldKeyTagKey
+	}
+
+	start, end := timestampRange(rng)
+	return s.engine.TagValues(ctx, platform.ID(source.OrganizationID), platform.ID(source.BucketID), key, start, end, cond)
+}
+
+func (s *store) MeasurementFields(ctx context.Context, src proto.Message, measurement string, rng datatypes.TimestampRange, predicate *datatypes.Predicate) (cursors.MeasurementFieldIterator, error) {
+	source, ok := src.(*readSource)
+	if !ok {
+		return nil, fmt.Errorf("unexpected read source %T", src)
+	}
+
+	cond, err := tagsCondition(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := timestampRange(rng)
+	return s.engine.MeasurementFields(ctx, platform.ID(source.OrganizationID), platform.ID(source.BucketID), measurement, start, end, cond)
+}
+
+// tagsCondition returns the condition of the predicate, which may only refer
+// to the tags of the series.
+func tagsCondition(predicate *datatypes.Predicate) (influxql.Expr, error) {
+	root := predicate.GetRoot()
+	if root == nil {
+		return nil, nil
+	}
+
+	cond, err := reads.NodeToExpr(root, nil)
+	if err != nil {
+		return nil, err
+	}
+	if reads.HasFieldValueKey(cond) {
+		return nil, errors.New("field values are not supported in schema predicates")
+	}
+	return cond, nil
+}
+
+// timestampRange returns the start and end of the range, which are unbounded
+// when they are not set.
+func timestampRange(rng datatypes.TimestampRange) (start, end int64) {
+	start, end = rng.Start, rng.End
+	if start == 0 {
+		start = models.MinNanoTime
+	}
+	if end == 0 {
+		end = models.MaxNanoTime
+	}
+	return start, end
+}
+
 func getReadSource(req *datatypes.ReadRequest) (*readSource, error) {
 	if req.ReadSource == nil {
 		return nil, errors.New("missing read source")
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+var _ platform.UsageService = (*Engine)(nil)
+
+// GetUsage returns the current series cardinality of the engine and its
+// limits. With a bucket ID the usage is of that bucket, with only an
+// organization ID it is of all of the buckets of the organization. The range
+// of the filter is ignored: cardinality is always the current one. Limits that
+// are not set are omitted.
+func (e *Engine) GetUsage(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	var (
+		series, limit int
+		names         [][]byte
+	)
+	for name, n := range e.index.MeasurementCardinalityStats() {
+		org, bucket, ok := decodeName([]byte(name))
+		if !ok {
+			continue
+		}
+		if filter.OrgID != nil && *filter.OrgID != org {
+			continue
+		}
+		if filter.BucketID != nil && *filter.BucketID != bucket {
+			continue
+		}
+		series += n
+		names = append(names, []byte(name))
+	}
+
+	switch {
+	case filter.BucketID != nil:
+		limit = e.limits.maxSeriesPerBucket
+	case filter.OrgID != nil:
+		limit = e.limits.maxSeriesPerOrg
+	}
+
+	var values int
+	for _, name := range names {
+		n, err := maxTagValues(e.index, name)
+		if err != nil {
+			return nil, err
+		}
+		if n > values {
+			values = n
+		}
+	}
+
+	usage := func(typ platform.UsageMetric, v int) *platform.Usage {
+		return &platform.Usage{
+			OrganizationID: filter.OrgID,
+			BucketID:       filter.BucketID,
+			Type:           typ,
+			Value:          float64(v),
+		}
+	}
+
+	m := map[platform.UsageMetric]*platform.Usage{
+		platform.UsageSeriesCardinality: usage(platform.UsageSeriesCardinality, series),
+		platform.UsageTagValues:         usage(platform.UsageTagValues, values),
+	}
+	if limit > 0 {
+		m[platform.UsageSeriesCardinalityLimit] = usage(platform.UsageSeriesCardinalityLimit, limit)
+	}
+	if e.limits.maxValuesPerTag > 0 {
+		m[platform.UsageTagValuesLimit] = usage(platform.UsageTagValuesLimit, e.limits.maxValuesPerTag)
+	}
+	return m, nil
+}
+
+// recordUsage records the number of values and series written to each bucket
+// by the collection.
+func (e *Engine) recordUsage(collection *tsdb.SeriesCollection) {
+	if e.usage == nil {
+		return
+	}
+
+	type counts struct {
+		values, series int
+	}
+	written := make(map[string]*counts)
+	keys := make(map[string]struct{}, collection.Length())
+	for iter := collection.Iterator(); iter.Next(); {
+		c, ok := written[string(iter.Name())]
+		if !ok {
+			c = &counts{}
+			written[string(iter.Name())] = c
+		}
+
+		// Every point of the collection is a single value.
+		c.values++
+		if _, ok := keys[string(iter.Key())]; !ok {
+			keys[string(iter.Key())] = struct{}{}
+			c.series++
+		}
+	}
+
+	for name, c := range written {
+		org, bucket, ok := decodeName([]byte(name))
+		if !ok {
+			continue
+		}
+		e.usage.RecordUsage(org, bucket, platform.UsageValues, float64(c.values))
+		e.usage.RecordUsage(org, bucket, platform.UsageSeries, float64(c.series))
+	}
+}
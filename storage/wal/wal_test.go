@@ -469,6 +469,30 @@ func TestDeleteBucketRangeWALEntry_UnmarshalBinary(t *testing.T) {
 	}
 }
 
+func TestDeleteBucketRangeWALEntry_UnmarshalBinary_Predicate(t *testing.T) {
+	in := &DeleteBucketRangeWALEntry{
+		OrgID:     influxdb.ID(1),
+		BucketID:  influxdb.ID(2),
+		Min:       3,
+		Max:       4,
+		Predicate: []byte(`_m = 'cpu' AND host = 'a'`),
+	}
+
+	b, err := in.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	out := &DeleteBucketRangeWALEntry{}
+	if err := out.UnmarshalBinary(b); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("got %+v, expected %+v", out, in)
+	}
+}
+
 func TestWriteWALSegment_UnmarshalBinary_DeleteBucketRangeWALCorrupt(t *testing.T) {
 	w := &DeleteBucketRangeWALEntry{
 		OrgID:    influxdb.ID(1),
@@ -48,6 +48,7 @@ type Run struct {
 	StartedAt    string `json:"startedAt,omitempty"`
 	FinishedAt   string `json:"finishedAt,omitempty"`
 	RequestedAt  string `json:"requestedAt,omitempty"`
+	Try          int    `json:"try,omitempty"`
 	Log          Log    `json:"log"`
 }
 
@@ -329,6 +330,10 @@ type RunFilter struct {
 	Limit      int
 	AfterTime  string
 	BeforeTime string
+
+	// The optional Status limits runs to those with the status, such as
+	// "success" or "failed".
+	Status string
 }
 
 // LogFilter represents a set of filters that restrict the returned log results.
@@ -338,4 +343,13 @@ type LogFilter struct {
 
 	// The optional Run ID limits logs to a single run.
 	Run *ID
+
+	// After and Limit page through the logs of a task by run ID.
+	After *ID
+	Limit int
+
+	// The optional AfterTime and BeforeTime, in RFC3339, limit logs to
+	// the lines written between them.
+	AfterTime  string
+	BeforeTime string
 }
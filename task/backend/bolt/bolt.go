@@ -9,6 +9,8 @@
 //    bucket(/tasks/v1/name_by_task_id) key(:task_id) -> The user-supplied name of the script.
 //    bucket(/tasks/v1/run_ids) -> Counter for run IDs
 //    bucket(/tasks/v1/orgs).bucket(:org_id) key(:task_id) -> Empty content; presence of :task_id allows for lookup from org to tasks.
+//    bucket(/tasks/v1/task_leases) key(:task_id) -> The owner ID holding the task's lease, followed by its big-endian expiry Unix timestamp.
+//    bucket(/tasks/v1/owners) key(:owner_id) -> Big-endian Unix timestamp of when the owner's heartbeat expires.
 // Note that task IDs are stored big-endian uint64s for sorting purposes,
 // but presented to the users with leading 0-bytes stripped.
 // Like other components of the system, IDs presented to users may be `0f12` rather than `f12`.
@@ -16,6 +18,7 @@ package bolt
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
@@ -60,6 +63,8 @@ var (
 	orgByTaskID  = []byte(basePath + "org_by_task_id")
 	nameByTaskID = []byte(basePath + "name_by_task_id")
 	runIDs       = []byte(basePath + "run_ids")
+	taskLeases   = []byte(basePath + "task_leases")
+	owners       = []byte(basePath + "owners")
 )
 
 // Option is a optional configuration for the store.
@@ -85,6 +90,7 @@ func New(db *bolt.DB, rootBucket string, opts ...Option) (*Store, error) {
 		for _, b := range [][]byte{
 			tasksPath, orgsPath, taskMetaPath,
 			orgByTaskID, nameByTaskID, runIDs,
+			taskLeases, owners,
 		} {
 			_, err := root.CreateBucketIfNotExists(b)
 			if err != nil {
@@ -530,6 +536,10 @@ func (s *Store) DeleteTask(ctx context.Context, id platform.ID) (deleted bool, e
 			return err
 		}
 
+		if err := b.Bucket(taskLeases).Delete(encodedID); err != nil {
+			return err
+		}
+
 		org := b.Bucket(orgByTaskID).Get(encodedID)
 		if len(org) > 0 {
 			if err := b.Bucket(orgsPath).Bucket(org).Delete(encodedID); err != nil {
@@ -620,6 +630,45 @@ func (s *Store) FinishRun(ctx context.Context, taskID, runID platform.ID) error
 	})
 }
 
+// IncrementRunTry increments the try count of runID, which must be currently running.
+func (s *Store) IncrementRunTry(ctx context.Context, taskID, runID platform.ID) (uint32, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return 0, err
+	}
+
+	var try uint32
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		stmBytes := b.Bucket(taskMetaPath).Get(encodedID)
+		if stmBytes == nil {
+			return backend.ErrTaskNotFound
+		}
+
+		var stm backend.StoreTaskMeta
+		if err := stm.Unmarshal(stmBytes); err != nil {
+			return err
+		}
+
+		var ok bool
+		try, ok = stm.IncrementRunTry(runID)
+		if !ok {
+			return ErrRunNotFound
+		}
+
+		stmBytes, err := stm.Marshal()
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(s.bucket).Bucket(taskMetaPath).Put(encodedID, stmBytes)
+	}); err != nil {
+		return 0, err
+	}
+
+	return try, nil
+}
+
 func (s *Store) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, start, end, requestedAt int64) (*backend.StoreTaskMetaManualRun, error) {
 	encodedID, err := taskID.Encode()
 	if err != nil {
@@ -694,6 +743,9 @@ func (s *Store) DeleteOrg(ctx context.Context, id platform.ID) error {
 			if err := b.Bucket(nameByTaskID).Delete(k); err != nil {
 				return err
 			}
+			if err := b.Bucket(taskLeases).Delete(k); err != nil {
+				return err
+			}
 		}
 		// check for cancelation one last time before we return
 		select {
@@ -704,3 +756,174 @@ func (s *Store) DeleteOrg(ctx context.Context, id platform.ID) error {
 		}
 	})
 }
+
+// Heartbeat records that owner is alive until expires, and forgets the owners whose heartbeat expired.
+// A heartbeat expiring no later than now removes the owner.
+func (s *Store) Heartbeat(ctx context.Context, owner platform.ID, now, expires int64) error {
+	encodedOwner, err := owner.Encode()
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		ob := tx.Bucket(s.bucket).Bucket(owners)
+
+		var expired [][]byte
+		c := ob.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if decodeUnix(v) <= now {
+				expired = append(expired, k)
+			}
+		}
+		for _, k := range expired {
+			if err := ob.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		if expires <= now {
+			return ob.Delete(encodedOwner)
+		}
+		return ob.Put(encodedOwner, encodeUnix(expires))
+	})
+}
+
+// ListOwners returns the owners whose heartbeat has not expired at now.
+func (s *Store) ListOwners(ctx context.Context, now int64) ([]platform.ID, error) {
+	var ids []platform.ID
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(s.bucket).Bucket(owners).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if decodeUnix(v) <= now {
+				continue
+			}
+			var id platform.ID
+			if err := id.Decode(k); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// AcquireTaskLease grants owner the lease of the task, unless another owner holds a lease that has not expired.
+func (s *Store) AcquireTaskLease(ctx context.Context, taskID, owner platform.ID, now, expires int64) (backend.TaskLease, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return backend.TaskLease{}, err
+	}
+
+	l := backend.TaskLease{TaskID: taskID, Owner: owner, Expires: expires}
+	v, err := encodeLease(l)
+	if err != nil {
+		return backend.TaskLease{}, err
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b.Bucket(tasksPath).Get(encodedID) == nil {
+			return backend.ErrTaskNotFound
+		}
+
+		if cur := b.Bucket(taskLeases).Get(encodedID); cur != nil {
+			held, err := decodeLease(taskID, cur)
+			if err != nil {
+				return err
+			}
+			if held.Owner != owner && !held.ExpiredAt(now) {
+				return backend.ErrTaskLeased
+			}
+		}
+
+		return b.Bucket(taskLeases).Put(encodedID, v)
+	})
+	if err != nil {
+		return backend.TaskLease{}, err
+	}
+	return l, nil
+}
+
+// ReleaseTaskLease releases the lease of the task if owner holds it.
+func (s *Store) ReleaseTaskLease(ctx context.Context, taskID, owner platform.ID) error {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		lb := tx.Bucket(s.bucket).Bucket(taskLeases)
+		cur := lb.Get(encodedID)
+		if cur == nil {
+			return nil
+		}
+		held, err := decodeLease(taskID, cur)
+		if err != nil {
+			return err
+		}
+		if held.Owner != owner {
+			return nil
+		}
+		return lb.Delete(encodedID)
+	})
+}
+
+// ListTaskLeases returns the leases of all tasks, including expired ones.
+func (s *Store) ListTaskLeases(ctx context.Context) ([]backend.TaskLease, error) {
+	var leases []backend.TaskLease
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(s.bucket).Bucket(taskLeases).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var taskID platform.ID
+			if err := taskID.Decode(k); err != nil {
+				return err
+			}
+			l, err := decodeLease(taskID, v)
+			if err != nil {
+				return err
+			}
+			leases = append(leases, l)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+func encodeLease(l backend.TaskLease) ([]byte, error) {
+	encodedOwner, err := l.Owner.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return append(encodedOwner, encodeUnix(l.Expires)...), nil
+}
+
+func decodeLease(taskID platform.ID, v []byte) (backend.TaskLease, error) {
+	if len(v) != platform.IDLength+8 {
+		return backend.TaskLease{}, fmt.Errorf("invalid lease stored for task %s", taskID)
+	}
+	l := backend.TaskLease{TaskID: taskID, Expires: decodeUnix(v[platform.IDLength:])}
+	if err := l.Owner.Decode(v[:platform.IDLength]); err != nil {
+		return backend.TaskLease{}, err
+	}
+	return l, nil
+}
+
+func encodeUnix(t int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(t))
+	return b
+}
+
+func decodeUnix(b []byte) int64 {
+	if len(b) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
@@ -3,12 +3,29 @@ package coordinator
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/task/backend"
 	"go.uber.org/zap"
 )
 
+// ownerIDs generates the default owner IDs, so that coordinators in the same process never share one.
+var ownerIDs = snowflake.NewIDGenerator()
+
+// DefaultLeaseTTL is how long a task lease lasts without being renewed, unless set with WithLeaseTTL.
+const DefaultLeaseTTL = 30 * time.Second
+
+// Coordinator keeps a scheduler in sync with the tasks in a store.
+//
+// Several coordinators, typically one per process, may share a store.
+// Each coordinator holds leases on the tasks its scheduler executes,
+// and renews them along with its own heartbeat.
+// Active tasks are spread evenly across the owners with a live heartbeat,
+// and the tasks of an owner that stops renewing its leases are taken over once the leases expire.
 type Coordinator struct {
 	backend.Store
 
@@ -16,6 +33,24 @@ type Coordinator struct {
 	sch    backend.Scheduler
 
 	limit int
+
+	owner    platform.ID
+	leaseTTL time.Duration
+
+	mu sync.Mutex // Protects claimed and serializes changes to leases.
+
+	// claimed holds the tasks whose lease is held by this coordinator and which are claimed in the scheduler.
+	claimed map[platform.ID]claim
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// claim is the state of a task when it was last handed to the scheduler,
+// so that changes made through another coordinator are noticed.
+type claim struct {
+	script    string
+	updatedAt int64
 }
 
 type Option func(*Coordinator)
@@ -26,50 +61,267 @@ func WithLimit(i int) Option {
 	}
 }
 
+// WithOwnerID sets the ID under which the coordinator holds task leases.
+// It must be unique among the coordinators sharing a store.
+// If not set, a random ID is generated.
+func WithOwnerID(id platform.ID) Option {
+	return func(c *Coordinator) {
+		c.owner = id
+	}
+}
+
+// WithLeaseTTL sets how long task leases and the coordinator's heartbeat last without being renewed.
+// They are renewed three times per TTL. Lease expiry is rounded up to whole seconds.
+// If not set, the coordinator uses DefaultLeaseTTL.
+func WithLeaseTTL(d time.Duration) Option {
+	return func(c *Coordinator) {
+		c.leaseTTL = d
+	}
+}
+
 func New(logger *zap.Logger, scheduler backend.Scheduler, st backend.Store, opts ...Option) *Coordinator {
 	c := &Coordinator{
-		logger: logger,
-		sch:    scheduler,
-		Store:  st,
-		limit:  1000,
+		logger:   logger,
+		sch:      scheduler,
+		Store:    st,
+		limit:    1000,
+		owner:    ownerIDs.ID(),
+		leaseTTL: DefaultLeaseTTL,
+		claimed:  make(map[platform.ID]claim),
+		done:     make(chan struct{}),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	go c.claimExistingTasks()
+	var ctx context.Context
+	ctx, c.cancel = context.WithCancel(context.Background())
+	go c.run(ctx)
 
 	return c
 }
 
-// claimExistingTasks is called on startup to claim all tasks in the store.
-func (c *Coordinator) claimExistingTasks() {
-	tasks, err := c.Store.ListTasks(context.Background(), backend.TaskSearchParams{})
+// Owner returns the ID under which the coordinator holds task leases.
+func (c *Coordinator) Owner() platform.ID {
+	return c.owner
+}
+
+// Stop stops renewing leases, and releases every task held by the coordinator
+// so that other coordinators can take them over without waiting for the leases to expire.
+func (c *Coordinator) Stop() {
+	c.cancel()
+	<-c.done
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx := context.Background()
+	for id := range c.claimed {
+		if err := c.release(ctx, id); err != nil {
+			c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+		}
+	}
+
+	now := time.Now().Unix()
+	if err := c.Store.Heartbeat(ctx, c.owner, now, now); err != nil {
+		c.logger.Error("failed to remove heartbeat", zap.Error(err))
+	}
+}
+
+// run balances the tasks on startup, and again every third of the lease TTL until the coordinator is stopped.
+func (c *Coordinator) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.leaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		c.balance(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// leaseExpiry returns the Unix timestamp at which a lease acquired at now expires.
+// It is rounded up, so that the lease lasts at least the full TTL.
+func (c *Coordinator) leaseExpiry(now time.Time) int64 {
+	t := now.Add(c.leaseTTL)
+	if t.Nanosecond() > 0 {
+		return t.Unix() + 1
+	}
+	return t.Unix()
+}
+
+// balance records the coordinator's heartbeat, renews the leases it holds,
+// and claims or releases tasks so that it holds its fair share of the active tasks.
+func (c *Coordinator) balance(ctx context.Context, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now, expires := at.Unix(), c.leaseExpiry(at)
+	if err := c.Store.Heartbeat(ctx, c.owner, now, expires); err != nil {
+		c.logger.Error("failed to record heartbeat", zap.Error(err))
+		return
+	}
+
+	owners, err := c.Store.ListOwners(ctx, now)
+	if err != nil {
+		c.logger.Error("failed to list owners", zap.Error(err))
+		return
+	}
+	if len(owners) == 0 {
+		// Our own heartbeat was just recorded, but count ourselves regardless.
+		owners = []platform.ID{c.owner}
+	}
+
+	tasks, err := c.listActiveTasks(ctx)
 	if err != nil {
 		c.logger.Error("failed to list tasks", zap.Error(err))
 		return
 	}
+	active := make(map[platform.ID]*backend.StoreTaskWithMeta, len(tasks))
+	for i := range tasks {
+		active[tasks[i].Task.ID] = &tasks[i]
+	}
 
-	for len(tasks) > 0 {
-		for _, task := range tasks {
-			t := task // Copy to avoid mistaken closure around task value.
-			if err := c.sch.ClaimTask(&t.Task, &t.Meta); err != nil {
-				c.logger.Error("failed claim task", zap.Error(err))
+	list, err := c.Store.ListTaskLeases(ctx)
+	if err != nil {
+		c.logger.Error("failed to list task leases", zap.Error(err))
+		return
+	}
+	leases := make(map[platform.ID]backend.TaskLease, len(list))
+	for _, l := range list {
+		leases[l.TaskID] = l
+	}
+
+	// Renew the leases we hold, and let go of the tasks that were deleted, disabled or taken over.
+	for id, cl := range c.claimed {
+		t, ok := active[id]
+		if !ok {
+			if err := c.release(ctx, id); err != nil {
+				c.logger.Error("failed to release inactive task", zap.String("task_id", id.String()), zap.Error(err))
+			}
+			continue
+		}
+
+		if _, err := c.Store.AcquireTaskLease(ctx, id, c.owner, now, expires); err != nil {
+			if err != backend.ErrTaskLeased {
+				c.logger.Error("failed to renew task lease", zap.String("task_id", id.String()), zap.Error(err))
+			}
+			// The lease expired and another owner took it over; stop executing the task here.
+			if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+				c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+			}
+			delete(c.claimed, id)
+			continue
+		}
+
+		if cl.script != t.Task.Script || cl.updatedAt != t.Meta.UpdatedAt {
+			if err := c.sch.UpdateTask(&t.Task, &t.Meta); err != nil && err != backend.ErrTaskNotClaimed {
+				c.logger.Error("failed to update task", zap.String("task_id", id.String()), zap.Error(err))
 				continue
 			}
+			c.claimed[id] = claim{script: t.Task.Script, updatedAt: t.Meta.UpdatedAt}
+		}
+	}
+
+	share := (len(tasks) + len(owners) - 1) / len(owners)
+
+	// Hand back the tasks beyond our share, newest first, for the other owners to pick up.
+	if excess := len(c.claimed) - share; excess > 0 {
+		ids := make([]platform.ID, 0, len(c.claimed))
+		for id := range c.claimed {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+		for _, id := range ids[:excess] {
+			if err := c.release(ctx, id); err != nil {
+				c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+			}
+		}
+	}
+
+	// Claim tasks that nobody holds a live lease on, until we reach our share.
+	for i := range tasks {
+		if len(c.claimed) >= share {
+			break
+		}
+
+		task := &tasks[i]
+		if _, ok := c.claimed[task.Task.ID]; ok {
+			continue
+		}
+		if l, ok := leases[task.Task.ID]; ok && l.Owner != c.owner && !l.ExpiredAt(now) {
+			continue
+		}
+
+		if err := c.claim(ctx, &task.Task, &task.Meta, at); err != nil && err != backend.ErrTaskLeased {
+			c.logger.Error("failed to claim task", zap.String("task_id", task.Task.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// listActiveTasks returns all the active tasks in the store.
+func (c *Coordinator) listActiveTasks(ctx context.Context) ([]backend.StoreTaskWithMeta, error) {
+	var active []backend.StoreTaskWithMeta
+
+	tasks, err := c.Store.ListTasks(ctx, backend.TaskSearchParams{})
+	for len(tasks) > 0 {
+		for _, t := range tasks {
+			if t.Meta.Status == string(backend.TaskActive) {
+				active = append(active, t)
+			}
 		}
-		tasks, err = c.Store.ListTasks(context.Background(), backend.TaskSearchParams{
+		tasks, err = c.Store.ListTasks(ctx, backend.TaskSearchParams{
 			After: tasks[len(tasks)-1].Task.ID,
 		})
-		if err != nil {
-			c.logger.Error("failed list additional tasks", zap.Error(err))
-			return
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return active, nil
+}
+
+// claim acquires the lease of the task and claims it in the scheduler.
+// c.mu must be held.
+func (c *Coordinator) claim(ctx context.Context, task *backend.StoreTask, meta *backend.StoreTaskMeta, now time.Time) error {
+	if _, err := c.Store.AcquireTaskLease(ctx, task.ID, c.owner, now.Unix(), c.leaseExpiry(now)); err != nil {
+		return err
+	}
+
+	if err := c.sch.ClaimTask(task, meta); err != nil && err != backend.ErrTaskAlreadyClaimed {
+		if relErr := c.Store.ReleaseTaskLease(ctx, task.ID, c.owner); relErr != nil {
+			return fmt.Errorf("claim task failed: %s\n\trelease lease also failed: %s", err, relErr)
 		}
+		return err
+	}
+
+	c.claimed[task.ID] = claim{script: task.Script, updatedAt: meta.UpdatedAt}
+	return nil
+}
+
+// release releases the task in the scheduler and gives up its lease.
+// c.mu must be held.
+func (c *Coordinator) release(ctx context.Context, id platform.ID) error {
+	delete(c.claimed, id)
+
+	if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+		return err
 	}
+
+	return c.Store.ReleaseTaskLease(ctx, id, c.owner)
 }
 
 func (c *Coordinator) CreateTask(ctx context.Context, req backend.CreateTaskRequest) (platform.ID, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	id, err := c.Store.CreateTask(ctx, req)
 	if err != nil {
 		return id, err
@@ -80,7 +332,13 @@ func (c *Coordinator) CreateTask(ctx context.Context, req backend.CreateTaskRequ
 		return id, err
 	}
 
-	if err := c.sch.ClaimTask(task, meta); err != nil {
+	if meta.Status != string(backend.TaskActive) {
+		return id, nil
+	}
+
+	// The new task may take this coordinator past its share;
+	// the next balance hands the excess to the other owners.
+	if err := c.claim(ctx, task, meta, time.Now()); err != nil {
 		_, delErr := c.Store.DeleteTask(ctx, id)
 		if delErr != nil {
 			return id, fmt.Errorf("schedule task failed: %s\n\tcleanup also failed: %s", err, delErr)
@@ -92,6 +350,9 @@ func (c *Coordinator) CreateTask(ctx context.Context, req backend.CreateTaskRequ
 }
 
 func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (backend.UpdateTaskResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	res, err := c.Store.UpdateTask(ctx, req)
 	if err != nil {
 		return res, err
@@ -104,19 +365,26 @@ func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequ
 
 	// If disabling the task, do so before modifying the script.
 	if req.Status == backend.TaskInactive && res.OldStatus != backend.TaskInactive {
-		if err := c.sch.ReleaseTask(req.ID); err != nil && err != backend.ErrTaskNotClaimed {
+		if err := c.release(ctx, req.ID); err != nil {
 			return res, err
 		}
 	}
 
-	if err := c.sch.UpdateTask(task, meta); err != nil && err != backend.ErrTaskNotClaimed {
-		return res, err
+	// Only the coordinator holding the lease updates its scheduler;
+	// the others pick up the change when they next balance.
+	if _, ok := c.claimed[req.ID]; ok {
+		if err := c.sch.UpdateTask(task, meta); err != nil && err != backend.ErrTaskNotClaimed {
+			return res, err
+		}
+		c.claimed[req.ID] = claim{script: task.Script, updatedAt: meta.UpdatedAt}
 	}
 
 	// If enabling the task, claim it after modifying the script.
 	if req.Status == backend.TaskActive {
-		if err := c.sch.ClaimTask(task, meta); err != nil && err != backend.ErrTaskAlreadyClaimed {
-			return res, err
+		if _, ok := c.claimed[req.ID]; !ok {
+			if err := c.claim(ctx, task, meta, time.Now()); err != nil && err != backend.ErrTaskLeased {
+				return res, err
+			}
 		}
 	}
 
@@ -124,7 +392,10 @@ func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequ
 }
 
 func (c *Coordinator) DeleteTask(ctx context.Context, id platform.ID) (deleted bool, err error) {
-	if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.release(ctx, id); err != nil {
 		return false, err
 	}
 
@@ -132,6 +403,9 @@ func (c *Coordinator) DeleteTask(ctx context.Context, id platform.ID) (deleted b
 }
 
 func (c *Coordinator) DeleteOrg(ctx context.Context, orgID platform.ID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	orgTasks, err := c.Store.ListTasks(ctx, backend.TaskSearchParams{
 		Org: orgID,
 	})
@@ -140,7 +414,7 @@ func (c *Coordinator) DeleteOrg(ctx context.Context, orgID platform.ID) error {
 	}
 
 	for _, orgTask := range orgTasks {
-		if err := c.sch.ReleaseTask(orgTask.Task.ID); err != nil {
+		if err := c.release(ctx, orgTask.Task.ID); err != nil {
 			return err
 		}
 	}
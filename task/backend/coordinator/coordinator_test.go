@@ -3,12 +3,18 @@ package coordinator_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
+	bolt "github.com/coreos/bbolt"
+	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/influxdata/influxdb/task/backend"
+	boltstore "github.com/influxdata/influxdb/task/backend/bolt"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
 	"github.com/influxdata/influxdb/task/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
@@ -31,6 +37,7 @@ func TestCoordinator(t *testing.T) {
 	sched := mock.NewScheduler()
 
 	coord := coordinator.New(zaptest.NewLogger(t), sched, st)
+	defer coord.Stop()
 	createChan := sched.TaskCreateChan()
 	releaseChan := sched.TaskReleaseChan()
 	updateChan := sched.TaskUpdateChan()
@@ -139,6 +146,7 @@ func TestCoordinator_DeleteUnclaimedTask(t *testing.T) {
 	sched := mock.NewScheduler()
 
 	coord := coordinator.New(zaptest.NewLogger(t), sched, st)
+	defer coord.Stop()
 
 	// Create an isolated task directly through the store so the coordinator doesn't know about it.
 	id, err := st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script})
@@ -177,7 +185,8 @@ func TestCoordinator_ClaimExistingTasks(t *testing.T) {
 		createdIDs[i] = id
 	}
 
-	coordinator.New(zaptest.NewLogger(t), sched, st)
+	coord := coordinator.New(zaptest.NewLogger(t), sched, st)
+	defer coord.Stop()
 
 	for i := 0; i < numTasks; i++ {
 		_, err := timeoutSelector(createChan)
@@ -192,3 +201,192 @@ func TestCoordinator_ClaimExistingTasks(t *testing.T) {
 		}
 	}
 }
+
+func newBoltStore(t *testing.T) (*boltstore.Store, func()) {
+	f, err := ioutil.TempFile("", "influx_bolt_task_coordinator_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	db, err := bolt.Open(f.Name(), os.ModeTemporary, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := boltstore.New(db, "testbucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return st, func() {
+		if err := st.Close(); err != nil {
+			t.Error(err)
+		}
+		os.Remove(f.Name())
+	}
+}
+
+func newTickScheduler(t *testing.T, st backend.Store) *backend.TickScheduler {
+	sch := backend.NewScheduler(st, mock.NewExecutor(), backend.NopLogWriter{}, time.Now().Unix(), backend.WithLogger(zaptest.NewLogger(t)))
+	sch.Start(context.Background())
+	return sch
+}
+
+// claimCounts returns how many of the tasks each scheduler has claimed,
+// and an error if any task is claimed by more or less than one scheduler.
+func claimCounts(ids []platform.ID, schs ...*backend.TickScheduler) ([]int, error) {
+	counts := make([]int, len(schs))
+	for _, id := range ids {
+		owners := 0
+		for i, sch := range schs {
+			// Canceling an unknown run only fails with ErrTaskNotFound if the task is not claimed.
+			if err := sch.CancelRun(context.Background(), id, platform.ID(1)); err != backend.ErrTaskNotFound {
+				counts[i]++
+				owners++
+			}
+		}
+		if owners != 1 {
+			return counts, fmt.Errorf("task %s claimed by %d schedulers", id, owners)
+		}
+	}
+	return counts, nil
+}
+
+// pollForClaimCounts waits until each task is claimed by exactly one scheduler,
+// and the schedulers claim the wanted number of tasks.
+func pollForClaimCounts(t *testing.T, ids []platform.ID, want []int, schs ...*backend.TickScheduler) {
+	t.Helper()
+
+	var (
+		counts []int
+		err    error
+	)
+	for i := 0; i < 100; i++ {
+		counts, err = claimCounts(ids, schs...)
+		if err == nil && cmp.Equal(counts, want) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("did not see %v tasks claimed per scheduler in time, last saw %v (%v)", want, counts, err)
+}
+
+func createTasks(t *testing.T, st backend.Store, n int) []platform.ID {
+	ids := make([]platform.ID, n)
+	for i := range ids {
+		id, err := st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: time.Now().Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+func TestCoordinator_SpreadTasksAcrossSchedulers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	st, closeStore := newBoltStore(t)
+	defer closeStore()
+
+	ids := createTasks(t, st, 10)
+
+	sch1, sch2 := newTickScheduler(t, st), newTickScheduler(t, st)
+	defer sch1.Stop()
+	defer sch2.Stop()
+
+	coord1 := coordinator.New(zaptest.NewLogger(t), sch1, st, coordinator.WithLeaseTTL(time.Second))
+	coord2 := coordinator.New(zaptest.NewLogger(t), sch2, st, coordinator.WithLeaseTTL(time.Second))
+	defer coord2.Stop()
+
+	pollForClaimCounts(t, ids, []int{5, 5}, sch1, sch2)
+
+	// A task created through either coordinator is claimed once, and the tasks stay balanced.
+	id, err := coord1.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: time.Now().Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = append(ids, id)
+	id, err = coord1.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: time.Now().Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = append(ids, id)
+	pollForClaimCounts(t, ids, []int{6, 6}, sch1, sch2)
+
+	// Deleting a task through the coordinator that does not hold it releases it from the other scheduler.
+	leases, err := st.ListTaskLeases(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deleteID platform.ID
+	for _, l := range leases {
+		if l.Owner == coord2.Owner() {
+			deleteID = l.TaskID
+			break
+		}
+	}
+	if _, err := coord1.DeleteTask(context.Background(), deleteID); err != nil {
+		t.Fatal(err)
+	}
+	for i := range ids {
+		if ids[i] == deleteID {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+	pollForClaimCounts(t, ids, []int{6, 5}, sch1, sch2)
+
+	// Once a coordinator stops, the other one takes over all its tasks.
+	coord1.Stop()
+	pollForClaimCounts(t, ids, []int{0, 11}, sch1, sch2)
+}
+
+func TestCoordinator_TakeOverExpiredLeases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	st, closeStore := newBoltStore(t)
+	defer closeStore()
+
+	ids := createTasks(t, st, 4)
+
+	// Lease all tasks to an owner that stops renewing them, as if its process crashed.
+	ctx := context.Background()
+	crashed := platform.ID(1)
+	now := time.Now().Unix()
+	if err := st.Heartbeat(ctx, crashed, now, now+2); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		if _, err := st.AcquireTaskLease(ctx, id, crashed, now, now+2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sch := newTickScheduler(t, st)
+	defer sch.Stop()
+	coord := coordinator.New(zaptest.NewLogger(t), sch, st, coordinator.WithLeaseTTL(time.Second))
+	defer coord.Stop()
+
+	// The leases are still live, so none of the tasks may be claimed yet.
+	time.Sleep(500 * time.Millisecond)
+	if counts, _ := claimCounts(ids, sch); counts[0] != 0 {
+		t.Fatalf("expected no tasks to be claimed before their leases expire, got %d", counts[0])
+	}
+
+	pollForClaimCounts(t, ids, []int{4}, sch)
+
+	leases, err := st.ListTaskLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range leases {
+		if l.Owner != coord.Owner() {
+			t.Fatalf("expected task %s to be leased by %s, got %s", l.TaskID, coord.Owner(), l.Owner)
+		}
+	}
+}
@@ -166,7 +166,8 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	}
 
 	// Is it okay to assume it.Err will be set if the query context is canceled?
-	p.finish(&runResult{err: it.Err(), statistics: it.Statistics()}, nil)
+	err = it.Err()
+	p.finish(&runResult{err: err, retryable: backend.IsRetryableError(err), statistics: it.Statistics()}, nil)
 }
 
 func (p *syncRunPromise) cancelOnContextDone(wg *sync.WaitGroup) {
@@ -302,7 +303,8 @@ func (p *asyncRunPromise) followQuery(wg *sync.WaitGroup) {
 	case results, ok := <-p.q.Ready():
 		if !ok {
 			// Something went wrong with the flux. Set the error in the run result.
-			rr := &runResult{err: p.q.Err()}
+			err := p.q.Err()
+			rr := &runResult{err: err, retryable: backend.IsRetryableError(err)}
 			p.finish(rr, nil)
 			return
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -52,6 +53,9 @@ func (r *runReaderWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, wh
 		if rlb.RequestedAt != 0 {
 			run.RequestedAt = time.Unix(rlb.RequestedAt, 0).UTC().Format(time.RFC3339)
 		}
+		if rlb.Try > 0 {
+			run.Try = int(rlb.Try)
+		}
 		timeSetter(run)
 		r.byRunID[ridStr] = run
 		ot := orgtask{o: rlb.Task.Org, t: rlb.Task.ID}
@@ -61,6 +65,9 @@ func (r *runReaderWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, wh
 
 	timeSetter(existingRun)
 	existingRun.Status = status.String()
+	if int(rlb.Try) > existingRun.Try {
+		existingRun.Try = int(rlb.Try)
+	}
 	return nil
 }
 
@@ -110,6 +117,9 @@ func (r *runReaderWriter) ListRuns(ctx context.Context, orgID platform.ID, runFi
 		if r.ID.String() <= afterID {
 			continue
 		}
+		if runFilter.Status != "" && runFilter.Status != r.Status {
+			continue
+		}
 
 		// Copy the element, to avoid a data race if the original Run is modified in UpdateRunState or AddRunLog.
 		r := *r
@@ -143,19 +153,34 @@ func (r *runReaderWriter) ListLogs(ctx context.Context, orgID platform.ID, logFi
 		return nil, errors.New("task ID required")
 	}
 
+	var runs []*platform.Run
 	if logFilter.Run != nil {
 		run, ok := r.byRunID[logFilter.Run.String()]
 		if !ok {
 			return nil, ErrRunNotFound
 		}
 		// TODO(mr): validate that task ID matches, if task is also set. Needs test.
-		return []platform.Log{run.Log}, nil
+		runs = []*platform.Run{run}
+	} else {
+		runs = r.byOrgTask[orgtask{o: orgID, t: logFilter.Task}]
 	}
 
 	var logs []platform.Log
-	ot := orgtask{o: orgID, t: logFilter.Task}
-	for _, run := range r.byOrgTask[ot] {
-		logs = append(logs, run.Log)
+	for _, run := range runs {
+		if logFilter.After != nil && run.ID <= *logFilter.After {
+			continue
+		}
+		log, err := logLinesBetween(run.Log, logFilter.AfterTime, logFilter.BeforeTime)
+		if err != nil {
+			return nil, err
+		}
+		if log == "" && run.Log != "" {
+			continue
+		}
+		logs = append(logs, log)
+		if logFilter.Limit > 0 && len(logs) >= logFilter.Limit {
+			break
+		}
 	}
 
 	if len(logs) == 0 {
@@ -164,3 +189,42 @@ func (r *runReaderWriter) ListLogs(ctx context.Context, orgID platform.ID, logFi
 
 	return logs, nil
 }
+
+// logLinesBetween returns the lines of the log that were written between the
+// optional RFC3339 times afterTime and beforeTime. Each line starts with the
+// time it was written at.
+func logLinesBetween(log platform.Log, afterTime, beforeTime string) (platform.Log, error) {
+	if afterTime == "" && beforeTime == "" {
+		return log, nil
+	}
+
+	var after, before time.Time
+	var err error
+	if afterTime != "" {
+		if after, err = time.Parse(time.RFC3339, afterTime); err != nil {
+			return "", err
+		}
+	}
+	if beforeTime != "" {
+		if before, err = time.Parse(time.RFC3339, beforeTime); err != nil {
+			return "", err
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(log), "\n") {
+		i := strings.Index(line, ": ")
+		if i < 0 {
+			continue
+		}
+		when, err := time.Parse(time.RFC3339Nano, line[:i])
+		if err != nil {
+			return "", err
+		}
+		if (afterTime != "" && when.Before(after)) || (beforeTime != "" && !when.Before(before)) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return platform.Log(strings.Join(lines, "\n")), nil
+}
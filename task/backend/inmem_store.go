@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,14 +26,21 @@ type inmem struct {
 	tasks []StoreTask
 
 	meta map[platform.ID]StoreTaskMeta
+
+	// owners maps an owner ID to the Unix timestamp its heartbeat expires.
+	owners map[platform.ID]int64
+
+	leases map[platform.ID]TaskLease
 }
 
 // NewInMemStore returns a new in-memory store.
 // This store is not designed to be efficient, it is here for testing purposes.
 func NewInMemStore() Store {
 	return &inmem{
-		idgen: snowflake.NewIDGenerator(),
-		meta:  map[platform.ID]StoreTaskMeta{},
+		idgen:  snowflake.NewIDGenerator(),
+		meta:   map[platform.ID]StoreTaskMeta{},
+		owners: map[platform.ID]int64{},
+		leases: map[platform.ID]TaskLease{},
 	}
 }
 
@@ -247,6 +255,7 @@ func (s *inmem) DeleteTask(_ context.Context, id platform.ID) (deleted bool, err
 	// Delete entry from slice.
 	s.tasks = append(s.tasks[:idx], s.tasks[idx+1:]...)
 	delete(s.meta, id)
+	delete(s.leases, id)
 	return true, nil
 }
 
@@ -297,6 +306,24 @@ func (s *inmem) FinishRun(ctx context.Context, taskID, runID platform.ID) error
 	return nil
 }
 
+func (s *inmem) IncrementRunTry(ctx context.Context, taskID, runID platform.ID) (uint32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return 0, errors.New("taskRunner not found")
+	}
+
+	try, ok := stm.IncrementRunTry(runID)
+	if !ok {
+		return 0, errors.New("run not found")
+	}
+
+	s.meta[taskID] = stm
+	return try, nil
+}
+
 func (s *inmem) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, start, end, requestedAt int64) (*StoreTaskMetaManualRun, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -343,11 +370,82 @@ func (s *inmem) delete(ctx context.Context, id platform.ID, f func(StoreTask) pl
 	}
 	for i := range deletingTasks {
 		delete(s.meta, s.tasks[i].ID)
+		delete(s.leases, deletingTasks[i])
 	}
 	s.tasks = newTasks
 	return nil
 }
 
+func (s *inmem) Heartbeat(_ context.Context, owner platform.ID, now, expires int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, exp := range s.owners {
+		if exp <= now {
+			delete(s.owners, id)
+		}
+	}
+	if expires <= now {
+		delete(s.owners, owner)
+		return nil
+	}
+	s.owners[owner] = expires
+	return nil
+}
+
+func (s *inmem) ListOwners(_ context.Context, now int64) ([]platform.ID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var owners []platform.ID
+	for id, exp := range s.owners {
+		if exp > now {
+			owners = append(owners, id)
+		}
+	}
+	sort.Slice(owners, func(i, j int) bool { return owners[i] < owners[j] })
+	return owners, nil
+}
+
+func (s *inmem) AcquireTaskLease(_ context.Context, taskID, owner platform.ID, now, expires int64) (TaskLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.meta[taskID]; !ok {
+		return TaskLease{}, ErrTaskNotFound
+	}
+
+	if l, ok := s.leases[taskID]; ok && l.Owner != owner && !l.ExpiredAt(now) {
+		return TaskLease{}, ErrTaskLeased
+	}
+
+	l := TaskLease{TaskID: taskID, Owner: owner, Expires: expires}
+	s.leases[taskID] = l
+	return l, nil
+}
+
+func (s *inmem) ReleaseTaskLease(_ context.Context, taskID, owner platform.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.leases[taskID]; ok && l.Owner == owner {
+		delete(s.leases, taskID)
+	}
+	return nil
+}
+
+func (s *inmem) ListTaskLeases(_ context.Context) ([]TaskLease, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	leases := make([]TaskLease, 0, len(s.leases))
+	for _, l := range s.leases {
+		leases = append(leases, l)
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].TaskID < leases[j].TaskID })
+	return leases, nil
+}
+
 func getOrg(st StoreTask) platform.ID {
 	return st.Org
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/influxdata/flux/control"
 	"github.com/influxdata/flux/execute"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/query"
 	pcontrol "github.com/influxdata/influxdb/query/control"
@@ -15,6 +16,7 @@ import (
 	"github.com/influxdata/influxdb/storage/readservice"
 	"github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/backend/storetest"
+	platformtesting "github.com/influxdata/influxdb/testing"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 )
@@ -90,6 +92,15 @@ func newFullStackAwareLogReaderWriter(t *testing.T) *fullStackAwareLogReaderWrit
 	}()
 
 	svc := inmem.NewService()
+	// The runs and logs are written to the system bucket of the organization
+	// of the authorization that the store tests use.
+	if err := svc.PutOrganization(context.Background(), &platform.Organization{
+		ID:   platformtesting.MustIDBase16("ab01ab01ab01ab05"),
+		Name: "tasks",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	systemBuckets := backend.NewSystemBucketService(svc, 0)
 
 	const (
 		concurrencyQuota = 10
@@ -112,8 +123,8 @@ func newFullStackAwareLogReaderWriter(t *testing.T) *fullStackAwareLogReaderWrit
 	queryController := pcontrol.New(cc)
 
 	return &fullStackAwareLogReaderWriter{
-		PointLogWriter: backend.NewPointLogWriter(engine),
-		QueryLogReader: backend.NewQueryLogReader(query.QueryServiceBridge{AsyncQueryService: queryController}),
+		PointLogWriter: backend.NewPointLogWriter(engine, systemBuckets),
+		QueryLogReader: backend.NewQueryLogReader(query.QueryServiceBridge{AsyncQueryService: queryController}, systemBuckets),
 
 		queryController: queryController,
 
@@ -94,6 +94,22 @@ func (stm *StoreTaskMeta) FinishRun(runID platform.ID) bool {
 	return false
 }
 
+// IncrementRunTry increments the Try value of the run matching runID in m's CurrentlyRunning slice,
+// before the run is attempted again.
+//
+// If runID matched a run, IncrementRunTry returns the new Try value and true. Otherwise it returns 0 and false.
+func (stm *StoreTaskMeta) IncrementRunTry(runID platform.ID) (uint32, bool) {
+	for _, runner := range stm.CurrentlyRunning {
+		if platform.ID(runner.RunID) != runID {
+			continue
+		}
+
+		runner.Try++
+		return runner.Try, true
+	}
+	return 0, false
+}
+
 // CreateNextRun attempts to update stm's CurrentlyRunning slice with a new run.
 // The new run's now is assigned the earliest possible time according to stm.EffectiveCron,
 // that is later than any in-progress run and stm's LatestCompleted timestamp.
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
@@ -17,9 +18,7 @@ const (
 	statusField       = "status"
 
 	taskIDTag = "taskID"
-
-	// Fixed system bucket ID for task and run logs.
-	taskSystemBucketID platform.ID = 10
+	tryTag    = "try"
 )
 
 // Copy of storage.PointsWriter interface.
@@ -28,20 +27,25 @@ type PointsWriter interface {
 	WritePoints(ctx context.Context, points []models.Point) error
 }
 
-// PointLogWriter writes task and run logs as time-series points.
+// PointLogWriter writes task and run logs as time-series points to the
+// task system bucket of the organization of the task.
 type PointLogWriter struct {
-	pointsWriter PointsWriter
+	pointsWriter  PointsWriter
+	systemBuckets *SystemBucketService
 }
 
 // NewPointLogWriter returns a PointLogWriter.
-func NewPointLogWriter(pw PointsWriter) *PointLogWriter {
-	return &PointLogWriter{pointsWriter: pw}
+func NewPointLogWriter(pw PointsWriter, sbs *SystemBucketService) *PointLogWriter {
+	return &PointLogWriter{pointsWriter: pw, systemBuckets: sbs}
 }
 
 func (p *PointLogWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, when time.Time, status RunStatus) error {
 	tags := models.Tags{
 		models.NewTag([]byte(taskIDTag), []byte(rlb.Task.ID.String())),
 	}
+	if rlb.Try > 0 {
+		tags = append(tags, models.NewTag([]byte(tryTag), []byte(strconv.FormatUint(uint64(rlb.Try), 10))))
+	}
 	fields := make(map[string]interface{}, 4)
 	fields[statusField] = status.String()
 	fields[runIDField] = rlb.RunID.String()
@@ -55,13 +59,7 @@ func (p *PointLogWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, whe
 		return err
 	}
 
-	// TODO(mr): it would probably be lighter-weight to just build exploded points in the first place.
-	exploded, err := tsdb.ExplodePoints(rlb.Task.Org, taskSystemBucketID, []models.Point{pt})
-	if err != nil {
-		return err
-	}
-
-	return p.pointsWriter.WritePoints(ctx, exploded)
+	return p.writePoint(ctx, rlb.Task.Org, pt)
 }
 
 func (p *PointLogWriter) AddRunLog(ctx context.Context, rlb RunLogBase, when time.Time, log string) error {
@@ -77,8 +75,18 @@ func (p *PointLogWriter) AddRunLog(ctx context.Context, rlb RunLogBase, when tim
 		return err
 	}
 
+	return p.writePoint(ctx, rlb.Task.Org, pt)
+}
+
+// writePoint writes the point to the task system bucket of the organization.
+func (p *PointLogWriter) writePoint(ctx context.Context, orgID platform.ID, pt models.Point) error {
+	b, err := p.systemBuckets.FindOrCreateSystemBucket(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
 	// TODO(mr): it would probably be lighter-weight to just build exploded points in the first place.
-	exploded, err := tsdb.ExplodePoints(rlb.Task.Org, taskSystemBucketID, []models.Point{pt})
+	exploded, err := tsdb.ExplodePoints(orgID, b.ID, []models.Point{pt})
 	if err != nil {
 		return err
 	}
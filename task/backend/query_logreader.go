@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,22 +20,34 @@ import (
 )
 
 type QueryLogReader struct {
-	queryService query.QueryService
+	queryService  query.QueryService
+	systemBuckets *SystemBucketService
 }
 
 var _ LogReader = (*QueryLogReader)(nil)
 
-func NewQueryLogReader(qs query.QueryService) *QueryLogReader {
+func NewQueryLogReader(qs query.QueryService, sbs *SystemBucketService) *QueryLogReader {
 	return &QueryLogReader{
-		queryService: qs,
+		queryService:  qs,
+		systemBuckets: sbs,
 	}
 }
 
+// defaultRunsLimit is the number of runs that ListRuns returns without a limit.
+const defaultRunsLimit = 100
+
 func (qlr *QueryLogReader) ListLogs(ctx context.Context, orgID platform.ID, logFilter platform.LogFilter) ([]platform.Log, error) {
 	if !logFilter.Task.Valid() {
 		return nil, errors.New("task ID required to list logs")
 	}
 
+	from, err := qlr.fromSystemBucket(ctx, orgID, logFilter.AfterTime, logFilter.BeforeTime)
+	if err != nil {
+		return nil, err
+	} else if from == "" {
+		return nil, ErrNoRunsFound
+	}
+
 	filterPart := ""
 	if logFilter.Run != nil {
 		filterPart = fmt.Sprintf(`|> filter(fn: (r) => r._measurement == "logs" and r.runID == %q)`, logFilter.Run.String())
@@ -42,49 +55,35 @@ func (qlr *QueryLogReader) ListLogs(ctx context.Context, orgID platform.ID, logF
 		filterPart = fmt.Sprintf(`|> filter(fn: (r) => r._measurement == "logs" and r.taskID == %q)`, logFilter.Task.String())
 	}
 
-	// TODO(lh): Change the range to something more reasonable. Not sure what that range will be.
-	listScript := fmt.Sprintf(`from(bucketID: "000000000000000a")
-  |> range(start: -100h)
+	listScript := fmt.Sprintf(`%s
   |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
   %s
   |> group(columns: ["taskID", "runID", "_measurement"])
-  `, filterPart)
+  `, from, filterPart)
 
-	auth, err := pctx.GetAuthorizer(ctx)
+	ittr, err := qlr.query(ctx, orgID, listScript)
 	if err != nil {
 		return nil, err
 	}
-	if auth.Kind() != "authorization" {
-		return nil, platform.ErrAuthorizerNotSupported
-	}
-	request := &query.Request{Authorization: auth.(*platform.Authorization), OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: listScript}}
-
-	ittr, err := qlr.queryService.Query(ctx, request)
+	runs, err := queryIttrToRuns(ittr)
 	if err != nil {
 		return nil, err
 	}
-	defer ittr.Release()
 
-	re := newRunExtractor()
-	for ittr.More() {
-		if err := ittr.Next().Tables().Do(re.Extract); err != nil {
-			return nil, err
+	// Logs are paged by run, like the runs themselves.
+	logs := make([]platform.Log, 0, len(runs))
+	for _, r := range runs {
+		if logFilter.After != nil && r.ID <= *logFilter.After {
+			continue
+		}
+		logs = append(logs, r.Log)
+		if logFilter.Limit > 0 && len(logs) >= logFilter.Limit {
+			break
 		}
 	}
-
-	if err := ittr.Err(); err != nil {
-		return nil, err
-	}
-
-	runs := re.Runs()
-	if len(runs) == 0 {
+	if len(logs) == 0 {
 		return nil, ErrNoRunsFound
 	}
-
-	logs := make([]platform.Log, len(runs))
-	for i, r := range runs {
-		logs[i] = r.Log
-	}
 	return logs, nil
 }
 
@@ -93,9 +92,11 @@ func (qlr *QueryLogReader) ListRuns(ctx context.Context, orgID platform.ID, runF
 		return nil, errors.New("task required")
 	}
 
-	limit := "|> limit(n: 100)\n"
-	if runFilter.Limit > 0 {
-		limit = fmt.Sprintf("|> limit(n: %d)\n", runFilter.Limit)
+	from, err := qlr.fromSystemBucket(ctx, orgID, "", "")
+	if err != nil {
+		return nil, err
+	} else if from == "" {
+		return nil, ErrNoRunsFound
 	}
 
 	afterID := ""
@@ -111,76 +112,78 @@ func (qlr *QueryLogReader) ListRuns(ctx context.Context, orgID platform.ID, runF
 	listScript := fmt.Sprintf(`
 import "influxdata/influxdb/v1"
 
-from(bucketID: "000000000000000a")
-  |> range(start: -24h)
+%s
 	|> filter(fn: (r) => r._measurement == "records" and r.taskID == %q)
 	|> drop(columns: ["_start", "_stop"])
-	|> group(columns: ["_measurement", "taskID", "scheduledFor", "status", "runID"])
+	|> group(columns: ["_measurement", "taskID", "scheduledFor", "status", "runID", "try"])
 	|> v1.fieldsAsCols()
 	|> filter(fn: (r) => r.scheduledFor < %q and r.scheduledFor > %q and r.runID > %q)
 	|> pivot(rowKey:["runID", "scheduledFor"], columnKey: ["status"], valueColumn: "_time")
-	%s
-	`, runFilter.Task.String(), scheduledBefore, scheduledAfter, afterID, limit)
+	`, from, runFilter.Task.String(), scheduledBefore, scheduledAfter, afterID)
 
-	auth, err := pctx.GetAuthorizer(ctx)
+	ittr, err := qlr.query(ctx, orgID, listScript)
 	if err != nil {
 		return nil, err
 	}
-	if auth.Kind() != "authorization" {
-		return nil, platform.ErrAuthorizerNotSupported
-	}
-	request := &query.Request{Authorization: auth.(*platform.Authorization), OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: listScript}}
 
-	ittr, err := qlr.queryService.Query(ctx, request)
+	runs, err := queryIttrToRuns(ittr)
 	if err != nil {
 		return nil, err
 	}
 
-	runs, err := queryIttrToRuns(ittr)
-	if err != nil {
-		return nil, err
+	// The status of a run is only known once the records of all of its
+	// tries are merged, so the status and the limit are applied here.
+	limit := defaultRunsLimit
+	if runFilter.Limit > 0 {
+		limit = runFilter.Limit
+	}
+	filtered := runs[:0]
+	for _, r := range runs {
+		if runFilter.Status != "" && r.Status != runFilter.Status {
+			continue
+		}
+		filtered = append(filtered, r)
+		if len(filtered) >= limit {
+			break
+		}
 	}
 
-	if len(runs) == 0 {
+	if len(filtered) == 0 {
 		return nil, ErrNoRunsFound
 	}
 
-	return runs, nil
+	return filtered, nil
 }
 
 func (qlr *QueryLogReader) FindRunByID(ctx context.Context, orgID, runID platform.ID) (*platform.Run, error) {
+	from, err := qlr.fromSystemBucket(ctx, orgID, "", "")
+	if err != nil {
+		return nil, err
+	} else if from == "" {
+		return nil, ErrRunNotFound
+	}
+
 	showScript := fmt.Sprintf(`
 import "influxdata/influxdb/v1"
 
-logs = from(bucketID: "000000000000000a")
-	|> range(start: -24h)
+logs = %s
 	|> filter(fn: (r) => r._measurement == "logs")
 	|> drop(columns: ["_start", "_stop"])
 	|> v1.fieldsAsCols()
 	|> filter(fn: (r) => r.runID == %q)
 	|> yield(name: "logs")
 
-from(bucketID: "000000000000000a")
-  |> range(start: -24h)
+%s
 	|> filter(fn: (r) => r._measurement == "records")
 	|> drop(columns: ["_start", "_stop"])
-	|> group(columns: ["_measurement", "taskID", "scheduledFor", "status", "runID"])
+	|> group(columns: ["_measurement", "taskID", "scheduledFor", "status", "runID", "try"])
 	|> v1.fieldsAsCols()
 	|> filter(fn: (r) => r.runID == %q)
 	|> pivot(rowKey:["runID", "scheduledFor"], columnKey: ["status"], valueColumn: "_time")
 	|> yield(name: "result")
-  `, runID.String(), runID.String())
-
-	auth, err := pctx.GetAuthorizer(ctx)
-	if err != nil {
-		return nil, err
-	}
-	if auth.Kind() != "authorization" {
-		return nil, platform.ErrAuthorizerNotSupported
-	}
-	request := &query.Request{Authorization: auth.(*platform.Authorization), OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: showScript}}
+  `, from, runID.String(), from, runID.String())
 
-	ittr, err := qlr.queryService.Query(ctx, request)
+	ittr, err := qlr.query(ctx, orgID, showScript)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +201,61 @@ from(bucketID: "000000000000000a")
 	return runs[0], nil
 }
 
+// fromSystemBucket returns the start of a Flux script that reads the task
+// system bucket of the organization between the RFC3339 times afterTime and
+// beforeTime, which are optional. The range starts no earlier than the
+// retention period of the bucket. It returns an empty script when the range
+// is empty, or when the organization has no system bucket since none of its
+// tasks have run.
+func (qlr *QueryLogReader) fromSystemBucket(ctx context.Context, orgID platform.ID, afterTime, beforeTime string) (string, error) {
+	b, err := qlr.systemBuckets.FindSystemBucket(ctx, orgID)
+	if err != nil || b == nil {
+		return "", err
+	}
+
+	start := time.Unix(0, 0)
+	if b.RetentionPeriod > 0 {
+		start = time.Now().Add(-b.RetentionPeriod)
+	}
+	if afterTime != "" {
+		t, err := time.Parse(time.RFC3339, afterTime)
+		if err != nil {
+			return "", err
+		}
+		if t.After(start) {
+			start = t
+		}
+	}
+	rangePart := fmt.Sprintf("range(start: %s)", start.UTC().Format(time.RFC3339Nano))
+	if beforeTime != "" {
+		stop, err := time.Parse(time.RFC3339, beforeTime)
+		if err != nil {
+			return "", err
+		}
+		if !stop.After(start) {
+			return "", nil
+		}
+		rangePart = fmt.Sprintf("range(start: %s, stop: %s)", start.UTC().Format(time.RFC3339Nano), stop.UTC().Format(time.RFC3339Nano))
+	}
+
+	return fmt.Sprintf(`from(bucketID: %q)
+  |> %s`, b.ID.String(), rangePart), nil
+}
+
+// query runs the script with the authorizer of the context on behalf of the organization.
+func (qlr *QueryLogReader) query(ctx context.Context, orgID platform.ID, script string) (flux.ResultIterator, error) {
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if auth.Kind() != "authorization" {
+		return nil, platform.ErrAuthorizerNotSupported
+	}
+	request := &query.Request{Authorization: auth.(*platform.Authorization), OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: script}}
+
+	return qlr.queryService.Query(ctx, request)
+}
+
 func queryIttrToRuns(results flux.ResultIterator) ([]*platform.Run, error) {
 	defer results.Release()
 
@@ -265,6 +323,14 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 			switch col.Label {
 			case requestedAtField:
 				r.RequestedAt = cr.Strings(j).ValueString(i)
+			case tryTag:
+				if s := cr.Strings(j).ValueString(i); s != "" {
+					try, err := strconv.Atoi(s)
+					if err != nil {
+						return err
+					}
+					r.Try = try
+				}
 			case scheduledForField:
 				r.ScheduledFor = cr.Strings(j).ValueString(i)
 			case "runID":
@@ -280,12 +346,20 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 				}
 				r.TaskID = *id
 			case RunStarted.String():
+				// The pivot of the records of several runs has a column for every
+				// status that any of them reached, which is null for the others.
+				if cr.Times(j).IsNull(i) {
+					continue
+				}
 				r.StartedAt = values.Time(cr.Times(j).Value(i)).Time().Format(time.RFC3339Nano)
 				if r.Status == "" {
 					// Only set status if it wasn't already set.
 					r.Status = col.Label
 				}
 			case RunSuccess.String(), RunFail.String(), RunCanceled.String():
+				if cr.Times(j).IsNull(i) {
+					continue
+				}
 				r.FinishedAt = values.Time(cr.Times(j).Value(i)).Time().Format(time.RFC3339Nano)
 				// Finished can be set unconditionally;
 				// it's fine to overwrite if the status was already set to started.
@@ -298,7 +372,8 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 		}
 
 		if ex, ok := re.runs[r.ID]; ok {
-			r.Log = ex.Log
+			// Each try of a run is recorded separately; merge them into a single run.
+			r = mergeRunRecords(ex, r)
 		}
 
 		re.runs[r.ID] = r
@@ -307,6 +382,34 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 	return nil
 }
 
+// mergeRunRecords combines two records of the same run, which may belong to different tries.
+// The run's status is taken from the latest finished try, if any try has finished.
+func mergeRunRecords(ex, r platform.Run) platform.Run {
+	r.Log = ex.Log
+
+	if r.StartedAt == "" {
+		r.StartedAt = ex.StartedAt
+	} else if ex.StartedAt != "" {
+		exStarted, _ := time.Parse(time.RFC3339Nano, ex.StartedAt)
+		rStarted, _ := time.Parse(time.RFC3339Nano, r.StartedAt)
+		if exStarted.Before(rStarted) {
+			r.StartedAt = ex.StartedAt
+		}
+	}
+	if r.RequestedAt == "" {
+		r.RequestedAt = ex.RequestedAt
+	}
+
+	exFinished, rFinished := ex.FinishedAt != "", r.FinishedAt != ""
+	if (exFinished && !rFinished) || (exFinished == rFinished && ex.Try > r.Try) {
+		r.Status, r.FinishedAt = ex.Status, ex.FinishedAt
+	}
+	if ex.Try > r.Try {
+		r.Try = ex.Try
+	}
+	return r
+}
+
 func (re *runExtractor) extractLog(cr flux.ColReader) error {
 	entries := make(map[platform.ID][]string)
 	for i := 0; i < cr.Len(); i++ {
@@ -336,6 +439,7 @@ func (re *runExtractor) extractLog(cr flux.ColReader) error {
 
 	for id, lines := range entries {
 		run := re.runs[id]
+		run.ID = id
 		run.Log = platform.Log(strings.Join(lines, "\n"))
 		re.runs[id] = run
 	}
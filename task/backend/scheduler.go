@@ -12,6 +12,7 @@ import (
 
 	"github.com/influxdata/flux"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/options"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -28,6 +29,14 @@ var (
 	ErrTaskAlreadyClaimed = errors.New("task already claimed")
 )
 
+const (
+	// defaultRetryBackoff is the delay before the second try of a run, if not set with WithRetryBackoff.
+	defaultRetryBackoff = time.Second
+
+	// maxRetryBackoff is the longest delay between two tries of a run.
+	maxRetryBackoff = 5 * time.Minute
+)
+
 // DesiredState persists the desired state of a run.
 type DesiredState interface {
 	// CreateNextRun requests the next run from the desired state, delegating to (*StoreTaskMeta).CreateNextRun.
@@ -41,6 +50,10 @@ type DesiredState interface {
 	// FinishRun indicates that the given run is no longer intended to be executed.
 	// This may be called after a successful or failed execution, or upon cancellation.
 	FinishRun(ctx context.Context, taskID, runID platform.ID) error
+
+	// IncrementRunTry indicates that the given run failed with a retryable error and is about to be attempted again.
+	// It returns the try count of the next attempt.
+	IncrementRunTry(ctx context.Context, taskID, runID platform.ID) (uint32, error)
 }
 
 // Executor handles execution of a run.
@@ -98,6 +111,22 @@ type RunResult interface {
 	Statistics() flux.Statistics
 }
 
+// IsRetryableError reports whether a run that failed with err may succeed on another try.
+// Only transient failures, such as an unavailable or overloaded storage engine, are retryable;
+// errors from an invalid script or a missing resource fail the same way on every try.
+func IsRetryableError(err error) bool {
+	switch platform.ErrorCode(err) {
+	case platform.EUnavailable, platform.ETooManyRequests:
+		return true
+	case platform.EInternal:
+		// ErrorCode reports EInternal for any plain error too,
+		// so only trust internal errors that were explicitly raised as such.
+		_, ok := err.(*platform.Error)
+		return ok
+	}
+	return false
+}
+
 // Scheduler accepts tasks and handles their scheduling.
 //
 // TODO(mr): right now the methods on Scheduler are synchronous.
@@ -155,6 +184,15 @@ func WithTicker(ctx context.Context, d time.Duration) TickSchedulerOption {
 	}
 }
 
+// WithRetryBackoff sets the base delay before retrying a run that failed with a retryable error.
+// The delay doubles with each subsequent try, up to maxRetryBackoff.
+// If not set, the scheduler will use defaultRetryBackoff.
+func WithRetryBackoff(d time.Duration) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.retryBackoff = d
+	}
+}
+
 // WithLogger sets the logger for the scheduler.
 // If not set, the scheduler will use a no-op logger.
 func WithLogger(logger *zap.Logger) TickSchedulerOption {
@@ -174,6 +212,7 @@ func NewScheduler(desiredState DesiredState, executor Executor, lw LogWriter, no
 		logger:         zap.NewNop(),
 		wg:             &sync.WaitGroup{},
 		metrics:        newSchedulerMetrics(),
+		retryBackoff:   defaultRetryBackoff,
 	}
 
 	for _, opt := range opts {
@@ -193,6 +232,8 @@ type TickScheduler struct {
 
 	metrics *schedulerMetrics
 
+	retryBackoff time.Duration
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     *sync.WaitGroup
@@ -398,6 +439,11 @@ type taskScheduler struct {
 
 	metrics *schedulerMetrics
 
+	// Number of times a run may be attempted, taken from the task's retry option,
+	// and the delay before the second try.
+	retry        uint32
+	retryBackoff time.Duration
+
 	nextDueMu     sync.RWMutex // Protects following fields.
 	nextDue       int64        // Unix timestamp of next due.
 	nextDueSource int64        // Run time that produced nextDue.
@@ -417,6 +463,13 @@ func newTaskScheduler(
 		return nil, err
 	}
 
+	// Scripts that fail to parse still get the default options, which allow a single try.
+	opts, _ := options.FromScript(task.Script)
+	retry := uint32(1)
+	if opts.Retry > 1 {
+		retry = uint32(opts.Retry)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	ts := &taskScheduler{
 		now:           &s.now,
@@ -427,6 +480,8 @@ func newTaskScheduler(
 		running:       make(map[platform.ID]runCtx, meta.MaxConcurrency),
 		logger:        s.logger.With(zap.String("task_id", task.ID.String())),
 		metrics:       s.metrics,
+		retry:         retry,
+		retryBackoff:  s.retryBackoff,
 		nextDue:       firstDue,
 		nextDueSource: math.MinInt64,
 		hasQueue:      len(meta.ManualRuns) > 0,
@@ -457,7 +512,7 @@ func (ts *taskScheduler) WorkCurrentlyRunning(meta *StoreTaskMeta) error {
 		foundWorker := false
 		for _, r := range ts.runners {
 			qr := QueuedRun{TaskID: ts.task.ID, RunID: platform.ID(cr.RunID), Now: cr.Now}
-			if r.RestartRun(qr) {
+			if r.RestartRun(qr, cr.Try) {
 				foundWorker = true
 				break
 			}
@@ -566,8 +621,9 @@ func (r *runner) Start() {
 }
 
 // RestartRun attempts to restart a queued run if the runner is available to do the work.
+// try is the number of times the run has already been attempted.
 // If the runner was already busy we return false.
-func (r *runner) RestartRun(qr QueuedRun) bool {
+func (r *runner) RestartRun(qr QueuedRun, try uint32) bool {
 	if !atomic.CompareAndSwapUint32(r.state, runnerIdle, runnerWorking) {
 		// already working
 		return false
@@ -583,9 +639,12 @@ func (r *runner) RestartRun(qr QueuedRun) bool {
 		r.ts.running[qr.RunID] = rCtx
 	}
 	r.ts.runningMu.Unlock()
-	go r.executeAndWait(rCtx.Context, qr, runLogger)
+	if try < 1 {
+		try = 1
+	}
+	go r.executeAndWait(rCtx.Context, qr, try, runLogger)
 
-	r.updateRunState(qr, RunStarted, runLogger)
+	r.updateRunState(qr, try, RunStarted, runLogger)
 	return true
 }
 
@@ -618,9 +677,9 @@ func (r *runner) startFromWorking(now int64) {
 
 	runLogger.Info("Created run; beginning execution")
 	r.wg.Add(1)
-	go r.executeAndWait(ctx, qr, runLogger)
+	go r.executeAndWait(ctx, qr, 1, runLogger)
 
-	r.updateRunState(qr, RunStarted, runLogger)
+	r.updateRunState(qr, 1, RunStarted, runLogger)
 }
 
 func (r *runner) clearRunning(id platform.ID) {
@@ -630,112 +689,230 @@ func (r *runner) clearRunning(id platform.ID) {
 	r.ts.runningMu.Unlock()
 }
 
-func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *zap.Logger) {
+// executeAndWait executes qr, starting with the given try, and waits for the result.
+// A run whose result is retryable is attempted again, after a backoff,
+// until it has been tried as many times as the task's retry option allows.
+func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, try uint32, runLogger *zap.Logger) {
 	defer r.wg.Done()
 
-	sp, spCtx := opentracing.StartSpanFromContext(ctx, "task.run.execution")
-	defer sp.Finish()
+	for {
+		sp, spCtx := opentracing.StartSpanFromContext(ctx, "task.run.execution")
+		sp.SetTag("try", try)
+
+		rp, err := r.executor.Execute(spCtx, qr)
+		if err != nil {
+			sp.Finish()
+			runLogger.Info("Failed to begin run execution", zap.Error(err), zap.Uint32("try", try))
+			if IsRetryableError(err) && try < r.ts.retry {
+				// Nothing else is watching the run's context yet, so clear it here before replacing it.
+				r.clearRunning(qr.RunID)
+				var retry, canceled bool
+				if ctx, retry, canceled = r.retry(qr, try, err, runLogger); retry {
+					try++
+					continue
+				} else if canceled {
+					return
+				}
+			}
 
-	rp, err := r.executor.Execute(spCtx, qr)
-	if err != nil {
-		runLogger.Info("Failed to begin run execution", zap.Error(err))
-		if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
-			// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
-			runLogger.Error("Beginning run execution failed, and desired state update failed", zap.Error(err))
+			if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
+				// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
+				runLogger.Error("Beginning run execution failed, and desired state update failed", zap.Error(err))
+			}
+			atomic.StoreUint32(r.state, runnerIdle)
+			r.updateRunState(qr, try, RunFail, runLogger)
+			return
 		}
 
-		// TODO(mr): retry?
-		atomic.StoreUint32(r.state, runnerIdle)
-		r.updateRunState(qr, RunFail, runLogger)
-		return
-	}
+		ready := make(chan struct{})
+		cleared := make(chan struct{})
+		go func() {
+			defer close(cleared)
+
+			// If the runner's context is canceled, cancel the RunPromise.
+			select {
+			case <-ctx.Done():
+				r.clearRunning(qr.RunID)
+				rp.Cancel()
+			// Canceled context.
+			case <-r.ctx.Done():
+				r.clearRunning(qr.RunID)
+				rp.Cancel()
+			// Wait finished.
+			case <-ready:
+				r.clearRunning(qr.RunID)
+			}
+		}()
 
-	ready := make(chan struct{})
-	go func() {
-		// If the runner's context is canceled, cancel the RunPromise.
-		select {
-		case <-ctx.Done():
-			r.clearRunning(qr.RunID)
-			rp.Cancel()
-		// Canceled context.
-		case <-r.ctx.Done():
-			r.clearRunning(qr.RunID)
-			rp.Cancel()
-		// Wait finished.
-		case <-ready:
-			r.clearRunning(qr.RunID)
-		}
-	}()
+		rr, err := rp.Wait()
+		close(ready)
+		sp.Finish()
+		if err != nil {
+			if err == ErrRunCanceled {
+				_ = r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID)
+				r.updateRunState(qr, try, RunCanceled, runLogger)
+
+				// Move on to the next execution, for a canceled run.
+				r.startFromWorking(atomic.LoadInt64(r.ts.now))
+				return
+			}
 
-	// TODO(mr): handle rr.IsRetryable().
-	rr, err := rp.Wait()
-	close(ready)
-	if err != nil {
-		if err == ErrRunCanceled {
-			_ = r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID)
-			r.updateRunState(qr, RunCanceled, runLogger)
+			runLogger.Info("Failed to wait for execution result", zap.Error(err), zap.Uint32("try", try))
+			if IsRetryableError(err) && try < r.ts.retry {
+				// The previous context was canceled when the run was cleared, so wait for that to happen before replacing it.
+				<-cleared
+				var retry, canceled bool
+				if ctx, retry, canceled = r.retry(qr, try, err, runLogger); retry {
+					try++
+					continue
+				} else if canceled {
+					return
+				}
+			}
 
-			// Move on to the next execution, for a canceled run.
-			r.startFromWorking(atomic.LoadInt64(r.ts.now))
+			if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
+				// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
+				runLogger.Error("Waiting for execution result failed, and desired state update failed", zap.Error(err))
+			}
+			r.updateRunState(qr, try, RunFail, runLogger)
+			atomic.StoreUint32(r.state, runnerIdle)
 			return
 		}
+		if err := rr.Err(); err != nil {
+			runLogger.Info("Run failed to execute", zap.Error(err), zap.Uint32("try", try))
+			if rr.IsRetryable() && try < r.ts.retry {
+				// The previous context was canceled when the run was cleared, so wait for that to happen before replacing it.
+				<-cleared
+				var retry, canceled bool
+				if ctx, retry, canceled = r.retry(qr, try, err, runLogger); retry {
+					try++
+					continue
+				} else if canceled {
+					return
+				}
+			}
 
-		runLogger.Info("Failed to wait for execution result", zap.Error(err))
-		if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
-			// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
-			runLogger.Error("Waiting for execution result failed, and desired state update failed", zap.Error(err))
+			if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
+				// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
+				runLogger.Error("Run failed to execute, and desired state update failed", zap.Error(err))
+			}
+			r.updateRunState(qr, try, RunFail, runLogger)
+			atomic.StoreUint32(r.state, runnerIdle)
+			return
 		}
 
-		// TODO(mr): retry?
-		r.updateRunState(qr, RunFail, runLogger)
-		atomic.StoreUint32(r.state, runnerIdle)
-		return
-	}
-	if err := rr.Err(); err != nil {
-		runLogger.Info("Run failed to execute", zap.Error(err))
 		if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
-			// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
-			runLogger.Error("Run failed to execute, and desired state update failed", zap.Error(err))
+			runLogger.Info("Failed to finish run", zap.Error(err))
+			// Need to think about what it means if there was an error finishing a run.
+			atomic.StoreUint32(r.state, runnerIdle)
+			r.updateRunState(qr, try, RunFail, runLogger)
+			return
 		}
-		// TODO(mr): retry?
-		r.updateRunState(qr, RunFail, runLogger)
-		atomic.StoreUint32(r.state, runnerIdle)
+		rlb := RunLogBase{
+			Task:            r.task,
+			RunID:           qr.RunID,
+			RunScheduledFor: qr.Now,
+			RequestedAt:     qr.RequestedAt,
+			Try:             try,
+		}
+		stats := rr.Statistics()
+
+		b, err := json.Marshal(stats)
+		if err == nil {
+			r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), string(b))
+		}
+		r.updateRunState(qr, try, RunSuccess, runLogger)
+		runLogger.Info("Execution succeeded")
+
+		// Check again if there is a new run available, without returning to idle state.
+		r.startFromWorking(atomic.LoadInt64(r.ts.now))
 		return
 	}
+}
 
-	if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
-		runLogger.Info("Failed to finish run", zap.Error(err))
-		// TODO(mr): retry?
-		// Need to think about what it means if there was an error finishing a run.
-		atomic.StoreUint32(r.state, runnerIdle)
-		r.updateRunState(qr, RunFail, runLogger)
-		return
+// prepareRetry records another try of qr after a retryable failure, and waits out the backoff.
+// The returned context is registered as the run's context, so the run can be canceled while waiting.
+// If the retry should not happen, prepareRetry returns false and the caller is responsible for clearing the run.
+func (r *runner) prepareRetry(qr QueuedRun, try uint32, runErr error, runLogger *zap.Logger) (context.Context, bool) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.ts.runningMu.Lock()
+	r.ts.running[qr.RunID] = runCtx{Context: ctx, CancelFunc: cancel}
+	r.ts.runningMu.Unlock()
+
+	next, err := r.desiredState.IncrementRunTry(r.ctx, qr.TaskID, qr.RunID)
+	if err != nil {
+		runLogger.Error("Failed to record retry of run", zap.Error(err))
+		return ctx, false
 	}
+
+	backoff := retryBackoff(r.ts.retryBackoff, next)
 	rlb := RunLogBase{
 		Task:            r.task,
 		RunID:           qr.RunID,
 		RunScheduledFor: qr.Now,
 		RequestedAt:     qr.RequestedAt,
+		Try:             try,
+	}
+	r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), fmt.Sprintf("Try %d of %d failed with retryable error: %v; retrying in %s", try, r.ts.retry, runErr, backoff))
+	runLogger.Info("Retrying run", zap.Uint32("try", next), zap.Duration("backoff", backoff))
+
+	t := time.NewTimer(backoff)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return ctx, true
+	case <-ctx.Done():
+		return ctx, false
+	}
+}
+
+// retry waits to try qr again after the given try failed with runErr.
+// The run's previous context must already have been cleared.
+// If retry returns true, the next try should execute with the returned context.
+// Otherwise the run is no longer registered as running;
+// canceled reports whether it was canceled while waiting, in which case it has also been finished.
+func (r *runner) retry(qr QueuedRun, try uint32, runErr error, runLogger *zap.Logger) (ctx context.Context, retry, canceled bool) {
+	ctx, ok := r.prepareRetry(qr, try, runErr, runLogger)
+	if ok {
+		return ctx, true, false
 	}
-	stats := rr.Statistics()
 
-	b, err := json.Marshal(stats)
-	if err == nil {
-		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), string(b))
+	r.clearRunning(qr.RunID)
+	if ctx.Err() == nil {
+		return ctx, false, false
 	}
-	r.updateRunState(qr, RunSuccess, runLogger)
-	runLogger.Info("Execution succeeded")
 
-	// Check again if there is a new run available, without returning to idle state.
+	// The run was canceled while waiting to retry.
+	_ = r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID)
+	r.updateRunState(qr, try, RunCanceled, runLogger)
 	r.startFromWorking(atomic.LoadInt64(r.ts.now))
+	return ctx, false, true
+}
+
+// retryBackoff returns the delay before the given try of a run,
+// doubling base for every try after the second.
+func retryBackoff(base time.Duration, try uint32) time.Duration {
+	if try < 2 {
+		return 0
+	}
+
+	d := base
+	for i := uint32(2); i < try; i++ {
+		d *= 2
+		if d >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return d
 }
 
-func (r *runner) updateRunState(qr QueuedRun, s RunStatus, runLogger *zap.Logger) {
+func (r *runner) updateRunState(qr QueuedRun, try uint32, s RunStatus, runLogger *zap.Logger) {
 	rlb := RunLogBase{
 		Task:            r.task,
 		RunID:           qr.RunID,
 		RunScheduledFor: qr.Now,
 		RequestedAt:     qr.RequestedAt,
+		Try:             try,
 	}
 
 	switch s {
@@ -616,6 +616,136 @@ func TestScheduler_RunFailureCleanup(t *testing.T) {
 	}
 }
 
+func TestScheduler_Retry(t *testing.T) {
+	t.Parallel()
+
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	rl := backend.NewInMemRunReaderWriter()
+	s := backend.NewScheduler(d, e, rl, 5, backend.WithLogger(zaptest.NewLogger(t)), backend.WithRetryBackoff(50*time.Millisecond))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	task := &backend.StoreTask{
+		ID:  platform.ID(1),
+		Org: 2,
+		Script: `option task = {name: "a task", every: 1s, retry: 3}
+from(bucket: "b") |> range(start: -1m)`,
+	}
+	meta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "@every 1s",
+		LatestCompleted: 5,
+	}
+
+	d.SetTaskMeta(task.ID, *meta)
+	if err := s.ClaimTask(task, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Tick(6)
+
+	// A run that is not retryable fails immediately.
+	promises, err := e.PollForNumberRunning(task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	promises[0].Finish(mock.NewRunResult(errors.New("invalid script"), false), nil)
+	if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	pollForRunStatus(t, rl, task.ID, task.Org, 1, 0, backend.RunFail.String())
+
+	// A retryable run is attempted up to three times.
+	s.Tick(7)
+	for try := 1; try <= 3; try++ {
+		promises, err := e.PollForNumberRunning(task.ID, 1)
+		if err != nil {
+			t.Fatalf("try %d: %v", try, err)
+		}
+		promises[0].Finish(mock.NewRunResult(errors.New("temporary failure"), true), nil)
+		if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+			t.Fatalf("try %d: %v", try, err)
+		}
+	}
+	pollForRunStatus(t, rl, task.ID, task.Org, 2, 1, backend.RunFail.String())
+
+	runs, err := rl.ListRuns(context.Background(), task.Org, platform.RunFilter{Task: task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runs[0].Try; got != 1 {
+		t.Fatalf("expected 1 try, got %d", got)
+	}
+	if got := runs[1].Try; got != 3 {
+		t.Fatalf("expected 3 tries, got %d", got)
+	}
+	for _, want := range []string{"Try 1 of 3 failed", "Try 2 of 3 failed"} {
+		if !strings.Contains(string(runs[1].Log), want) {
+			t.Fatalf("expected run log to contain %q, got %q", want, runs[1].Log)
+		}
+	}
+
+	// A retried run that succeeds is reported as a success.
+	s.Tick(8)
+	promises, err = e.PollForNumberRunning(task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	promises[0].Finish(mock.NewRunResult(errors.New("temporary failure"), true), nil)
+	if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	promises, err = e.PollForNumberRunning(task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	promises[0].Finish(mock.NewRunResult(nil, false), nil)
+	pollForRunStatus(t, rl, task.ID, task.Org, 3, 2, backend.RunSuccess.String())
+
+	// A run that could not begin because of a transient error is retried too.
+	e.FailNextCallToExecute(&platform.Error{Code: platform.EUnavailable, Msg: "storage unavailable"})
+	s.Tick(9)
+	promises, err = e.PollForNumberRunning(task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	promises[0].Finish(mock.NewRunResult(nil, false), nil)
+	pollForRunStatus(t, rl, task.ID, task.Org, 4, 3, backend.RunSuccess.String())
+
+	runs, err = rl.ListRuns(context.Background(), task.Org, platform.RunFilter{Task: task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runs[3].Try; got != 2 {
+		t.Fatalf("expected 2 tries, got %d", got)
+	}
+
+	// A run that could not begin because of an invalid script is not retried.
+	e.FailNextCallToExecute(&platform.Error{Code: platform.EInvalid, Msg: "bad script"})
+	s.Tick(10)
+	pollForRunStatus(t, rl, task.ID, task.Org, 5, 4, backend.RunFail.String())
+}
+
+func TestIsRetryableError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{err: nil, want: false},
+		{err: errors.New("compilation failed"), want: false},
+		{err: &platform.Error{Code: platform.EInvalid, Msg: "bad script"}, want: false},
+		{err: &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}, want: false},
+		{err: &platform.Error{Code: platform.EUnavailable, Msg: "storage unavailable"}, want: true},
+		{err: &platform.Error{Code: platform.ETooManyRequests, Msg: "too many requests"}, want: true},
+		{err: &platform.Error{Code: platform.EInternal, Msg: "failed to read shard"}, want: true},
+	} {
+		if got := backend.IsRetryableError(tt.err); got != tt.want {
+			t.Errorf("IsRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
 func TestScheduler_Metrics(t *testing.T) {
 	t.Parallel()
 
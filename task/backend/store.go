@@ -33,6 +33,9 @@ var (
 
 	// ErrRunNotFinished is returned when a retry is invalid due to the run not being finished yet.
 	ErrRunNotFinished = errors.New("run is still in progress")
+
+	// ErrTaskLeased is returned when acquiring the lease of a task whose lease is held by another owner and has not expired.
+	ErrTaskLeased = errors.New("task is leased by another owner")
 )
 
 type TaskStatus string
@@ -252,6 +255,10 @@ type Store interface {
 	// FinishRun removes runID from the list of running tasks and if its `now` is later then last completed update it.
 	FinishRun(ctx context.Context, taskID, runID platform.ID) error
 
+	// IncrementRunTry records another attempt of the currently running run with the given ID,
+	// returning the run's new try count.
+	IncrementRunTry(ctx context.Context, taskID, runID platform.ID) (uint32, error)
+
 	// ManuallyRunTimeRange enqueues a request to run the task with the given ID for all schedules no earlier than start and no later than end (Unix timestamps).
 	// requestedAt is the Unix timestamp when the request was initiated.
 	// ManuallyRunTimeRange must delegate to an underlying StoreTaskMeta's ManuallyRunTimeRange method.
@@ -260,10 +267,49 @@ type Store interface {
 	// DeleteOrg deletes the org.
 	DeleteOrg(ctx context.Context, orgID platform.ID) error
 
+	// Heartbeat records that the owner is alive until the Unix timestamp expires.
+	// Owners whose heartbeat expired no later than the Unix timestamp now are forgotten,
+	// so an owner that stops can remove itself by passing an expires no later than now.
+	Heartbeat(ctx context.Context, owner platform.ID, now, expires int64) error
+
+	// ListOwners returns the IDs, in ascending order, of the owners whose heartbeat expires after the Unix timestamp now.
+	ListOwners(ctx context.Context, now int64) ([]platform.ID, error)
+
+	// AcquireTaskLease grants the owner the lease of the task until the Unix timestamp expires.
+	// A lease already held by the owner is renewed, and a lease that expired no later than the Unix timestamp now is taken over.
+	// If another owner holds a lease that has not expired, ErrTaskLeased is returned.
+	// If no task matches the ID, ErrTaskNotFound is returned.
+	AcquireTaskLease(ctx context.Context, taskID, owner platform.ID, now, expires int64) (TaskLease, error)
+
+	// ReleaseTaskLease releases the lease of the task if it is held by the owner.
+	// Releasing a lease that the owner does not hold is not an error.
+	ReleaseTaskLease(ctx context.Context, taskID, owner platform.ID) error
+
+	// ListTaskLeases returns the leases of all tasks, including expired leases, ordered by task ID.
+	ListTaskLeases(ctx context.Context) ([]TaskLease, error)
+
 	// Close closes the store for usage and cleans up running processes.
 	Close() error
 }
 
+// TaskLease records which owner, usually a scheduler in one process, is responsible for executing a task.
+// Leases allow several processes to share a Store without executing a task more than once.
+type TaskLease struct {
+	TaskID platform.ID
+
+	// The ID of the owner holding the lease.
+	Owner platform.ID
+
+	// Unix timestamp of when the lease expires, unless it is renewed.
+	// Once expired, the lease may be taken over by another owner.
+	Expires int64
+}
+
+// ExpiredAt reports whether the lease is expired at the Unix timestamp now.
+func (l TaskLease) ExpiredAt(now int64) bool {
+	return l.Expires <= now
+}
+
 // RunLogBase is the base information for a logs about an individual run.
 type RunLogBase struct {
 	// The parent task that owns the run.
@@ -277,6 +323,10 @@ type RunLogBase struct {
 
 	// When the log is requested, should be ignored when it is zero.
 	RequestedAt int64
+
+	// The number of the current attempt of the run, starting at 1.
+	// Should be ignored when it is zero.
+	Try uint32
 }
 
 // LogWriter writes task logs and task state changes to a store.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +30,10 @@ func NewRunStoreTest(name string, crf CreateRunStoreFunc, drf DestroyRunStoreFun
 				t.Parallel()
 				runLogTest(t, crf, drf)
 			})
+			t.Run("RunTries", func(t *testing.T) {
+				t.Parallel()
+				runTriesTest(t, crf, drf)
+			})
 			t.Run("ListRuns", func(t *testing.T) {
 				if testing.Short() {
 					t.Skip("Skipping test in short mode.")
@@ -106,6 +111,66 @@ func updateRunState(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFun
 	}
 }
 
+func runTriesTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc) {
+	writer, reader := crf(t)
+	defer drf(t, writer, reader)
+
+	now := time.Now().UTC()
+
+	task := &backend.StoreTask{
+		ID:  platformtesting.MustIDBase16("ab01ab01ab01ab01"),
+		Org: platformtesting.MustIDBase16("ab01ab01ab01ab05"),
+	}
+	scheduledFor := now.Add(-3 * time.Second)
+	startAt := now.Add(-2 * time.Second)
+	endAt := now.Add(-1 * time.Second)
+	run := platform.Run{
+		ID:           platformtesting.MustIDBase16("2c20766972747573"),
+		TaskID:       task.ID,
+		Status:       "success",
+		ScheduledFor: scheduledFor.Format(time.RFC3339),
+		StartedAt:    startAt.Format(time.RFC3339Nano),
+		FinishedAt:   endAt.Format(time.RFC3339Nano),
+		Try:          2,
+	}
+	rlb := backend.RunLogBase{
+		Task:            task,
+		RunID:           run.ID,
+		RunScheduledFor: scheduledFor.Unix(),
+		Try:             1,
+	}
+
+	ctx := pcontext.SetAuthorizer(context.Background(), makeNewAuthorization())
+
+	if err := writer.UpdateRunState(ctx, rlb, startAt, backend.RunStarted); err != nil {
+		t.Fatal(err)
+	}
+
+	// The run succeeds on its second try.
+	rlb.Try = 2
+	if err := writer.UpdateRunState(ctx, rlb, endAt, backend.RunSuccess); err != nil {
+		t.Fatal(err)
+	}
+
+	returnedRun, err := reader.FindRunByID(ctx, task.Org, run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(run, *returnedRun); diff != "" {
+		t.Fatalf("unexpected run found: -want/+got: %s", diff)
+	}
+
+	runs, err := reader.ListRuns(ctx, task.Org, platform.RunFilter{Task: task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]*platform.Run{&run}, runs); diff != "" {
+		t.Fatalf("unexpected runs found: -want/+got: %s", diff)
+	}
+}
+
 func runLogTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc) {
 	writer, reader := crf(t)
 	defer drf(t, writer, reader)
@@ -202,6 +267,14 @@ func listRunsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 		if err != nil {
 			t.Fatal(err)
 		}
+
+		// Every tenth run has finished.
+		if i%10 == 0 {
+			if err := writer.UpdateRunState(ctx, rlb, scheduledFor.Add(1500*time.Millisecond), backend.RunSuccess); err != nil {
+				t.Fatal(err)
+			}
+			runs[i].Status = "success"
+		}
 	}
 
 	if _, err := reader.ListRuns(ctx, task.Org, platform.RunFilter{}); err == nil {
@@ -277,6 +350,24 @@ func listRunsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 	if len(listRuns) != beforeTimeIdx {
 		t.Fatalf("retrieved: %d, expected: %d", len(listRuns), beforeTimeIdx)
 	}
+
+	listRuns, err = reader.ListRuns(ctx, task.Org, platform.RunFilter{
+		Task:   task.ID,
+		Status: "success",
+		Limit:  2 * nRuns,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listRuns) != nRuns/10 {
+		t.Fatalf("retrieved: %d, expected: %d", len(listRuns), nRuns/10)
+	}
+	for _, r := range listRuns {
+		if r.Status != "success" {
+			t.Fatalf("retrieved run %s with status %q, expected %q", r.ID, r.Status, "success")
+		}
+	}
 }
 
 func findRunByIDTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc) {
@@ -403,6 +494,45 @@ func listLogsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 	if len(logs) != len(runs) {
 		t.Fatal("not all logs retrieved")
 	}
+
+	logs, err = reader.ListLogs(ctx, task.Org, platform.LogFilter{
+		Task:  task.ID,
+		After: &runs[targetRun].ID,
+		Limit: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 5 {
+		t.Fatalf("expected 5 logs, got %d", len(logs))
+	}
+	if !strings.HasSuffix(string(logs[0]), ": log5") {
+		t.Fatalf("expected the first log after run %d to be log5, got %q", targetRun, logs[0])
+	}
+
+	// The log of each run is written a couple of milliseconds after it is
+	// scheduled, so count the logs that are within the range of whole seconds.
+	afterTime := now.Add(-15 * time.Second).Truncate(time.Second)
+	beforeTime := afterTime.Add(5 * time.Second)
+	var inRange int
+	for i := range runs {
+		when := now.Add(time.Duration(i-nRuns)*time.Second + 2*time.Millisecond)
+		if !when.Before(afterTime) && when.Before(beforeTime) {
+			inRange++
+		}
+	}
+
+	logs, err = reader.ListLogs(ctx, task.Org, platform.LogFilter{
+		Task:       task.ID,
+		AfterTime:  afterTime.Format(time.RFC3339),
+		BeforeTime: beforeTime.Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != inRange {
+		t.Fatalf("expected %d logs within the time range, got %d", inRange, len(logs))
+	}
 }
 
 func makeNewAuthorization() *platform.Authorization {
@@ -33,7 +33,10 @@ func NewStoreTest(name string, cf CreateStoreFunc, df DestroyStoreFunc, funcName
 			"DeleteTask",
 			"CreateNextRun",
 			"FinishRun",
+			"IncrementRunTry",
 			"ManuallyRunTimeRange",
+			"Heartbeat",
+			"TaskLease",
 		}
 	}
 	availableFuncs := map[string]TestFunc{
@@ -46,8 +49,11 @@ func NewStoreTest(name string, cf CreateStoreFunc, df DestroyStoreFunc, funcName
 		"DeleteTask":           testStoreDelete,
 		"CreateNextRun":        testStoreCreateNextRun,
 		"FinishRun":            testStoreFinishRun,
+		"IncrementRunTry":      testStoreIncrementRunTry,
 		"ManuallyRunTimeRange": testStoreManuallyRunTimeRange,
 		"DeleteOrg":            testStoreDeleteOrg,
+		"Heartbeat":            testStoreHeartbeat,
+		"TaskLease":            testStoreTaskLease,
 	}
 
 	return func(t *testing.T) {
@@ -936,6 +942,52 @@ from(bucket:"test") |> range(start:-1h)`
 	}
 }
 
+func testStoreIncrementRunTry(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+		retry: 3,
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+	s := create(t)
+	defer destroy(t, s)
+
+	task, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := s.CreateNextRun(context.Background(), task, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	try, err := s.IncrementRunTry(context.Background(), task, rc.Created.RunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if try != 2 {
+		t.Fatalf("expected try 2, got %d", try)
+	}
+
+	meta, err := s.FindTaskMetaByID(context.Background(), task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := meta.CurrentlyRunning[0].Try; got != 2 {
+		t.Fatalf("expected stored try 2, got %d", got)
+	}
+
+	if err := s.FinishRun(context.Background(), task, rc.Created.RunID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.IncrementRunTry(context.Background(), task, rc.Created.RunID); err == nil {
+		t.Fatal("expected failure when retrying run that doesnt exist")
+	}
+}
+
 func testStoreManuallyRunTimeRange(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
 	const script = `option task = {
 		name: "a task",
@@ -997,6 +1049,145 @@ func testStoreDeleteOrg(t *testing.T, create CreateStoreFunc, destroy DestroySto
 	}
 }
 
+func testStoreHeartbeat(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	s := create(t)
+	defer destroy(t, s)
+
+	ctx := context.Background()
+	owner1, owner2 := platform.ID(1), platform.ID(2)
+	if err := s.Heartbeat(ctx, owner2, 100, 130); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Heartbeat(ctx, owner1, 100, 110); err != nil {
+		t.Fatal(err)
+	}
+
+	owners, err := s.ListOwners(ctx, 105)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]platform.ID{owner1, owner2}, owners); diff != "" {
+		t.Fatalf("unexpected owners -want/+got:\n%s", diff)
+	}
+
+	// The heartbeat of owner1 expires at 110.
+	owners, err = s.ListOwners(ctx, 110)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]platform.ID{owner2}, owners); diff != "" {
+		t.Fatalf("unexpected owners -want/+got:\n%s", diff)
+	}
+
+	// Renewing the heartbeat brings owner1 back.
+	if err := s.Heartbeat(ctx, owner1, 115, 140); err != nil {
+		t.Fatal(err)
+	}
+	owners, err = s.ListOwners(ctx, 135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]platform.ID{owner1}, owners); diff != "" {
+		t.Fatalf("unexpected owners -want/+got:\n%s", diff)
+	}
+
+	// A heartbeat expiring immediately removes the owner.
+	if err := s.Heartbeat(ctx, owner1, 135, 135); err != nil {
+		t.Fatal(err)
+	}
+	owners, err = s.ListOwners(ctx, 135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 0 {
+		t.Fatalf("expected no owners, got %v", owners)
+	}
+}
+
+func testStoreTaskLease(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+
+	s := create(t)
+	defer destroy(t, s)
+
+	ctx := context.Background()
+	owner1, owner2 := idGen.ID(), idGen.ID()
+	id, err := s.CreateTask(ctx, backend.CreateTaskRequest{Org: 1, AuthorizationID: 2, Script: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.AcquireTaskLease(ctx, idGen.ID(), owner1, 100, 110); err != backend.ErrTaskNotFound {
+		t.Fatalf("expected ErrTaskNotFound for a nonexistent task, got %v", err)
+	}
+
+	l, err := s.AcquireTaskLease(ctx, id, owner1, 100, 110)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (backend.TaskLease{TaskID: id, Owner: owner1, Expires: 110}); l != want {
+		t.Fatalf("unexpected lease: got %+v, want %+v", l, want)
+	}
+
+	// Another owner cannot take over a lease that has not expired.
+	if _, err := s.AcquireTaskLease(ctx, id, owner2, 105, 115); err != backend.ErrTaskLeased {
+		t.Fatalf("expected ErrTaskLeased, got %v", err)
+	}
+
+	// The owner renews its own lease.
+	if _, err := s.AcquireTaskLease(ctx, id, owner1, 105, 120); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AcquireTaskLease(ctx, id, owner2, 115, 125); err != backend.ErrTaskLeased {
+		t.Fatalf("expected ErrTaskLeased after renewal, got %v", err)
+	}
+
+	// Once expired, another owner takes over the lease.
+	if _, err := s.AcquireTaskLease(ctx, id, owner2, 120, 130); err != nil {
+		t.Fatalf("expected expired lease to be taken over, got %v", err)
+	}
+	leases, err := s.ListTaskLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]backend.TaskLease{{TaskID: id, Owner: owner2, Expires: 130}}, leases); diff != "" {
+		t.Fatalf("unexpected leases -want/+got:\n%s", diff)
+	}
+
+	// Releasing a lease held by another owner does nothing.
+	if err := s.ReleaseTaskLease(ctx, id, owner1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AcquireTaskLease(ctx, id, owner1, 125, 135); err != backend.ErrTaskLeased {
+		t.Fatalf("expected ErrTaskLeased after release by non-owner, got %v", err)
+	}
+
+	// Once released, the lease is free to acquire.
+	if err := s.ReleaseTaskLease(ctx, id, owner2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AcquireTaskLease(ctx, id, owner1, 125, 135); err != nil {
+		t.Fatalf("expected released lease to be acquired, got %v", err)
+	}
+
+	// Deleting the task deletes its lease.
+	if _, err := s.DeleteTask(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	leases, err = s.ListTaskLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 0 {
+		t.Fatalf("expected lease of deleted task to be deleted, got %v", leases)
+	}
+}
+
 func createABunchOFTasks(t *testing.T, s backend.Store, filter func(org uint64) bool) []platform.ID {
 	const script = `option task = {
 		name: "a task",
@@ -0,0 +1,74 @@
+package backend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// SystemBucketService finds the task system bucket of an organization, which
+// the runs and logs of its tasks are written to. The bucket is a regular
+// bucket named platform.TasksSystemBucketName, so that it can be queried and
+// its retention changed like any other bucket.
+type SystemBucketService struct {
+	bucketService platform.BucketService
+	retention     time.Duration
+
+	// mu serializes the creation of buckets, so that concurrent runs of an
+	// organization do not try to create its bucket twice.
+	mu sync.Mutex
+}
+
+// NewSystemBucketService returns a SystemBucketService that creates missing
+// buckets with the retention period.
+func NewSystemBucketService(bs platform.BucketService, retention time.Duration) *SystemBucketService {
+	return &SystemBucketService{
+		bucketService: bs,
+		retention:     retention,
+	}
+}
+
+// FindSystemBucket returns the task system bucket of the organization, or
+// nil if it does not exist yet.
+func (s *SystemBucketService) FindSystemBucket(ctx context.Context, orgID platform.ID) (*platform.Bucket, error) {
+	name := platform.TasksSystemBucketName
+	b, err := s.bucketService.FindBucket(ctx, platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &name,
+	})
+	if err != nil {
+		if platform.ErrorCode(err) == platform.ENotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// FindOrCreateSystemBucket returns the task system bucket of the
+// organization, and creates it when it does not exist yet.
+func (s *SystemBucketService) FindOrCreateSystemBucket(ctx context.Context, orgID platform.ID) (*platform.Bucket, error) {
+	if b, err := s.FindSystemBucket(ctx, orgID); err != nil || b != nil {
+		return b, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another run may have created the bucket while we waited.
+	if b, err := s.FindSystemBucket(ctx, orgID); err != nil || b != nil {
+		return b, err
+	}
+
+	b := &platform.Bucket{
+		OrganizationID:  orgID,
+		Name:            platform.TasksSystemBucketName,
+		RetentionPeriod: s.retention,
+	}
+	if err := s.bucketService.CreateBucket(ctx, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
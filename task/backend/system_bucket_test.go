@@ -0,0 +1,43 @@
+package backend_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+func TestSystemBucketService(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	org := &platform.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	sbs := backend.NewSystemBucketService(svc, 72*time.Hour)
+
+	if b, err := sbs.FindSystemBucket(ctx, org.ID); err != nil {
+		t.Fatal(err)
+	} else if b != nil {
+		t.Fatalf("expected no system bucket before the first run, got %v", b)
+	}
+
+	created, err := sbs.FindOrCreateSystemBucket(ctx, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != platform.TasksSystemBucketName || created.OrganizationID != org.ID || created.RetentionPeriod != 72*time.Hour {
+		t.Fatalf("unexpected system bucket: %+v", created)
+	}
+
+	found, err := sbs.FindOrCreateSystemBucket(ctx, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != created.ID {
+		t.Fatalf("expected the system bucket %s to be found again, got %s", created.ID, found.ID)
+	}
+}
@@ -249,6 +249,20 @@ func (d *DesiredState) FinishRun(_ context.Context, taskID, runID platform.ID) e
 	return nil
 }
 
+func (d *DesiredState) IncrementRunTry(_ context.Context, taskID, runID platform.ID) (uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tid := taskID.String()
+	m := d.meta[tid]
+	try, ok := m.IncrementRunTry(runID)
+	if !ok {
+		return 0, fmt.Errorf("unknown run ID %s", runID)
+	}
+	d.meta[tid] = m
+	return try, nil
+}
+
 func (d *DesiredState) CreatedFor(taskID platform.ID) []backend.QueuedRun {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -332,7 +346,10 @@ func (e *Executor) Execute(ctx context.Context, run backend.QueuedRun) (backend.
 		defer e.wg.Done()
 		res, _ := rp.Wait()
 		e.mu.Lock()
-		delete(e.running, id)
+		// A retried run may already be executing under the same ID.
+		if e.running[id] == rp {
+			delete(e.running, id)
+		}
 		e.finished[id] = res
 		e.mu.Unlock()
 	}()
@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/influxdata/influxdb/telegraf/plugins"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
@@ -69,11 +74,69 @@ type TelegrafConfig struct {
 	Plugins []TelegrafPlugin
 }
 
-// TOML returns the telegraf toml config string.
+// telegrafSecretRefRegexp matches the secret references of plugin fields.
+var telegrafSecretRefRegexp = regexp.MustCompile(`\$\{secret:([^}]+)\}`)
+
+// TelegrafSecretRef returns the reference to the organization secret with the
+// key k. A plugin field set to the reference is rendered as the secret value
+// or as an environment variable placeholder, and the value is never stored
+// with the telegraf config.
+func TelegrafSecretRef(k string) string {
+	return "${secret:" + k + "}"
+}
+
+// TelegrafSecretEnvVar returns the environment variable that telegraf reads
+// the organization secret with the key k from, when the config is rendered
+// without the secret value.
+func TelegrafSecretEnvVar(k string) string {
+	return "INFLUX_SECRET_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, k)
+}
+
+// SecretKeys returns the sorted keys of the secrets referenced by the plugins.
+func (tc TelegrafConfig) SecretKeys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, p := range tc.Plugins {
+		for _, m := range telegrafSecretRefRegexp.FindAllStringSubmatch(p.Config.TOML(), -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				keys = append(keys, m[1])
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TOML returns the telegraf toml config string. Secret references are
+// rendered as environment variable placeholders, which telegraf replaces with
+// the values of the environment variables when it loads the config.
 func (tc TelegrafConfig) TOML() string {
+	return tc.TOMLWithSecrets(nil)
+}
+
+// TOMLWithSecrets returns the telegraf toml config string with the values of
+// the referenced secrets. References to secrets missing from secrets are
+// rendered as environment variable placeholders.
+func (tc TelegrafConfig) TOMLWithSecrets(secrets map[string]string) string {
 	plugins := ""
 	for _, p := range tc.Plugins {
-		plugins += p.Config.TOML()
+		plugins += telegrafSecretRefRegexp.ReplaceAllStringFunc(p.Config.TOML(), func(ref string) string {
+			k := telegrafSecretRefRegexp.FindStringSubmatch(ref)[1]
+			if v, ok := secrets[k]; ok {
+				return escapeTOMLString(v)
+			}
+			return "${" + TelegrafSecretEnvVar(k) + "}"
+		})
 	}
 	interval := time.Duration(tc.Agent.Interval * 1000000)
 	return fmt.Sprintf(`# Configuration for telegraf agent
@@ -133,6 +196,33 @@ func (tc TelegrafConfig) TOML() string {
 %s`, interval.String(), plugins)
 }
 
+// escapeTOMLString escapes s to be the content of a toml basic string, as the
+// plugins render their string fields.
+func escapeTOMLString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 // telegrafConfigEncode is the helper struct for json encoding.
 type telegrafConfigEncode struct {
 	ID             ID     `json:"id"`
@@ -258,7 +348,13 @@ func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
 				}
 				continue
 			}
-			for _, configData := range configDataArray.([]map[string]interface{}) {
+			configDatas, ok := configDataArray.([]map[string]interface{})
+			if !ok {
+				return &Error{
+					Msg: fmt.Sprintf("bad config for %s plugin %s", tp, name),
+				}
+			}
+			for _, configData := range configDatas {
 				if err := tc.parseTOMLPluginConfig(tp, name, configData); err != nil {
 					return err
 				}
@@ -270,27 +366,18 @@ func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
 }
 
 func (tc *TelegrafConfig) parseTOMLPluginConfig(typ, name string, configData interface{}) error {
-	var ok bool
-	var tpFn func() plugins.Config
-	switch typ {
-	case "inputs":
-		tpFn, ok = availableInputPlugins[name]
-	case "outputs":
-		tpFn, ok = availableOutputPlugins[name]
-	default:
-		return &Error{
-			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginType, typ),
-		}
-	}
-
+	pt, ok := telegrafPluginTypes[typ]
 	if !ok {
 		return &Error{
-			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginName, name, typ),
+			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginType, typ),
 		}
 	}
-	p := tpFn()
 
-	if err := p.UnmarshalTOML(configData); err != nil {
+	table, _ := configData.(map[string]interface{})
+	p, err := newTelegrafPlugin(pt, name, table, func(p plugins.Config) error {
+		return p.UnmarshalTOML(configData)
+	})
+	if err != nil {
 		return err
 	}
 	tc.Plugins = append(tc.Plugins, TelegrafPlugin{
@@ -319,14 +406,8 @@ func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 func decodePluginRaw(tcd *telegrafConfigDecode, tc *TelegrafConfig) (err error) {
 	op := "unmarshal telegraf config raw plugin"
 	for k, pr := range tcd.Plugins {
-		var tpFn func() plugins.Config
-		var config plugins.Config
-		var ok bool
 		switch pr.Type {
-		case plugins.Input:
-			tpFn, ok = availableInputPlugins[pr.Name]
-		case plugins.Output:
-			tpFn, ok = availableOutputPlugins[pr.Name]
+		case plugins.Input, plugins.Output, plugins.Processor, plugins.Aggregator:
 		default:
 			return &Error{
 				Code: EInvalid,
@@ -334,29 +415,208 @@ func decodePluginRaw(tcd *telegrafConfigDecode, tc *TelegrafConfig) (err error)
 				Op:   op,
 			}
 		}
-		if ok {
-			config = tpFn()
-			if err = json.Unmarshal(pr.Config, config); err != nil {
+
+		g := &plugins.Generic{PluginType: pr.Type, Name: pr.Name}
+		if len(pr.Config) > 0 && string(pr.Config) != "null" {
+			if err := json.Unmarshal(pr.Config, g); err != nil {
 				return &Error{
 					Code: EInvalid,
 					Err:  err,
 					Op:   op,
 				}
 			}
-			tc.Plugins[k] = TelegrafPlugin{
-				Comment: pr.Comment,
-				Config:  config,
+		}
+		config, err := newTelegrafPlugin(pr.Type, pr.Name, g.Table, func(p plugins.Config) error {
+			return json.Unmarshal(pr.Config, p)
+		})
+		if err != nil {
+			return &Error{
+				Code: EInvalid,
+				Err:  err,
+				Op:   op,
 			}
+		}
+		tc.Plugins[k] = TelegrafPlugin{
+			Comment: pr.Comment,
+			Config:  config,
+		}
+	}
+	return nil
+}
+
+// telegrafPluginTypes are the plugin types by the name of their toml tables.
+var telegrafPluginTypes = map[string]plugins.Type{
+	"inputs":      plugins.Input,
+	"outputs":     plugins.Output,
+	"processors":  plugins.Processor,
+	"aggregators": plugins.Aggregator,
+}
+
+// newTelegrafPlugin returns the config of the plugin with the settings of the
+// table. A known plugin is validated by decoding the settings into its
+// dedicated config with decode. The dedicated config is returned only if it
+// keeps every setting of the table, otherwise the plugin is returned as a
+// generic plugin, as are the plugins without a dedicated config.
+func newTelegrafPlugin(typ plugins.Type, name string, table map[string]interface{}, decode func(plugins.Config) error) (plugins.Config, error) {
+	if newConfig, ok := knownTelegrafPlugin(typ, name); ok {
+		p := newConfig()
+		if err := decode(p); err != nil {
+			return nil, err
+		}
+		if keepsTelegrafSettings(p, table) {
+			return p, nil
+		}
+	}
+	return plugins.NewGeneric(typ, name, table)
+}
+
+// keepsTelegrafSettings reports whether the dedicated config of a plugin keeps
+// the settings of the table. A setting is kept if it is a field of the config,
+// or if the config renders it with the same value.
+func keepsTelegrafSettings(p plugins.Config, table map[string]interface{}) bool {
+	fields := make(map[string]bool)
+	for _, f := range telegrafPluginFields(reflect.TypeOf(p)) {
+		fields[f.Name] = true
+	}
+
+	var rendered map[string]interface{}
+	var tables map[string]map[string][]map[string]interface{}
+	if _, err := toml.Decode(p.TOML(), &tables); err == nil {
+		if ts := tables[string(p.Type())+"s"][p.PluginName()]; len(ts) == 1 {
+			rendered = ts[0]
+		}
+	}
+
+	for k, v := range table {
+		if fields[k] {
 			continue
 		}
-		return &Error{
-			Code: EInvalid,
-			Op:   op,
-			Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, pr.Name, pr.Type),
+		if rv, ok := rendered[k]; !ok || !reflect.DeepEqual(rv, v) {
+			return false
 		}
+	}
+	return true
+}
 
+// knownTelegrafPlugin returns the constructor of the dedicated config of the
+// plugin, or false if the plugin has none.
+func knownTelegrafPlugin(typ plugins.Type, name string) (func() plugins.Config, bool) {
+	switch typ {
+	case plugins.Input:
+		fn, ok := availableInputPlugins[name]
+		return fn, ok
+	case plugins.Output:
+		fn, ok := availableOutputPlugins[name]
+		return fn, ok
+	}
+	return nil, false
+}
+
+// TelegrafPluginInfo describes a telegraf plugin with a dedicated config.
+type TelegrafPluginInfo struct {
+	Type   plugins.Type          `json:"type"`
+	Name   string                `json:"name"`
+	Fields []TelegrafPluginField `json:"fields"`
+}
+
+// TelegrafPluginField describes a setting of a telegraf plugin. Its type is
+// one of string, boolean, integer, number, array or object. Items is the type
+// of the elements of an array, and Fields are the settings of an object or of
+// the objects of an array.
+type TelegrafPluginField struct {
+	Name   string                `json:"name"`
+	Type   string                `json:"type"`
+	Items  string                `json:"items,omitempty"`
+	Fields []TelegrafPluginField `json:"fields,omitempty"`
+}
+
+// TelegrafPlugins returns the telegraf plugins with a dedicated config, sorted
+// by type and name. Other plugins are kept as they are configured.
+func TelegrafPlugins() []TelegrafPluginInfo {
+	var infos []TelegrafPluginInfo
+	for _, available := range []map[string](func() plugins.Config){
+		availableInputPlugins,
+		availableOutputPlugins,
+	} {
+		for name, newConfig := range available {
+			p := newConfig()
+			infos = append(infos, TelegrafPluginInfo{
+				Type:   p.Type(),
+				Name:   name,
+				Fields: telegrafPluginFields(reflect.TypeOf(p)),
+			})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Type != infos[j].Type {
+			return infos[i].Type < infos[j].Type
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// telegrafPluginFields returns the settings of the config of type t, which are
+// its exported fields by their json names.
+func telegrafPluginFields(t reflect.Type) []TelegrafPluginField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := []TelegrafPluginField{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous || sf.PkgPath != "" {
+			continue
+		}
+		name := sf.Name
+		if tag := strings.Split(sf.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		f := TelegrafPluginField{
+			Name: name,
+			Type: telegrafPluginFieldType(sf.Type),
+		}
+		switch f.Type {
+		case "array":
+			f.Items = telegrafPluginFieldType(sf.Type.Elem())
+			if f.Items == "object" {
+				f.Fields = telegrafPluginFields(sf.Type.Elem())
+			}
+		case "object":
+			f.Fields = telegrafPluginFields(sf.Type)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// telegrafPluginFieldType returns the type of the setting of type t.
+func telegrafPluginFieldType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return "string"
 	}
-	return nil
 }
 
 var availableInputPlugins = map[string](func() plugins.Config){
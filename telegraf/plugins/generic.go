@@ -0,0 +1,260 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// validName matches the names of telegraf plugins.
+	validName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	// bareKey matches the toml keys that need no quotes.
+	bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// Generic is a telegraf plugin of any type and name. It keeps the toml table
+// of the plugin as is, so that plugins without a dedicated config, and known
+// plugins with settings their config does not have, are rendered without loss.
+type Generic struct {
+	PluginType Type
+	Name       string
+	// Table is the toml table of the plugin, with the values decoded by
+	// toml: strings, int64, float64, bool, time.Time, []interface{},
+	// map[string]interface{} and []map[string]interface{}.
+	Table map[string]interface{}
+}
+
+// NewGeneric returns the generic plugin of the type and name with the table.
+func NewGeneric(typ Type, name string, table map[string]interface{}) (*Generic, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid telegraf plugin name %q", name)
+	}
+	g := &Generic{PluginType: typ, Name: name}
+	if err := g.UnmarshalTOML(table); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Type is the plugin type.
+func (g *Generic) Type() Type {
+	return g.PluginType
+}
+
+// PluginName is the telegraf plugin name.
+func (g *Generic) PluginName() string {
+	return g.Name
+}
+
+// TOML encodes to toml string.
+func (g *Generic) TOML() string {
+	var buf bytes.Buffer
+	writeTable(&buf, fmt.Sprintf("%ss.%s", g.PluginType, g.Name), g.table(), true, 0)
+	return buf.String()
+}
+
+// table returns the table of the plugin, which is empty rather than nil.
+func (g *Generic) table() map[string]interface{} {
+	if g.Table == nil {
+		return map[string]interface{}{}
+	}
+	return g.Table
+}
+
+// UnmarshalTOML decodes the parsed data to the object.
+func (g *Generic) UnmarshalTOML(data interface{}) error {
+	if data == nil {
+		g.Table = nil
+		return nil
+	}
+	table, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("bad config for %s plugin %s", g.PluginType, g.Name)
+	}
+	g.Table = table
+	return nil
+}
+
+// MarshalJSON encodes the table of the plugin.
+func (g *Generic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.table())
+}
+
+// UnmarshalJSON decodes the table of the plugin. Numbers without a fraction
+// or exponent are decoded as integers, and arrays of objects as arrays of
+// tables, as toml decodes them.
+func (g *Generic) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var table map[string]interface{}
+	if err := dec.Decode(&table); err != nil {
+		return err
+	}
+	for k, v := range table {
+		table[k] = fromJSON(v)
+	}
+	g.Table = table
+	return nil
+}
+
+// fromJSON converts a JSON value to the value toml decodes.
+func fromJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = fromJSON(e)
+		}
+		return v
+	case []interface{}:
+		tables := make([]map[string]interface{}, 0, len(v))
+		for i, e := range v {
+			v[i] = fromJSON(e)
+			if t, ok := v[i].(map[string]interface{}); ok {
+				tables = append(tables, t)
+			}
+		}
+		if len(v) > 0 && len(tables) == len(v) {
+			return tables
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// writeTable writes the table with the name, its values first and then its
+// sub-tables and arrays of tables, with the keys in order.
+func writeTable(buf *bytes.Buffer, name string, table map[string]interface{}, array bool, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if array {
+		fmt.Fprintf(buf, "%s[[%s]]\n", indent, name)
+	} else {
+		fmt.Fprintf(buf, "%s[%s]\n", indent, name)
+	}
+
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var tables []string
+	for _, k := range keys {
+		switch table[k].(type) {
+		case nil:
+			// A JSON null is an unset setting.
+			continue
+		case map[string]interface{}, []map[string]interface{}:
+			tables = append(tables, k)
+			continue
+		}
+		fmt.Fprintf(buf, "%s  %s = %s\n", indent, encodeKey(k), encodeValue(table[k]))
+	}
+
+	for _, k := range tables {
+		switch v := table[k].(type) {
+		case map[string]interface{}:
+			writeTable(buf, name+"."+encodeKey(k), v, false, depth+1)
+		case []map[string]interface{}:
+			for _, t := range v {
+				writeTable(buf, name+"."+encodeKey(k), t, true, depth+1)
+			}
+		}
+	}
+}
+
+// encodeKey encodes a toml key, quoting it if needed.
+func encodeKey(k string) string {
+	if bareKey.MatchString(k) {
+		return k
+	}
+	return encodeString(k)
+}
+
+// encodeValue encodes a toml value. Tables in arrays are encoded as inline
+// tables.
+func encodeValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return encodeString(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			// Keep the value a float.
+			s += ".0"
+		}
+		return s
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = encodeValue(e)
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case []map[string]interface{}:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = encodeValue(e)
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, len(keys))
+		for i, k := range keys {
+			values[i] = encodeKey(k) + " = " + encodeValue(v[k])
+		}
+		return "{" + strings.Join(values, ", ") + "}"
+	default:
+		// Other values only come from JSON.
+		return encodeString(fmt.Sprint(v))
+	}
+}
+
+// encodeString encodes a toml basic string.
+func encodeString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package plugins
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenericTOML(t *testing.T) {
+	g, err := NewGeneric(Processor, "rename", map[string]interface{}{
+		"order":   int64(1),
+		"enabled": true,
+		"ratio":   float64(2),
+		"my key":  "a \"quoted\"\nvalue",
+		"tags":    map[string]interface{}{"source": "rename"},
+		"replace": []map[string]interface{}{
+			{"tag": "hostname", "dest": "host"},
+		},
+		"unset": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[[processors.rename]]
+  enabled = true
+  "my key" = "a \"quoted\"\nvalue"
+  order = 1
+  ratio = 2.0
+  [[processors.rename.replace]]
+    dest = "host"
+    tag = "hostname"
+  [processors.rename.tags]
+    source = "rename"
+`
+	if got := g.TOML(); got != want {
+		t.Fatalf("unexpected toml: got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := NewGeneric(Input, "bad name", nil); err == nil {
+		t.Fatal("expected an error for an invalid plugin name")
+	}
+}
+
+func TestGenericJSON(t *testing.T) {
+	g := &Generic{PluginType: Aggregator, Name: "basicstats"}
+	if err := json.Unmarshal([]byte(`{"period":"30s","delta":0.5,"count":2,"stats":["min","max"],"replace":[{"tag":"t"}]}`), g); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"period":  "30s",
+		"delta":   float64(0.5),
+		"count":   int64(2),
+		"stats":   []interface{}{"min", "max"},
+		"replace": []map[string]interface{}{{"tag": "t"}},
+	}
+	if !reflect.DeepEqual(g.Table, want) {
+		t.Fatalf("unexpected table: got %#v, want %#v", g.Table, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/BurntSushi/toml"
@@ -46,7 +47,7 @@ func (u *unsupportedPluginType) PluginName() string {
 }
 
 func (u *unsupportedPluginType) Type() plugins.Type {
-	return plugins.Aggregator
+	return plugins.Type("bogus")
 }
 
 func (u *unsupportedPluginType) UnmarshalTOML(data interface{}) error {
@@ -152,6 +153,7 @@ func TestTelegrafConfigJSON(t *testing.T) {
 	cases := []struct {
 		name string
 		cfg  *TelegrafConfig
+		want *TelegrafConfig
 		err  error
 	}{
 		{
@@ -207,12 +209,12 @@ func TestTelegrafConfigJSON(t *testing.T) {
 			},
 			err: &Error{
 				Code: EInvalid,
-				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "aggregator"),
+				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "bogus"),
 				Op:   "unmarshal telegraf config raw plugin",
 			},
 		},
 		{
-			name: "unsupported plugin",
+			name: "plugin without config",
 			cfg: &TelegrafConfig{
 				ID:             *id1,
 				OrganizationID: *id2,
@@ -225,10 +227,39 @@ func TestTelegrafConfigJSON(t *testing.T) {
 					},
 				},
 			},
-			err: &Error{
-				Code: EInvalid,
-				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, "kafka", plugins.Output),
-				Op:   "unmarshal telegraf config raw plugin",
+			want: &TelegrafConfig{
+				ID:             *id1,
+				OrganizationID: *id2,
+				Name:           "n1",
+				Plugins: []TelegrafPlugin{
+					{
+						Config: &plugins.Generic{
+							PluginType: plugins.Output,
+							Name:       "kafka",
+							Table:      map[string]interface{}{"field": "f2"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "known plugin with other settings",
+			cfg: &TelegrafConfig{
+				ID:             *id1,
+				OrganizationID: *id2,
+				Name:           "n1",
+				Plugins: []TelegrafPlugin{
+					{
+						Config: &plugins.Generic{
+							PluginType: plugins.Input,
+							Name:       "file",
+							Table: map[string]interface{}{
+								"files":       []interface{}{"f1"},
+								"data_format": "json",
+							},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -244,7 +275,11 @@ func TestTelegrafConfigJSON(t *testing.T) {
 			t.Fatalf("%s decode failed, got err: %v, should be %v", c.name, err, c.err)
 		}
 
-		if diff := cmp.Diff(got, c.cfg, telegrafCmpOptions...); c.err == nil && diff != "" {
+		want := c.cfg
+		if c.want != nil {
+			want = c.want
+		}
+		if diff := cmp.Diff(got, want, telegrafCmpOptions...); c.err == nil && diff != "" {
 			t.Errorf("failed %s, telegraf configs are different -got/+want\ndiff %s", c.name, diff)
 		}
 	}
@@ -399,3 +434,156 @@ func TestTOML(t *testing.T) {
 		t.Fatalf("telegraf toml parsing issue, want %q, got %q", tc, tcr)
 	}
 }
+
+func TestTOMLRoundTrip(t *testing.T) {
+	config := `[agent]
+  interval = "10s"
+[[inputs.cpu]]
+  percpu = true
+  totalcpu = true
+  collect_cpu_time = false
+  report_active = false
+[[inputs.cpu]]
+  percpu = false
+[[inputs.exec]]
+  commands = ["/usr/bin/mycollector --foo=bar"]
+  timeout = "5s"
+  [inputs.exec.tags]
+    source = "exec"
+[[processors.rename]]
+  order = 1
+  [[processors.rename.replace]]
+    measurement = "network_interface_throughput"
+    dest = "throughput"
+  [[processors.rename.replace]]
+    tag = "hostname"
+    dest = "host"
+[[aggregators.basicstats]]
+  period = "30s"
+  drop_original = false
+  stats = ["count", "min", "max", "mean"]
+  delta = 0.5
+[[outputs.file]]
+  files = ["stdout"]
+  data_format = "json"
+`
+	tc := new(TelegrafConfig)
+	if _, err := toml.Decode(config, tc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tc.Plugins) != 6 {
+		t.Fatalf("unexpected number of plugins: got %d, want %d", len(tc.Plugins), 6)
+	}
+	var generics []string
+	for _, p := range tc.Plugins {
+		if _, ok := p.Config.(*plugins.Generic); ok {
+			generics = append(generics, string(p.Config.Type())+"s."+p.Config.PluginName())
+		}
+	}
+	sort.Strings(generics)
+	// The second cpu plugin and the file output have settings their configs
+	// do not keep.
+	want := []string{"aggregators.basicstats", "inputs.cpu", "inputs.exec", "outputs.file", "processors.rename"}
+	if !reflect.DeepEqual(generics, want) {
+		t.Fatalf("unexpected generic plugins: got %v, want %v", generics, want)
+	}
+
+	var got, expected map[string]interface{}
+	if _, err := toml.Decode(tc.TOML(), &got); err != nil {
+		t.Fatalf("config is not valid toml: %v\n%s", err, tc.TOML())
+	}
+	if _, err := toml.Decode(config, &expected); err != nil {
+		t.Fatal(err)
+	}
+	delete(got, "agent")
+	delete(expected, "agent")
+	// The order of the plugins of different names is not kept.
+	for _, tables := range []map[string]interface{}{got, expected} {
+		cpus := tables["inputs"].(map[string]interface{})["cpu"].([]map[string]interface{})
+		sort.Slice(cpus, func(i, j int) bool {
+			return fmt.Sprint(cpus[i]) < fmt.Sprint(cpus[j])
+		})
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Fatalf("plugins are different -got/+want\ndiff %s", diff)
+	}
+
+	if err := toml.Unmarshal([]byte("[agent]\n  interval = \"10s\"\n[[inputs.file]]\n  files = \"f1\"\n"), new(TelegrafConfig)); err == nil {
+		t.Fatal("expected an error for an invalid known plugin")
+	}
+	if err := toml.Unmarshal([]byte("[agent]\n  interval = \"10s\"\n[[bogus.file]]\n"), new(TelegrafConfig)); err == nil {
+		t.Fatal("expected an error for an unsupported plugin type")
+	}
+}
+
+func TestTOMLWithSecrets(t *testing.T) {
+	tc := &TelegrafConfig{
+		Plugins: []TelegrafPlugin{
+			{
+				Config: &inputs.Redis{
+					Servers:  []string{"tcp://localhost:6379"},
+					Password: TelegrafSecretRef("redis-password"),
+				},
+			},
+			{
+				Config: &outputs.InfluxDBV2{
+					URLs:         []string{"url1"},
+					Token:        TelegrafSecretRef("token"),
+					Organization: "org1",
+					Bucket:       "bucket1",
+				},
+			},
+		},
+	}
+
+	if got, want := tc.SecretKeys(), []string{"redis-password", "token"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected secret keys: got %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		secrets map[string]string
+		want    []string
+	}{
+		{
+			name: "placeholders",
+			want: []string{
+				`password = "${INFLUX_SECRET_REDIS_PASSWORD}"`,
+				`token = "${INFLUX_SECRET_TOKEN}"`,
+			},
+		},
+		{
+			name:    "resolved",
+			secrets: map[string]string{"redis-password": `pa"ss`, "token": "t0k3n"},
+			want: []string{
+				`password = "pa\"ss"`,
+				`token = "t0k3n"`,
+			},
+		},
+		{
+			name:    "missing secret",
+			secrets: map[string]string{"token": "t0k3n"},
+			want: []string{
+				`password = "${INFLUX_SECRET_REDIS_PASSWORD}"`,
+				`token = "t0k3n"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tc.TOMLWithSecrets(tt.secrets)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("config does not contain %s:\n%s", want, got)
+				}
+			}
+			if strings.Contains(got, "${secret:") {
+				t.Errorf("config contains a secret reference:\n%s", got)
+			}
+			if _, err := toml.Decode(got, &map[string]interface{}{}); err != nil {
+				t.Errorf("config is not valid toml: %v", err)
+			}
+		})
+	}
+}
@@ -35,7 +35,10 @@ var dbrpMappingCmpOptions = cmp.Options{
 			if out[i].Database != out[j].Database {
 				return out[i].Database < out[j].Database
 			}
-			return out[i].RetentionPolicy < out[j].RetentionPolicy
+			if out[i].RetentionPolicy != out[j].RetentionPolicy {
+				return out[i].RetentionPolicy < out[j].RetentionPolicy
+			}
+			return out[i].OrganizationID < out[j].OrganizationID
 		})
 		return out
 	}),
@@ -64,7 +67,7 @@ func CleanupDBRPMappings(ctx context.Context, s platform.DBRPMappingService) err
 	}
 
 	for _, m := range mappings {
-		if err := s.Delete(ctx, m.Cluster, m.Database, m.RetentionPolicy); err != nil {
+		if err := s.Delete(ctx, m.OrganizationID, m.Cluster, m.Database, m.RetentionPolicy); err != nil {
 			return errors.Wrapf(err, "failed to remove dbrp mapping %s/%s/%s", m.Cluster, m.Database, m.RetentionPolicy)
 		}
 	}
@@ -194,6 +197,49 @@ func CreateDBRPMapping(
 				},
 			},
 		},
+		{
+			name: "create the same dbrpMapping in another organization",
+			fields: DBRPMappingFields{
+				DBRPMappings: []*platform.DBRPMapping{{
+					Cluster:         "cluster1",
+					Database:        "database1",
+					RetentionPolicy: "retention_policy1",
+					Default:         true,
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket1ID),
+				}},
+			},
+			args: args{
+				dbrpMapping: &platform.DBRPMapping{
+					Cluster:         "cluster1",
+					Database:        "database1",
+					RetentionPolicy: "retention_policy1",
+					Default:         true,
+					OrganizationID:  MustIDBase16(dbrpOrg2ID),
+					BucketID:        MustIDBase16(dbrpBucket2ID),
+				},
+			},
+			wants: wants{
+				dbrpMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy1",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket1ID),
+					},
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy1",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg2ID),
+						BucketID:        MustIDBase16(dbrpBucket2ID),
+					},
+				},
+			},
+		},
 		{
 			name: "error on create existing dbrpMapping",
 			fields: DBRPMappingFields{
@@ -217,7 +263,10 @@ func CreateDBRPMapping(
 				},
 			},
 			wants: wants{
-				err: errors.New("dbrp mapping already exists"),
+				err: &platform.Error{
+					Code: platform.EConflict,
+					Msg:  platform.ErrDBRPMappingExists,
+				},
 				dbrpMappings: []*platform.DBRPMapping{
 					{
 						Cluster:         "cluster1",
@@ -243,9 +292,7 @@ func CreateDBRPMapping(
 			}
 
 			if err != nil && tt.wants.err != nil {
-				if err.Error() != tt.wants.err.Error() {
-					t.Fatalf("expected error messages to match '%v' got '%v'", tt.wants.err, err.Error())
-				}
+				ErrorsEqual(t, err, tt.wants.err)
 			}
 
 			dbrpMappings, _, err := s.FindMany(ctx, platform.DBRPMappingFilter{})
@@ -364,6 +411,48 @@ func FindDBRPMappings(
 				},
 			},
 		},
+		{
+			name: "find dbrpMappings by organization",
+			fields: DBRPMappingFields{
+				DBRPMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster",
+						Database:        "database",
+						RetentionPolicy: "retention_policy",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket1ID),
+					},
+					{
+						Cluster:         "cluster",
+						Database:        "database",
+						RetentionPolicy: "retention_policy",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg2ID),
+						BucketID:        MustIDBase16(dbrpBucket2ID),
+					},
+				},
+			},
+			args: args{
+				filter: platform.DBRPMappingFilter{
+					Cluster:        strPtr("cluster"),
+					Database:       strPtr("database"),
+					OrganizationID: idPtr(MustIDBase16(dbrpOrg2ID)),
+				},
+			},
+			wants: wants{
+				dbrpMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster",
+						Database:        "database",
+						RetentionPolicy: "retention_policy",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg2ID),
+						BucketID:        MustIDBase16(dbrpBucket2ID),
+					},
+				},
+			},
+		},
 		{
 			name: "find default rp from dbrpMappings",
 			fields: DBRPMappingFields{
@@ -420,9 +509,7 @@ func FindDBRPMappings(
 			}
 
 			if err != nil && tt.wants.err != nil {
-				if err.Error() != tt.wants.err.Error() {
-					t.Fatalf("expected error '%v' got '%v'", tt.wants.err, err)
-				}
+				ErrorsEqual(t, err, tt.wants.err)
 			}
 
 			if diff := cmp.Diff(dbrpMappings, tt.wants.dbrpMappings, dbrpMappingCmpOptions...); diff != "" {
@@ -438,6 +525,7 @@ func FindDBRPMappingByKey(
 	t *testing.T,
 ) {
 	type args struct {
+		OrganizationID platform.ID
 		Cluster,
 		Database,
 		RetentionPolicy string
@@ -477,6 +565,7 @@ func FindDBRPMappingByKey(
 				},
 			},
 			args: args{
+				OrganizationID:  MustIDBase16(dbrpOrg3ID),
 				Cluster:         "cluster",
 				Database:        "database",
 				RetentionPolicy: "retention_policyB",
@@ -507,12 +596,43 @@ func FindDBRPMappingByKey(
 				},
 			},
 			args: args{
+				OrganizationID:  MustIDBase16(dbrpOrg3ID),
 				Cluster:         "clusterX",
 				Database:        "database",
 				RetentionPolicy: "retention_policyA",
 			},
 			wants: wants{
-				err: errors.New("dbrp mapping not found"),
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Msg:  platform.ErrDBRPMappingNotFound,
+				},
+			},
+		},
+		{
+			name: "find dbrpMapping of another organization",
+			fields: DBRPMappingFields{
+				DBRPMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster",
+						Database:        "database",
+						RetentionPolicy: "retention_policyA",
+						Default:         false,
+						OrganizationID:  MustIDBase16(dbrpOrg3ID),
+						BucketID:        MustIDBase16(dbrpBucketAID),
+					},
+				},
+			},
+			args: args{
+				OrganizationID:  MustIDBase16(dbrpOrg1ID),
+				Cluster:         "cluster",
+				Database:        "database",
+				RetentionPolicy: "retention_policyA",
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Msg:  platform.ErrDBRPMappingNotFound,
+				},
 			},
 		},
 	}
@@ -523,15 +643,13 @@ func FindDBRPMappingByKey(
 			defer done()
 			ctx := context.Background()
 
-			dbrpMapping, err := s.FindBy(ctx, tt.args.Cluster, tt.args.Database, tt.args.RetentionPolicy)
+			dbrpMapping, err := s.FindBy(ctx, tt.args.OrganizationID, tt.args.Cluster, tt.args.Database, tt.args.RetentionPolicy)
 			if (err != nil) != (tt.wants.err != nil) {
 				t.Fatalf("expected error '%v' got '%v'", tt.wants.err, err)
 			}
 
 			if err != nil && tt.wants.err != nil {
-				if err.Error() != tt.wants.err.Error() {
-					t.Fatalf("expected error messages to match '%v' got '%v'", tt.wants.err, err.Error())
-				}
+				ErrorsEqual(t, err, tt.wants.err)
 			}
 
 			if diff := cmp.Diff(dbrpMapping, tt.wants.dbrpMapping, dbrpMappingCmpOptions...); diff != "" {
@@ -655,9 +773,7 @@ func FindDBRPMapping(
 			}
 
 			if err != nil && tt.wants.err != nil {
-				if err.Error() != tt.wants.err.Error() {
-					t.Fatalf("expected error messages to match '%v' got '%v'", tt.wants.err, err.Error())
-				}
+				ErrorsEqual(t, err, tt.wants.err)
 			}
 
 			if diff := cmp.Diff(dbrpMapping, tt.wants.dbrpMapping, dbrpMappingCmpOptions...); diff != "" {
@@ -673,6 +789,7 @@ func DeleteDBRPMapping(
 	t *testing.T,
 ) {
 	type args struct {
+		OrganizationID                     platform.ID
 		Cluster, Database, RetentionPolicy string
 	}
 	type wants struct {
@@ -709,6 +826,7 @@ func DeleteDBRPMapping(
 				},
 			},
 			args: args{
+				OrganizationID:  MustIDBase16(dbrpOrg1ID),
 				Cluster:         "cluster1",
 				Database:        "database1",
 				RetentionPolicy: "retention_policy1",
@@ -747,6 +865,7 @@ func DeleteDBRPMapping(
 				},
 			},
 			args: args{
+				OrganizationID:  MustIDBase16(dbrpOrg1ID),
 				Cluster:         "cluster3",
 				Database:        "db",
 				RetentionPolicy: "rp",
@@ -772,6 +891,39 @@ func DeleteDBRPMapping(
 				},
 			},
 		},
+		{
+			name: "delete dbrpMapping of another organization",
+			fields: DBRPMappingFields{
+				DBRPMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy1",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket1ID),
+					},
+				},
+			},
+			args: args{
+				OrganizationID:  MustIDBase16(dbrpOrg2ID),
+				Cluster:         "cluster1",
+				Database:        "database1",
+				RetentionPolicy: "retention_policy1",
+			},
+			wants: wants{
+				dbrpMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy1",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket1ID),
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -779,15 +931,13 @@ func DeleteDBRPMapping(
 			s, done := init(tt.fields, t)
 			defer done()
 			ctx := context.Background()
-			err := s.Delete(ctx, tt.args.Cluster, tt.args.Database, tt.args.RetentionPolicy)
+			err := s.Delete(ctx, tt.args.OrganizationID, tt.args.Cluster, tt.args.Database, tt.args.RetentionPolicy)
 			if (err != nil) != (tt.wants.err != nil) {
 				t.Fatalf("expected error '%v' got '%v'", tt.wants.err, err)
 			}
 
 			if err != nil && tt.wants.err != nil {
-				if err.Error() != tt.wants.err.Error() {
-					t.Fatalf("expected error messages to match '%v' got '%v'", tt.wants.err, err.Error())
-				}
+				ErrorsEqual(t, err, tt.wants.err)
 			}
 
 			filter := platform.DBRPMappingFilter{}
@@ -730,8 +730,9 @@ func UpdateOrganization(
 	t *testing.T,
 ) {
 	type args struct {
-		name string
-		id   platform.ID
+		name   string
+		id     platform.ID
+		quotas *platform.OrgQuotas
 	}
 	type wants struct {
 		err          error
@@ -795,6 +796,50 @@ func UpdateOrganization(
 				},
 			},
 		},
+		{
+			name: "update quotas",
+			fields: OrganizationFields{
+				Organizations: []*platform.Organization{
+					{
+						ID:   MustIDBase16(orgOneID),
+						Name: "organization1",
+					},
+				},
+			},
+			args: args{
+				id:     MustIDBase16(orgOneID),
+				quotas: &platform.OrgQuotas{ConcurrentQueries: 2, MaxBuckets: 10},
+			},
+			wants: wants{
+				organization: &platform.Organization{
+					ID:     MustIDBase16(orgOneID),
+					Name:   "organization1",
+					Quotas: &platform.OrgQuotas{ConcurrentQueries: 2, MaxBuckets: 10},
+				},
+			},
+		},
+		{
+			name: "remove quotas",
+			fields: OrganizationFields{
+				Organizations: []*platform.Organization{
+					{
+						ID:     MustIDBase16(orgOneID),
+						Name:   "organization1",
+						Quotas: &platform.OrgQuotas{MaxTasks: 5},
+					},
+				},
+			},
+			args: args{
+				id:     MustIDBase16(orgOneID),
+				quotas: &platform.OrgQuotas{},
+			},
+			wants: wants{
+				organization: &platform.Organization{
+					ID:   MustIDBase16(orgOneID),
+					Name: "organization1",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -807,6 +852,7 @@ func UpdateOrganization(
 			if tt.args.name != "" {
 				upd.Name = &tt.args.name
 			}
+			upd.Quotas = tt.args.quotas
 
 			organization, err := s.UpdateOrganization(ctx, tt.args.id, upd)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
@@ -96,6 +96,22 @@ func (s *Size) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// MarshalText converts a size to a string for encoding toml. The size uses
+// the largest suffix that represents it exactly.
+func (s Size) MarshalText() (text []byte, err error) {
+	switch {
+	case s == 0:
+		return []byte("0"), nil
+	case s%(1<<30) == 0:
+		return []byte(fmt.Sprintf("%dg", s>>30)), nil
+	case s%(1<<20) == 0:
+		return []byte(fmt.Sprintf("%dm", s>>20)), nil
+	case s%(1<<10) == 0:
+		return []byte(fmt.Sprintf("%dk", s>>10)), nil
+	}
+	return []byte(strconv.FormatUint(uint64(s), 10)), nil
+}
+
 type FileMode uint32
 
 func (m *FileMode) UnmarshalText(text []byte) error {
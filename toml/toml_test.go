@@ -61,6 +61,37 @@ func TestSize_UnmarshalText(t *testing.T) {
 	}
 }
 
+func TestSize_MarshalText(t *testing.T) {
+	for _, test := range []struct {
+		size itoml.Size
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{1000, "1000"},
+		{1 << 10, "1k"},
+		{1536, "1536"},
+		{25 << 20, "25m"},
+		{1 << 30, "1g"},
+		{(1 << 30) + (1 << 20), "1025m"},
+	} {
+		text, err := test.size.MarshalText()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := string(text); got != test.want {
+			t.Errorf("wanted: %s got: %s", test.want, got)
+		}
+
+		var s itoml.Size
+		if err := s.UnmarshalText(text); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if s != test.size {
+			t.Errorf("round trip of %s: wanted %d got %d", text, test.size, s)
+		}
+	}
+}
+
 func TestFileMode_MarshalText(t *testing.T) {
 	for _, test := range []struct {
 		mode int
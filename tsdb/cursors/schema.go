@@ -0,0 +1,97 @@
+package cursors
+
+// FieldType represents the primitive field data types available in tsm.
+type FieldType int
+
+const (
+	Float     FieldType = iota // means the data type is a float
+	Integer                    // means the data type is an integer
+	Unsigned                   // means the data type is an unsigned integer
+	String                     // means the data type is a string of text
+	Boolean                    // means the data type is a boolean
+	Undefined                  // means the data type is unknown or undefined
+)
+
+// String returns the name of the field type as it is used in the API.
+func (t FieldType) String() string {
+	switch t {
+	case Float:
+		return "float"
+	case Integer:
+		return "integer"
+	case Unsigned:
+		return "unsigned"
+	case String:
+		return "string"
+	case Boolean:
+		return "boolean"
+	default:
+		return "undefined"
+	}
+}
+
+// MeasurementField is the key and the data type of a field of a measurement.
+type MeasurementField struct {
+	Key  string
+	Type FieldType
+}
+
+// MeasurementFieldIterator describes the behavior for enumerating a sequence
+// of the fields of a measurement.
+type MeasurementFieldIterator interface {
+	// Next advances the MeasurementFieldIterator to the next value. It returns
+	// false when there are no more values.
+	Next() bool
+
+	// Value returns the current value of the cursor after a call to Next.
+	Value() MeasurementField
+}
+
+// EmptyMeasurementFieldIterator is an implementation of
+// MeasurementFieldIterator that returns no values.
+var EmptyMeasurementFieldIterator MeasurementFieldIterator = &measurementFieldIterator{}
+
+type measurementFieldIterator struct{}
+
+func (*measurementFieldIterator) Next() bool              { return false }
+func (*measurementFieldIterator) Value() MeasurementField { return MeasurementField{} }
+
+// MeasurementFieldSliceIterator is a MeasurementFieldIterator over a slice of
+// fields.
+type MeasurementFieldSliceIterator struct {
+	f []MeasurementField
+	v MeasurementField
+}
+
+// NewMeasurementFieldSliceIterator returns a MeasurementFieldIterator over the
+// values of f.
+func NewMeasurementFieldSliceIterator(f []MeasurementField) *MeasurementFieldSliceIterator {
+	return &MeasurementFieldSliceIterator{f: f}
+}
+
+func (s *MeasurementFieldSliceIterator) Next() bool {
+	if len(s.f) > 0 {
+		s.v, s.f = s.f[0], s.f[1:]
+		return true
+	}
+	s.v = MeasurementField{}
+	return false
+}
+
+func (s *MeasurementFieldSliceIterator) Value() MeasurementField {
+	return s.v
+}
+
+// MeasurementFieldIteratorToSlice reads the remaining values of i into a
+// slice.
+func MeasurementFieldIteratorToSlice(i MeasurementFieldIterator) []MeasurementField {
+	if i == nil {
+		return nil
+	}
+
+	var a []MeasurementField
+	for i.Next() {
+		a = append(a, i.Value())
+	}
+	return a
+}
@@ -0,0 +1,58 @@
+package cursors
+
+// StringIterator describes the behavior for enumerating a sequence of
+// string values.
+type StringIterator interface {
+	// Next advances the StringIterator to the next value. It returns false
+	// when there are no more values.
+	Next() bool
+
+	// Value returns the current value of the cursor after a call to Next.
+	Value() string
+}
+
+// EmptyStringIterator is an implementation of StringIterator that returns
+// no values.
+var EmptyStringIterator StringIterator = &stringIterator{}
+
+type stringIterator struct{}
+
+func (*stringIterator) Next() bool    { return false }
+func (*stringIterator) Value() string { return "" }
+
+// StringSliceIterator is a StringIterator over a slice of strings.
+type StringSliceIterator struct {
+	s []string
+	v string
+}
+
+// NewStringSliceIterator returns a StringIterator over the values of s.
+func NewStringSliceIterator(s []string) *StringSliceIterator {
+	return &StringSliceIterator{s: s}
+}
+
+func (s *StringSliceIterator) Next() bool {
+	if len(s.s) > 0 {
+		s.v, s.s = s.s[0], s.s[1:]
+		return true
+	}
+	s.v = ""
+	return false
+}
+
+func (s *StringSliceIterator) Value() string {
+	return s.v
+}
+
+// StringIteratorToSlice reads the remaining values of i into a slice.
+func StringIteratorToSlice(i StringIterator) []string {
+	if i == nil {
+		return nil
+	}
+
+	var a []string
+	for i.Next() {
+		a = append(a, i.Value())
+	}
+	return a
+}
@@ -24,7 +24,7 @@ type Config struct {
 	//
 	// The cache uses an LRU strategy for eviction. Setting the value to 0 will
 	// disable the cache.
-	SeriesIDSetCacheSize uint64
+	SeriesIDSetCacheSize uint64 `toml:"series-id-set-cache-size"`
 }
 
 // NewConfig returns a new Config.
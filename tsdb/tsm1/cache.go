@@ -593,6 +593,37 @@ func (c *Cache) DeleteBucketRange(name []byte, min, max int64) {
 	c.tracker.SetMemBytes(uint64(c.Size()))
 }
 
+// DeleteRange removes values for the provided keys with timestamps between
+// min and max from the cache.
+func (c *Cache) DeleteRange(keys [][]byte, min, max int64) {
+	c.init()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var total uint64
+	for _, k := range keys {
+		e := c.store.entry(k)
+		if e == nil {
+			continue
+		}
+		total += uint64(e.size())
+
+		// filter the values and subtract out the remaining bytes from the reduction.
+		e.filter(min, max)
+		total -= uint64(e.size())
+
+		// if it has no entries left, remove it.
+		if e.count() == 0 {
+			total += uint64(len(k))
+			c.store.remove(k)
+		}
+	}
+
+	c.tracker.DecCacheSize(total)
+	c.tracker.SetMemBytes(uint64(c.Size()))
+}
+
 // SetMaxSize updates the memory limit of the cache.
 func (c *Cache) SetMaxSize(size uint64) {
 	c.mu.Lock()
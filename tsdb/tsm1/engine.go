@@ -745,6 +745,18 @@ func (t *compactionTracker) SetOptimiseQueue(length uint64) { t.SetQueue(4, leng
 // SetFullQueue sets the queue depth for Full compactions.
 func (t *compactionTracker) SetFullQueue(length uint64) { t.SetQueue(5, length) }
 
+// CreateSnapshot will create a temp directory that holds temporary hard links
+// to the underlying TSM files, after first writing the cache out to disk.
+func (e *Engine) CreateSnapshot() (string, error) {
+	if err := e.WriteSnapshot(); err != nil {
+		return "", err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.FileStore.CreateSnapshot()
+}
+
 // WriteSnapshot will snapshot the cache and write a new TSM file with its contents, releasing the snapshot when done.
 func (e *Engine) WriteSnapshot() error {
 	// Lock and grab the cache snapshot along with all the closed WAL
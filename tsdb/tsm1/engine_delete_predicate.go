@@ -0,0 +1,140 @@
+package tsm1
+
+import (
+	"math"
+	"sync"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/bytesutil"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxql"
+)
+
+// DeleteBucketRangePredicate removes TSM data belonging to a bucket for the series
+// matching the provided predicate. The predicate is evaluated against the tags of
+// each series, including the measurement (_m) and field (_f) tags. Series that no
+// longer have any data are removed from the index and series file. A nil predicate
+// matches every series in the bucket, which is equivalent to DeleteBucketRange.
+func (e *Engine) DeleteBucketRangePredicate(name []byte, min, max int64, pred influxql.Expr) error {
+	if pred == nil {
+		return e.DeleteBucketRange(name, min, max)
+	}
+
+	// Ensure that the index does not compact away the measurement or series we're
+	// going to delete before we're done with them.
+	e.index.DisableCompactions()
+	defer e.index.EnableCompactions()
+	e.index.Wait()
+
+	fs, err := e.index.RetainFileSet()
+	if err != nil {
+		return err
+	}
+	defer fs.Release()
+
+	// See DeleteBucketRange for why only level compactions are disabled.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	e.sfile.DisableCompactions()
+	defer e.sfile.EnableCompactions()
+	e.sfile.Wait()
+
+	if min == influxql.MinTime {
+		min = math.MinInt64
+	}
+	if max == influxql.MaxTime {
+		max = math.MaxInt64
+	}
+
+	// The TSI index and Series File do not store series data in escaped form.
+	itr, err := e.index.MeasurementSeriesByExprIterator(models.UnescapeMeasurement(name), pred)
+	if err != nil {
+		return err
+	} else if itr == nil {
+		return nil
+	}
+	defer itr.Close()
+
+	// Build up the set of composite TSM keys for the matching series.
+	var (
+		keys [][]byte
+		sids = make(map[string]tsdb.SeriesID)
+	)
+	for {
+		elem, err := itr.Next()
+		if err != nil {
+			return err
+		} else if elem.SeriesID.IsZero() {
+			break
+		}
+
+		skey := e.sfile.SeriesKey(elem.SeriesID)
+		if len(skey) == 0 {
+			continue
+		}
+
+		sname, tags := tsdb.ParseSeriesKey(skey)
+		field := tags.Get(tsdb.FieldKeyTagKeyBytes)
+		if field == nil {
+			continue
+		}
+
+		key := SeriesFieldKeyBytes(string(models.MakeKey(sname, tags)), string(field))
+		keys = append(keys, key)
+		sids[string(key)] = elem.SeriesID
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// Tombstones and cache deletes require the keys to be sorted.
+	bytesutil.Sort(keys)
+
+	if err := e.FileStore.DeleteRange(keys, min, max); err != nil {
+		return err
+	}
+	e.Cache.DeleteRange(keys, min, max)
+
+	// Any series with no remaining data in the cache or the TSM files can now be
+	// removed from the index and the series file.
+	var possiblyDead struct {
+		sync.Mutex
+		keys map[string]struct{}
+	}
+	possiblyDead.keys = make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if len(e.Cache.Values(key)) == 0 {
+			possiblyDead.keys[string(key)] = struct{}{}
+		}
+	}
+
+	if err := e.FileStore.Apply(func(r TSMFile) error {
+		for _, key := range keys {
+			if !r.Contains(key) {
+				continue
+			}
+			possiblyDead.Lock()
+			delete(possiblyDead.keys, string(key))
+			possiblyDead.Unlock()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for key := range possiblyDead.keys {
+		sid := sids[key]
+		seriesKey, _ := SeriesAndFieldFromCompositeKey([]byte(key))
+		if err := e.index.DropSeries(sid, seriesKey, true); err != nil {
+			return err
+		}
+
+		if err := e.sfile.DeleteSeriesID(sid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
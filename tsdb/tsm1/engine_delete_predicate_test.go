@@ -0,0 +1,77 @@
+package tsm1_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxql"
+)
+
+func TestEngine_DeleteBucketRangePredicate(t *testing.T) {
+	// Create a few points.
+	p1 := MustParsePointString("cpu,host=A value=1.1 1", "mm0")
+	p2 := MustParsePointString("cpu,host=A value=1.2 2", "mm0")
+	p3 := MustParsePointString("cpu,host=B value=1.3 3", "mm0")
+	p4 := MustParsePointString("mem,host=A value=1.4 4", "mm0")
+	p5 := MustParsePointString("cpu,host=A value=1.5 5", "mm1")
+
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mock the planner so compactions don't run during the test
+	e.CompactionPlan = &mockPlanner{}
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	if err := e.writePoints(p1, p2, p3, p4, p5); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	if err := e.WriteSnapshot(); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	// Only remove part of the time range for cpu,host=A in mm0.
+	pred := influxql.MustParseExpr(`_m = 'cpu' AND host = 'A'`)
+	if err := e.DeleteBucketRangePredicate([]byte("mm0"), 0, 1, pred); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	keys := e.FileStore.Keys()
+	exp := map[string]byte{
+		"mm0,_f=value,_m=cpu,host=A#!~#value": 0,
+		"mm0,_f=value,_m=cpu,host=B#!~#value": 0,
+		"mm0,_f=value,_m=mem,host=A#!~#value": 0,
+		"mm1,_f=value,_m=cpu,host=A#!~#value": 0,
+	}
+	if !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("unexpected series in file store: %v != %v", keys, exp)
+	}
+
+	if got, exp := e.index.SeriesN(), int64(4); got != exp {
+		t.Fatalf("series cardinality mismatch: got %d, exp %d", got, exp)
+	}
+
+	// Removing the remaining data for the series should drop it from the index.
+	if err := e.DeleteBucketRangePredicate([]byte("mm0"), 0, 9, pred); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	keys = e.FileStore.Keys()
+	exp = map[string]byte{
+		"mm0,_f=value,_m=cpu,host=B#!~#value": 0,
+		"mm0,_f=value,_m=mem,host=A#!~#value": 0,
+		"mm1,_f=value,_m=cpu,host=A#!~#value": 0,
+	}
+	if !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("unexpected series in file store: %v != %v", keys, exp)
+	}
+
+	if got, exp := e.index.SeriesN(), int64(3); got != exp {
+		t.Fatalf("series cardinality mismatch: got %d, exp %d", got, exp)
+	}
+}
@@ -0,0 +1,334 @@
+package tsm1
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+// TagKeys returns an iterator over the sorted tag keys of the series of the
+// bucket that match the predicate and have data between start and end. The
+// keys and the series come from the index, and the series are bounded by time
+// with the time ranges of their blocks in the TSM files and of their values in
+// the cache. A nil predicate matches every series of the bucket.
+func (e *Engine) TagKeys(ctx context.Context, name []byte, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	// The TSI index and Series File do not store series data in escaped form.
+	name = models.UnescapeMeasurement(name)
+
+	s, err := e.newSchemaSeries(name, start, end, predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := e.index.TagKeyIterator(name)
+	if err != nil {
+		return nil, err
+	} else if itr == nil {
+		return cursors.EmptyStringIterator, nil
+	}
+	defer itr.Close()
+
+	var keys []string
+	for {
+		key, err := itr.Next()
+		if err != nil {
+			return nil, err
+		} else if key == nil {
+			break
+		}
+
+		sitr, err := e.index.TagKeySeriesIDIterator(name, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := s.any(ctx, sitr); err != nil {
+			return nil, err
+		} else if ok {
+			keys = append(keys, string(key))
+		}
+	}
+	return cursors.NewStringSliceIterator(keys), nil
+}
+
+// TagValues returns an iterator over the sorted values of the tag key of the
+// series of the bucket that match the predicate and have data between start
+// and end. The series are found and bounded by time like in TagKeys.
+func (e *Engine) TagValues(ctx context.Context, name, key []byte, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	// The TSI index and Series File do not store series data in escaped form.
+	name = models.UnescapeMeasurement(name)
+
+	s, err := e.newSchemaSeries(name, start, end, predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := e.index.TagValueIterator(name, key)
+	if err != nil {
+		return nil, err
+	} else if itr == nil {
+		return cursors.EmptyStringIterator, nil
+	}
+	defer itr.Close()
+
+	var values []string
+	for {
+		value, err := itr.Next()
+		if err != nil {
+			return nil, err
+		} else if value == nil {
+			break
+		}
+
+		sitr, err := e.index.TagValueSeriesIDIterator(name, key, value)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := s.any(ctx, sitr); err != nil {
+			return nil, err
+		} else if ok {
+			values = append(values, string(value))
+		}
+	}
+	return cursors.NewStringSliceIterator(values), nil
+}
+
+// MeasurementNames returns an iterator over the sorted names of the
+// measurements of the series of the bucket that match the predicate and have
+// data between start and end.
+func (e *Engine) MeasurementNames(ctx context.Context, name []byte, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error) {
+	return e.TagValues(ctx, name, tsdb.MeasurementTagKeyBytes, start, end, predicate)
+}
+
+// MeasurementFields returns an iterator over the fields, sorted by their
+// keys, of the series of the measurement of the bucket that match the
+// predicate and have data between start and end. The type of a field is the
+// type of its values in the first series that has data in the range.
+func (e *Engine) MeasurementFields(ctx context.Context, name, measurement []byte, start, end int64, predicate influxql.Expr) (cursors.MeasurementFieldIterator, error) {
+	// The TSI index and Series File do not store series data in escaped form.
+	name = models.UnescapeMeasurement(name)
+
+	cond := influxql.Expr(&influxql.BinaryExpr{
+		Op:  influxql.EQ,
+		LHS: &influxql.VarRef{Val: tsdb.MeasurementTagKey},
+		RHS: &influxql.StringLiteral{Val: string(measurement)},
+	})
+	if predicate != nil {
+		cond = &influxql.BinaryExpr{
+			Op:  influxql.AND,
+			LHS: &influxql.ParenExpr{Expr: cond},
+			RHS: &influxql.ParenExpr{Expr: predicate},
+		}
+	}
+	s, err := e.newSchemaSeries(name, start, end, cond)
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := e.index.TagValueIterator(name, tsdb.FieldKeyTagKeyBytes)
+	if err != nil {
+		return nil, err
+	} else if itr == nil {
+		return cursors.EmptyMeasurementFieldIterator, nil
+	}
+	defer itr.Close()
+
+	var fields []cursors.MeasurementField
+	for {
+		key, err := itr.Next()
+		if err != nil {
+			return nil, err
+		} else if key == nil {
+			break
+		}
+
+		sitr, err := e.index.TagValueSeriesIDIterator(name, tsdb.FieldKeyTagKeyBytes, key)
+		if err != nil {
+			return nil, err
+		}
+		id, err := s.first(ctx, sitr)
+		if err != nil {
+			return nil, err
+		} else if id.IsZero() {
+			continue
+		}
+
+		typ, err := s.fieldType(id)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, cursors.MeasurementField{Key: string(key), Type: typ})
+	}
+	return cursors.NewMeasurementFieldSliceIterator(fields), nil
+}
+
+// schemaSeries finds the series of a bucket that match a predicate and have
+// data within a time range. The result for each series is kept, since most
+// series have many tag keys and values.
+type schemaSeries struct {
+	e          *Engine
+	start, end int64
+	// matches is the set of series that match the predicate, or nil when
+	// every series matches.
+	matches *tsdb.SeriesIDSet
+	seen    map[tsdb.SeriesID]bool
+	key     []byte
+}
+
+func (e *Engine) newSchemaSeries(name []byte, start, end int64, predicate influxql.Expr) (*schemaSeries, error) {
+	s := &schemaSeries{
+		e:     e,
+		start: start,
+		end:   end,
+		seen:  make(map[tsdb.SeriesID]bool),
+	}
+	if predicate == nil {
+		return s, nil
+	}
+
+	s.matches = tsdb.NewSeriesIDSet()
+	itr, err := e.index.MeasurementSeriesByExprIterator(name, predicate)
+	if err != nil {
+		return nil, err
+	} else if itr == nil {
+		return s, nil
+	}
+	defer itr.Close()
+
+	for {
+		elem, err := itr.Next()
+		if err != nil {
+			return nil, err
+		} else if elem.SeriesID.IsZero() {
+			break
+		}
+		s.matches.Add(elem.SeriesID)
+	}
+	return s, nil
+}
+
+// any returns true if any series of the iterator matches the predicate and
+// has data within the time range. The iterator is closed.
+func (s *schemaSeries) any(ctx context.Context, itr tsdb.SeriesIDIterator) (bool, error) {
+	id, err := s.first(ctx, itr)
+	return !id.IsZero(), err
+}
+
+// first returns the first series of the iterator that matches the predicate
+// and has data within the time range, or the zero series ID if there is none.
+// The iterator is closed.
+func (s *schemaSeries) first(ctx context.Context, itr tsdb.SeriesIDIterator) (tsdb.SeriesID, error) {
+	if itr == nil {
+		return tsdb.SeriesID{}, nil
+	}
+	defer itr.Close()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return tsdb.SeriesID{}, err
+		}
+
+		elem, err := itr.Next()
+		if err != nil {
+			return tsdb.SeriesID{}, err
+		} else if elem.SeriesID.IsZero() {
+			return tsdb.SeriesID{}, nil
+		}
+
+		if s.matches != nil && !s.matches.Contains(elem.SeriesID) {
+			continue
+		}
+		if ok, err := s.hasData(elem.SeriesID); err != nil {
+			return tsdb.SeriesID{}, err
+		} else if ok {
+			return elem.SeriesID, nil
+		}
+	}
+}
+
+// hasData returns true if the series has data within the time range.
+func (s *schemaSeries) hasData(id tsdb.SeriesID) (bool, error) {
+	if ok, found := s.seen[id]; found {
+		return ok, nil
+	}
+
+	if !s.makeKey(id) {
+		return false, nil
+	}
+
+	ok := len(s.cacheValues()) > 0
+	if !ok {
+		var err error
+		if ok, err = s.e.FileStore.HasData(s.key, s.start, s.end); err != nil {
+			return false, err
+		}
+	}
+
+	s.seen[id] = ok
+	return ok, nil
+}
+
+// fieldType returns the type of the values of the series that has data
+// within the time range.
+func (s *schemaSeries) fieldType(id tsdb.SeriesID) (cursors.FieldType, error) {
+	if !s.makeKey(id) {
+		return cursors.Undefined, nil
+	}
+
+	if values := s.cacheValues(); len(values) > 0 {
+		typ, err := values.InfluxQLType()
+		if err != nil {
+			return cursors.Undefined, err
+		}
+		return dataTypeToFieldType(typ), nil
+	}
+
+	typ, err := s.e.FileStore.Type(s.key)
+	if err != nil {
+		return cursors.Undefined, err
+	}
+	return dataTypeToFieldType(BlockTypeToInfluxQLDataType(typ)), nil
+}
+
+// makeKey makes the TSM key of the series. It returns false if the series
+// does not exist.
+func (s *schemaSeries) makeKey(id tsdb.SeriesID) bool {
+	skey := s.e.sfile.SeriesKey(id)
+	if len(skey) == 0 {
+		return false
+	}
+
+	name, tags := tsdb.ParseSeriesKey(skey)
+	field := tags.Get(tsdb.FieldKeyTagKeyBytes)
+	s.key = models.AppendMakeKey(s.key[:0], name, tags)
+	s.key = append(s.key, keyFieldSeparatorBytes...)
+	s.key = append(s.key, field...)
+	return true
+}
+
+// cacheValues returns the values of the key in the cache within the time
+// range. The cache returns a copy of its values.
+func (s *schemaSeries) cacheValues() Values {
+	return s.e.Cache.Values(s.key).Include(s.start, s.end)
+}
+
+// dataTypeToFieldType converts the InfluxQL data type of a field into the
+// type of the field.
+func dataTypeToFieldType(typ influxql.DataType) cursors.FieldType {
+	switch typ {
+	case influxql.Float:
+		return cursors.Float
+	case influxql.Integer:
+		return cursors.Integer
+	case influxql.Unsigned:
+		return cursors.Unsigned
+	case influxql.String:
+		return cursors.String
+	case influxql.Boolean:
+		return cursors.Boolean
+	default:
+		return cursors.Undefined
+	}
+}
@@ -0,0 +1,329 @@
+package tsm1_test
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+func TestEngine_TagKeys(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	// The first points are snapshotted to a TSM file and the others stay
+	// in the cache.
+	if err := e.WritePointsString("mm0",
+		"cpu,host=A value=1.1 10",
+		"cpu,host=B,region=east value=1.2 20",
+		"mem,host=A,node=n1 value=1.3 30",
+	); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+	if err := e.WritePointsString("mm0",
+		"disk,host=A,path=/ value=1.4 40",
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.WritePointsString("mm1",
+		"cpu,zone=z1 value=1.5 10",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		start, end int64
+		predicate  string
+		exp        []string
+	}{
+		{
+			name:  "all",
+			start: math.MinInt64,
+			end:   math.MaxInt64,
+			exp:   []string{"_f", "_m", "host", "node", "path", "region"},
+		},
+		{
+			name:  "range in tsm",
+			start: 15,
+			end:   25,
+			exp:   []string{"_f", "_m", "host", "region"},
+		},
+		{
+			name:  "range in cache",
+			start: 35,
+			end:   100,
+			exp:   []string{"_f", "_m", "host", "path"},
+		},
+		{
+			name:  "empty range",
+			start: 50,
+			end:   100,
+		},
+		{
+			name:      "predicate",
+			start:     math.MinInt64,
+			end:       math.MaxInt64,
+			predicate: `_m = 'cpu'`,
+			exp:       []string{"_f", "_m", "host", "region"},
+		},
+		{
+			name:      "predicate and range",
+			start:     0,
+			end:       15,
+			predicate: `_m = 'cpu'`,
+			exp:       []string{"_f", "_m", "host"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var predicate influxql.Expr
+			if tt.predicate != "" {
+				predicate = influxql.MustParseExpr(tt.predicate)
+			}
+			itr, err := e.TagKeys(context.Background(), []byte("mm0"), tt.start, tt.end, predicate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cursors.StringIteratorToSlice(itr); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("unexpected tag keys: got %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestEngine_TagValues(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	if err := e.WritePointsString("mm0",
+		"cpu,host=A value=1.1 10",
+		"cpu,host=B value=1.2 20",
+		"mem,host=C value=1.3 30",
+	); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+
+	// Values that are deleted are not within the range.
+	if err := e.DeleteBucketRangePredicate([]byte("mm0"), 0, 20, influxql.MustParseExpr(`host = 'B'`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.WritePointsString("mm0", "cpu,host=B value=1.4 40"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		start, end int64
+		predicate  string
+		exp        []string
+	}{
+		{
+			name:  "all",
+			key:   "host",
+			start: math.MinInt64,
+			end:   math.MaxInt64,
+			exp:   []string{"A", "B", "C"},
+		},
+		{
+			name:  "measurements",
+			key:   "_m",
+			start: math.MinInt64,
+			end:   math.MaxInt64,
+			exp:   []string{"cpu", "mem"},
+		},
+		{
+			name:  "deleted",
+			key:   "host",
+			start: 0,
+			end:   35,
+			exp:   []string{"A", "C"},
+		},
+		{
+			name:      "predicate",
+			key:       "host",
+			start:     math.MinInt64,
+			end:       math.MaxInt64,
+			predicate: `_m = 'cpu'`,
+			exp:       []string{"A", "B"},
+		},
+		{
+			name:  "missing key",
+			key:   "region",
+			start: math.MinInt64,
+			end:   math.MaxInt64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var predicate influxql.Expr
+			if tt.predicate != "" {
+				predicate = influxql.MustParseExpr(tt.predicate)
+			}
+			itr, err := e.TagValues(context.Background(), []byte("mm0"), []byte(tt.key), tt.start, tt.end, predicate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cursors.StringIteratorToSlice(itr); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("unexpected tag values: got %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestEngine_MeasurementNames(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	if err := e.WritePointsString("mm0",
+		"cpu,host=A value=1.1 10",
+		"mem,host=B value=1.2 20",
+	); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+	if err := e.WritePointsString("mm0", "disk,host=A value=1.3 30"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		start, end int64
+		predicate  string
+		exp        []string
+	}{
+		{
+			name:  "all",
+			start: math.MinInt64,
+			end:   math.MaxInt64,
+			exp:   []string{"cpu", "disk", "mem"},
+		},
+		{
+			name:  "range",
+			start: 15,
+			end:   35,
+			exp:   []string{"disk", "mem"},
+		},
+		{
+			name:      "predicate",
+			start:     math.MinInt64,
+			end:       math.MaxInt64,
+			predicate: `host = 'A'`,
+			exp:       []string{"cpu", "disk"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var predicate influxql.Expr
+			if tt.predicate != "" {
+				predicate = influxql.MustParseExpr(tt.predicate)
+			}
+			itr, err := e.MeasurementNames(context.Background(), []byte("mm0"), tt.start, tt.end, predicate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cursors.StringIteratorToSlice(itr); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("unexpected measurement names: got %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestEngine_MeasurementFields(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	if err := e.WritePointsString("mm0",
+		"cpu,host=A usage=1.1,count=3i 10",
+		"cpu,host=B up=true 20",
+		"mem,host=A free=4i 10",
+	); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+	if err := e.WritePointsString("mm0", `cpu,host=C name="c" 30`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		measurement string
+		start, end  int64
+		predicate   string
+		exp         []cursors.MeasurementField
+	}{
+		{
+			name:        "all",
+			measurement: "cpu",
+			start:       math.MinInt64,
+			end:         math.MaxInt64,
+			exp: []cursors.MeasurementField{
+				{Key: "count", Type: cursors.Integer},
+				{Key: "name", Type: cursors.String},
+				{Key: "up", Type: cursors.Boolean},
+				{Key: "usage", Type: cursors.Float},
+			},
+		},
+		{
+			name:        "range",
+			measurement: "cpu",
+			start:       15,
+			end:         100,
+			exp: []cursors.MeasurementField{
+				{Key: "name", Type: cursors.String},
+				{Key: "up", Type: cursors.Boolean},
+			},
+		},
+		{
+			name:        "predicate",
+			measurement: "cpu",
+			start:       math.MinInt64,
+			end:         math.MaxInt64,
+			predicate:   `host = 'A'`,
+			exp: []cursors.MeasurementField{
+				{Key: "count", Type: cursors.Integer},
+				{Key: "usage", Type: cursors.Float},
+			},
+		},
+		{
+			name:        "other measurement",
+			measurement: "mem",
+			start:       math.MinInt64,
+			end:         math.MaxInt64,
+			exp: []cursors.MeasurementField{
+				{Key: "free", Type: cursors.Integer},
+			},
+		},
+		{
+			name:        "missing measurement",
+			measurement: "disk",
+			start:       math.MinInt64,
+			end:         math.MaxInt64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var predicate influxql.Expr
+			if tt.predicate != "" {
+				predicate = influxql.MustParseExpr(tt.predicate)
+			}
+			itr, err := e.MeasurementFields(context.Background(), []byte("mm0"), []byte(tt.measurement), tt.start, tt.end, predicate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cursors.MeasurementFieldIteratorToSlice(itr); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("unexpected measurement fields: got %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+}
@@ -693,6 +693,58 @@ func (f *FileStore) Read(key []byte, t int64) ([]Value, error) {
 	return nil, nil
 }
 
+// HasData returns true if any file has values for the given key between min
+// and max, that have not been deleted. The time ranges of the blocks in the
+// index are used rather than the values, so a block that overlaps the range
+// is assumed to have values within it.
+func (f *FileStore) HasData(key []byte, min, max int64) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var (
+		entries    []IndexEntry
+		tombstones []TimeRange
+		err        error
+	)
+	for _, f := range f.files {
+		if !f.OverlapsTimeRange(min, max) || !f.OverlapsKeyRange(key, key) {
+			continue
+		}
+
+		entries, err = f.ReadEntries(key, entries[:0])
+		if err != nil {
+			return false, err
+		}
+
+		// Only the parts of the blocks within the range must be covered
+		// by tombstones for the file to have no values in the range.
+		n := 0
+		for _, e := range entries {
+			if !e.OverlapsTimeRange(min, max) {
+				continue
+			}
+			if e.MinTime < min {
+				e.MinTime = min
+			}
+			if e.MaxTime > max {
+				e.MaxTime = max
+			}
+			entries[n] = e
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+
+		tombstones = f.TombstoneRange(key, tombstones[:0])
+		sort.Slice(tombstones, func(i, j int) bool { return tombstones[i].Less(tombstones[j]) })
+		if !timeRangesCoverEntries(timeRangeMerger{sorted: tombstones, used: true}, entries[:n]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (f *FileStore) Cost(key []byte, min, max int64) query.IteratorCost {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
@@ -1157,6 +1209,12 @@ func (f *FileStore) CreateSnapshot() (string, error) {
 	return tmpPath, nil
 }
 
+// InternalBackupPath returns the path of the temp directory created by
+// CreateSnapshot for the provided snapshot ID.
+func (f *FileStore) InternalBackupPath(backupID int) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%d.%s", backupID, TmpTSMFileExtension))
+}
+
 // MeasurementStats returns the sum of all measurement stats within the store.
 func (f *FileStore) MeasurementStats() (MeasurementStats, error) {
 	f.mu.RLock()
@@ -23,6 +23,15 @@ const (
 	UsageQueryRequestCount UsageMetric = "usage_query_request_count"
 	// UsageQueryRequestBytes is the name of the metrics for tracking the number of query bytes.
 	UsageQueryRequestBytes UsageMetric = "usage_query_request_bytes"
+
+	// UsageSeriesCardinality is the name of the metrics for tracking the number of series stored.
+	UsageSeriesCardinality UsageMetric = "usage_series_cardinality"
+	// UsageSeriesCardinalityLimit is the name of the metrics for tracking the limit of the number of series stored.
+	UsageSeriesCardinalityLimit UsageMetric = "usage_series_cardinality_limit"
+	// UsageTagValues is the name of the metrics for tracking the largest number of values of a tag key.
+	UsageTagValues UsageMetric = "usage_tag_values"
+	// UsageTagValuesLimit is the name of the metrics for tracking the limit of the number of values of a tag key.
+	UsageTagValuesLimit UsageMetric = "usage_tag_values_limit"
 )
 
 // Usage is a metric associated with the utilization of a particular resource.
@@ -38,6 +47,13 @@ type UsageService interface {
 	GetUsage(ctx context.Context, filter UsageFilter) (map[UsageMetric]*Usage, error)
 }
 
+// UsageRecorder records usage as it happens.
+type UsageRecorder interface {
+	// RecordUsage adds value to the usage metric of the organization, or of
+	// the bucket if bucketID is valid.
+	RecordUsage(orgID, bucketID ID, metric UsageMetric, value float64)
+}
+
 // UsageFilter is used to filter usage.
 type UsageFilter struct {
 	OrgID    *ID
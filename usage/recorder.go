@@ -0,0 +1,194 @@
+// Package usage records the usage of organizations and buckets into a system
+// bucket of each organization and reports it for arbitrary time ranges.
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	// SystemBucketID is the fixed ID of the system bucket of each organization
+	// that its usage is recorded into.
+	SystemBucketID platform.ID = 11
+
+	// DefaultFlushInterval is the default interval at which recorded usage is
+	// written to the system buckets.
+	DefaultFlushInterval = 10 * time.Second
+
+	// measurement is the measurement of the usage points. Its fields are the
+	// usage metrics.
+	measurement = "usage"
+	// bucketIDTag is the tag of the bucket of usage that is recorded for a
+	// bucket. Usage of the organization as a whole, such as queries, has no
+	// bucket tag.
+	bucketIDTag = "bucketID"
+)
+
+var _ platform.UsageRecorder = (*Recorder)(nil)
+
+// usageKey identifies a usage metric of an organization or one of its
+// buckets. The bucket is invalid for usage of the organization as a whole.
+type usageKey struct {
+	org    platform.ID
+	bucket platform.ID
+	metric platform.UsageMetric
+}
+
+// Recorder accumulates recorded usage in memory and periodically writes it
+// as points into the system bucket of each organization.
+type Recorder struct {
+	pointsWriter storage.PointsWriter
+	interval     time.Duration
+	logger       *zap.Logger
+
+	mu      sync.Mutex
+	pending map[usageKey]float64
+
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+// RecorderOption is an option for a Recorder.
+type RecorderOption func(r *Recorder)
+
+// WithFlushInterval sets the interval at which the recorder writes usage.
+func WithFlushInterval(d time.Duration) RecorderOption {
+	return func(r *Recorder) {
+		r.interval = d
+	}
+}
+
+// NewRecorder returns a Recorder that writes usage with pw.
+func NewRecorder(pw storage.PointsWriter, opts ...RecorderOption) *Recorder {
+	r := &Recorder{
+		pointsWriter: pw,
+		interval:     DefaultFlushInterval,
+		logger:       zap.NewNop(),
+		pending:      make(map[usageKey]float64),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// WithLogger sets the logger on the recorder. It must be called before Open.
+func (r *Recorder) WithLogger(log *zap.Logger) {
+	r.logger = log.With(zap.String("service", "usage-recorder"))
+}
+
+// Open starts writing the recorded usage every flush interval.
+func (r *Recorder) Open() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Flush(ctx); err != nil {
+					r.logger.Error("Failed to write usage", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops the recorder and writes the usage that is still pending.
+func (r *Recorder) Close() error {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+	return r.Flush(context.Background())
+}
+
+// RecordUsage adds value to the usage metric of the organization, or of the
+// bucket if bucketID is valid. Usage of the system bucket itself is ignored.
+func (r *Recorder) RecordUsage(orgID, bucketID platform.ID, metric platform.UsageMetric, value float64) {
+	if bucketID == SystemBucketID {
+		return
+	}
+
+	r.mu.Lock()
+	r.pending[usageKey{org: orgID, bucket: bucketID, metric: metric}] += value
+	r.mu.Unlock()
+}
+
+// Flush writes the pending usage into the system buckets. Usage that fails to
+// be written stays pending.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[usageKey]float64)
+	r.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	points, err := usagePoints(pending, time.Now())
+	if err == nil {
+		err = r.pointsWriter.WritePoints(ctx, points)
+	}
+	if err != nil {
+		r.mu.Lock()
+		for k, v := range pending {
+			r.pending[k] += v
+		}
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// usagePoints returns the exploded points of the usage at time t, one point
+// per organization and bucket with a field per usage metric.
+func usagePoints(pending map[usageKey]float64, t time.Time) ([]models.Point, error) {
+	type resource struct {
+		org, bucket platform.ID
+	}
+	fields := make(map[resource]models.Fields)
+	for k, v := range pending {
+		res := resource{org: k.org, bucket: k.bucket}
+		if fields[res] == nil {
+			fields[res] = make(models.Fields)
+		}
+		fields[res][string(k.metric)] = v
+	}
+
+	var points []models.Point
+	for res, f := range fields {
+		var tags models.Tags
+		if res.bucket.Valid() {
+			tags = models.NewTags(map[string]string{bucketIDTag: res.bucket.String()})
+		}
+		pt, err := models.NewPoint(measurement, tags, f, t)
+		if err != nil {
+			return nil, err
+		}
+
+		exploded, err := tsdb.ExplodePoints(res.org, SystemBucketID, []models.Point{pt})
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, exploded...)
+	}
+	return points, nil
+}
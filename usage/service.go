@@ -0,0 +1,179 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+var _ platform.UsageService = (*Service)(nil)
+
+// The measurement and field tag keys of the series read from the store.
+var (
+	measurementKeyBytes = []byte("_measurement")
+	fieldKeyBytes       = []byte("_field")
+)
+
+// Service reports the usage recorded into the system buckets of the
+// organizations.
+type Service struct {
+	// Store reads the system buckets.
+	Store reads.Store
+	// OrganizationService finds the organizations of usage across all
+	// organizations.
+	OrganizationService platform.OrganizationService
+	// Recorder, if set, writes its pending usage before usage is reported.
+	Recorder *Recorder
+	// UsageService, if set, reports additional usage that is not recorded
+	// over time, such as the current series cardinality.
+	UsageService platform.UsageService
+}
+
+// GetUsage returns the sum of each usage metric recorded for the organization
+// or bucket of the filter during its range. Without an organization, usage is
+// summed across all organizations. Without a range, all recorded usage is
+// summed.
+func (s *Service) GetUsage(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+	if s.Recorder != nil {
+		if err := s.Recorder.Flush(ctx); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInternal,
+				Op:   "usage/GetUsage",
+				Msg:  "unable to write pending usage",
+				Err:  err,
+			}
+		}
+	}
+
+	var orgs []platform.ID
+	if filter.OrgID != nil {
+		orgs = append(orgs, *filter.OrgID)
+	} else {
+		os, _, err := s.OrganizationService.FindOrganizations(ctx, platform.OrganizationFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range os {
+			orgs = append(orgs, o.ID)
+		}
+	}
+
+	start, end := int64(models.MinNanoTime), int64(models.MaxNanoTime)
+	if filter.Range != nil {
+		if !filter.Range.Start.IsZero() {
+			start = filter.Range.Start.UnixNano()
+		}
+		if !filter.Range.Stop.IsZero() {
+			end = filter.Range.Stop.UnixNano()
+		}
+	}
+
+	usage := make(map[platform.UsageMetric]*platform.Usage)
+	for _, org := range orgs {
+		if err := s.sum(ctx, org, filter.BucketID, start, end, func(metric platform.UsageMetric, v float64) {
+			u, ok := usage[metric]
+			if !ok {
+				u = &platform.Usage{
+					OrganizationID: filter.OrgID,
+					BucketID:       filter.BucketID,
+					Type:           metric,
+				}
+				usage[metric] = u
+			}
+			u.Value += v
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.UsageService != nil {
+		other, err := s.UsageService.GetUsage(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for metric, u := range other {
+			usage[metric] = u
+		}
+	}
+	return usage, nil
+}
+
+// sum calls fn with the sum of each usage metric recorded for the
+// organization, or for the bucket if it is not nil, between start and end.
+func (s *Service) sum(ctx context.Context, org platform.ID, bucket *platform.ID, start, end int64, fn func(platform.UsageMetric, float64)) error {
+	src, err := s.Store.GetSource(influxdb.ReadSpec{
+		OrganizationID: org,
+		BucketID:       SystemBucketID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var req datatypes.ReadRequest
+	if req.ReadSource, err = types.MarshalAny(src); err != nil {
+		return err
+	}
+	req.TimestampRange.Start = start
+	req.TimestampRange.End = end
+
+	rs, err := s.Store.Read(ctx, &req)
+	if err != nil {
+		return err
+	} else if rs == nil {
+		return nil
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		tags := rs.Tags()
+		if string(tags.Get(measurementKeyBytes)) != measurement {
+			continue
+		}
+
+		// Usage of the organization as a whole is not usage of any bucket.
+		if bucket != nil && string(tags.Get([]byte(bucketIDTag))) != bucket.String() {
+			continue
+		}
+
+		metric := platform.UsageMetric(tags.Get(fieldKeyBytes))
+		if v, ok := sumCursor(rs.Cursor()); ok {
+			fn(metric, v)
+		}
+	}
+	return rs.Err()
+}
+
+// sumCursor returns the sum of the values of a float cursor, and false if
+// the cursor has no values or is not a float cursor.
+func sumCursor(cur cursors.Cursor) (float64, bool) {
+	if cur == nil {
+		return 0, false
+	}
+	defer cur.Close()
+
+	c, ok := cur.(cursors.FloatArrayCursor)
+	if !ok {
+		return 0, false
+	}
+
+	var (
+		sum float64
+		n   int
+	)
+	for {
+		a := c.Next()
+		if a.Len() == 0 {
+			return sum, n > 0
+		}
+		for _, v := range a.Values {
+			sum += v
+		}
+		n += a.Len()
+	}
+}
@@ -0,0 +1,123 @@
+package usage_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/readservice"
+	"github.com/influxdata/influxdb/usage"
+)
+
+func TestService_GetUsage(t *testing.T) {
+	path, err := ioutil.TempDir("", "usage_service_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	engine := storage.NewEngine(path, storage.NewConfig())
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	var (
+		org1, org2       = platform.ID(1), platform.ID(2)
+		bucket1, bucket2 = platform.ID(100), platform.ID(200)
+	)
+
+	recorder := usage.NewRecorder(engine)
+	recorder.RecordUsage(org1, bucket1, platform.UsageWriteRequestCount, 1)
+	recorder.RecordUsage(org1, bucket1, platform.UsageWriteRequestBytes, 100)
+	recorder.RecordUsage(org1, bucket2, platform.UsageWriteRequestCount, 1)
+	recorder.RecordUsage(org1, 0, platform.UsageQueryRequestCount, 1)
+	recorder.RecordUsage(org2, bucket1, platform.UsageWriteRequestCount, 1)
+	if err := recorder.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+
+	// Usage of the system bucket is not recorded.
+	recorder.RecordUsage(org1, usage.SystemBucketID, platform.UsageWriteRequestCount, 1)
+
+	// The pending usage is written before usage is reported.
+	recorder.RecordUsage(org1, bucket1, platform.UsageWriteRequestCount, 1)
+
+	s := &usage.Service{
+		Store: readservice.NewStore(engine),
+		OrganizationService: &mock.OrganizationService{
+			FindOrganizationsF: func(ctx context.Context, filter platform.OrganizationFilter, opt ...platform.FindOptions) ([]*platform.Organization, int, error) {
+				return []*platform.Organization{{ID: org1}, {ID: org2}}, 2, nil
+			},
+		},
+		Recorder: recorder,
+	}
+
+	tests := []struct {
+		name   string
+		filter platform.UsageFilter
+		exp    map[platform.UsageMetric]float64
+	}{
+		{
+			name:   "bucket",
+			filter: platform.UsageFilter{OrgID: &org1, BucketID: &bucket1},
+			exp: map[platform.UsageMetric]float64{
+				platform.UsageWriteRequestCount: 2,
+				platform.UsageWriteRequestBytes: 100,
+			},
+		},
+		{
+			name:   "organization",
+			filter: platform.UsageFilter{OrgID: &org1},
+			exp: map[platform.UsageMetric]float64{
+				platform.UsageWriteRequestCount: 3,
+				platform.UsageWriteRequestBytes: 100,
+				platform.UsageQueryRequestCount: 1,
+			},
+		},
+		{
+			name:   "all organizations",
+			filter: platform.UsageFilter{},
+			exp: map[platform.UsageMetric]float64{
+				platform.UsageWriteRequestCount: 4,
+				platform.UsageWriteRequestBytes: 100,
+				platform.UsageQueryRequestCount: 1,
+			},
+		},
+		{
+			name:   "range",
+			filter: platform.UsageFilter{OrgID: &org1, Range: &platform.Timespan{Start: start, Stop: time.Now().Add(time.Minute)}},
+			exp: map[platform.UsageMetric]float64{
+				platform.UsageWriteRequestCount: 1,
+			},
+		},
+		{
+			name:   "empty range",
+			filter: platform.UsageFilter{OrgID: &org1, Range: &platform.Timespan{Start: start.Add(-time.Hour), Stop: start.Add(-time.Minute)}},
+			exp:    map[platform.UsageMetric]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetUsage(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.exp) {
+				t.Fatalf("unexpected usage: got %v, exp %v", got, tt.exp)
+			}
+			for metric, v := range tt.exp {
+				if u, ok := got[metric]; !ok || u.Value != v {
+					t.Errorf("unexpected usage %s: got %v, exp %v", metric, u, v)
+				}
+			}
+		})
+	}
+}